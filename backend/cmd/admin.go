@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/domain"
+	infraDB "backend/internal/infra/db"
+	"backend/internal/service"
+)
+
+const adminUsage = `usage: backend admin <command>
+
+commands:
+  create --name NAME [--email EMAIL]   create an admin, or promote an existing user named NAME
+  reset-password --name NAME           replace the password and revoke all sessions
+  list                                 list admin users
+
+The password is read from the first line of stdin.`
+
+// runAdmin は `backend admin ...` サブコマンドを実行する。
+func runAdmin(ctx context.Context, cfg config.Config, args []string, stdin io.Reader, stdout io.Writer, logger *log.Logger) error {
+	if len(args) == 0 {
+		return errors.New(adminUsage)
+	}
+
+	pool, err := infraDB.NewConnection(ctx, cfg.Database)
+	if err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+	defer pool.Close()
+
+	passwordPolicy, err := cfg.Auth.PasswordPolicy()
+	if err != nil {
+		return err
+	}
+	passwordHasher, err := cfg.Auth.PasswordHasher()
+	if err != nil {
+		return err
+	}
+
+	repos := newPostgresRepositories(pool)
+	accounts := service.NewAdminAccountService(repos.users, repos.sessions, passwordPolicy, passwordHasher, logger)
+
+	var prompt io.Writer
+	if isTerminal(stdin) {
+		prompt = stdout
+	}
+	return runAdminCommand(ctx, accounts, args, newPasswordReader(stdin, prompt), stdout)
+}
+
+func runAdminCommand(ctx context.Context, accounts *service.AdminAccountService, args []string, passwords *passwordReader, stdout io.Writer) error {
+	command, rest := args[0], args[1:]
+	switch command {
+	case "create":
+		return adminCreate(ctx, accounts, rest, passwords, stdout)
+	case "reset-password":
+		return adminResetPassword(ctx, accounts, rest, passwords, stdout)
+	case "list":
+		if len(rest) != 0 {
+			return errors.New(adminUsage)
+		}
+		return adminList(ctx, accounts, stdout)
+	default:
+		return errors.New(adminUsage)
+	}
+}
+
+func adminCreate(ctx context.Context, accounts *service.AdminAccountService, args []string, passwords *passwordReader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("admin create", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	rawName := fs.String("name", "", "username")
+	rawEmail := fs.String("email", "", "email address (required when creating)")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return errors.New(adminUsage)
+	}
+
+	name, err := domain.NewName(*rawName)
+	if err != nil {
+		return fmt.Errorf("invalid --name: %w", err)
+	}
+
+	existing, err := accounts.FindByName(ctx, name)
+	switch {
+	case err == nil:
+		if *rawEmail != "" && !strings.EqualFold(strings.TrimSpace(*rawEmail), existing.Email().String()) {
+			return fmt.Errorf("user %q already exists with a different email (%s)", name, existing.Email())
+		}
+		promoted, err := accounts.Promote(ctx, existing)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "promoted %s (%s) to admin\n", promoted.Username(), promoted.ID())
+		return nil
+	case !errors.Is(err, domain.ErrUserNotFound):
+		return err
+	}
+
+	email, err := domain.NewEmail(*rawEmail)
+	if err != nil {
+		return fmt.Errorf("invalid --email: %w", err)
+	}
+
+	password, err := passwords.read()
+	if err != nil {
+		return err
+	}
+
+	user, err := accounts.CreateAdmin(ctx, name, email, password)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "created admin %s (%s)\n", user.Username(), user.ID())
+	return nil
+}
+
+func adminResetPassword(ctx context.Context, accounts *service.AdminAccountService, args []string, passwords *passwordReader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("admin reset-password", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	rawName := fs.String("name", "", "username")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return errors.New(adminUsage)
+	}
+
+	name, err := domain.NewName(*rawName)
+	if err != nil {
+		return fmt.Errorf("invalid --name: %w", err)
+	}
+
+	// 存在しないユーザーに対してパスワードを入力させないよう、先に確認する。
+	if _, err := accounts.FindByName(ctx, name); err != nil {
+		return err
+	}
+
+	password, err := passwords.read()
+	if err != nil {
+		return err
+	}
+
+	user, err := accounts.ResetPassword(ctx, name, password)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "reset password for %s (%s); all sessions were revoked\n", user.Username(), user.ID())
+	return nil
+}
+
+func adminList(ctx context.Context, accounts *service.AdminAccountService, stdout io.Writer) error {
+	admins, err := accounts.ListAdmins(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tCREATED\tSTATUS")
+	for _, u := range admins {
+		status := "active"
+		if u.IsDisabled() {
+			status = "disabled"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", u.ID(), u.Username(), u.Email(), u.CreatedAt().Format(time.RFC3339), status)
+	}
+	return w.Flush()
+}
+
+// passwordReader は stdin の 1 行をパスワードとして読む。端末では入力が表示されるため、パイプで渡すことを推奨する。
+type passwordReader struct {
+	in     *bufio.Reader
+	prompt io.Writer
+}
+
+// newPasswordReader は prompt が nil ならプロンプトを表示しない。
+func newPasswordReader(in io.Reader, prompt io.Writer) *passwordReader {
+	return &passwordReader{in: bufio.NewReader(in), prompt: prompt}
+}
+
+func (r *passwordReader) read() (string, error) {
+	if r.prompt != nil {
+		fmt.Fprint(r.prompt, "Password: ")
+	}
+
+	line, err := r.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(password) == "" {
+		return "", errors.New("password must not be empty")
+	}
+	return password, nil
+}
+
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+	"backend/internal/service"
+)
+
+func TestRunAdminCommand_CreatePromoteAndList(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	accounts := service.NewAdminAccountService(users, memory.NewLoginSessionRepository(), domain.PasswordPolicy{}, domain.DefaultBcryptHasher(), nil)
+
+	var out bytes.Buffer
+	args := []string{"create", "--name", "root", "--email", "root@example.com"}
+	if err := runAdminCommand(ctx, accounts, args, newPasswordReader(strings.NewReader("s3cret\n"), nil), &out); err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	if !strings.Contains(out.String(), "created admin root") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	name, _ := domain.NewName("root")
+	root, err := users.FindByName(ctx, name)
+	if err != nil || root.Role() != domain.UserRoleAdmin || root.HashedPassword().Verify("s3cret") != nil {
+		t.Fatalf("expected admin with the given password, got %v (%v)", root.Role(), err)
+	}
+	if !root.IsEmailVerified() {
+		t.Fatalf("expected an address given on the command line to be treated as verified")
+	}
+
+	alice := createAdmin(t, repositories{users: users}, "alice", "secret")
+	if _, err := users.SetRole(ctx, alice.ID(), domain.UserRoleUser, alice.UpdatedAt()); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if _, err := users.SetDisabled(ctx, alice.ID(), true, alice.UpdatedAt()); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+
+	out.Reset()
+	if err := runAdminCommand(ctx, accounts, []string{"create", "--name", "alice"}, newPasswordReader(strings.NewReader(""), nil), &out); err != nil {
+		t.Fatalf("promote error: %v", err)
+	}
+	promoted, _ := users.FindByID(ctx, alice.ID())
+	if promoted.Role() != domain.UserRoleAdmin || promoted.IsDisabled() {
+		t.Fatalf("expected alice to be an enabled admin, got %s (disabled=%t)", promoted.Role(), promoted.IsDisabled())
+	}
+
+	if err := runAdminCommand(ctx, accounts, []string{"create", "--name", "alice", "--email", "other@example.com"}, newPasswordReader(strings.NewReader(""), nil), &out); err == nil {
+		t.Fatalf("expected an error when the email does not match")
+	}
+
+	out.Reset()
+	if err := runAdminCommand(ctx, accounts, []string{"list"}, newPasswordReader(strings.NewReader(""), nil), &out); err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 3 {
+		t.Fatalf("expected header and two admins, got %q", out.String())
+	}
+}
+
+func TestRunAdminCommand_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	accounts := service.NewAdminAccountService(users, memory.NewLoginSessionRepository(), domain.PasswordPolicy{}, domain.DefaultBcryptHasher(), nil)
+	admin := createAdmin(t, repositories{users: users}, "admin", "old")
+
+	var out bytes.Buffer
+	if err := runAdminCommand(ctx, accounts, []string{"reset-password", "--name", "admin"}, newPasswordReader(strings.NewReader("new-password\r\n"), nil), &out); err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	updated, _ := users.FindByID(ctx, admin.ID())
+	if updated.HashedPassword().Verify("new-password") != nil {
+		t.Fatalf("expected the new password to verify")
+	}
+
+	if err := runAdminCommand(ctx, accounts, []string{"reset-password", "--name", "nobody"}, newPasswordReader(strings.NewReader("x\n"), nil), &out); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := runAdminCommand(ctx, accounts, []string{"reset-password", "--name", "admin"}, newPasswordReader(strings.NewReader("\n"), nil), &out); err == nil {
+		t.Fatalf("expected an error for an empty password")
+	}
+	if err := runAdminCommand(ctx, accounts, []string{"rename"}, newPasswordReader(strings.NewReader(""), nil), &out); err == nil {
+		t.Fatalf("expected usage error for unknown command")
+	}
+}
@@ -3,40 +3,104 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"backend/internal/config"
+	"backend/internal/domain"
 	"backend/internal/handler"
 	infraDB "backend/internal/infra/db"
+	"backend/internal/mail"
+	"backend/internal/ratelimit"
 	"backend/internal/repository"
 	"backend/internal/service"
 )
 
+const usage = `usage: backend [command]
+
+commands:
+  serve      start the HTTP server (default)
+  migrate    apply, roll back or inspect database migrations
+  admin      create, promote and list administrators`
+
+// commands は受け付けるサブコマンド。打ち間違いでサーバーが起動しないよう、設定を読む前に確かめる。
+var commands = []string{"serve", "migrate", "admin"}
+
 func main() {
 	logger := log.New(os.Stdout, "", log.LstdFlags)
 
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	if !slices.Contains(commands, command) {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", command, usage)
+		os.Exit(2)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	pool, err := infraDB.NewConnection(ctx)
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("config: %v", err)
+	}
+
+	switch command {
+	case "migrate":
+		if err := runMigrate(ctx, cfg.Database, args, os.Stdout, logger); err != nil {
+			logger.Fatalf("migrate: %v", err)
+		}
+	case "admin":
+		if err := runAdmin(ctx, cfg, args, os.Stdin, os.Stdout, logger); err != nil {
+			logger.Fatalf("admin: %v", err)
+		}
+	default:
+		runServer(ctx, cfg, logger)
+	}
+}
+
+func runServer(ctx context.Context, cfg config.Config, logger *log.Logger) {
+	pool, err := infraDB.NewConnection(ctx, cfg.Database)
 	if err != nil {
 		logger.Fatalf("database connection failed: %v", err)
 	}
 	defer pool.Close()
 
-	server := newHTTPServer(pool, logger)
+	if cfg.AutoMigrate {
+		if err := applyMigrations(pool); err != nil {
+			logger.Fatalf("auto migrate failed: %v", err)
+		}
+		logger.Println("database migrations applied")
+	}
+
+	repos := newPostgresRepositories(pool)
+	server, err := newHTTPServer(cfg, repos, logger)
+	if err != nil {
+		logger.Fatalf("http server setup failed: %v", err)
+	}
+
+	reaper := service.NewSessionReaper(repos.sessions, service.DefaultSessionReapInterval, service.DefaultSessionReapBatchSize, logger)
+	reaperDone := make(chan struct{})
+	go func() {
+		defer close(reaperDone)
+		reaper.Run(ctx)
+	}()
 
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
 		<-ctx.Done()
 
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 		defer cancel()
 
 		if err := server.Shutdown(shutdownCtx); err != nil {
@@ -50,63 +114,222 @@ func main() {
 		logger.Fatalf("server stopped with error: %v", err)
 	}
 
+	// ListenAndServe は Shutdown の完了を待たずに戻るため、処理中のリクエストとワーカーの終了を待ってからプールを閉じる。
+	<-shutdownDone
+	<-reaperDone
 	logger.Println("server stopped")
 }
 
-func newHTTPServer(pool *pgxpool.Pool, logger *log.Logger) *http.Server {
+var (
+	// アカウントごとの枠は 1 つの接続元の枠より大きくし、1 か所からの失敗だけで他人のログインを止められないようにする。
+	loginAccountLimit         = ratelimit.Limit{Burst: 30, Per: time.Minute}
+	loginIPLimit              = ratelimit.Limit{Burst: 20, Per: time.Minute}
+	loginLockoutThreshold     = 10
+	loginLockoutDuration      = 15 * time.Minute
+	passwordChangeIPLimit     = ratelimit.Limit{Burst: 20, Per: time.Minute}
+	passwordResetIPLimit      = ratelimit.Limit{Burst: 5, Per: time.Minute}
+	resendVerificationIPLimit = ratelimit.Limit{Burst: 5, Per: time.Minute}
+	// トークンの確定はログインと同じく、推測を繰り返されないよう IP ごとに抑える。
+	passwordResetConfirmIPLimit = ratelimit.Limit{Burst: 20, Per: time.Minute}
+	emailVerifyIPLimit          = ratelimit.Limit{Burst: 20, Per: time.Minute}
+	// 教室や研究室では 1 つの NAT の後ろから何十人も同時に回答するため、IP ごとの枠は大量送信を止める程度にとどめる。
+	// 1 回答に 1 つ要るチャレンジが二重送信を防ぐ。チャレンジとセッションはやり直しの分だけ多めに許す。
+	// 保存の枠は save-result と sessions/finish で共有する。1 語ずつ届く回答は保存の枠に単語数を掛けた程度にする。
+	hueSaveIPLimit      = ratelimit.Limit{Burst: 60, Per: 10 * time.Minute}
+	hueChallengeIPLimit = ratelimit.Limit{Burst: 120, Per: 10 * time.Minute}
+	hueSessionIPLimit   = ratelimit.Limit{Burst: 120, Per: 10 * time.Minute}
+	hueAnswerIPLimit    = ratelimit.Limit{Burst: 3000, Per: 10 * time.Minute}
+)
+
+func applyMigrations(pool *pgxpool.Pool) error {
+	migrator, err := infraDB.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up()
+}
+
+// repositories はサービス層が依存するリポジトリ一式。テストではインメモリ実装に差し替える。
+type repositories struct {
+	users         service.UserRepository
+	sessions      service.LoginSessionRepository
+	hues          service.HueRepository
+	audits        service.AdminAuditRepository
+	resets        service.PasswordResetRepository
+	verifications service.EmailVerificationRepository
+	wordSets      service.WordSetRepository
+	palettes      service.PaletteRepository
+	hueSessions   service.HueSessionRepository
+}
+
+func newPostgresRepositories(pool *pgxpool.Pool) repositories {
+	return repositories{
+		users:         repository.NewUserRepository(pool),
+		sessions:      repository.NewLoginSessionRepository(pool),
+		hues:          repository.NewHueRepository(pool),
+		audits:        repository.NewAdminAuditRepository(pool),
+		resets:        repository.NewPasswordResetRepository(pool),
+		verifications: repository.NewEmailVerificationRepository(pool),
+		wordSets:      repository.NewWordSetRepository(pool),
+		palettes:      repository.NewPaletteRepository(pool),
+		hueSessions:   repository.NewHueSessionRepository(pool),
+	}
+}
+
+func newHTTPServer(cfg config.Config, repos repositories, logger *log.Logger) (*http.Server, error) {
+	h, err := newHTTPHandler(cfg, repos, logger)
+	if err != nil {
+		return nil, err
+	}
 	return &http.Server{
-		Addr:              serverAddr(),
-		Handler:           newHTTPHandler(pool, logger),
-		ReadHeaderTimeout: 5 * time.Second,
-		WriteTimeout:      15 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		Addr:              cfg.HTTP.Addr,
+		Handler:           h,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
 		ErrorLog:          logger,
-	}
+	}, nil
 }
 
-func newHTTPHandler(pool *pgxpool.Pool, logger *log.Logger) http.Handler {
-	userRepo := repository.NewUserRepository(pool)
-	sessionRepo := repository.NewLoginSessionRepository(pool)
-	hueRepo := repository.NewHueRepository(pool)
+func newHTTPHandler(cfg config.Config, repos repositories, logger *log.Logger) (http.Handler, error) {
+	cors, err := handler.NewCORSPolicy(cfg.CORS.AllowedOrigins, cfg.CORS.MaxAge)
+	if err != nil {
+		return nil, err
+	}
 
-	signInService := service.NewSignInService(userRepo, sessionRepo, logger)
-	loginService := service.NewLoginService(userRepo, sessionRepo, logger)
-	hueSaveService := service.NewHueSaveService(hueRepo, logger)
-	hueGetService := service.NewHueGetService(hueRepo, sessionRepo, userRepo, logger)
+	clientIPs, err := handler.NewClientIPResolver(cfg.Proxy.Trusted)
+	if err != nil {
+		return nil, err
+	}
 
-	mux := http.NewServeMux()
-	mux.Handle("/api/sign-in", withCORS(handler.NewSignInHandler(signInService)))
-	mux.Handle("/api/login", withCORS(handler.NewLoginHandler(loginService)))
-	mux.Handle("/api/hue-are-you/save-result", withCORS(handler.NewHueSaveHandler(hueSaveService)))
-	mux.Handle("/api/hue-are-you/get-data", withCORS(handler.NewHueGetHandler(hueGetService)))
+	rateStore := ratelimit.NewMemoryStore()
+	loginGuard := ratelimit.NewLoginGuard("login",
+		ratelimit.NewLimiter(rateStore, loginAccountLimit),
+		ratelimit.NewLockout(loginLockoutThreshold, loginLockoutDuration),
+	)
+	passwordChangeGuard := ratelimit.NewLoginGuard("password-change",
+		ratelimit.NewLimiter(rateStore, loginAccountLimit),
+		ratelimit.NewLockout(loginLockoutThreshold, loginLockoutDuration),
+	)
+	loginIPLimiter := ratelimit.NewLimiter(rateStore, loginIPLimit)
+	// RateLimitByIP はどれも "ip:" で始まるキーを使うため、エンドポイントごとにストアを分けて枠を共有しないようにする。
+	passwordChangeIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), passwordChangeIPLimit)
+	passwordResetIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), passwordResetIPLimit)
+	resendVerificationIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), resendVerificationIPLimit)
+	passwordResetConfirmIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), passwordResetConfirmIPLimit)
+	emailVerifyIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), emailVerifyIPLimit)
+	hueSaveIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), hueSaveIPLimit)
+	hueChallengeIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), hueChallengeIPLimit)
+	hueSessionIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), hueSessionIPLimit)
+	hueAnswerIPLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), hueAnswerIPLimit)
 
-	return mux
-}
+	passwordPolicy, err := cfg.Auth.PasswordPolicy()
+	if err != nil {
+		return nil, err
+	}
+	passwordHasher, err := cfg.Auth.PasswordHasher()
+	if err != nil {
+		return nil, err
+	}
+	wordOrderMode, err := cfg.Hue.WordOrderMode()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Hue.ChallengeSecret == "" {
+		logger.Printf("%s is not set; save-result challenges will not survive a restart or be shared between servers", config.EnvHueChallengeSecret)
+	}
+	challengeSigner, err := cfg.Hue.ChallengeSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	mailer := newMailer(cfg.Mail)
+	verificationPolicy := domain.NewEmailVerificationPolicy(cfg.Auth.RequireVerifiedEmailForLogin, cfg.Auth.RequireVerifiedEmailForAdmin)
+	emailVerificationService := service.NewEmailVerificationService(repos.users, repos.verifications, mailer, cfg.Auth.EmailVerifyTTL, cfg.Auth.EmailVerifyURL, logger)
+	signInService := service.NewSignInService(repos.users, repos.sessions, emailVerificationService, verificationPolicy, passwordPolicy, passwordHasher, cfg.Auth.SessionTTL, logger)
+	loginService := service.NewLoginService(repos.users, repos.sessions, loginGuard, verificationPolicy, passwordHasher, cfg.Auth.SessionTTL, logger)
+	hueSaveService := service.NewHueSaveService(repos.hues, repos.wordSets, repos.palettes, challengeSigner, logger)
+	hueGetService := service.NewHueGetService(repos.hues, repos.palettes, logger)
+	hueSessionService := service.NewHueSessionService(repos.hueSessions, repos.hues, repos.wordSets, repos.palettes, challengeSigner, wordOrderMode, logger)
+	authService := service.NewAuthService(repos.sessions, repos.users, logger)
+	sessionService := service.NewSessionService(repos.sessions, cfg.Auth.SessionTTL, logger)
+	userAdminService := service.NewUserAdminService(repos.users, repos.sessions, repos.audits, verificationPolicy, logger)
+	wordSetService := service.NewWordSetService(repos.wordSets, repos.audits, logger)
+	paletteService := service.NewPaletteService(repos.palettes, repos.audits, logger)
+	passwordService := service.NewPasswordService(repos.users, repos.sessions, repos.resets, mailer, passwordChangeGuard, passwordPolicy, passwordHasher, cfg.Auth.PasswordResetTTL, cfg.Auth.PasswordResetURL, logger)
 
-func serverAddr() string {
-	port := strings.TrimSpace(os.Getenv("PORT"))
-	if port == "" {
-		return ":8080"
+	auth := handler.NewAuthMiddleware(authService)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/sign-in", cors.Wrap(handler.NewSignInHandler(signInService)))
+	mux.Handle("/api/login", cors.Wrap(clientIPs.RateLimitByIP(loginIPLimiter, handler.NewLoginHandler(loginService, clientIPs))))
+	mux.Handle("/api/logout", cors.Wrap(auth.Require(handler.NewLogoutHandler(sessionService))))
+	mux.Handle("/api/logout-all", cors.Wrap(auth.Require(handler.NewLogoutAllHandler(sessionService))))
+	mux.Handle("/api/token/refresh", cors.Wrap(auth.Require(handler.NewRefreshHandler(sessionService))))
+	mux.Handle("/api/password/change", cors.Wrap(clientIPs.RateLimitByIP(passwordChangeIPLimiter, auth.Require(handler.NewPasswordChangeHandler(passwordService)))))
+	mux.Handle("/api/password/reset-request", cors.Wrap(clientIPs.RateLimitByIP(passwordResetIPLimiter, handler.NewPasswordResetRequestHandler(passwordService))))
+	mux.Handle("/api/password/reset-confirm", cors.Wrap(clientIPs.RateLimitByIP(passwordResetConfirmIPLimiter, handler.NewPasswordResetConfirmHandler(passwordService))))
+	mux.Handle("/api/email/verify", cors.Wrap(clientIPs.RateLimitByIP(emailVerifyIPLimiter, handler.NewEmailVerifyHandler(emailVerificationService))))
+	mux.Handle("/api/email/resend-verification", cors.Wrap(clientIPs.RateLimitByIP(resendVerificationIPLimiter, handler.NewResendVerificationHandler(emailVerificationService))))
+	mux.Handle("/api/hue-are-you/words", cors.Wrap(handler.NewActiveWordSetHandler(wordSetService)))
+	mux.Handle("/api/hue-are-you/palette", cors.Wrap(handler.NewPaletteHandler(paletteService)))
+	mux.Handle("/api/hue-are-you/challenge", cors.Wrap(clientIPs.RateLimitByIP(hueChallengeIPLimiter, handler.NewHueChallengeHandler(hueSaveService))))
+	mux.Handle("/api/hue-are-you/save-result", cors.Wrap(clientIPs.RateLimitByIP(hueSaveIPLimiter, auth.Optional(handler.NewHueSaveHandler(hueSaveService)))))
+	mux.Handle("/api/hue-are-you/sessions/start", cors.Wrap(clientIPs.RateLimitByIP(hueSessionIPLimiter, auth.Optional(handler.NewHueSessionStartHandler(hueSessionService)))))
+	mux.Handle("/api/hue-are-you/sessions/answer", cors.Wrap(clientIPs.RateLimitByIP(hueAnswerIPLimiter, handler.NewHueSessionAnswerHandler(hueSessionService))))
+	mux.Handle("/api/hue-are-you/sessions/finish", cors.Wrap(clientIPs.RateLimitByIP(hueSaveIPLimiter, handler.NewHueSessionFinishHandler(hueSessionService))))
+	mux.Handle("/api/hue-are-you/sessions/funnel", cors.Wrap(auth.Require(handler.NewHueSessionFunnelHandler(hueSessionService), domain.UserRoleAdmin)))
+	mux.Handle("/api/hue-are-you/my-results", cors.Wrap(auth.Require(handler.NewHueMyResultsHandler(hueGetService))))
+	mux.Handle("/api/hue-are-you/get-data", cors.Wrap(auth.Require(handler.NewHueGetHandler(hueGetService), domain.UserRoleAdmin)))
+	mux.Handle("/api/hue-are-you/export", cors.Wrap(auth.Require(handler.NewHueExportHandler(hueGetService), domain.UserRoleAdmin)))
+	mux.Handle("/api/hue-are-you/stats", cors.Wrap(auth.Require(handler.NewHueStatsHandler(hueGetService), domain.UserRoleAdmin)))
+	mux.Handle("/api/admin/users", cors.Wrap(auth.Require(handler.NewAdminUserListHandler(userAdminService), domain.UserRoleAdmin)))
+	for path, action := range map[string]domain.AdminAction{
+		"/api/admin/users/role":            domain.AdminActionChangeRole,
+		"/api/admin/users/disable":         domain.AdminActionDisableUser,
+		"/api/admin/users/enable":          domain.AdminActionEnableUser,
+		"/api/admin/users/revoke-sessions": domain.AdminActionRevokeSessions,
+		"/api/admin/users/delete":          domain.AdminActionDeleteUser,
+	} {
+		mux.Handle(path, cors.Wrap(auth.Require(handler.NewAdminUserActionHandler(userAdminService, action), domain.UserRoleAdmin)))
 	}
-	if strings.HasPrefix(port, ":") {
-		return port
+	mux.Handle("/api/admin/word-sets", cors.Wrap(auth.Require(handler.NewAdminWordSetListHandler(wordSetService), domain.UserRoleAdmin)))
+	for path, action := range map[string]domain.AdminAction{
+		"/api/admin/word-sets/create":   domain.AdminActionCreateWordSet,
+		"/api/admin/word-sets/update":   domain.AdminActionUpdateWordSet,
+		"/api/admin/word-sets/activate": domain.AdminActionActivateWordSet,
+		"/api/admin/word-sets/delete":   domain.AdminActionDeleteWordSet,
+	} {
+		mux.Handle(path, cors.Wrap(auth.Require(handler.NewAdminWordSetActionHandler(wordSetService, action), domain.UserRoleAdmin)))
 	}
-	return ":" + port
-}
+	mux.Handle("/api/admin/palettes", cors.Wrap(auth.Require(handler.NewAdminPaletteListHandler(paletteService), domain.UserRoleAdmin)))
+	for path, action := range map[string]domain.AdminAction{
+		"/api/admin/palettes/create":   domain.AdminActionCreatePalette,
+		"/api/admin/palettes/activate": domain.AdminActionActivatePalette,
+	} {
+		mux.Handle(path, cors.Wrap(auth.Require(handler.NewAdminPaletteActionHandler(paletteService, action), domain.UserRoleAdmin)))
+	}
+	mux.Handle("/api/admin/audit-log", cors.Wrap(auth.Require(handler.NewAdminAuditLogHandler(userAdminService), domain.UserRoleAdmin)))
 
-func withCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-
-		// Preflight
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+	return mux, nil
+}
 
-		next.ServeHTTP(w, r)
-	})
+// newMailer は設定に応じて SMTP か、ファイル (省略時は標準出力) への書き出しを返す。
+func newMailer(cfg config.Mail) service.Mailer {
+	switch {
+	case cfg.Transport == config.MailTransportSMTP:
+		return mail.NewSMTPSender(mail.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.From,
+		})
+	case cfg.File != "":
+		return mail.NewAppendFileSender(cfg.File, cfg.From)
+	default:
+		return mail.NewFileSender(os.Stdout, cfg.From)
+	}
 }
@@ -0,0 +1,712 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/domain"
+	"backend/internal/ratelimit"
+	"backend/internal/repository/memory"
+	"backend/pkg/api"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHTTPHandler_InProcess(t *testing.T) {
+	repos := newMemoryRepositories()
+	server := httptest.NewServer(newTestHandler(t, testConfig(), repos))
+	defer server.Close()
+
+	createAdmin(t, repos, "admin", "admin-secret")
+	seedWordSet(t, repos, "夜", "海")
+	seedPalette(t, repos)
+
+	var signIn api.SignInResponse
+	status := doJSON(t, server, http.MethodPost, "/api/sign-in", "", `{"name":"alice","email":"alice@example.com","password":"alice-passphrase"}`, &signIn)
+	if status != http.StatusOK {
+		t.Fatalf("sign-in: expected 200, got %d", status)
+	}
+	aliceBearer := signIn.UserID + "." + signIn.Token
+
+	if status := saveResult(t, server, "", `{"name":"alice","choice":{"夜":"黒"}}`); status != http.StatusCreated {
+		t.Fatalf("save-result: expected 201, got %d", status)
+	}
+
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/get-data", aliceBearer, `{"limit":10}`, nil); status != http.StatusForbidden {
+		t.Fatalf("get-data as user: expected 403, got %d", status)
+	}
+
+	var login api.LoginResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"admin","password":"admin-secret"}`, &login); status != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d", status)
+	}
+	adminBearer := login.UserID + "." + login.Token
+
+	var data api.GetDataResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/get-data", adminBearer, `{"limit":10}`, &data); status != http.StatusOK {
+		t.Fatalf("get-data as admin: expected 200, got %d", status)
+	}
+	if len(data.Records) != 1 || data.Records[0].Name != "alice" {
+		t.Fatalf("unexpected records: %+v", data.Records)
+	}
+
+	var refreshed api.RefreshResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/token/refresh", adminBearer, "", &refreshed); status != http.StatusOK {
+		t.Fatalf("refresh: expected 200, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/get-data", adminBearer, `{"limit":10}`, nil); status != http.StatusUnauthorized {
+		t.Fatalf("rotated token: expected 401, got %d", status)
+	}
+	adminBearer = refreshed.UserID + "." + refreshed.Token
+
+	if status := doJSON(t, server, http.MethodPost, "/api/logout", adminBearer, "", nil); status != http.StatusNoContent {
+		t.Fatalf("logout: expected 204, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/get-data", adminBearer, `{"limit":10}`, nil); status != http.StatusUnauthorized {
+		t.Fatalf("after logout: expected 401, got %d", status)
+	}
+}
+
+func TestHTTPHandler_AdminUserManagement(t *testing.T) {
+	repos := newMemoryRepositories()
+	server := httptest.NewServer(newTestHandler(t, testConfig(), repos))
+	defer server.Close()
+
+	createAdmin(t, repos, "admin", "admin-secret")
+
+	var login api.LoginResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"admin","password":"admin-secret"}`, &login); status != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d", status)
+	}
+	adminBearer := login.UserID + "." + login.Token
+
+	var signIn api.SignInResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/sign-in", "", `{"name":"alice","email":"alice@example.com","password":"alice-passphrase"}`, &signIn); status != http.StatusOK {
+		t.Fatalf("sign-in: expected 200, got %d", status)
+	}
+	aliceBearer := signIn.UserID + "." + signIn.Token
+	target := `{"user_id":"` + signIn.UserID + `"}`
+
+	var list api.ListUsersResponse
+	if status := doJSON(t, server, http.MethodGet, "/api/admin/users?q=ALI&role=user", adminBearer, "", &list); status != http.StatusOK {
+		t.Fatalf("list users: expected 200, got %d", status)
+	}
+	if list.Total != 1 || list.Users[0].ID != signIn.UserID {
+		t.Fatalf("unexpected users: %+v", list)
+	}
+
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/role", aliceBearer, `{"user_id":"`+signIn.UserID+`","role":"admin"}`, nil); status != http.StatusForbidden {
+		t.Fatalf("role change by user: expected 403, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/role", adminBearer, `{"user_id":"`+signIn.UserID+`","role":"admin"}`, nil); status != http.StatusOK {
+		t.Fatalf("role change: expected 200, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/get-data", aliceBearer, `{}`, nil); status != http.StatusOK {
+		t.Fatalf("get-data after promotion: expected 200, got %d", status)
+	}
+
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/disable", adminBearer, `{"user_id":"`+login.UserID+`"}`, nil); status != http.StatusConflict {
+		t.Fatalf("self disable: expected 409, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/disable", adminBearer, target, nil); status != http.StatusOK {
+		t.Fatalf("disable: expected 200, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/token/refresh", aliceBearer, "", nil); status != http.StatusUnauthorized {
+		t.Fatalf("disabled user's session: expected 401, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"alice","password":"alice-passphrase"}`, nil); status != http.StatusForbidden {
+		t.Fatalf("disabled login: expected 403, got %d", status)
+	}
+
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/enable", adminBearer, target, nil); status != http.StatusOK {
+		t.Fatalf("enable: expected 200, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"alice","password":"alice-passphrase"}`, nil); status != http.StatusOK {
+		t.Fatalf("login after enable: expected 200, got %d", status)
+	}
+
+	var revoked api.RevokeSessionsResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/revoke-sessions", adminBearer, target, &revoked); status != http.StatusOK || revoked.Revoked != 1 {
+		t.Fatalf("revoke sessions: expected 200 with 1 session, got %d (%d)", status, revoked.Revoked)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/delete", adminBearer, target, nil); status != http.StatusNoContent {
+		t.Fatalf("delete: expected 204, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/users/delete", adminBearer, target, nil); status != http.StatusNotFound {
+		t.Fatalf("delete twice: expected 404, got %d", status)
+	}
+
+	var audit api.AuditLogResponse
+	if status := doJSON(t, server, http.MethodGet, "/api/admin/audit-log", adminBearer, "", &audit); status != http.StatusOK {
+		t.Fatalf("audit log: expected 200, got %d", status)
+	}
+	var actions []string
+	for _, entry := range audit.Entries {
+		actions = append(actions, entry.Action)
+	}
+	if strings.Join(actions, ",") != "delete_user,revoke_sessions,enable_user,disable_user,change_role" {
+		t.Fatalf("unexpected audit log: %v", actions)
+	}
+}
+
+func doJSON(t *testing.T, server *httptest.Server, method, path, bearer, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+
+	if out != nil && res.StatusCode < http.StatusBadRequest {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decode error: %v", method, path, err)
+		}
+	}
+	return res.StatusCode
+}
+
+// saveResult はチャレンジを発行してから body に添えて save-result へ送る。key が空なら Idempotency-Key を付けない。
+func saveResult(t *testing.T, server *httptest.Server, key, body string) int {
+	t.Helper()
+	status, _ := postResult(t, server, key, withChallenge(t, server, body))
+	return status
+}
+
+// withChallenge は body の JSON に新しく発行したチャレンジを加える。
+func withChallenge(t *testing.T, server *httptest.Server, body string) string {
+	t.Helper()
+	var challenge api.HueChallengeResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/challenge", "", "", &challenge); status != http.StatusCreated {
+		t.Fatalf("challenge: expected 201, got %d", status)
+	}
+	return strings.Replace(body, "{", fmt.Sprintf(`{"challenge":%q,`, challenge.Challenge), 1)
+}
+
+func postResult(t *testing.T, server *httptest.Server, key, body string) (int, http.Header) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/hue-are-you/save-result", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("save-result: %v", err)
+	}
+	res.Body.Close()
+	return res.StatusCode, res.Header
+}
+
+func TestHTTPHandler_SaveResultGuard(t *testing.T) {
+	repos := newMemoryRepositories()
+	server := httptest.NewServer(newTestHandler(t, testConfig(), repos))
+	defer server.Close()
+
+	seedWordSet(t, repos, "夜", "海")
+	seedPalette(t, repos)
+
+	if status, _ := postResult(t, server, "", `{"name":"alice","choice":{"夜":"黒"}}`); status != http.StatusBadRequest {
+		t.Fatalf("save-result without a challenge: expected 400, got %d", status)
+	}
+
+	body := withChallenge(t, server, `{"name":"alice","choice":{"夜":"黒"}}`)
+	if status, header := postResult(t, server, "retry-1", body); status != http.StatusCreated || header.Get("Idempotent-Replayed") != "" {
+		t.Fatalf("save-result: expected 201, got %d", status)
+	}
+	// 応答を受け取れなかったクライアントの再送。
+	if status, header := postResult(t, server, "retry-1", body); status != http.StatusOK || header.Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("save-result retry: expected 200 replayed, got %d %v", status, header)
+	}
+	if status, _ := postResult(t, server, "retry-2", body); status != http.StatusConflict {
+		t.Fatalf("save-result with a used challenge: expected 409, got %d", status)
+	}
+
+	// 1 つの IP から送れる回数には上限がある。ここまでの 4 回も数える。
+	for i := 4; i < hueSaveIPLimit.Burst; i++ {
+		if status := saveResult(t, server, "", `{"name":"alice","choice":{"海":"青"}}`); status != http.StatusCreated {
+			t.Fatalf("save-result %d: expected 201, got %d", i+1, status)
+		}
+	}
+	if status := saveResult(t, server, "", `{"name":"alice","choice":{"海":"青"}}`); status != http.StatusTooManyRequests {
+		t.Fatalf("save-result over the limit: expected 429, got %d", status)
+	}
+	// セッションの終了も同じ枠で数える。
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/sessions/finish", "", `{}`, nil); status != http.StatusTooManyRequests {
+		t.Fatalf("finish over the limit: expected 429, got %d", status)
+	}
+}
+
+func TestHTTPHandler_WordSets(t *testing.T) {
+	repos := newMemoryRepositories()
+	server := httptest.NewServer(newTestHandler(t, testConfig(), repos))
+	defer server.Close()
+
+	standard := seedWordSet(t, repos, "夜", "海")
+	seedPalette(t, repos)
+	createAdmin(t, repos, "admin", "admin-secret")
+
+	var login api.LoginResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"admin","password":"admin-secret"}`, &login); status != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d", status)
+	}
+	adminBearer := login.UserID + "." + login.Token
+
+	var active api.WordSetPayload
+	if status := doJSON(t, server, http.MethodGet, "/api/hue-are-you/words", "", "", &active); status != http.StatusOK || active.ID != standard.ID().String() {
+		t.Fatalf("words: expected the seeded set, got %d %+v", status, active)
+	}
+
+	if status := saveResult(t, server, "", `{"name":"alice","choice":{"森":"緑"}}`); status != http.StatusBadRequest {
+		t.Fatalf("save-result with unknown word: expected 400, got %d", status)
+	}
+	if status := saveResult(t, server, "", `{"name":"alice","choice":{"夜":"黒"}}`); status != http.StatusCreated {
+		t.Fatalf("save-result: expected 201, got %d", status)
+	}
+
+	var created api.WordSetPayload
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/word-sets/create", adminBearer, `{"name":"自然","words":["森","川"],"activate":true}`, &created); status != http.StatusCreated || !created.Active {
+		t.Fatalf("create: expected 201 with an active set, got %d %+v", status, created)
+	}
+	if status := doJSON(t, server, http.MethodGet, "/api/hue-are-you/words", "", "", &active); status != http.StatusOK || active.ID != created.ID {
+		t.Fatalf("words: expected the new set, got %d %+v", status, active)
+	}
+
+	// 切り替え前に読み込んだページからの回答も、名乗ったセットで確かめて受け付ける。
+	body := fmt.Sprintf(`{"name":"bob","choice":{"海":"青"},"word_set_id":%q}`, standard.ID())
+	if status := saveResult(t, server, "", body); status != http.StatusCreated {
+		t.Fatalf("save-result for previous set: expected 201, got %d", status)
+	}
+
+	update := fmt.Sprintf(`{"id":%q,"name":"標準","words":["夜"]}`, standard.ID())
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/word-sets/update", adminBearer, update, nil); status != http.StatusConflict {
+		t.Fatalf("update answered set: expected 409, got %d", status)
+	}
+	remove := fmt.Sprintf(`{"id":%q}`, created.ID)
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/word-sets/delete", adminBearer, remove, nil); status != http.StatusConflict {
+		t.Fatalf("delete active set: expected 409, got %d", status)
+	}
+
+	var list api.ListWordSetsResponse
+	if status := doJSON(t, server, http.MethodGet, "/api/admin/word-sets", adminBearer, "", &list); status != http.StatusOK || len(list.WordSets) != 2 {
+		t.Fatalf("list: expected 2 sets, got %d %+v", status, list)
+	}
+	if status := doJSON(t, server, http.MethodGet, "/api/admin/word-sets", "", "", nil); status != http.StatusUnauthorized {
+		t.Fatalf("list without session: expected 401, got %d", status)
+	}
+}
+
+func TestHTTPHandler_Palettes(t *testing.T) {
+	repos := newMemoryRepositories()
+	server := httptest.NewServer(newTestHandler(t, testConfig(), repos))
+	defer server.Close()
+
+	seedWordSet(t, repos, "夜", "海")
+	seedPalette(t, repos)
+	createAdmin(t, repos, "admin", "admin-secret")
+
+	var login api.LoginResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"admin","password":"admin-secret"}`, &login); status != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d", status)
+	}
+	adminBearer := login.UserID + "." + login.Token
+
+	var palette api.PalettePayload
+	if status := doJSON(t, server, http.MethodGet, "/api/hue-are-you/palette", "", "", &palette); status != http.StatusOK || palette.Version != 1 || len(palette.Colors) != 3 {
+		t.Fatalf("palette: expected version 1, got %d %+v", status, palette)
+	}
+
+	if status := saveResult(t, server, "", `{"name":"alice","choice":{"夜":"金"}}`); status != http.StatusBadRequest {
+		t.Fatalf("save-result with unknown color: expected 400, got %d", status)
+	}
+	if status := saveResult(t, server, "", `{"name":"alice","choice":{"夜":"黒"}}`); status != http.StatusCreated {
+		t.Fatalf("save-result: expected 201, got %d", status)
+	}
+
+	create := `{"colors":[{"id":"black","names":{"ja":"黒","en":"Black"},"rgb":"#444444"},{"id":"gold","names":{"ja":"金","en":"Gold"},"rgb":"#d4af37"}],"activate":true}`
+	var created api.PalettePayload
+	if status := doJSON(t, server, http.MethodPost, "/api/admin/palettes/create", adminBearer, create, &created); status != http.StatusCreated || created.Version != 2 || !created.Active {
+		t.Fatalf("create: expected an active version 2, got %d %+v", status, created)
+	}
+
+	// 切り替え前に読み込んだページからの回答も、名乗った版の色で受け付ける。
+	if status := saveResult(t, server, "", `{"name":"bob","choice":{"海":"青"},"palette_version":1}`); status != http.StatusCreated {
+		t.Fatalf("save-result for previous palette: expected 201, got %d", status)
+	}
+	if status := saveResult(t, server, "", `{"name":"carol","choice":{"夜":"Gold"}}`); status != http.StatusCreated {
+		t.Fatalf("save-result with an English name: expected 201, got %d", status)
+	}
+
+	var stats api.HueStatsResponse
+	if status := doJSON(t, server, http.MethodGet, "/api/hue-are-you/stats?palette=1", adminBearer, "", &stats); status != http.StatusOK || stats.PaletteVersion != 1 || len(stats.Words) != 2 {
+		t.Fatalf("stats for version 1: got %d %+v", status, stats)
+	}
+	if status := doJSON(t, server, http.MethodGet, "/api/hue-are-you/stats", adminBearer, "", &stats); status != http.StatusOK || stats.PaletteVersion != 2 || len(stats.Words) != 1 || stats.Words[0].Mode != "gold" {
+		t.Fatalf("stats for the active version: got %d %+v", status, stats)
+	}
+
+	var data api.GetDataResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/get-data", adminBearer, `{"filter":{"choices":{"夜":"金"}}}`, &data); status != http.StatusOK || len(data.Records) != 1 || data.Records[0].PaletteVersion != 2 {
+		t.Fatalf("get-data by color name: got %d %+v", status, data)
+	}
+
+	var list api.ListPalettesResponse
+	if status := doJSON(t, server, http.MethodGet, "/api/admin/palettes", adminBearer, "", &list); status != http.StatusOK || len(list.Palettes) != 2 || list.Palettes[0].Active {
+		t.Fatalf("list: expected 2 versions with only the latest active, got %d %+v", status, list)
+	}
+}
+
+func TestHTTPHandler_HueSessions(t *testing.T) {
+	repos := newMemoryRepositories()
+	server := httptest.NewServer(newTestHandler(t, testConfig(), repos))
+	defer server.Close()
+
+	seedWordSet(t, repos, "夜", "海")
+	seedPalette(t, repos)
+	createAdmin(t, repos, "admin", "admin-secret")
+
+	var login api.LoginResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"admin","password":"admin-secret"}`, &login); status != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d", status)
+	}
+	adminBearer := login.UserID + "." + login.Token
+
+	var session api.HueSessionResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/sessions/start", "", "", &session); status != http.StatusCreated || len(session.Words) != 2 || session.PaletteVersion != 1 {
+		t.Fatalf("start: expected 201 with 2 words, got %d %+v", status, session)
+	}
+	answer := func(word, color string, latency int) int {
+		body := fmt.Sprintf(`{"session_id":%q,"word":%q,"color":%q,"latency_ms":%d}`, session.SessionID, word, color, latency)
+		return doJSON(t, server, http.MethodPost, "/api/hue-are-you/sessions/answer", "", body, nil)
+	}
+	if status := answer("夜", "黒", 900); status != http.StatusOK {
+		t.Fatalf("answer: expected 200, got %d", status)
+	}
+	if status := answer("海", "金", 900); status != http.StatusBadRequest {
+		t.Fatalf("answer with unknown color: expected 400, got %d", status)
+	}
+	if status := answer("海", "blue", 1100); status != http.StatusOK {
+		t.Fatalf("answer: expected 200, got %d", status)
+	}
+
+	// 途中でやめたセッション。
+	var dropped api.HueSessionResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/sessions/start", "", "", &dropped); status != http.StatusCreated {
+		t.Fatalf("start: expected 201, got %d", status)
+	}
+
+	var finished api.FinishHueSessionResponse
+	body := fmt.Sprintf(`{"session_id":%q,"name":"alice"}`, session.SessionID)
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/sessions/finish", "", body, nil); status != http.StatusBadRequest {
+		t.Fatalf("finish without a challenge: expected 400, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/sessions/finish", "", withChallenge(t, server, body), &finished); status != http.StatusCreated || finished.RecordID == "" {
+		t.Fatalf("finish: expected 201 with a record id, got %d %+v", status, finished)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/sessions/finish", "", withChallenge(t, server, body), nil); status != http.StatusConflict {
+		t.Fatalf("second finish: expected 409, got %d", status)
+	}
+	if status := answer("夜", "白", 500); status != http.StatusConflict {
+		t.Fatalf("answer after finish: expected 409, got %d", status)
+	}
+
+	var data api.GetDataResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/hue-are-you/get-data", adminBearer, `{}`, &data); status != http.StatusOK || len(data.Records) != 1 || data.Records[0].Choice["海"] != "blue" {
+		t.Fatalf("get-data: expected the finished session as a record, got %d %+v", status, data)
+	}
+
+	if status := doJSON(t, server, http.MethodGet, "/api/hue-are-you/sessions/funnel", "", "", nil); status != http.StatusUnauthorized {
+		t.Fatalf("funnel without auth: expected 401, got %d", status)
+	}
+	var funnel api.HueSessionFunnelResponse
+	if status := doJSON(t, server, http.MethodGet, "/api/hue-are-you/sessions/funnel", adminBearer, "", &funnel); status != http.StatusOK {
+		t.Fatalf("funnel: expected 200, got %d", status)
+	}
+	if funnel.Started != 2 || funnel.Finished != 1 || funnel.InProgress != 1 || len(funnel.Reached) != 2 || funnel.Reached[0] != 1 {
+		t.Fatalf("unexpected funnel: %+v", funnel)
+	}
+}
+
+func TestHTTPHandler_PasswordChangeAndReset(t *testing.T) {
+	repos := newMemoryRepositories()
+	cfg := testConfig()
+	cfg.Mail.File = filepath.Join(t.TempDir(), "mail.log")
+	cfg.Auth.PasswordResetURL = "https://example.com/reset-password"
+	server := httptest.NewServer(newTestHandler(t, cfg, repos))
+	defer server.Close()
+
+	if status := doJSON(t, server, http.MethodPost, "/api/sign-in", "", `{"name":"alice","email":"alice@example.com","password":"Password123"}`, nil); status != http.StatusBadRequest {
+		t.Fatalf("sign-in with a common password: expected 400, got %d", status)
+	}
+
+	var signIn api.SignInResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/sign-in", "", `{"name":"alice","email":"alice@example.com","password":"alice-passphrase"}`, &signIn); status != http.StatusOK {
+		t.Fatalf("sign-in: expected 200, got %d", status)
+	}
+	bearer := signIn.UserID + "." + signIn.Token
+
+	if status := doJSON(t, server, http.MethodPost, "/api/password/change", bearer, `{"old_password":"alice-passphrase","new_password":"short"}`, nil); status != http.StatusBadRequest {
+		t.Fatalf("change to a short password: expected 400, got %d", status)
+	}
+
+	if status := doJSON(t, server, http.MethodPost, "/api/password/change", bearer, `{"old_password":"wrong","new_password":"changed-passphrase"}`, nil); status != http.StatusForbidden {
+		t.Fatalf("change with wrong password: expected 403, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/password/change", bearer, `{"old_password":"alice-passphrase","new_password":"changed-passphrase"}`, nil); status != http.StatusNoContent {
+		t.Fatalf("change: expected 204, got %d", status)
+	}
+
+	if status := doJSON(t, server, http.MethodPost, "/api/password/reset-request", "", `{"email":"nobody@example.com"}`, nil); status != http.StatusAccepted {
+		t.Fatalf("reset-request for unknown email: expected 202, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/password/reset-request", "", `{"email":"alice@example.com"}`, nil); status != http.StatusAccepted {
+		t.Fatalf("reset-request: expected 202, got %d", status)
+	}
+
+	sent, err := os.ReadFile(cfg.Mail.File)
+	if err != nil {
+		t.Fatalf("read mail file: %v", err)
+	}
+	_, token, ok := strings.Cut(string(sent), "reset-password?token=")
+	if !ok || strings.Count(string(sent), "reset-password?token=") != 1 {
+		t.Fatalf("expected exactly one reset mail, got:\n%s", sent)
+	}
+	token, _, _ = strings.Cut(token, "\n")
+	token, err = url.QueryUnescape(token)
+	if err != nil {
+		t.Fatalf("unescape token: %v", err)
+	}
+
+	body := `{"token":"` + token + `","new_password":"reset-passphrase"}`
+	if status := doJSON(t, server, http.MethodPost, "/api/password/reset-confirm", "", body, nil); status != http.StatusNoContent {
+		t.Fatalf("reset-confirm: expected 204, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/password/reset-confirm", "", body, nil); status != http.StatusBadRequest {
+		t.Fatalf("reused token: expected 400, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/token/refresh", bearer, "", nil); status != http.StatusUnauthorized {
+		t.Fatalf("session after reset: expected 401, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"alice","password":"reset-passphrase"}`, nil); status != http.StatusOK {
+		t.Fatalf("login with reset password: expected 200, got %d", status)
+	}
+}
+
+func TestHTTPHandler_TokenEndpointsAreRateLimited(t *testing.T) {
+	server := httptest.NewServer(newTestHandler(t, testConfig(), newMemoryRepositories()))
+	defer server.Close()
+
+	// 推測したトークンを繰り返し送られないよう、確定の窓口は IP ごとに回数を抑える。
+	cases := []struct {
+		path  string
+		body  string
+		limit ratelimit.Limit
+	}{
+		{"/api/password/reset-confirm", `{"token":"guess","new_password":"reset-passphrase"}`, passwordResetConfirmIPLimit},
+		{"/api/email/verify", `{"token":"guess"}`, emailVerifyIPLimit},
+	}
+	for _, tc := range cases {
+		for i := range tc.limit.Burst {
+			if status := doJSON(t, server, http.MethodPost, tc.path, "", tc.body, nil); status != http.StatusBadRequest {
+				t.Fatalf("%s %d: expected 400, got %d", tc.path, i+1, status)
+			}
+		}
+		if status := doJSON(t, server, http.MethodPost, tc.path, "", tc.body, nil); status != http.StatusTooManyRequests {
+			t.Fatalf("%s over the limit: expected 429, got %d", tc.path, status)
+		}
+	}
+}
+
+func TestHTTPHandler_EmailVerificationRequiredForLogin(t *testing.T) {
+	repos := newMemoryRepositories()
+	cfg := testConfig()
+	cfg.Mail.File = filepath.Join(t.TempDir(), "mail.log")
+	cfg.Auth.EmailVerifyURL = "https://example.com/verify-email"
+	cfg.Auth.RequireVerifiedEmailForLogin = true
+	server := httptest.NewServer(newTestHandler(t, cfg, repos))
+	defer server.Close()
+
+	var pending api.SignInPendingResponse
+	if status := doJSON(t, server, http.MethodPost, "/api/sign-in", "", `{"name":"alice","email":"alice@example.com","password":"alice-passphrase"}`, &pending); status != http.StatusAccepted || !pending.VerificationRequired {
+		t.Fatalf("sign-in: expected 202 with verification_required, got %d (%+v)", status, pending)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"alice","password":"alice-passphrase"}`, nil); status != http.StatusForbidden {
+		t.Fatalf("login before verification: expected 403, got %d", status)
+	}
+
+	sent, err := os.ReadFile(cfg.Mail.File)
+	if err != nil {
+		t.Fatalf("read mail file: %v", err)
+	}
+	_, token, ok := strings.Cut(string(sent), "verify-email?token=")
+	if !ok {
+		t.Fatalf("expected a verification mail, got:\n%s", sent)
+	}
+	token, _, _ = strings.Cut(token, "\n")
+	token, err = url.QueryUnescape(token)
+	if err != nil {
+		t.Fatalf("unescape token: %v", err)
+	}
+
+	if status := doJSON(t, server, http.MethodPost, "/api/email/verify", "", `{"token":"`+token+`"}`, nil); status != http.StatusNoContent {
+		t.Fatalf("verify: expected 204, got %d", status)
+	}
+	if status := doJSON(t, server, http.MethodPost, "/api/login", "", `{"name":"alice","password":"alice-passphrase"}`, nil); status != http.StatusOK {
+		t.Fatalf("login after verification: expected 200, got %d", status)
+	}
+}
+
+func createAdmin(t *testing.T, repos repositories, name, password string) domain.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash error: %v", err)
+	}
+	hashed, _ := domain.NewHashedPassword(string(hash))
+	n, _ := domain.NewName(name)
+	email, _ := domain.NewEmail(name + "@example.com")
+	user, err := domain.NewUser(n, email, hashed, domain.UserRoleAdmin, time.Now())
+	if err != nil {
+		t.Fatalf("user error: %v", err)
+	}
+	if err := repos.users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	return user
+}
+
+func TestHTTPHandler_CORSPreflight(t *testing.T) {
+	cfg := testConfig()
+	cfg.CORS.AllowedOrigins = []string{"https://www.ahaha-craft.org", "https://*.ahaha-craft.org"}
+	server := httptest.NewServer(newTestHandler(t, cfg, newMemoryRepositories()))
+	defer server.Close()
+
+	cases := []struct {
+		path   string
+		origin string
+		want   string
+	}{
+		{"/api/login", "https://www.ahaha-craft.org", "https://www.ahaha-craft.org"},
+		{"/api/hue-are-you/get-data", "https://staging.ahaha-craft.org", "https://staging.ahaha-craft.org"},
+		{"/api/login", "http://localhost:3000", ""},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(http.MethodOptions, server.URL+tc.path, nil)
+		req.Header.Set("Origin", tc.origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "authorization")
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("preflight error: %v", err)
+		}
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusNoContent {
+			t.Fatalf("%s from %s: expected 204, got %d", tc.path, tc.origin, res.StatusCode)
+		}
+		if got := res.Header.Get("Access-Control-Allow-Origin"); got != tc.want {
+			t.Fatalf("%s from %s: expected allow-origin %q, got %q", tc.path, tc.origin, tc.want, got)
+		}
+		if tc.want != "" && res.Header.Get("Access-Control-Allow-Methods") != "POST, OPTIONS" {
+			t.Fatalf("%s: expected handler methods, got %q", tc.path, res.Header.Get("Access-Control-Allow-Methods"))
+		}
+	}
+}
+
+// newMemoryRepositories はインメモリのリポジトリ一式を返す。
+func newMemoryRepositories() repositories {
+	hues := memory.NewHueRepository()
+	hueSessions := memory.NewHueSessionRepository(hues)
+	users := memory.NewUserRepository()
+	return repositories{
+		users:         users,
+		sessions:      memory.NewLoginSessionRepository(),
+		hues:          hues,
+		audits:        memory.NewAdminAuditRepository(),
+		resets:        memory.NewPasswordResetRepository(users),
+		verifications: memory.NewEmailVerificationRepository(),
+		wordSets:      memory.NewWordSetRepository(hues, hueSessions),
+		palettes:      memory.NewPaletteRepository(),
+		hueSessions:   hueSessions,
+	}
+}
+
+// seedPalette は黒・青・緑の版 1 を有効なパレットとして登録する。
+func seedPalette(t *testing.T, repos repositories) domain.Palette {
+	t.Helper()
+	var colors []domain.PaletteColor
+	for _, c := range []struct{ id, ja, hex string }{{"black", "黒", "#444444"}, {"blue", "青", "#4f86e2"}, {"green", "緑", "#4cad68"}} {
+		rgb, _ := domain.ParseRGB(c.hex)
+		color, err := domain.NewPaletteColor(c.id, map[string]string{"ja": c.ja}, rgb, domain.LabFromRGB(rgb))
+		if err != nil {
+			t.Fatalf("palette color error: %v", err)
+		}
+		colors = append(colors, color)
+	}
+	palette, err := domain.NewPalette(1, colors, time.Now())
+	if err != nil {
+		t.Fatalf("palette error: %v", err)
+	}
+	palette = palette.Activate()
+	if err := repos.palettes.Create(context.Background(), palette); err != nil {
+		t.Fatalf("create palette error: %v", err)
+	}
+	return palette
+}
+
+// seedWordSet は words を出題中の単語セットとして登録する。
+func seedWordSet(t *testing.T, repos repositories, words ...string) domain.WordSet {
+	t.Helper()
+	set, err := domain.NewWordSet("テスト", words, time.Now())
+	if err != nil {
+		t.Fatalf("word set error: %v", err)
+	}
+	set = set.Activate(time.Now())
+	if err := repos.wordSets.Create(context.Background(), set); err != nil {
+		t.Fatalf("create word set error: %v", err)
+	}
+	return set
+}
+
+func newTestHandler(t *testing.T, cfg config.Config, repos repositories) http.Handler {
+	t.Helper()
+	h, err := newHTTPHandler(cfg, repos, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("handler setup error: %v", err)
+	}
+	return h
+}
+
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Database.URL = "postgres://unused"
+	cfg.Auth.PasswordHash = domain.PasswordHashBcrypt.String()
+	cfg.Auth.BcryptCost = bcrypt.MinCost
+	cfg.Mail.File = os.DevNull
+	return cfg
+}
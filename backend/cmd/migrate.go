@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+
+	"backend/internal/config"
+	infraDB "backend/internal/infra/db"
+)
+
+const migrateUsage = `usage: backend migrate <command>
+
+commands:
+  up [N]       apply all pending migrations, or the next N
+  down [N]     roll back the last N migrations (default 1)
+  status       show the applied version and embedded migrations
+  force V      mark version V as applied and clear the dirty flag`
+
+// runMigrate は `backend migrate ...` サブコマンドを実行する。
+func runMigrate(ctx context.Context, dbConfig config.Database, args []string, stdout io.Writer, logger *log.Logger) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	pool, err := infraDB.NewConnection(ctx, dbConfig)
+	if err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+	defer pool.Close()
+
+	migrator, err := infraDB.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := migrator.Close(); err != nil {
+			logger.Printf("close migrator: %v", err)
+		}
+	}()
+
+	command, rest := args[0], args[1:]
+	switch command {
+	case "up":
+		steps, err := optionalSteps(rest, 0)
+		if err != nil {
+			return err
+		}
+		if steps == 0 {
+			return migrator.Up()
+		}
+		return migrator.Steps(steps)
+	case "down":
+		steps, err := optionalSteps(rest, 1)
+		if err != nil {
+			return err
+		}
+		return migrator.Steps(-steps)
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(stdout, status)
+		return nil
+	case "force":
+		if len(rest) != 1 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(rest[0])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", rest[0])
+		}
+		return migrator.Force(version)
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+func optionalSteps(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps <= 0 {
+		return 0, fmt.Errorf("invalid step count %q", args[0])
+	}
+	return steps, nil
+}
+
+func printMigrationStatus(w io.Writer, status infraDB.MigrationStatus) {
+	if status.Applied {
+		fmt.Fprintf(w, "version: %d (dirty: %t)\n", status.Version, status.Dirty)
+	} else {
+		fmt.Fprintln(w, "version: none")
+	}
+
+	for _, m := range status.Available {
+		mark := " "
+		if status.Applied && m.Version <= status.Version {
+			mark = "x"
+		}
+		fmt.Fprintf(w, "  [%s] %06d %s\n", mark, m.Version, m.Name)
+	}
+}
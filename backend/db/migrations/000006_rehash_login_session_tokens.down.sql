@@ -0,0 +1,4 @@
+-- SHA-256 形式のセッションは旧実装で照合できないため、戻す際も全て失効させる。
+DELETE FROM login_sessions;
+
+COMMENT ON COLUMN login_sessions.token IS NULL;
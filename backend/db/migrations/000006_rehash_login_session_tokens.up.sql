@@ -0,0 +1,5 @@
+-- トークンは "<session_id>.<verifier>" 形式になり、token 列には検証子の SHA-256 を保存する。
+-- 旧形式(bcrypt)のセッションは照合できないため全て失効させ、再ログインを求める。
+DELETE FROM login_sessions;
+
+COMMENT ON COLUMN login_sessions.token IS 'hex(sha256(verifier)); selector is login_sessions.id';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS login_sessions_user_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX login_sessions_user_id_idx ON login_sessions (user_id);
@@ -0,0 +1 @@
+CREATE INDEX hue_records_created_at_id_idx ON hue_records (created_at, id);
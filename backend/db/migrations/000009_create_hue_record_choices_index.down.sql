@@ -0,0 +1 @@
+DROP INDEX IF EXISTS hue_records_choices_idx;
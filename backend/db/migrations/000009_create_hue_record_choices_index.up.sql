@@ -0,0 +1 @@
+CREATE INDEX hue_records_choices_idx ON hue_records USING GIN (choices jsonb_path_ops);
@@ -0,0 +1,4 @@
+ALTER TABLE hue_records
+    ADD COLUMN user_id UUID REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX hue_records_user_id_created_at_id_idx ON hue_records (user_id, created_at, id) WHERE user_id IS NOT NULL;
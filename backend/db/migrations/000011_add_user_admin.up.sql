@@ -0,0 +1,15 @@
+ALTER TABLE users
+    ADD COLUMN disabled_at TIMESTAMP WITH TIME ZONE;
+
+/* 対象ユーザーが削除されても記録を残すため、外部キーは張らない */
+CREATE TABLE admin_audit_logs
+(
+    id         UUID PRIMARY KEY,
+    actor_id   UUID        NOT NULL,
+    target_id  UUID        NOT NULL,
+    action     VARCHAR(32) NOT NULL,
+    detail     TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX admin_audit_logs_created_at_idx ON admin_audit_logs (created_at DESC, id);
@@ -0,0 +1,18 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+
+/* 確認の仕組みより前に登録されたユーザーは確認済みとして扱う */
+UPDATE users
+SET email_verified_at = created_at;
+
+CREATE TABLE email_verifications
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token      TEXT        NOT NULL, /* hashed */
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at    TIMESTAMPTZ
+);
+
+CREATE INDEX email_verifications_user_id_idx ON email_verifications (user_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS hue_records_word_set_id_idx;
+
+ALTER TABLE hue_records
+    DROP COLUMN IF EXISTS word_set_id;
+
+DROP TABLE IF EXISTS word_sets;
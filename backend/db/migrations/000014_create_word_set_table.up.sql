@@ -0,0 +1,34 @@
+CREATE TABLE word_sets
+(
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(64) NOT NULL,
+    words      TEXT[]      NOT NULL,
+    active     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+/* 有効な単語セットは常に 1 つまで */
+CREATE UNIQUE INDEX word_sets_active_idx ON word_sets (active) WHERE active;
+
+/* それまでフロントエンドに埋め込んでいた一覧を初期の単語セットにする (重複していた「情緒」は 1 つにまとめる) */
+INSERT INTO word_sets (id, name, words, active)
+VALUES ('6f1c1f3e-5b0a-4d55-9a51-2a7c1e0f0001', '標準', ARRAY [
+        '夜', '詐欺', '毒', '男性', '平和', '児童', '心', '母', '宗教', '孤独',
+        '未来', '良心', '熱情', '情緒', '気質', '活動', '反抗', '力', '緊張', '愛情',
+        '勝利', '自発性', '恥', '野望', '嫉妬', '戯れ', '笑い', 'お祭り', '快楽', '朝',
+        '喜び', '独創', '成功', '調和', '利益', '娘', '家庭', '満足', '幸福', '女性',
+        '嫌悪', '冗談', '苦痛', '野心', '協力', '自然', '善', '慈善', '教育', '親切',
+        '息子', '信任', '献身', '科学', '涙', '理論', '理想', '不幸', '病気', '夕暮',
+        '拘束', '憐み', '霊魂', '仕事', '機械仕掛け', '父', '依存', '老人', '労働', '苦難',
+        '退屈', '過去', '悲しみ', '敗北', '責任', '自分個人の', '盗み', '逆境（不幸）', '殺人', '性欲',
+        '怨恨', '裸体', '祝祭', '女友達', '男友達', '自然さ', '従順', '有用', '兄弟', '確信',
+        '若者', '心配', '職業', '機械', '苦悩', '損害', '赤ん坊', '単純さ', '自由', '結婚',
+        '都会', '優雅'
+    ], TRUE);
+
+/* 既存の回答はどの一覧に答えたか分からないため NULL のまま残す */
+ALTER TABLE hue_records
+    ADD COLUMN word_set_id UUID REFERENCES word_sets (id);
+
+CREATE INDEX hue_records_word_set_id_idx ON hue_records (word_set_id);
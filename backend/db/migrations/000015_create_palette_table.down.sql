@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS hue_records_palette_version_idx;
+
+ALTER TABLE hue_records
+    DROP COLUMN IF EXISTS palette_version;
+
+/* 色 ID を版 1 の日本語名に戻す */
+UPDATE hue_records AS h
+SET choices = (SELECT jsonb_object_agg(c.key, COALESCE(p.color -> 'names' ->> 'ja', c.value))
+               FROM jsonb_each_text(h.choices) AS c(key, value)
+                        LEFT JOIN LATERAL (SELECT color
+                                           FROM palettes, jsonb_array_elements(palettes.colors) AS color
+                                           WHERE palettes.version = 1
+                                             AND color ->> 'id' = c.value) AS p ON TRUE)
+WHERE h.choices <> '{}'::jsonb;
+
+DROP TABLE IF EXISTS palettes;
@@ -0,0 +1,49 @@
+CREATE TABLE palettes
+(
+    id         UUID PRIMARY KEY,
+    version    INTEGER     NOT NULL UNIQUE CHECK (version > 0),
+    colors     JSONB       NOT NULL,
+    active     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+/* 有効なパレットは常に 1 つまで */
+CREATE UNIQUE INDEX palettes_active_idx ON palettes (active) WHERE active;
+
+/* それまでコードに埋め込んでいた 11 色を版 1 にする。RGB は回答画面の表示色、Lab はその D65 換算 */
+INSERT INTO palettes (id, version, colors, active)
+VALUES ('6f1c1f3e-5b0a-4d55-9a51-2a7c1e0f0101', 1, '[
+  {"id": "black",  "names": {"ja": "黒",       "en": "Black"},  "rgb": "#444444", "lab": {"l": 28.85, "a": 0,      "b": 0}},
+  {"id": "gray",   "names": {"ja": "灰色",     "en": "Gray"},   "rgb": "#999999", "lab": {"l": 63.22, "a": 0,      "b": 0}},
+  {"id": "white",  "names": {"ja": "白",       "en": "White"},  "rgb": "#f5f5f5", "lab": {"l": 96.54, "a": 0,      "b": 0}},
+  {"id": "pink",   "names": {"ja": "ピンク",   "en": "Pink"},   "rgb": "#e86b87", "lab": {"l": 61.15, "a": 51.01,  "b": 8.41}},
+  {"id": "red",    "names": {"ja": "赤",       "en": "Red"},    "rgb": "#d94f4f", "lab": {"l": 52.85, "a": 54.02,  "b": 29.38}},
+  {"id": "orange", "names": {"ja": "オレンジ", "en": "Orange"}, "rgb": "#f18c3c", "lab": {"l": 67.86, "a": 32.42,  "b": 57.09}},
+  {"id": "yellow", "names": {"ja": "黄色",     "en": "Yellow"}, "rgb": "#e7c84f", "lab": {"l": 81.22, "a": -2.32,  "b": 62.39}},
+  {"id": "green",  "names": {"ja": "緑",       "en": "Green"},  "rgb": "#4cad68", "lab": {"l": 63.69, "a": -44.02, "b": 27.07}},
+  {"id": "blue",   "names": {"ja": "青",       "en": "Blue"},   "rgb": "#4f86e2", "lab": {"l": 56.29, "a": 10.78,  "b": -52.2}},
+  {"id": "purple", "names": {"ja": "紫",       "en": "Purple"}, "rgb": "#956ec4", "lab": {"l": 53.52, "a": 32.76,  "b": -39.32}},
+  {"id": "brown",  "names": {"ja": "茶",       "en": "Brown"},  "rgb": "#a1693c", "lab": {"l": 49.51, "a": 17.82,  "b": 34.11}}
+]', TRUE);
+
+/* 既存の回答の色名を版 1 の色 ID に置き換える。版 1 に無い値 (保存時の検証より前のデータ) はそのまま残す */
+UPDATE hue_records AS h
+SET choices = (SELECT jsonb_object_agg(c.key, COALESCE(p.color ->> 'id', c.value))
+               FROM jsonb_each_text(h.choices) AS c(key, value)
+                        LEFT JOIN LATERAL (SELECT color
+                                           FROM palettes, jsonb_array_elements(palettes.colors) AS color
+                                           WHERE palettes.version = 1
+                                             AND color -> 'names' ->> 'ja' = c.value) AS p ON TRUE)
+WHERE h.choices <> '{}'::jsonb;
+
+/* 既存の回答はすべて版 1 の 11 色から選ばれている */
+ALTER TABLE hue_records
+    ADD COLUMN palette_version INTEGER REFERENCES palettes (version);
+
+UPDATE hue_records
+SET palette_version = 1;
+
+ALTER TABLE hue_records
+    ALTER COLUMN palette_version SET NOT NULL;
+
+CREATE INDEX hue_records_palette_version_idx ON hue_records (palette_version);
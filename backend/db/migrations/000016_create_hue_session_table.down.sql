@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS hue_session_answers;
+
+DROP TABLE IF EXISTS hue_sessions;
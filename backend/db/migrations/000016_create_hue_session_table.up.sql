@@ -0,0 +1,26 @@
+CREATE TABLE hue_sessions
+(
+    id              UUID PRIMARY KEY,
+    user_id         UUID REFERENCES users (id) ON DELETE SET NULL,
+    word_set_id     UUID        NOT NULL REFERENCES word_sets (id),
+    palette_version INT         NOT NULL REFERENCES palettes (version),
+    words           TEXT[]      NOT NULL,
+    started_at      TIMESTAMPTZ NOT NULL,
+    last_active_at  TIMESTAMPTZ NOT NULL,
+    finished_at     TIMESTAMPTZ,
+    record_id       UUID REFERENCES hue_records (id) ON DELETE SET NULL
+);
+
+/* 離脱の集計では開始日時で区切って数える */
+CREATE INDEX hue_sessions_started_at_idx ON hue_sessions (started_at);
+
+/* 1 語への回答。答え直すと上書きする */
+CREATE TABLE hue_session_answers
+(
+    session_id  UUID        NOT NULL REFERENCES hue_sessions (id) ON DELETE CASCADE,
+    word        TEXT        NOT NULL,
+    color       TEXT        NOT NULL,
+    latency_ms  INT         NOT NULL CHECK (latency_ms >= 0),
+    answered_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (session_id, word)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE hue_records
+    DROP CONSTRAINT IF EXISTS hue_records_word_order_check,
+    DROP COLUMN IF EXISTS word_order,
+    DROP COLUMN IF EXISTS word_order_seed,
+    DROP COLUMN IF EXISTS word_order_mode;
+
+DROP INDEX IF EXISTS hue_sessions_word_set_id_idx;
+
+ALTER TABLE hue_sessions
+    DROP COLUMN IF EXISTS word_order_seed,
+    DROP COLUMN IF EXISTS word_order_mode;
@@ -0,0 +1,17 @@
+/* それまでのセッションは単語セットの登録順で出題していた */
+ALTER TABLE hue_sessions
+    ADD COLUMN word_order_mode TEXT   NOT NULL DEFAULT 'fixed'
+        CHECK (word_order_mode IN ('fixed', 'shuffle', 'latin_square')),
+    ADD COLUMN word_order_seed BIGINT NOT NULL DEFAULT 0;
+
+/* ラテン方格の行は単語セットごとのセッション数で順番に割り当てる */
+CREATE INDEX hue_sessions_word_set_id_idx ON hue_sessions (word_set_id);
+
+/* セッションを通した回答の出題順。セッションを通さない回答は NULL のまま */
+ALTER TABLE hue_records
+    ADD COLUMN word_order_mode TEXT
+        CHECK (word_order_mode IN ('fixed', 'shuffle', 'latin_square')),
+    ADD COLUMN word_order_seed BIGINT,
+    ADD COLUMN word_order      TEXT[],
+    ADD CONSTRAINT hue_records_word_order_check
+        CHECK ((word_order_mode IS NULL) = (word_order IS NULL) AND (word_order_mode IS NULL) = (word_order_seed IS NULL));
@@ -0,0 +1,4 @@
+ALTER TABLE hue_records
+    DROP COLUMN IF EXISTS quality_flags,
+    DROP COLUMN IF EXISTS challenge_id,
+    DROP COLUMN IF EXISTS idempotency_key;
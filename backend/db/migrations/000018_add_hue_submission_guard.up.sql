@@ -0,0 +1,8 @@
+/* save-result の再送判定キーと使い捨てチャレンジ。どちらも同じ値で 2 件目を保存させない */
+ALTER TABLE hue_records
+    ADD COLUMN idempotency_key TEXT
+        CONSTRAINT hue_records_idempotency_key_key UNIQUE,
+    ADD COLUMN challenge_id    UUID
+        CONSTRAINT hue_records_challenge_id_key UNIQUE,
+    ADD COLUMN quality_flags   TEXT[] NOT NULL DEFAULT '{}';
+
@@ -0,0 +1,7 @@
+/* 前後の空白を除いてからハッシュ化していた頃の bcrypt ハッシュ。除いた平文での照合はこの印がある行に限り、作り直すと外す */
+ALTER TABLE users
+    ADD COLUMN password_legacy_trim BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users
+SET password_legacy_trim = TRUE
+WHERE hashed_password LIKE '$2%';
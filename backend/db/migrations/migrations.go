@@ -0,0 +1,8 @@
+// Package migrations はスキーママイグレーションの SQL をバイナリへ埋め込む。
+// ファイル名は golang-migrate の <version>_<name>.(up|down).sql 規約に従う。
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,557 @@
+// Package config はサーバーと CLI の設定を環境変数と任意の設定ファイルから読み込み、起動時に検証する。
+//
+// 優先順位は「既定値 < CONFIG_FILE で指定した JSON ファイル < 環境変数」。
+package config
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"backend/internal/domain"
+)
+
+// 設定を読み込む環境変数名。検証エラーもこの名前で報告する。
+const (
+	EnvConfigFile                   = "CONFIG_FILE"
+	EnvDatabaseURL                  = "DATABASE_URL"
+	EnvDatabaseMaxConns             = "DATABASE_MAX_CONNS"
+	EnvDatabaseMinConns             = "DATABASE_MIN_CONNS"
+	EnvPort                         = "PORT"
+	EnvReadHeaderTimeout            = "HTTP_READ_HEADER_TIMEOUT"
+	EnvWriteTimeout                 = "HTTP_WRITE_TIMEOUT"
+	EnvIdleTimeout                  = "HTTP_IDLE_TIMEOUT"
+	EnvShutdownTimeout              = "HTTP_SHUTDOWN_TIMEOUT"
+	EnvAllowedOrigins               = "CORS_ALLOWED_ORIGINS"
+	EnvCORSMaxAge                   = "CORS_MAX_AGE"
+	EnvTrustedProxies               = "TRUSTED_PROXIES"
+	EnvPasswordHash                 = "PASSWORD_HASH"
+	EnvBcryptCost                   = "BCRYPT_COST"
+	EnvArgon2Memory                 = "ARGON2_MEMORY"
+	EnvArgon2Iterations             = "ARGON2_ITERATIONS"
+	EnvArgon2Parallelism            = "ARGON2_PARALLELISM"
+	EnvSessionTTL                   = "SESSION_TTL"
+	EnvAutoMigrate                  = "AUTO_MIGRATE"
+	EnvPasswordResetTTL             = "PASSWORD_RESET_TTL"
+	EnvPasswordResetURL             = "PASSWORD_RESET_URL"
+	EnvEmailVerifyTTL               = "EMAIL_VERIFY_TTL"
+	EnvEmailVerifyURL               = "EMAIL_VERIFY_URL"
+	EnvRequireVerifiedEmailForLogin = "REQUIRE_VERIFIED_EMAIL_FOR_LOGIN"
+	EnvRequireVerifiedEmailForAdmin = "REQUIRE_VERIFIED_EMAIL_FOR_ADMIN"
+	EnvPasswordMinLength            = "PASSWORD_MIN_LENGTH"
+	EnvPasswordMinClasses           = "PASSWORD_MIN_CLASSES"
+	EnvPasswordDenyCommon           = "PASSWORD_DENY_COMMON"
+	EnvMailTransport                = "MAIL_TRANSPORT"
+	EnvMailFrom                     = "MAIL_FROM"
+	EnvMailFile                     = "MAIL_FILE"
+	EnvSMTPHost                     = "SMTP_HOST"
+	EnvSMTPPort                     = "SMTP_PORT"
+	EnvSMTPUsername                 = "SMTP_USERNAME"
+	EnvSMTPPassword                 = "SMTP_PASSWORD"
+	EnvHueWordOrder                 = "HUE_WORD_ORDER"
+	EnvHueChallengeSecret           = "HUE_CHALLENGE_SECRET"
+)
+
+// メールの配送方法。file は MAIL_FILE (省略時は標準出力) へ書き出すだけで、ローカル開発向け。
+const (
+	MailTransportFile = "file"
+	MailTransportSMTP = "smtp"
+)
+
+// minSessionTTL より短い TTL ではリフレッシュが間に合わないため拒否する。
+const minSessionTTL = time.Minute
+
+// Config はアプリケーション全体の設定。
+type Config struct {
+	HTTP        HTTP
+	Database    Database
+	Auth        Auth
+	CORS        CORS
+	Proxy       Proxy
+	Mail        Mail
+	Hue         Hue
+	AutoMigrate bool
+}
+
+// HTTP は http.Server の待ち受けアドレスとタイムアウト。
+type HTTP struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// Database は接続先とコネクションプールの大きさ。
+type Database struct {
+	URL      string
+	MaxConns int32
+	MinConns int32
+}
+
+// Auth はパスワードハッシュ、ログインセッション、パスワード再設定、メールアドレス確認に関する設定。
+// PasswordResetURL と EmailVerifyURL はメールに載せるページの URL で、token クエリを付けて送る。
+// RequireVerifiedEmailFor* が true なら、未確認のユーザーにはログインや admin への昇格を許さない。
+// Password* は新しく設定するパスワードの規則で、domain.PasswordPolicy に渡す。
+// PasswordHash は新しく作るハッシュの方式で、BcryptCost と Argon2* はそれぞれの方式のパラメータ (Argon2Memory は KiB)。
+type Auth struct {
+	PasswordHash                 string
+	BcryptCost                   int
+	Argon2Memory                 int
+	Argon2Iterations             int
+	Argon2Parallelism            int
+	SessionTTL                   time.Duration
+	PasswordResetTTL             time.Duration
+	PasswordResetURL             string
+	EmailVerifyTTL               time.Duration
+	EmailVerifyURL               string
+	RequireVerifiedEmailForLogin bool
+	RequireVerifiedEmailForAdmin bool
+	PasswordMinLength            int
+	PasswordMinClasses           int
+	PasswordDenyCommon           bool
+}
+
+// PasswordHasher は設定から PasswordHash の方式の domain.PasswordHasher を組み立てる。
+func (a Auth) PasswordHasher() (domain.PasswordHasher, error) {
+	algorithm, err := domain.NewPasswordHashAlgorithm(a.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	if algorithm == domain.PasswordHashBcrypt {
+		return domain.NewBcryptHasher(a.BcryptCost)
+	}
+	if a.Argon2Memory < 0 || int64(a.Argon2Memory) > math.MaxUint32 || a.Argon2Iterations < 0 || int64(a.Argon2Iterations) > math.MaxUint32 ||
+		a.Argon2Parallelism < 0 || a.Argon2Parallelism > math.MaxUint8 {
+		return nil, domain.ErrInvalidPasswordHasher
+	}
+	return domain.NewArgon2idHasher(uint32(a.Argon2Memory), uint32(a.Argon2Iterations), uint8(a.Argon2Parallelism))
+}
+
+// PasswordPolicy は設定から domain.PasswordPolicy を組み立てる。
+func (a Auth) PasswordPolicy() (domain.PasswordPolicy, error) {
+	return domain.NewPasswordPolicy(a.PasswordMinLength, a.PasswordMinClasses, a.PasswordDenyCommon)
+}
+
+// Mail はメールの配送方法と差出人。SMTP は Transport が smtp のときだけ使う。
+type Mail struct {
+	Transport string
+	From      string
+	File      string
+	SMTP      SMTP
+}
+
+// SMTP は送信に使うサーバー。Username が空なら認証しない。
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// Hue は Hue テストの出題と回答の受け付けに関する設定。WordOrder はセッションごとの出題順の決め方で、domain.WordOrderMode の値を書く。
+// ChallengeSecret は save-result に添えるチャレンジの署名鍵。空なら起動ごとに生成するため、再起動で発行済みのチャレンジが無効になり、
+// 複数のサーバーの間でも共有されない。
+type Hue struct {
+	WordOrder       string
+	ChallengeSecret string
+}
+
+// WordOrderMode は設定から domain.WordOrderMode を組み立てる。
+func (h Hue) WordOrderMode() (domain.WordOrderMode, error) {
+	return domain.NewWordOrderMode(h.WordOrder)
+}
+
+// ChallengeSigner は ChallengeSecret で署名する domain.HueChallengeSigner を組み立てる。ChallengeSecret が空なら鍵を生成する。
+func (h Hue) ChallengeSigner() (domain.HueChallengeSigner, error) {
+	if h.ChallengeSecret == "" {
+		secret := make([]byte, domain.MinHueChallengeSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return domain.HueChallengeSigner{}, err
+		}
+		return domain.NewHueChallengeSigner(secret)
+	}
+	return domain.NewHueChallengeSigner([]byte(h.ChallengeSecret))
+}
+
+// CORS はブラウザからのクロスオリジン呼び出しを許可するオリジンと、プリフライト結果のキャッシュ時間。
+// オリジンは完全一致か "https://*.example.com" のようなサブドメインのワイルドカードで書く。
+type CORS struct {
+	AllowedOrigins []string
+	MaxAge         time.Duration
+}
+
+// Proxy は前段のリバースプロキシ。Trusted は信頼するプロキシのアドレスで、"10.0.0.1" のような IP か
+// "172.16.0.0/12" のような CIDR で書く。直接の接続元がここに含まれるときだけ、X-Forwarded-For / X-Real-IP を
+// クライアントのアドレスとして使う。空なら転送ヘッダーは見ない。
+type Proxy struct {
+	Trusted []string
+}
+
+// Default は DATABASE_URL 以外の既定値を返す。
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      15 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   10 * time.Second,
+		},
+		Database: Database{
+			MaxConns: 10,
+		},
+		Auth: Auth{
+			PasswordHash:       domain.PasswordHashArgon2id.String(),
+			BcryptCost:         bcrypt.DefaultCost,
+			Argon2Memory:       int(domain.DefaultArgon2Memory),
+			Argon2Iterations:   int(domain.DefaultArgon2Iterations),
+			Argon2Parallelism:  int(domain.DefaultArgon2Parallelism),
+			SessionTTL:         domain.DefaultLoginSessionTTL,
+			PasswordResetTTL:   domain.DefaultPasswordResetTTL,
+			PasswordResetURL:   "http://localhost:3000/reset-password",
+			EmailVerifyTTL:     domain.DefaultEmailVerificationTTL,
+			EmailVerifyURL:     "http://localhost:3000/verify-email",
+			PasswordMinLength:  domain.DefaultPasswordMinLength,
+			PasswordMinClasses: domain.DefaultPasswordMinClasses,
+			PasswordDenyCommon: true,
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{"http://localhost:3000"},
+			MaxAge:         10 * time.Minute,
+		},
+		Mail: Mail{
+			Transport: MailTransportFile,
+			From:      "no-reply@localhost",
+			SMTP:      SMTP{Port: 587},
+		},
+		Hue: Hue{
+			WordOrder: domain.WordOrderShuffle.String(),
+		},
+	}
+}
+
+// Load はプロセスの環境変数から設定を読み込み、検証済みの Config を返す。
+func Load() (Config, error) {
+	return load(os.LookupEnv)
+}
+
+func load(lookup func(string) (string, bool)) (Config, error) {
+	cfg := Default()
+
+	if path, ok := lookup(EnvConfigFile); ok && strings.TrimSpace(path) != "" {
+		if err := applyFile(&cfg, strings.TrimSpace(path)); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := applyEnv(&cfg, lookup); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate は全項目を検査し、問題をまとめて 1 つのエラーとして返す。
+func (c Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if strings.TrimSpace(c.Database.URL) == "" {
+		add("%s is required", EnvDatabaseURL)
+	}
+	if c.Database.MaxConns < 1 {
+		add("%s must be at least 1, got %d", EnvDatabaseMaxConns, c.Database.MaxConns)
+	}
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		add("%s must be between 0 and %s (%d), got %d", EnvDatabaseMinConns, EnvDatabaseMaxConns, c.Database.MaxConns, c.Database.MinConns)
+	}
+
+	if err := validateAddr(c.HTTP.Addr); err != nil {
+		add("%s: %v", EnvPort, err)
+	}
+	for _, t := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{EnvReadHeaderTimeout, c.HTTP.ReadHeaderTimeout},
+		{EnvWriteTimeout, c.HTTP.WriteTimeout},
+		{EnvIdleTimeout, c.HTTP.IdleTimeout},
+		{EnvShutdownTimeout, c.HTTP.ShutdownTimeout},
+	} {
+		if t.value <= 0 {
+			add("%s must be positive, got %s", t.name, t.value)
+		}
+	}
+
+	if _, err := domain.NewPasswordHashAlgorithm(c.Auth.PasswordHash); err != nil {
+		add("%s must be %q or %q, got %q", EnvPasswordHash, domain.PasswordHashBcrypt, domain.PasswordHashArgon2id, c.Auth.PasswordHash)
+	}
+	if c.Auth.BcryptCost < bcrypt.MinCost || c.Auth.BcryptCost > bcrypt.MaxCost {
+		add("%s must be between %d and %d, got %d", EnvBcryptCost, bcrypt.MinCost, bcrypt.MaxCost, c.Auth.BcryptCost)
+	}
+	if c.Auth.Argon2Iterations < 1 || int64(c.Auth.Argon2Iterations) > math.MaxUint32 {
+		add("%s must be between 1 and %d, got %d", EnvArgon2Iterations, uint32(math.MaxUint32), c.Auth.Argon2Iterations)
+	}
+	if c.Auth.Argon2Parallelism < 1 || c.Auth.Argon2Parallelism > math.MaxUint8 {
+		add("%s must be between 1 and %d, got %d", EnvArgon2Parallelism, math.MaxUint8, c.Auth.Argon2Parallelism)
+	}
+	if minMemory := 8 * max(c.Auth.Argon2Parallelism, 1); c.Auth.Argon2Memory < minMemory || int64(c.Auth.Argon2Memory) > math.MaxUint32 {
+		add("%s must be at least %d KiB (8 per %s), got %d", EnvArgon2Memory, minMemory, EnvArgon2Parallelism, c.Auth.Argon2Memory)
+	}
+	if c.Auth.SessionTTL < minSessionTTL {
+		add("%s must be at least %s, got %s", EnvSessionTTL, minSessionTTL, c.Auth.SessionTTL)
+	}
+
+	if c.Auth.PasswordResetTTL < time.Minute {
+		add("%s must be at least %s, got %s", EnvPasswordResetTTL, time.Minute, c.Auth.PasswordResetTTL)
+	}
+	if u, err := url.Parse(c.Auth.PasswordResetURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add("%s must be an absolute http(s) URL, got %q", EnvPasswordResetURL, c.Auth.PasswordResetURL)
+	}
+	if c.Auth.PasswordMinLength < 1 || c.Auth.PasswordMinLength > domain.MaxPasswordBytes {
+		add("%s must be between 1 and %d, got %d", EnvPasswordMinLength, domain.MaxPasswordBytes, c.Auth.PasswordMinLength)
+	}
+	if c.Auth.PasswordMinClasses < 1 || c.Auth.PasswordMinClasses > 4 {
+		add("%s must be between 1 and 4, got %d", EnvPasswordMinClasses, c.Auth.PasswordMinClasses)
+	}
+	if c.Auth.EmailVerifyTTL < time.Minute {
+		add("%s must be at least %s, got %s", EnvEmailVerifyTTL, time.Minute, c.Auth.EmailVerifyTTL)
+	}
+	if u, err := url.Parse(c.Auth.EmailVerifyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add("%s must be an absolute http(s) URL, got %q", EnvEmailVerifyURL, c.Auth.EmailVerifyURL)
+	}
+
+	switch c.Mail.Transport {
+	case MailTransportFile:
+	case MailTransportSMTP:
+		if strings.TrimSpace(c.Mail.SMTP.Host) == "" {
+			add("%s is required when %s is %q", EnvSMTPHost, EnvMailTransport, MailTransportSMTP)
+		}
+		if c.Mail.SMTP.Port < 1 || c.Mail.SMTP.Port > 65535 {
+			add("%s must be between 1 and 65535, got %d", EnvSMTPPort, c.Mail.SMTP.Port)
+		}
+	default:
+		add("%s must be %q or %q, got %q", EnvMailTransport, MailTransportFile, MailTransportSMTP, c.Mail.Transport)
+	}
+	if _, err := domain.NewEmail(c.Mail.From); err != nil {
+		add("%s must be a valid email address, got %q", EnvMailFrom, c.Mail.From)
+	}
+
+	if _, err := c.Hue.WordOrderMode(); err != nil {
+		add("%s must be %q, %q or %q, got %q", EnvHueWordOrder, domain.WordOrderFixed, domain.WordOrderShuffle, domain.WordOrderLatinSquare, c.Hue.WordOrder)
+	}
+	if c.Hue.ChallengeSecret != "" && len(c.Hue.ChallengeSecret) < domain.MinHueChallengeSecretBytes {
+		// 鍵そのものはエラーに含めない。
+		add("%s must be at least %d bytes, got %d", EnvHueChallengeSecret, domain.MinHueChallengeSecretBytes, len(c.Hue.ChallengeSecret))
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			add("%s: %v", EnvAllowedOrigins, err)
+		}
+	}
+	for _, proxy := range c.Proxy.Trusted {
+		if err := validateProxy(proxy); err != nil {
+			add("%s: %v", EnvTrustedProxies, err)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		add("%s must not be negative, got %s", EnvCORSMaxAge, c.CORS.MaxAge)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+}
+
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	get := func(name string) (string, bool) {
+		value, ok := lookup(name)
+		value = strings.TrimSpace(value)
+		return value, ok && value != ""
+	}
+	duration := func(name string, dst *time.Duration) {
+		if value, ok := get(name); ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid duration %q (use e.g. \"15s\" or \"30m\")", name, value))
+				return
+			}
+			*dst = d
+		}
+	}
+	integer := func(name string, bits int, set func(int64)) {
+		if value, ok := get(name); ok {
+			n, err := strconv.ParseInt(value, 10, bits)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid integer %q", name, value))
+				return
+			}
+			set(n)
+		}
+	}
+	boolean := func(name string, dst *bool) {
+		if value, ok := get(name); ok {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid boolean %q", name, value))
+				return
+			}
+			*dst = b
+		}
+	}
+
+	if value, ok := get(EnvDatabaseURL); ok {
+		cfg.Database.URL = value
+	}
+	integer(EnvDatabaseMaxConns, 32, func(n int64) { cfg.Database.MaxConns = int32(n) })
+	integer(EnvDatabaseMinConns, 32, func(n int64) { cfg.Database.MinConns = int32(n) })
+
+	if value, ok := get(EnvPort); ok {
+		cfg.HTTP.Addr = portAddr(value)
+	}
+	duration(EnvReadHeaderTimeout, &cfg.HTTP.ReadHeaderTimeout)
+	duration(EnvWriteTimeout, &cfg.HTTP.WriteTimeout)
+	duration(EnvIdleTimeout, &cfg.HTTP.IdleTimeout)
+	duration(EnvShutdownTimeout, &cfg.HTTP.ShutdownTimeout)
+
+	if value, ok := get(EnvAllowedOrigins); ok {
+		cfg.CORS.AllowedOrigins = splitList(value)
+	}
+	duration(EnvCORSMaxAge, &cfg.CORS.MaxAge)
+	if value, ok := get(EnvTrustedProxies); ok {
+		cfg.Proxy.Trusted = splitList(value)
+	}
+
+	if value, ok := get(EnvPasswordHash); ok {
+		cfg.Auth.PasswordHash = value
+	}
+	integer(EnvBcryptCost, 0, func(n int64) { cfg.Auth.BcryptCost = int(n) })
+	integer(EnvArgon2Memory, 0, func(n int64) { cfg.Auth.Argon2Memory = int(n) })
+	integer(EnvArgon2Iterations, 0, func(n int64) { cfg.Auth.Argon2Iterations = int(n) })
+	integer(EnvArgon2Parallelism, 0, func(n int64) { cfg.Auth.Argon2Parallelism = int(n) })
+	duration(EnvSessionTTL, &cfg.Auth.SessionTTL)
+	duration(EnvPasswordResetTTL, &cfg.Auth.PasswordResetTTL)
+	if value, ok := get(EnvPasswordResetURL); ok {
+		cfg.Auth.PasswordResetURL = value
+	}
+	duration(EnvEmailVerifyTTL, &cfg.Auth.EmailVerifyTTL)
+	if value, ok := get(EnvEmailVerifyURL); ok {
+		cfg.Auth.EmailVerifyURL = value
+	}
+	boolean(EnvRequireVerifiedEmailForLogin, &cfg.Auth.RequireVerifiedEmailForLogin)
+	boolean(EnvRequireVerifiedEmailForAdmin, &cfg.Auth.RequireVerifiedEmailForAdmin)
+	integer(EnvPasswordMinLength, 0, func(n int64) { cfg.Auth.PasswordMinLength = int(n) })
+	integer(EnvPasswordMinClasses, 0, func(n int64) { cfg.Auth.PasswordMinClasses = int(n) })
+	boolean(EnvPasswordDenyCommon, &cfg.Auth.PasswordDenyCommon)
+
+	if value, ok := get(EnvMailTransport); ok {
+		cfg.Mail.Transport = strings.ToLower(value)
+	}
+	if value, ok := get(EnvMailFrom); ok {
+		cfg.Mail.From = value
+	}
+	if value, ok := get(EnvMailFile); ok {
+		cfg.Mail.File = value
+	}
+	if value, ok := get(EnvSMTPHost); ok {
+		cfg.Mail.SMTP.Host = value
+	}
+	integer(EnvSMTPPort, 0, func(n int64) { cfg.Mail.SMTP.Port = int(n) })
+	if value, ok := get(EnvSMTPUsername); ok {
+		cfg.Mail.SMTP.Username = value
+	}
+	if value, ok := lookup(EnvSMTPPassword); ok && value != "" {
+		// パスワードは前後の空白も値の一部として扱う。
+		cfg.Mail.SMTP.Password = value
+	}
+
+	if value, ok := get(EnvHueWordOrder); ok {
+		cfg.Hue.WordOrder = value
+	}
+	if value, ok := lookup(EnvHueChallengeSecret); ok && value != "" {
+		// SMTP_PASSWORD と同じく、前後の空白も鍵の一部として扱う。
+		cfg.Hue.ChallengeSecret = value
+	}
+
+	boolean(EnvAutoMigrate, &cfg.AutoMigrate)
+
+	return errors.Join(errs...)
+}
+
+// portAddr は PORT に "8080" と ":8080" のどちらが来ても待ち受けアドレスに正規化する。
+func portAddr(port string) string {
+	if strings.Contains(port, ":") {
+		return port
+	}
+	return ":" + port
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func validateAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q", addr)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535, got %q", port)
+	}
+	return nil
+}
+
+// validateProxy は IP アドレスか CIDR だけを受け付ける。
+func validateProxy(proxy string) error {
+	if _, err := netip.ParseAddr(proxy); err == nil {
+		return nil
+	}
+	if _, err := netip.ParsePrefix(proxy); err != nil {
+		return fmt.Errorf("proxy %q must be an IP address or CIDR like 10.0.0.0/8", proxy)
+	}
+	return nil
+}
+
+// validateOrigin は "scheme://host[:port]" の形だけを受け付ける。資格情報付きの CORS では "*" 単体は使えず、
+// ワイルドカードは先頭のラベルにだけ書ける。
+func validateOrigin(origin string) error {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("origin %q must look like https://example.com", origin)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("origin %q must not contain a path, query or credentials", origin)
+	}
+	if host := u.Hostname(); strings.Contains(host, "*") {
+		if rest, ok := strings.CutPrefix(host, "*."); !ok || rest == "" || strings.Contains(rest, "*") {
+			return fmt.Errorf("origin %q may only use a wildcard as the leftmost label, like https://*.example.com", origin)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+)
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Default()
+	if cfg.HTTP != want.HTTP || cfg.Auth != want.Auth || cfg.Database.MaxConns != want.Database.MaxConns {
+		t.Fatalf("expected defaults, got %+v", cfg)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 1 || cfg.CORS.AllowedOrigins[0] != "http://localhost:3000" {
+		t.Fatalf("unexpected default origins: %v", cfg.CORS.AllowedOrigins)
+	}
+	if cfg.AutoMigrate {
+		t.Fatalf("auto migrate should be off by default")
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{
+		EnvDatabaseURL:      "postgres://localhost/app",
+		EnvDatabaseMaxConns: "20",
+		EnvDatabaseMinConns: "2",
+		EnvPort:             "9090",
+		EnvWriteTimeout:     "30s",
+		EnvAllowedOrigins:   "https://a.example.com, https://b.example.com",
+		EnvBcryptCost:       "12",
+		EnvSessionTTL:       "1h",
+		EnvAutoMigrate:      "true",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.HTTP.Addr != ":9090" {
+		t.Fatalf("expected :9090, got %s", cfg.HTTP.Addr)
+	}
+	if cfg.HTTP.WriteTimeout != 30*time.Second {
+		t.Fatalf("expected write timeout 30s, got %s", cfg.HTTP.WriteTimeout)
+	}
+	if cfg.Database.MaxConns != 20 || cfg.Database.MinConns != 2 {
+		t.Fatalf("unexpected pool size: %+v", cfg.Database)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[1] != "https://b.example.com" {
+		t.Fatalf("unexpected origins: %v", cfg.CORS.AllowedOrigins)
+	}
+	if cfg.Auth.BcryptCost != 12 || cfg.Auth.SessionTTL != time.Hour {
+		t.Fatalf("unexpected auth config: %+v", cfg.Auth)
+	}
+	if !cfg.AutoMigrate {
+		t.Fatalf("expected auto migrate to be enabled")
+	}
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	file := `{
+		"http": {"addr": ":7070", "idle_timeout": "2m"},
+		"database": {"url": "postgres://file/app", "max_conns": 4},
+		"auth": {"session_ttl": "45m"},
+		"cors": {"allowed_origins": ["https://file.example.com"]}
+	}`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	cfg, err := load(envLookup(map[string]string{
+		EnvConfigFile: path,
+		EnvSessionTTL: "10m",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.HTTP.Addr != ":7070" || cfg.HTTP.IdleTimeout != 2*time.Minute {
+		t.Fatalf("expected file http settings, got %+v", cfg.HTTP)
+	}
+	if cfg.Database.URL != "postgres://file/app" || cfg.Database.MaxConns != 4 {
+		t.Fatalf("expected file database settings, got %+v", cfg.Database)
+	}
+	if cfg.Auth.SessionTTL != 10*time.Minute {
+		t.Fatalf("expected env to override file ttl, got %s", cfg.Auth.SessionTTL)
+	}
+	if cfg.HTTP.WriteTimeout != Default().HTTP.WriteTimeout {
+		t.Fatalf("expected omitted fields to keep defaults")
+	}
+}
+
+func TestLoad_FileErrors(t *testing.T) {
+	dir := t.TempDir()
+	unknown := filepath.Join(dir, "unknown.json")
+	if err := os.WriteFile(unknown, []byte(`{"htp": {}}`), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	badDuration := filepath.Join(dir, "duration.json")
+	if err := os.WriteFile(badDuration, []byte(`{"auth": {"session_ttl": 30}}`), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	for _, path := range []string{unknown, badDuration, filepath.Join(dir, "missing.json")} {
+		if _, err := load(envLookup(map[string]string{EnvConfigFile: path, EnvDatabaseURL: "postgres://x"})); err == nil {
+			t.Fatalf("%s: expected error", path)
+		}
+	}
+}
+
+func TestLoad_ValidationErrors(t *testing.T) {
+	_, err := load(envLookup(map[string]string{
+		EnvDatabaseMaxConns: "0",
+		EnvPort:             "70000",
+		EnvAllowedOrigins:   "*,https://ok.example.com,https://*.ok.example.com,https://bad.example.com/path,https://a.*.example.com",
+		EnvCORSMaxAge:       "-1s",
+		EnvBcryptCost:       "2",
+		EnvSessionTTL:       "10s",
+	}))
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"DATABASE_URL is required",
+		"DATABASE_MAX_CONNS must be at least 1",
+		"PORT: port must be between 1 and 65535",
+		`CORS_ALLOWED_ORIGINS: origin "*"`,
+		`origin "https://bad.example.com/path" must not contain a path`,
+		`origin "https://a.*.example.com" may only use a wildcard as the leftmost label`,
+		"CORS_MAX_AGE must not be negative",
+		"BCRYPT_COST must be between 4 and 31",
+		"SESSION_TTL must be at least 1m0s",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in error, got:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "ok.example.com") {
+		t.Fatalf("valid origin should not be reported: %s", msg)
+	}
+}
+
+func TestLoad_ParseErrors(t *testing.T) {
+	_, err := load(envLookup(map[string]string{
+		EnvDatabaseURL:  "postgres://x",
+		EnvWriteTimeout: "15",
+		EnvBcryptCost:   "high",
+		EnvAutoMigrate:  "maybe",
+	}))
+	if err == nil {
+		t.Fatalf("expected parse error")
+	}
+	for _, want := range []string{EnvWriteTimeout, EnvBcryptCost, EnvAutoMigrate} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %s in error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_Mail(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{
+		EnvDatabaseURL:      "postgres://localhost/app",
+		EnvMailTransport:    "SMTP",
+		EnvMailFrom:         "no-reply@example.com",
+		EnvSMTPHost:         "smtp.example.com",
+		EnvSMTPPort:         "2525",
+		EnvSMTPUsername:     "app",
+		EnvSMTPPassword:     " secret ",
+		EnvPasswordResetURL: "https://example.com/reset-password",
+		EnvPasswordResetTTL: "30m",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Mail{Transport: MailTransportSMTP, From: "no-reply@example.com", SMTP: SMTP{Host: "smtp.example.com", Port: 2525, Username: "app", Password: " secret "}}
+	if cfg.Mail != want {
+		t.Fatalf("unexpected mail config: %+v", cfg.Mail)
+	}
+	if cfg.Auth.PasswordResetURL != "https://example.com/reset-password" || cfg.Auth.PasswordResetTTL != 30*time.Minute {
+		t.Fatalf("unexpected reset config: %+v", cfg.Auth)
+	}
+
+	_, err = load(envLookup(map[string]string{
+		EnvDatabaseURL:      "postgres://localhost/app",
+		EnvMailTransport:    "smtp",
+		EnvMailFrom:         "nobody",
+		EnvPasswordResetURL: "/reset",
+	}))
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{"SMTP_HOST is required", "MAIL_FROM must be a valid email address", "PASSWORD_RESET_URL must be an absolute"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoad_EmailVerification(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Auth.RequireVerifiedEmailForLogin || cfg.Auth.RequireVerifiedEmailForAdmin || cfg.Auth.EmailVerifyTTL != 24*time.Hour {
+		t.Fatalf("unexpected defaults: %+v", cfg.Auth)
+	}
+
+	cfg, err = load(envLookup(map[string]string{
+		EnvDatabaseURL:                  "postgres://localhost/app",
+		EnvEmailVerifyURL:               "https://example.com/verify",
+		EnvEmailVerifyTTL:               "2h",
+		EnvRequireVerifiedEmailForLogin: "true",
+		EnvRequireVerifiedEmailForAdmin: "1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Auth.EmailVerifyURL != "https://example.com/verify" || cfg.Auth.EmailVerifyTTL != 2*time.Hour ||
+		!cfg.Auth.RequireVerifiedEmailForLogin || !cfg.Auth.RequireVerifiedEmailForAdmin {
+		t.Fatalf("unexpected verification config: %+v", cfg.Auth)
+	}
+
+	_, err = load(envLookup(map[string]string{
+		EnvDatabaseURL:                  "postgres://localhost/app",
+		EnvEmailVerifyURL:               "verify",
+		EnvRequireVerifiedEmailForLogin: "sometimes",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "REQUIRE_VERIFIED_EMAIL_FOR_LOGIN: invalid boolean") {
+		t.Fatalf("expected invalid boolean error, got %v", err)
+	}
+}
+
+func TestLoad_PasswordPolicy(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{
+		EnvDatabaseURL:        "postgres://localhost/app",
+		EnvPasswordMinLength:  "12",
+		EnvPasswordMinClasses: "3",
+		EnvPasswordDenyCommon: "false",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	policy, err := cfg.Auth.PasswordPolicy()
+	if err != nil || policy.MinLength() != 12 || policy.MinClasses() != 3 || policy.DeniesCommon() {
+		t.Fatalf("unexpected policy: %+v (%v)", policy, err)
+	}
+
+	_, err = load(envLookup(map[string]string{
+		EnvDatabaseURL:        "postgres://localhost/app",
+		EnvPasswordMinLength:  "100",
+		EnvPasswordMinClasses: "5",
+	}))
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{"PASSWORD_MIN_LENGTH must be between 1 and 72", "PASSWORD_MIN_CLASSES must be between 1 and 4"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoad_PasswordHasher(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hasher, err := cfg.Auth.PasswordHasher()
+	if err != nil {
+		t.Fatalf("hasher error: %v", err)
+	}
+	if _, ok := hasher.(domain.Argon2idHasher); !ok {
+		t.Fatalf("expected argon2id by default, got %T", hasher)
+	}
+
+	cfg, err = load(envLookup(map[string]string{
+		EnvDatabaseURL:  "postgres://localhost/app",
+		EnvPasswordHash: "bcrypt",
+		EnvBcryptCost:   "11",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hasher, _ = cfg.Auth.PasswordHasher()
+	if bcryptHasher, ok := hasher.(domain.BcryptHasher); !ok || bcryptHasher.Cost() != 11 {
+		t.Fatalf("expected bcrypt cost 11, got %#v", hasher)
+	}
+
+	_, err = load(envLookup(map[string]string{
+		EnvDatabaseURL:       "postgres://localhost/app",
+		EnvPasswordHash:      "md5",
+		EnvArgon2Parallelism: "4",
+		EnvArgon2Memory:      "16",
+		EnvArgon2Iterations:  "0",
+	}))
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{"PASSWORD_HASH must be", "ARGON2_ITERATIONS must be between 1", "ARGON2_MEMORY must be at least 32 KiB"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoad_HueWordOrder(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mode, err := cfg.Hue.WordOrderMode(); err != nil || mode != domain.WordOrderShuffle {
+		t.Fatalf("expected shuffle by default, got %q (%v)", mode, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"hue": {"word_order": "latin_square"}}`), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	cfg, err = load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app", EnvConfigFile: path}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mode, _ := cfg.Hue.WordOrderMode(); mode != domain.WordOrderLatinSquare {
+		t.Fatalf("expected latin_square from the file, got %q", mode)
+	}
+
+	_, err = load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app", EnvHueWordOrder: "alphabetical"}))
+	if err == nil || !strings.Contains(err.Error(), "HUE_WORD_ORDER must be") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestLoad_HueChallengeSecret(t *testing.T) {
+	secret := strings.Repeat("s", domain.MinHueChallengeSecretBytes)
+	cfg, err := load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app", EnvHueChallengeSecret: secret}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	signer, err := cfg.Hue.ChallengeSigner()
+	if err != nil {
+		t.Fatalf("signer error: %v", err)
+	}
+	// 同じ鍵で組み立てた署名器は互いのチャレンジを受け付ける。
+	_, token := signer.Issue(time.Now())
+	again, _ := cfg.Hue.ChallengeSigner()
+	if _, err := again.Verify(token, time.Now()); err != nil {
+		t.Fatalf("expected the configured secret to be shared, got %v", err)
+	}
+
+	generated, err := Default().Hue.ChallengeSigner()
+	if err != nil {
+		t.Fatalf("expected a secret to be generated, got %v", err)
+	}
+	if _, err := generated.Verify(token, time.Now()); err == nil {
+		t.Fatal("expected a generated secret to differ from the configured one")
+	}
+
+	_, err = load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app", EnvHueChallengeSecret: "short-secret"}))
+	if err == nil || !strings.Contains(err.Error(), "HUE_CHALLENGE_SECRET must be at least") || strings.Contains(err.Error(), "short-secret") {
+		t.Fatalf("expected validation error without the secret, got %v", err)
+	}
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	cfg, err := load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app", EnvTrustedProxies: "10.0.0.2, 172.16.0.0/12"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Proxy.Trusted) != 2 || cfg.Proxy.Trusted[1] != "172.16.0.0/12" {
+		t.Fatalf("unexpected proxies: %v", cfg.Proxy.Trusted)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"proxy": {"trusted": ["10.0.0.3"]}}`), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	cfg, err = load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app", EnvConfigFile: path}))
+	if err != nil || len(cfg.Proxy.Trusted) != 1 || cfg.Proxy.Trusted[0] != "10.0.0.3" {
+		t.Fatalf("expected proxies from the file, got %v (%v)", cfg.Proxy.Trusted, err)
+	}
+
+	_, err = load(envLookup(map[string]string{EnvDatabaseURL: "postgres://localhost/app", EnvTrustedProxies: "nginx,10.0.0.0/40"}))
+	if err == nil || !strings.Contains(err.Error(), `proxy "nginx"`) || !strings.Contains(err.Error(), `proxy "10.0.0.0/40"`) {
+		t.Fatalf("expected validation errors for both proxies, got %v", err)
+	}
+}
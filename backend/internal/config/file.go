@@ -0,0 +1,190 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// fileConfig は設定ファイルの JSON 表現。省略した項目は既定値のまま残す。
+//
+//	{
+//	  "http": {"addr": ":8080", "write_timeout": "30s"},
+//	  "database": {"url": "postgres://...", "max_conns": 20},
+//	  "auth": {"password_hash": "argon2id", "argon2_memory": 65536, "session_ttl": "1h", "password_reset_ttl": "30m", "password_reset_url": "https://example.com/reset-password"},
+//	  "cors": {"allowed_origins": ["https://example.com", "https://*.example.com"], "max_age": "10m"},
+//	  "mail": {"transport": "smtp", "from": "no-reply@example.com", "smtp": {"host": "smtp.example.com", "port": 587, "username": "app"}},
+//	  "hue": {"word_order": "latin_square", "challenge_secret": "..."},
+//	  "auto_migrate": true
+//	}
+type fileConfig struct {
+	HTTP struct {
+		Addr              *string   `json:"addr"`
+		ReadHeaderTimeout *duration `json:"read_header_timeout"`
+		WriteTimeout      *duration `json:"write_timeout"`
+		IdleTimeout       *duration `json:"idle_timeout"`
+		ShutdownTimeout   *duration `json:"shutdown_timeout"`
+	} `json:"http"`
+	Database struct {
+		URL      *string `json:"url"`
+		MaxConns *int32  `json:"max_conns"`
+		MinConns *int32  `json:"min_conns"`
+	} `json:"database"`
+	Auth struct {
+		PasswordHash                 *string   `json:"password_hash"`
+		BcryptCost                   *int      `json:"bcrypt_cost"`
+		Argon2Memory                 *int      `json:"argon2_memory"`
+		Argon2Iterations             *int      `json:"argon2_iterations"`
+		Argon2Parallelism            *int      `json:"argon2_parallelism"`
+		SessionTTL                   *duration `json:"session_ttl"`
+		PasswordResetTTL             *duration `json:"password_reset_ttl"`
+		PasswordResetURL             *string   `json:"password_reset_url"`
+		EmailVerifyTTL               *duration `json:"email_verify_ttl"`
+		EmailVerifyURL               *string   `json:"email_verify_url"`
+		RequireVerifiedEmailForLogin *bool     `json:"require_verified_email_for_login"`
+		RequireVerifiedEmailForAdmin *bool     `json:"require_verified_email_for_admin"`
+		PasswordMinLength            *int      `json:"password_min_length"`
+		PasswordMinClasses           *int      `json:"password_min_classes"`
+		PasswordDenyCommon           *bool     `json:"password_deny_common"`
+	} `json:"auth"`
+	CORS struct {
+		AllowedOrigins []string  `json:"allowed_origins"`
+		MaxAge         *duration `json:"max_age"`
+	} `json:"cors"`
+	Proxy struct {
+		Trusted []string `json:"trusted"`
+	} `json:"proxy"`
+	Mail struct {
+		Transport *string `json:"transport"`
+		From      *string `json:"from"`
+		File      *string `json:"file"`
+		SMTP      struct {
+			Host     *string `json:"host"`
+			Port     *int    `json:"port"`
+			Username *string `json:"username"`
+			Password *string `json:"password"`
+		} `json:"smtp"`
+	} `json:"mail"`
+	Hue struct {
+		WordOrder       *string `json:"word_order"`
+		ChallengeSecret *string `json:"challenge_secret"`
+	} `json:"hue"`
+	AutoMigrate *bool `json:"auto_migrate"`
+}
+
+// duration は "15s" のような time.ParseDuration 形式の文字列を受け付ける。
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"15s\", got %s", data)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (use e.g. \"15s\" or \"30m\")", s)
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+func applyFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	var file fileConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	setString(&cfg.HTTP.Addr, file.HTTP.Addr)
+	setDuration(&cfg.HTTP.ReadHeaderTimeout, file.HTTP.ReadHeaderTimeout)
+	setDuration(&cfg.HTTP.WriteTimeout, file.HTTP.WriteTimeout)
+	setDuration(&cfg.HTTP.IdleTimeout, file.HTTP.IdleTimeout)
+	setDuration(&cfg.HTTP.ShutdownTimeout, file.HTTP.ShutdownTimeout)
+
+	setString(&cfg.Database.URL, file.Database.URL)
+	if file.Database.MaxConns != nil {
+		cfg.Database.MaxConns = *file.Database.MaxConns
+	}
+	if file.Database.MinConns != nil {
+		cfg.Database.MinConns = *file.Database.MinConns
+	}
+
+	setString(&cfg.Auth.PasswordHash, file.Auth.PasswordHash)
+	if file.Auth.BcryptCost != nil {
+		cfg.Auth.BcryptCost = *file.Auth.BcryptCost
+	}
+	if file.Auth.Argon2Memory != nil {
+		cfg.Auth.Argon2Memory = *file.Auth.Argon2Memory
+	}
+	if file.Auth.Argon2Iterations != nil {
+		cfg.Auth.Argon2Iterations = *file.Auth.Argon2Iterations
+	}
+	if file.Auth.Argon2Parallelism != nil {
+		cfg.Auth.Argon2Parallelism = *file.Auth.Argon2Parallelism
+	}
+	setDuration(&cfg.Auth.SessionTTL, file.Auth.SessionTTL)
+	setDuration(&cfg.Auth.PasswordResetTTL, file.Auth.PasswordResetTTL)
+	setString(&cfg.Auth.PasswordResetURL, file.Auth.PasswordResetURL)
+	setDuration(&cfg.Auth.EmailVerifyTTL, file.Auth.EmailVerifyTTL)
+	setString(&cfg.Auth.EmailVerifyURL, file.Auth.EmailVerifyURL)
+	if file.Auth.RequireVerifiedEmailForLogin != nil {
+		cfg.Auth.RequireVerifiedEmailForLogin = *file.Auth.RequireVerifiedEmailForLogin
+	}
+	if file.Auth.RequireVerifiedEmailForAdmin != nil {
+		cfg.Auth.RequireVerifiedEmailForAdmin = *file.Auth.RequireVerifiedEmailForAdmin
+	}
+	if file.Auth.PasswordMinLength != nil {
+		cfg.Auth.PasswordMinLength = *file.Auth.PasswordMinLength
+	}
+	if file.Auth.PasswordMinClasses != nil {
+		cfg.Auth.PasswordMinClasses = *file.Auth.PasswordMinClasses
+	}
+	if file.Auth.PasswordDenyCommon != nil {
+		cfg.Auth.PasswordDenyCommon = *file.Auth.PasswordDenyCommon
+	}
+
+	if file.CORS.AllowedOrigins != nil {
+		cfg.CORS.AllowedOrigins = file.CORS.AllowedOrigins
+	}
+	setDuration(&cfg.CORS.MaxAge, file.CORS.MaxAge)
+
+	if file.Proxy.Trusted != nil {
+		cfg.Proxy.Trusted = file.Proxy.Trusted
+	}
+
+	setString(&cfg.Mail.Transport, file.Mail.Transport)
+	setString(&cfg.Mail.From, file.Mail.From)
+	setString(&cfg.Mail.File, file.Mail.File)
+	setString(&cfg.Mail.SMTP.Host, file.Mail.SMTP.Host)
+	if file.Mail.SMTP.Port != nil {
+		cfg.Mail.SMTP.Port = *file.Mail.SMTP.Port
+	}
+	setString(&cfg.Mail.SMTP.Username, file.Mail.SMTP.Username)
+	setString(&cfg.Mail.SMTP.Password, file.Mail.SMTP.Password)
+	setString(&cfg.Hue.WordOrder, file.Hue.WordOrder)
+	setString(&cfg.Hue.ChallengeSecret, file.Hue.ChallengeSecret)
+	if file.AutoMigrate != nil {
+		cfg.AutoMigrate = *file.AutoMigrate
+	}
+	return nil
+}
+
+func setString(dst *string, src *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setDuration(dst *time.Duration, src *duration) {
+	if src != nil {
+		*dst = time.Duration(*src)
+	}
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminAction は監査ログに残す管理操作の種類。
+type AdminAction string
+
+const (
+	AdminActionChangeRole     AdminAction = "change_role"
+	AdminActionDisableUser    AdminAction = "disable_user"
+	AdminActionEnableUser     AdminAction = "enable_user"
+	AdminActionRevokeSessions AdminAction = "revoke_sessions"
+	AdminActionDeleteUser     AdminAction = "delete_user"
+
+	// 単語セットの操作では、監査ログの対象に単語セットの ID を記録する。
+	AdminActionCreateWordSet   AdminAction = "create_word_set"
+	AdminActionUpdateWordSet   AdminAction = "update_word_set"
+	AdminActionActivateWordSet AdminAction = "activate_word_set"
+	AdminActionDeleteWordSet   AdminAction = "delete_word_set"
+
+	// パレットの操作では、監査ログの対象にパレットの ID を記録する。
+	AdminActionCreatePalette   AdminAction = "create_palette"
+	AdminActionActivatePalette AdminAction = "activate_palette"
+)
+
+func (a AdminAction) valid() bool {
+	switch a {
+	case AdminActionChangeRole, AdminActionDisableUser, AdminActionEnableUser, AdminActionRevokeSessions, AdminActionDeleteUser,
+		AdminActionCreateWordSet, AdminActionUpdateWordSet, AdminActionActivateWordSet, AdminActionDeleteWordSet,
+		AdminActionCreatePalette, AdminActionActivatePalette:
+		return true
+	default:
+		return false
+	}
+}
+
+// AdminAuditEntry は誰が誰に何をしたかの記録。対象ユーザーが削除されても残るよう、対象は ID だけを持つ。
+type AdminAuditEntry struct {
+	id        uuid.UUID
+	actorID   uuid.UUID
+	targetID  uuid.UUID
+	action    AdminAction
+	detail    string
+	createdAt time.Time
+}
+
+// NewAdminAuditEntry は操作時に新しい記録を作る。detail は変更前後の値など人が読むための補足。
+func NewAdminAuditEntry(actorID, targetID uuid.UUID, action AdminAction, detail string, now time.Time) (AdminAuditEntry, error) {
+	return NewAdminAuditEntryFromPersistence(uuid.New(), actorID, targetID, action, detail, now)
+}
+
+// NewAdminAuditEntryFromPersistence は永続化済みの記録を再構築する。
+func NewAdminAuditEntryFromPersistence(id, actorID, targetID uuid.UUID, action AdminAction, detail string, createdAt time.Time) (AdminAuditEntry, error) {
+	if id == uuid.Nil || actorID == uuid.Nil || targetID == uuid.Nil || !action.valid() || createdAt.IsZero() {
+		return AdminAuditEntry{}, ErrInvalidAuditEntry
+	}
+
+	return AdminAuditEntry{
+		id:        id,
+		actorID:   actorID,
+		targetID:  targetID,
+		action:    action,
+		detail:    detail,
+		createdAt: createdAt.UTC(),
+	}, nil
+}
+
+func (e AdminAuditEntry) ID() uuid.UUID {
+	return e.id
+}
+
+func (e AdminAuditEntry) ActorID() uuid.UUID {
+	return e.actorID
+}
+
+func (e AdminAuditEntry) TargetID() uuid.UUID {
+	return e.targetID
+}
+
+func (e AdminAuditEntry) Action() AdminAction {
+	return e.action
+}
+
+func (e AdminAuditEntry) Detail() string {
+	return e.detail
+}
+
+func (e AdminAuditEntry) CreatedAt() time.Time {
+	return e.createdAt
+}
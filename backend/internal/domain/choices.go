@@ -4,33 +4,15 @@ import "strings"
 
 type HueWord string
 
+// HueColor はパレットの色 ID。選べる色かどうかは回答したパレットの版で決まる (Palette.Paint)。
 type HueColor string
 
-var allowedHueColors = map[HueColor]struct{}{
-	"黒":    {},
-	"灰色":   {},
-	"白":    {},
-	"ピンク":  {},
-	"赤":    {},
-	"オレンジ": {},
-	"黄色":   {},
-	"緑":    {},
-	"青":    {},
-	"紫":    {},
-	"茶":    {},
-}
-
-func (c HueColor) valid() bool {
-	_, ok := allowedHueColors[c]
-	return ok
-}
-
 // HueChoices は単語ごとの色割り当てを保持し、空や空白キーを許可しない。
 type HueChoices struct {
 	values map[HueWord]HueColor
 }
 
-// NewHueChoices 空が含まれていれば ErrInvalidChoice を返す。
+// NewHueChoices 空が含まれていれば ErrInvalidChoice を返す。色がパレットにあるかは確かめない。
 func NewHueChoices(raw map[string]string) (HueChoices, error) {
 	if len(raw) == 0 {
 		return HueChoices{}, ErrInvalidChoice
@@ -40,7 +22,7 @@ func NewHueChoices(raw map[string]string) (HueChoices, error) {
 	for word, color := range raw {
 		w := HueWord(strings.TrimSpace(word))
 		c := HueColor(strings.TrimSpace(color))
-		if w == "" || c == "" || !c.valid() {
+		if w == "" || c == "" {
 			return HueChoices{}, ErrInvalidChoice
 		}
 
@@ -1,31 +1,21 @@
 package domain
 
-import (
-	"golang.org/x/crypto/bcrypt"
-	"strings"
-)
+import "strings"
 
 type AdminCredential struct {
-	name           Name
-	password       string
-	hashedPassword string
+	name     Name
+	password string
 }
 
+// NewAdminCredential は rawPassword を入力されたとおりに保持する。空白だけなら ErrInvalidCredential を返す。
 func NewAdminCredential(name Name, rawPassword string) (AdminCredential, error) {
-	trimmed := strings.TrimSpace(rawPassword)
-	if trimmed == "" {
-		return AdminCredential{}, ErrInvalidCredential
-	}
-
-	hashed, err := bcrypt.GenerateFromPassword([]byte(trimmed), bcrypt.DefaultCost)
-	if err != nil {
+	if strings.TrimSpace(rawPassword) == "" {
 		return AdminCredential{}, ErrInvalidCredential
 	}
 
 	return AdminCredential{
-		name:           name,
-		password:       trimmed,
-		hashedPassword: string(hashed),
+		name:     name,
+		password: rawPassword,
 	}, nil
 }
 
@@ -36,7 +26,3 @@ func (c AdminCredential) Name() Name {
 func (c AdminCredential) Password() string {
 	return c.password
 }
-
-func (c AdminCredential) HashedPassword() string {
-	return c.hashedPassword
-}
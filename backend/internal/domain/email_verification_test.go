@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEmailVerification_Verify(t *testing.T) {
+	token, _ := NewOneTimeToken()
+	other, _ := NewOneTimeToken()
+	issued := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	verification, err := NewEmailVerification(uuid.New(), token, issued, DefaultEmailVerificationTTL)
+	if err != nil {
+		t.Fatalf("verification error: %v", err)
+	}
+	if err := verification.Verify(token, issued.Add(time.Hour)); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if err := verification.Verify(other, issued.Add(time.Hour)); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Fatalf("expected mismatch to fail, got %v", err)
+	}
+	if err := verification.Verify(token, issued.Add(DefaultEmailVerificationTTL)); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Fatalf("expected expired token to fail, got %v", err)
+	}
+
+	if _, err := NewEmailVerification(uuid.New(), token, issued, 0); !errors.Is(err, ErrInvalidEmailVerification) {
+		t.Fatalf("expected ErrInvalidEmailVerification, got %v", err)
+	}
+}
+
+func TestEmailVerificationPolicy(t *testing.T) {
+	name, _ := NewName("alice")
+	email, _ := NewEmail("alice@example.com")
+	hashed, _ := NewHashedPassword("hashed")
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	user, err := NewUser(name, email, hashed, UserRoleUser, now)
+	if err != nil {
+		t.Fatalf("user error: %v", err)
+	}
+	if user.IsEmailVerified() {
+		t.Fatalf("expected new user to be unverified")
+	}
+
+	var none EmailVerificationPolicy
+	if err := none.CheckLogin(user); err != nil {
+		t.Fatalf("zero policy should allow login, got %v", err)
+	}
+	if err := none.CheckRole(user, UserRoleAdmin); err != nil {
+		t.Fatalf("zero policy should allow promotion, got %v", err)
+	}
+
+	strict := NewEmailVerificationPolicy(true, true)
+	if err := strict.CheckLogin(user); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("expected ErrEmailNotVerified on login, got %v", err)
+	}
+	if err := strict.CheckRole(user, UserRoleAdmin); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("expected ErrEmailNotVerified on promotion, got %v", err)
+	}
+	if err := strict.CheckRole(user, UserRoleUser); err != nil {
+		t.Fatalf("demotion should not require verification, got %v", err)
+	}
+
+	verified := user.VerifyEmail(now.Add(time.Minute))
+	if at, ok := verified.EmailVerifiedAt(); !ok || !at.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected verified at %v, got %v (%v)", now.Add(time.Minute), at, ok)
+	}
+	if err := strict.CheckLogin(verified); err != nil {
+		t.Fatalf("verified user should log in, got %v", err)
+	}
+}
@@ -3,21 +3,55 @@ package domain
 import "errors"
 
 var (
-	ErrEmptyName           = errors.New("domain: empty name")
-	ErrInvalidChoice       = errors.New("domain: invalid choice")
-	ErrInvalidRange        = errors.New("domain: invalid record range")
-	ErrInvalidToken        = errors.New("domain: invalid token")
-	ErrExpiredToken        = errors.New("domain: expired token")
-	ErrInvalidCredential   = errors.New("domain: invalid credential")
-	ErrInvalidPassword     = errors.New("domain: invalid password")
-	ErrInvalidSessionToken = errors.New("domain: invalid login session token")
-	ErrInvalidLoginSession = errors.New("domain: invalid login session")
-	ErrInvalidSessionData  = errors.New("domain: invalid session data")
-	ErrInvalidEmail        = errors.New("domain: invalid email")
-	ErrInvalidPasswordHash = errors.New("domain: invalid password hash")
-	ErrInvalidUserRole     = errors.New("domain: invalid user role")
-	ErrInvalidUser         = errors.New("domain: invalid user")
-	ErrDuplicateUsername   = errors.New("domain: duplicate username")
-	ErrDuplicateEmail      = errors.New("domain: duplicate email")
-	ErrInvalidAPIError     = errors.New("domain: invalid api error")
+	ErrEmptyName                 = errors.New("domain: empty name")
+	ErrInvalidChoice             = errors.New("domain: invalid choice")
+	ErrInvalidRange              = errors.New("domain: invalid record range")
+	ErrInvalidCursor             = errors.New("domain: invalid record cursor")
+	ErrInvalidFilter             = errors.New("domain: invalid record filter")
+	ErrInvalidToken              = errors.New("domain: invalid token")
+	ErrExpiredToken              = errors.New("domain: expired token")
+	ErrInvalidCredential         = errors.New("domain: invalid credential")
+	ErrInvalidPassword           = errors.New("domain: invalid password")
+	ErrInvalidPasswordPolicy     = errors.New("domain: invalid password policy")
+	ErrInvalidSessionToken       = errors.New("domain: invalid login session token")
+	ErrInvalidLoginSession       = errors.New("domain: invalid login session")
+	ErrInvalidSessionData        = errors.New("domain: invalid session data")
+	ErrInvalidEmail              = errors.New("domain: invalid email")
+	ErrInvalidPasswordHash       = errors.New("domain: invalid password hash")
+	ErrInvalidPasswordHasher     = errors.New("domain: invalid password hasher")
+	ErrPasswordMismatch          = errors.New("domain: password mismatch")
+	ErrInvalidUserRole           = errors.New("domain: invalid user role")
+	ErrInvalidUser               = errors.New("domain: invalid user")
+	ErrDuplicateUsername         = errors.New("domain: duplicate username")
+	ErrDuplicateEmail            = errors.New("domain: duplicate email")
+	ErrInvalidAPIError           = errors.New("domain: invalid api error")
+	ErrRateLimited               = errors.New("domain: rate limited")
+	ErrUserDisabled              = errors.New("domain: user disabled")
+	ErrSelfAdministration        = errors.New("domain: cannot administer own account")
+	ErrUserNotFound              = errors.New("domain: user not found")
+	ErrInvalidUserQuery          = errors.New("domain: invalid user query")
+	ErrInvalidAuditEntry         = errors.New("domain: invalid audit entry")
+	ErrInvalidResetToken         = errors.New("domain: invalid password reset token")
+	ErrInvalidPasswordReset      = errors.New("domain: invalid password reset")
+	ErrInvalidVerificationToken  = errors.New("domain: invalid email verification token")
+	ErrInvalidEmailVerification  = errors.New("domain: invalid email verification")
+	ErrEmailNotVerified          = errors.New("domain: email not verified")
+	ErrEmailAlreadyVerified      = errors.New("domain: email already verified")
+	ErrInvalidWordSet            = errors.New("domain: invalid word set")
+	ErrWordSetNotFound           = errors.New("domain: word set not found")
+	ErrWordSetInUse              = errors.New("domain: word set in use")
+	ErrWordSetActive             = errors.New("domain: word set is active")
+	ErrInvalidPalette            = errors.New("domain: invalid palette")
+	ErrPaletteNotFound           = errors.New("domain: palette not found")
+	ErrInvalidHueSession         = errors.New("domain: invalid hue session")
+	ErrHueSessionNotFound        = errors.New("domain: hue session not found")
+	ErrHueSessionClosed          = errors.New("domain: hue session closed")
+	ErrInvalidLatency            = errors.New("domain: invalid answer latency")
+	ErrInvalidWordOrder          = errors.New("domain: invalid word order")
+	ErrInvalidHueChallenge       = errors.New("domain: invalid hue challenge")
+	ErrInvalidHueChallengeSecret = errors.New("domain: invalid hue challenge secret")
+	ErrHueChallengeUsed          = errors.New("domain: hue challenge already used")
+	ErrInvalidIdempotencyKey     = errors.New("domain: invalid idempotency key")
+	ErrDuplicateIdempotencyKey   = errors.New("domain: duplicate idempotency key")
+	ErrIdempotencyKeyReused      = errors.New("domain: idempotency key reused for a different submission")
 )
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NameMatch は回答者名の照合方法。
+type NameMatch string
+
+const (
+	NameMatchPrefix   NameMatch = "prefix"
+	NameMatchContains NameMatch = "contains"
+)
+
+func (m NameMatch) valid() bool {
+	return m == NameMatchPrefix || m == NameMatchContains
+}
+
+// RecordFilter はレコード一覧を絞り込む条件。ゼロ値は全件に一致する。
+// 作成日時は [createdFrom, createdTo) の半開区間で、どちらも省略できる。
+type RecordFilter struct {
+	createdFrom time.Time
+	createdTo   time.Time
+	name        string
+	nameMatch   NameMatch
+	choices     map[HueWord]HueColor
+	userID      uuid.UUID
+}
+
+// NewRecordFilter は name が空なら名前で絞り込まない。nameMatch の省略時は部分一致。
+// choices は「この単語にこの色を選んだ」条件で、すべてを満たすレコードだけに一致する。
+// 区間が逆転している、照合方法が不正、単語や色が空の場合は ErrInvalidFilter を返す。
+// 色は表示名でも指定できるため、使う前に ResolveColors で色 ID にそろえる。
+func NewRecordFilter(createdFrom, createdTo time.Time, name string, nameMatch NameMatch, choices map[string]string) (RecordFilter, error) {
+	if !createdFrom.IsZero() && !createdTo.IsZero() && !createdFrom.Before(createdTo) {
+		return RecordFilter{}, ErrInvalidFilter
+	}
+
+	if nameMatch == "" {
+		nameMatch = NameMatchContains
+	}
+	if !nameMatch.valid() {
+		return RecordFilter{}, ErrInvalidFilter
+	}
+
+	filter := RecordFilter{
+		createdFrom: createdFrom,
+		createdTo:   createdTo,
+		name:        strings.TrimSpace(name),
+		nameMatch:   nameMatch,
+	}
+
+	if len(choices) > 0 {
+		parsed, err := NewHueChoices(choices)
+		if err != nil {
+			return RecordFilter{}, ErrInvalidFilter
+		}
+		filter.choices = parsed.values
+	}
+
+	return filter, nil
+}
+
+func (f RecordFilter) CreatedFrom() time.Time {
+	return f.createdFrom
+}
+
+func (f RecordFilter) CreatedTo() time.Time {
+	return f.createdTo
+}
+
+func (f RecordFilter) Name() string {
+	return f.name
+}
+
+func (f RecordFilter) NameMatch() NameMatch {
+	return f.nameMatch
+}
+
+// Choices は単語と色の条件を返す。条件が無ければ空。
+func (f RecordFilter) Choices() map[string]string {
+	copied := make(map[string]string, len(f.choices))
+	for w, c := range f.choices {
+		copied[string(w)] = string(c)
+	}
+	return copied
+}
+
+// ResolveColors は choices の色を palette の色 ID にそろえたコピーを返す。palette に無い色があれば ErrInvalidFilter を返す。
+func (f RecordFilter) ResolveColors(palette Palette) (RecordFilter, error) {
+	if len(f.choices) == 0 {
+		return f, nil
+	}
+
+	resolved := make(map[HueWord]HueColor, len(f.choices))
+	for w, c := range f.choices {
+		id, ok := palette.Resolve(string(c))
+		if !ok {
+			return RecordFilter{}, ErrInvalidFilter
+		}
+		resolved[w] = id
+	}
+	f.choices = resolved
+	return f, nil
+}
+
+// ForUser は userID のユーザーが回答したレコードに限定したコピーを返す。
+func (f RecordFilter) ForUser(userID uuid.UUID) RecordFilter {
+	f.userID = userID
+	return f
+}
+
+// UserID は回答者で絞り込む場合にそのユーザーを返す。
+func (f RecordFilter) UserID() (uuid.UUID, bool) {
+	return f.userID, f.userID != uuid.Nil
+}
+
+// Matches は record が条件をすべて満たすかを返す。DB を使わない実装やテストのための判定。
+func (f RecordFilter) Matches(record HueRecord) bool {
+	if !f.createdFrom.IsZero() && record.CreatedAt().Before(f.createdFrom) {
+		return false
+	}
+	if !f.createdTo.IsZero() && !record.CreatedAt().Before(f.createdTo) {
+		return false
+	}
+
+	if f.name != "" {
+		name := record.Name().String()
+		switch f.nameMatch {
+		case NameMatchPrefix:
+			if !strings.HasPrefix(name, f.name) {
+				return false
+			}
+		default:
+			if !strings.Contains(name, f.name) {
+				return false
+			}
+		}
+	}
+
+	if f.userID != uuid.Nil {
+		if owner, ok := record.UserID(); !ok || owner != f.userID {
+			return false
+		}
+	}
+
+	choices := record.ChoiceMap()
+	for w, c := range f.choices {
+		if choices[string(w)] != string(c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewRecordFilter_Invalid(t *testing.T) {
+	from := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		from, to time.Time
+		match    NameMatch
+		choices  map[string]string
+	}{
+		{"reversed window", from, from.Add(-time.Hour), "", nil},
+		{"empty window", from, from, "", nil},
+		{"unknown match", time.Time{}, time.Time{}, "regex", nil},
+		{"blank color", time.Time{}, time.Time{}, "", map[string]string{"孤独": " "}},
+		{"blank word", time.Time{}, time.Time{}, "", map[string]string{" ": "紫"}},
+	}
+	for _, tc := range cases {
+		if _, err := NewRecordFilter(tc.from, tc.to, "", tc.match, tc.choices); !errors.Is(err, ErrInvalidFilter) {
+			t.Fatalf("%s: expected ErrInvalidFilter, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestRecordFilter_Matches(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := buildPersistedHueRecord(t, createdAt) // Tester, 夜=黒
+
+	cases := []struct {
+		name     string
+		from, to time.Time
+		query    string
+		match    NameMatch
+		choices  map[string]string
+		expected bool
+	}{
+		{"zero value", time.Time{}, time.Time{}, "", "", nil, true},
+		{"from is inclusive", createdAt, time.Time{}, "", "", nil, true},
+		{"to is exclusive", time.Time{}, createdAt, "", "", nil, false},
+		{"prefix", time.Time{}, time.Time{}, "Tes", NameMatchPrefix, nil, true},
+		{"prefix miss", time.Time{}, time.Time{}, "ster", NameMatchPrefix, nil, false},
+		{"contains", time.Time{}, time.Time{}, "ster", NameMatchContains, nil, true},
+		{"choice hit", time.Time{}, time.Time{}, "", "", map[string]string{"夜": "黒"}, true},
+		{"choice miss", time.Time{}, time.Time{}, "", "", map[string]string{"夜": "白"}, false},
+		{"unanswered word", time.Time{}, time.Time{}, "", "", map[string]string{"孤独": "紫"}, false},
+	}
+	for _, tc := range cases {
+		filter, err := NewRecordFilter(tc.from, tc.to, tc.query, tc.match, tc.choices)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if got := filter.Matches(record); got != tc.expected {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestRecordFilter_ResolveColors(t *testing.T) {
+	palette := buildTestPalette(t, 1)
+
+	filter, _ := NewRecordFilter(time.Time{}, time.Time{}, "", "", map[string]string{"夜": "黒", "海": "blue"})
+	resolved, err := filter.ResolveColors(palette)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resolved.Choices(); got["夜"] != "black" || got["海"] != "blue" {
+		t.Fatalf("expected color ids, got %v", got)
+	}
+
+	unknown, _ := NewRecordFilter(time.Time{}, time.Time{}, "", "", map[string]string{"孤独": "金"})
+	if _, err := unknown.ResolveColors(palette); !errors.Is(err, ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter for an unknown color, got %v", err)
+	}
+}
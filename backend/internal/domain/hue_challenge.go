@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HueChallengeTTL は発行したチャレンジで save-result を送れる期間。テストを始めてから答え終えるまでに十分な長さにする。
+// MinHueChallengeSecretBytes は署名鍵の最短の長さ。
+const (
+	HueChallengeTTL            = 2 * time.Hour
+	MinHueChallengeSecretBytes = 32
+)
+
+// hueChallengePayloadLength は署名する本体 (ID 16 バイトと発行時刻のミリ秒 8 バイト) の長さ。
+const hueChallengePayloadLength = 16 + 8
+
+// HueChallenge はテスト開始時にサーバーが発行する使い捨ての証明。"<本体>.<署名>" 形式のトークンで渡し、
+// save-result に添えて送らせる。署名で発行元を確かめ、ID を回答に保存して 2 回目の利用を拒否する。
+type HueChallenge struct {
+	id       uuid.UUID
+	issuedAt time.Time
+}
+
+func (c HueChallenge) ID() uuid.UUID {
+	return c.id
+}
+
+func (c HueChallenge) IssuedAt() time.Time {
+	return c.issuedAt
+}
+
+func (c HueChallenge) ExpiresAt() time.Time {
+	return c.issuedAt.Add(HueChallengeTTL)
+}
+
+// HueChallengeSigner は HMAC-SHA256 でチャレンジに署名し、検証する。状態を持たないため、
+// 複数のサーバーで同じ鍵を使えばどのサーバーが発行したチャレンジも受け付けられる。
+type HueChallengeSigner struct {
+	key []byte
+}
+
+// NewHueChallengeSigner は secret が MinHueChallengeSecretBytes より短ければ ErrInvalidHueChallengeSecret を返す。
+func NewHueChallengeSigner(secret []byte) (HueChallengeSigner, error) {
+	if len(secret) < MinHueChallengeSecretBytes {
+		return HueChallengeSigner{}, ErrInvalidHueChallengeSecret
+	}
+	return HueChallengeSigner{key: append([]byte(nil), secret...)}, nil
+}
+
+// Issue は now に発行したチャレンジとそのトークンを返す。
+func (s HueChallengeSigner) Issue(now time.Time) (HueChallenge, string) {
+	challenge := HueChallenge{id: uuid.New(), issuedAt: now.UTC().Truncate(time.Millisecond)}
+
+	payload := make([]byte, 0, hueChallengePayloadLength)
+	payload = append(payload, challenge.id[:]...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(challenge.issuedAt.UnixMilli()))
+
+	encoding := base64.RawURLEncoding
+	return challenge, encoding.EncodeToString(payload) + tokenSeparator + encoding.EncodeToString(s.sign(payload))
+}
+
+// Verify は token の署名と期限を確かめる。形式や署名が不正か、now の時点で期限が切れていれば ErrInvalidHueChallenge を返す。
+// 使用済みかどうかは見ないため、呼び出し側で ID を保存して確かめる。
+func (s HueChallengeSigner) Verify(token string, now time.Time) (HueChallenge, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(strings.TrimSpace(token), tokenSeparator)
+	if !ok || len(s.key) == 0 {
+		return HueChallenge{}, ErrInvalidHueChallenge
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != hueChallengePayloadLength {
+		return HueChallenge{}, ErrInvalidHueChallenge
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(payload)) {
+		return HueChallenge{}, ErrInvalidHueChallenge
+	}
+
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil || id == uuid.Nil {
+		return HueChallenge{}, ErrInvalidHueChallenge
+	}
+	challenge := HueChallenge{id: id, issuedAt: time.UnixMilli(int64(binary.BigEndian.Uint64(payload[16:]))).UTC()}
+	if !now.Before(challenge.ExpiresAt()) {
+		return HueChallenge{}, ErrInvalidHueChallenge
+	}
+	return challenge, nil
+}
+
+func (s HueChallengeSigner) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHueChallengeSigner_SecretLength(t *testing.T) {
+	if _, err := NewHueChallengeSigner([]byte(strings.Repeat("k", MinHueChallengeSecretBytes-1))); !errors.Is(err, ErrInvalidHueChallengeSecret) {
+		t.Fatalf("expected ErrInvalidHueChallengeSecret for a short secret, got %v", err)
+	}
+	if _, err := NewHueChallengeSigner([]byte(strings.Repeat("k", MinHueChallengeSecretBytes))); err != nil {
+		t.Fatalf("expected a %d byte secret to be accepted, got %v", MinHueChallengeSecretBytes, err)
+	}
+}
+
+func TestHueChallengeSigner_IssueAndVerify(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	signer, _ := NewHueChallengeSigner([]byte(strings.Repeat("k", MinHueChallengeSecretBytes)))
+	issued, token := signer.Issue(now)
+
+	verified, err := signer.Verify(token, now.Add(HueChallengeTTL-time.Second))
+	if err != nil {
+		t.Fatalf("verify error: %v", err)
+	}
+	if verified.ID() != issued.ID() || !verified.IssuedAt().Equal(now) {
+		t.Fatalf("expected the issued challenge back, got %v at %v", verified.ID(), verified.IssuedAt())
+	}
+	if _, token2 := signer.Issue(now); token2 == token {
+		t.Fatal("expected every challenge to be different")
+	}
+
+	other, _ := NewHueChallengeSigner([]byte(strings.Repeat("x", MinHueChallengeSecretBytes)))
+	payload, signature, _ := strings.Cut(token, ".")
+	cases := map[string]struct {
+		signer HueChallengeSigner
+		token  string
+		at     time.Time
+	}{
+		"expired":       {signer, token, now.Add(HueChallengeTTL)},
+		"other key":     {other, token, now},
+		"no signature":  {signer, payload, now},
+		"bad signature": {signer, payload + "." + strings.ToUpper(signature), now},
+		"bad payload":   {signer, "AAAA." + signature, now},
+		"empty":         {signer, "", now},
+		"zero signer":   {HueChallengeSigner{}, token, now},
+	}
+	for name, tc := range cases {
+		if _, err := tc.signer.Verify(tc.token, tc.at); !errors.Is(err, ErrInvalidHueChallenge) {
+			t.Fatalf("%s: expected ErrInvalidHueChallenge, got %v", name, err)
+		}
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	key, err := NewIdempotencyKey(" 3f2c8a1e-6b0d-4c1f-9a57-2e4d8b1c0f93 ")
+	if err != nil || key.String() != "3f2c8a1e-6b0d-4c1f-9a57-2e4d8b1c0f93" {
+		t.Fatalf("expected a trimmed key, got %q (%v)", key, err)
+	}
+	for _, value := range []string{"", "  ", "a b", "キー", "a\tb", strings.Repeat("a", MaxIdempotencyKeyLength+1)} {
+		if _, err := NewIdempotencyKey(value); !errors.Is(err, ErrInvalidIdempotencyKey) {
+			t.Fatalf("%q: expected ErrInvalidIdempotencyKey, got %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// MinHueAnswerTime は 1 語あたりの回答時間の下限。すべての単語にこれより短い平均時間で答え終えた回答は、
+// 単語を読まずに選んだものとして too_fast とする。
+// MinSingleColorWords 語以上のすべてに同じ色を選んだ回答は single_color とする。
+const (
+	MinHueAnswerTime    = 500 * time.Millisecond
+	MinSingleColorWords = 3
+)
+
+// HueQualityFlag は分析から外すかどうかを判断するための回答の品質の印。印が付いた回答も保存はする。
+type HueQualityFlag string
+
+const (
+	HueQualityTooFast     HueQualityFlag = "too_fast"
+	HueQualitySingleColor HueQualityFlag = "single_color"
+)
+
+func (f HueQualityFlag) String() string {
+	return string(f)
+}
+
+// Assess は回答を終えるまでに elapsed かかった回答として品質を判定し、印を付け直したコピーを返す。
+func (r HueRecord) Assess(elapsed time.Duration) HueRecord {
+	var flags []HueQualityFlag
+
+	words := r.choices.Size()
+	if elapsed < time.Duration(words)*MinHueAnswerTime {
+		flags = append(flags, HueQualityTooFast)
+	}
+
+	colors := make(map[HueColor]struct{}, words)
+	for _, color := range r.choices.values {
+		colors[color] = struct{}{}
+	}
+	if words >= MinSingleColorWords && len(colors) == 1 {
+		flags = append(flags, HueQualitySingleColor)
+	}
+
+	r.flags = flags
+	return r
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHueRecord_Assess(t *testing.T) {
+	cases := []struct {
+		name    string
+		choices map[string]string
+		elapsed time.Duration
+		want    []HueQualityFlag
+	}{
+		{"plausible", map[string]string{"夜": "黒", "海": "青", "森": "緑"}, 3 * time.Second, nil},
+		{"too fast", map[string]string{"夜": "黒", "海": "青", "森": "緑"}, 3*MinHueAnswerTime - time.Millisecond, []HueQualityFlag{HueQualityTooFast}},
+		{"single color", map[string]string{"夜": "黒", "海": "黒", "森": "黒"}, time.Minute, []HueQualityFlag{HueQualitySingleColor}},
+		{"both", map[string]string{"夜": "黒", "海": "黒", "森": "黒"}, time.Second, []HueQualityFlag{HueQualityTooFast, HueQualitySingleColor}},
+		{"too few words for single color", map[string]string{"夜": "黒", "海": "黒"}, time.Minute, nil},
+	}
+	for _, tc := range cases {
+		record, err := NewHueRecordFromRaw("Tester", tc.choices)
+		if err != nil {
+			t.Fatalf("%s: record error: %v", tc.name, err)
+		}
+		if got := record.Assess(tc.elapsed).QualityFlags(); !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	// 判定し直すと前の印は残さない。
+	record, _ := NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒", "海": "青"})
+	if got := record.FlaggedAs(HueQualityTooFast).Assess(time.Minute).QualityFlags(); len(got) != 0 {
+		t.Fatalf("expected the previous flags to be cleared, got %v", got)
+	}
+}
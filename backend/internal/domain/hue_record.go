@@ -1,12 +1,33 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // HueRecord は参加者名と色割り当てをまとめた値オブジェクト。
+// createdAt は保存時に DB が決めるため、NewHueRecord で作った直後はゼロ値。
+// userID はログイン中に回答した場合だけ設定され、匿名の回答では uuid.Nil。
+// wordSetID は回答した単語セットで、単語セットを導入する前の回答では uuid.Nil。
+// paletteVersion は色を選んだパレットの版で、Palette.Paint を通すまでは 0。
+// order はセッションで回答した場合の出題順で、セッションを通さない回答では mode が空。
+// flags は Assess で判定した品質の印。idempotencyKey と challengeID は save-result での送信を保存するときだけ使い、
+// 読み出したレコードには含めない。
 type HueRecord struct {
-	id      uuid.UUID
-	name    Name
-	choices HueChoices
+	id             uuid.UUID
+	name           Name
+	choices        HueChoices
+	userID         uuid.UUID
+	wordSetID      uuid.UUID
+	paletteVersion int
+	order          WordOrder
+	flags          []HueQualityFlag
+	idempotencyKey IdempotencyKey
+	challengeID    uuid.UUID
+	createdAt      time.Time
 }
 
 // NewHueRecord は空の選択を拒否し、完全なレコードを構築する。
@@ -22,9 +43,9 @@ func NewHueRecord(name Name, choices HueChoices) (HueRecord, error) {
 	}, nil
 }
 
-// NewHueRecordFromPersistence は永続化済みデータから HueRecord を再構築する。
-func NewHueRecordFromPersistence(id uuid.UUID, name Name, choices HueChoices) (HueRecord, error) {
-	if id == uuid.Nil {
+// NewHueRecordFromPersistence は永続化済みデータから HueRecord を再構築する。匿名の回答では userID に uuid.Nil を渡す。
+func NewHueRecordFromPersistence(id uuid.UUID, name Name, choices HueChoices, userID uuid.UUID, createdAt time.Time) (HueRecord, error) {
+	if id == uuid.Nil || createdAt.IsZero() {
 		return HueRecord{}, ErrInvalidChoice
 	}
 
@@ -37,9 +58,11 @@ func NewHueRecordFromPersistence(id uuid.UUID, name Name, choices HueChoices) (H
 	}
 
 	return HueRecord{
-		id:      id,
-		name:    name,
-		choices: choices,
+		id:        id,
+		name:      name,
+		choices:   choices,
+		userID:    userID,
+		createdAt: createdAt.UTC(),
 	}, nil
 }
 
@@ -66,6 +89,96 @@ func (r HueRecord) Name() Name {
 	return r.name
 }
 
+// UserID は回答したユーザーを返す。匿名の回答なら false。
+func (r HueRecord) UserID() (uuid.UUID, bool) {
+	return r.userID, r.userID != uuid.Nil
+}
+
+// SubmittedBy は userID のユーザーによる回答として紐づけたコピーを返す。
+func (r HueRecord) SubmittedBy(userID uuid.UUID) HueRecord {
+	r.userID = userID
+	return r
+}
+
+// WordSetID は回答した単語セットを返す。単語セットを導入する前の回答なら false。
+func (r HueRecord) WordSetID() (uuid.UUID, bool) {
+	return r.wordSetID, r.wordSetID != uuid.Nil
+}
+
+// AnsweredFrom は wordSetID の単語セットへの回答として紐づけたコピーを返す。
+func (r HueRecord) AnsweredFrom(wordSetID uuid.UUID) HueRecord {
+	r.wordSetID = wordSetID
+	return r
+}
+
+// PaletteVersion は色を選んだパレットの版を返す。まだ決まっていなければ false。
+func (r HueRecord) PaletteVersion() (int, bool) {
+	return r.paletteVersion, r.paletteVersion > 0
+}
+
+// ColoredWith は version の版のパレットで色を選んだ回答として紐づけたコピーを返す。
+// 色がその版にあるかは確かめないため、新しい回答には Palette.Paint を使う。
+func (r HueRecord) ColoredWith(version int) HueRecord {
+	r.paletteVersion = version
+	return r
+}
+
+// WordOrder は回答したときの出題順を返す。セッションを通さない回答なら false。
+func (r HueRecord) WordOrder() (WordOrder, bool) {
+	return r.order, r.order.mode != ""
+}
+
+// PresentedIn は order の順に出題した回答として紐づけたコピーを返す。
+func (r HueRecord) PresentedIn(order WordOrder) HueRecord {
+	r.order = order
+	return r
+}
+
+// QualityFlags は品質の印を返す。印が無ければ空。
+func (r HueRecord) QualityFlags() []HueQualityFlag {
+	return slices.Clone(r.flags)
+}
+
+// FlaggedAs は flags の印を付けたコピーを返す。新しい回答には Assess を使う。
+func (r HueRecord) FlaggedAs(flags ...HueQualityFlag) HueRecord {
+	r.flags = slices.Clone(flags)
+	return r
+}
+
+// IdempotencyKey は送信に付いていた再送判定用のキーを返す。付いていなければ false。
+func (r HueRecord) IdempotencyKey() (IdempotencyKey, bool) {
+	return r.idempotencyKey, r.idempotencyKey != ""
+}
+
+// KeyedBy は key で送られた回答として紐づけたコピーを返す。
+func (r HueRecord) KeyedBy(key IdempotencyKey) HueRecord {
+	r.idempotencyKey = key
+	return r
+}
+
+// ChallengeID は送信に使ったチャレンジを返す。チャレンジを通さない回答なら false。
+func (r HueRecord) ChallengeID() (uuid.UUID, bool) {
+	return r.challengeID, r.challengeID != uuid.Nil
+}
+
+// ProvenBy は challenge を使って送られた回答として紐づけたコピーを返す。
+func (r HueRecord) ProvenBy(challenge HueChallenge) HueRecord {
+	r.challengeID = challenge.ID()
+	return r
+}
+
+// SameAnswers は other と回答者名、色割り当て、単語セット、パレットの版がすべて同じなら true を返す。
+// 同じ Idempotency-Key で送り直された回答が最初の送信と同じかを確かめるために使う。
+func (r HueRecord) SameAnswers(other HueRecord) bool {
+	return r.name == other.name && maps.Equal(r.choices.values, other.choices.values) &&
+		r.wordSetID == other.wordSetID && r.paletteVersion == other.paletteVersion
+}
+
+// CreatedAt は保存された時刻。未保存のレコードではゼロ値。
+func (r HueRecord) CreatedAt() time.Time {
+	return r.createdAt
+}
+
 func (r HueRecord) Choices() HueChoices {
 	return r.choices
 }
@@ -0,0 +1,352 @@
+package domain
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HueSessionIdleTimeout を過ぎても回答が届かないセッションは離脱したものとみなし、それ以上回答を受け付けない。
+// MaxHueAnswerLatency は 1 語の回答にかかった時間として受け付ける上限。
+const (
+	HueSessionIdleTimeout = 30 * time.Minute
+	MaxHueAnswerLatency   = 10 * time.Minute
+)
+
+// HueSessionStatus はセッションの状態。保存はせず、終了時刻と最後の回答時刻から決まる。
+type HueSessionStatus string
+
+const (
+	HueSessionInProgress HueSessionStatus = "in_progress"
+	HueSessionFinished   HueSessionStatus = "finished"
+	HueSessionAbandoned  HueSessionStatus = "abandoned"
+)
+
+// HueAnswer はセッション中の 1 語への回答。latency は単語を表示してから色を選ぶまでにクライアントで測った時間で、集計にだけ使う。
+// answeredAt はサーバーが回答を受け付けた時刻。
+type HueAnswer struct {
+	word       HueWord
+	color      HueColor
+	latency    time.Duration
+	answeredAt time.Time
+}
+
+// NewHueAnswer は latency が負または MaxHueAnswerLatency を超える場合に ErrInvalidLatency を返す。
+// color は色 ID で、パレットにあるかは呼び出し側で確かめておく。
+func NewHueAnswer(word HueWord, color HueColor, latency time.Duration, answeredAt time.Time) (HueAnswer, error) {
+	if word == "" || color == "" || answeredAt.IsZero() {
+		return HueAnswer{}, ErrInvalidChoice
+	}
+	if latency < 0 || latency > MaxHueAnswerLatency {
+		return HueAnswer{}, ErrInvalidLatency
+	}
+	return HueAnswer{word: word, color: color, latency: latency, answeredAt: answeredAt.UTC()}, nil
+}
+
+func (a HueAnswer) Word() HueWord {
+	return a.word
+}
+
+func (a HueAnswer) Color() HueColor {
+	return a.color
+}
+
+func (a HueAnswer) Latency() time.Duration {
+	return a.latency
+}
+
+func (a HueAnswer) AnsweredAt() time.Time {
+	return a.answeredAt
+}
+
+// HueSession は 1 回の Hue テストの開始から終了まで。回答は 1 語ずつ届き、同じ単語に答え直すと上書きする。
+// 終了すると回答を HueRecord にまとめ、recordID で紐づける。終わらなかったセッションも離脱の分析のために残す。
+// userID は開始時にログインしていた場合だけ設定され、匿名なら uuid.Nil。
+type HueSession struct {
+	id             uuid.UUID
+	userID         uuid.UUID
+	wordSetID      uuid.UUID
+	paletteVersion int
+	order          WordOrder
+	answers        map[HueWord]HueAnswer
+	startedAt      time.Time
+	lastActiveAt   time.Time
+	finishedAt     time.Time
+	recordID       uuid.UUID
+}
+
+// NewHueSession は set の単語を order の順に出し、palette の色で答えるセッションを始める。
+// order が set の単語の並べ替えでなければ ErrInvalidHueSession を返す。
+func NewHueSession(set WordSet, palette Palette, order WordOrder, userID uuid.UUID, now time.Time) (HueSession, error) {
+	if set.ID() == uuid.Nil || palette.Version() <= 0 || now.IsZero() {
+		return HueSession{}, ErrInvalidHueSession
+	}
+	if !sameWords(set.Words(), order.words) {
+		return HueSession{}, ErrInvalidHueSession
+	}
+
+	return HueSession{
+		id:             uuid.New(),
+		userID:         userID,
+		wordSetID:      set.ID(),
+		paletteVersion: palette.Version(),
+		order:          order,
+		answers:        make(map[HueWord]HueAnswer),
+		startedAt:      now.UTC(),
+		lastActiveAt:   now.UTC(),
+	}, nil
+}
+
+// NewHueSessionFromPersistence は永続化済みのセッションを再構築する。終了していなければ finishedAt はゼロ値、recordID は uuid.Nil を渡す。
+func NewHueSessionFromPersistence(id, userID, wordSetID uuid.UUID, paletteVersion int, order WordOrder, answers []HueAnswer, startedAt, lastActiveAt, finishedAt time.Time, recordID uuid.UUID) (HueSession, error) {
+	if id == uuid.Nil || wordSetID == uuid.Nil || paletteVersion <= 0 || len(order.words) == 0 || startedAt.IsZero() || lastActiveAt.Before(startedAt) {
+		return HueSession{}, ErrInvalidHueSession
+	}
+	// 回答が削除されると record_id は NULL に戻るため、終了済みでも recordID が無いことはある。
+	if finishedAt.IsZero() && recordID != uuid.Nil {
+		return HueSession{}, ErrInvalidHueSession
+	}
+
+	byWord := make(map[HueWord]HueAnswer, len(answers))
+	for _, a := range answers {
+		if !slices.Contains(order.words, a.word) {
+			return HueSession{}, ErrInvalidHueSession
+		}
+		byWord[a.word] = a
+	}
+
+	s := HueSession{
+		id:             id,
+		userID:         userID,
+		wordSetID:      wordSetID,
+		paletteVersion: paletteVersion,
+		order:          order,
+		answers:        byWord,
+		startedAt:      startedAt.UTC(),
+		lastActiveAt:   lastActiveAt.UTC(),
+		recordID:       recordID,
+	}
+	if !finishedAt.IsZero() {
+		s.finishedAt = finishedAt.UTC()
+	}
+	return s, nil
+}
+
+func (s HueSession) ID() uuid.UUID {
+	return s.id
+}
+
+// UserID は開始したユーザーを返す。匿名のセッションなら false。
+func (s HueSession) UserID() (uuid.UUID, bool) {
+	return s.userID, s.userID != uuid.Nil
+}
+
+func (s HueSession) WordSetID() uuid.UUID {
+	return s.wordSetID
+}
+
+func (s HueSession) PaletteVersion() int {
+	return s.paletteVersion
+}
+
+// Words は出題順の単語を返す。
+func (s HueSession) Words() []HueWord {
+	return s.order.Words()
+}
+
+// Order は出題順とその決め方を返す。
+func (s HueSession) Order() WordOrder {
+	return s.order
+}
+
+// Answers は出題順に並べた回答を返す。まだ答えていない単語は含まない。
+func (s HueSession) Answers() []HueAnswer {
+	answers := make([]HueAnswer, 0, len(s.answers))
+	for _, word := range s.order.words {
+		if a, ok := s.answers[word]; ok {
+			answers = append(answers, a)
+		}
+	}
+	return answers
+}
+
+func (s HueSession) StartedAt() time.Time {
+	return s.startedAt
+}
+
+// LastActiveAt は開始か最後の回答の時刻。
+func (s HueSession) LastActiveAt() time.Time {
+	return s.lastActiveAt
+}
+
+// FinishedAt は終了した時刻を返す。終了していなければ false。
+func (s HueSession) FinishedAt() (time.Time, bool) {
+	return s.finishedAt, !s.finishedAt.IsZero()
+}
+
+// RecordID は終了時に保存した回答を返す。終了していなければ false。
+func (s HueSession) RecordID() (uuid.UUID, bool) {
+	return s.recordID, s.recordID != uuid.Nil
+}
+
+// Status は now 時点の状態を返す。
+func (s HueSession) Status(now time.Time) HueSessionStatus {
+	switch {
+	case !s.finishedAt.IsZero():
+		return HueSessionFinished
+	case now.Sub(s.lastActiveAt) > HueSessionIdleTimeout:
+		return HueSessionAbandoned
+	default:
+		return HueSessionInProgress
+	}
+}
+
+// Answer は answer を記録したコピーを返す。進行中でなければ ErrHueSessionClosed、
+// 出題していない単語なら ErrInvalidChoice を返す。
+func (s HueSession) Answer(answer HueAnswer) (HueSession, error) {
+	if s.Status(answer.answeredAt) != HueSessionInProgress {
+		return HueSession{}, ErrHueSessionClosed
+	}
+	if !slices.Contains(s.order.words, answer.word) {
+		return HueSession{}, ErrInvalidChoice
+	}
+
+	s.answers = maps.Clone(s.answers)
+	s.answers[answer.word] = answer
+	if answer.answeredAt.After(s.lastActiveAt) {
+		s.lastActiveAt = answer.answeredAt
+	}
+	return s, nil
+}
+
+// Finish は回答を name の HueRecord にまとめ、終了したコピーと一緒に返す。
+// 進行中でなければ ErrHueSessionClosed、1 語も答えていなければ ErrInvalidChoice を返す。
+func (s HueSession) Finish(name Name, now time.Time) (HueSession, HueRecord, error) {
+	if s.Status(now) != HueSessionInProgress {
+		return HueSession{}, HueRecord{}, ErrHueSessionClosed
+	}
+
+	values := make(map[HueWord]HueColor, len(s.answers))
+	answeredAt := s.startedAt
+	for word, a := range s.answers {
+		values[word] = a.color
+		if a.answeredAt.After(answeredAt) {
+			answeredAt = a.answeredAt
+		}
+	}
+	record, err := NewHueRecord(name, HueChoices{values: values})
+	if err != nil {
+		return HueSession{}, HueRecord{}, err
+	}
+	// 品質は開始から最後の回答を受け付けるまでのサーバーの時刻で判定する。クライアントが送る latency は信用しない。
+	record = record.AnsweredFrom(s.wordSetID).ColoredWith(s.paletteVersion).PresentedIn(s.order).Assess(answeredAt.Sub(s.startedAt))
+	if userID, ok := s.UserID(); ok {
+		record = record.SubmittedBy(userID)
+	}
+
+	s.finishedAt = now.UTC()
+	s.lastActiveAt = s.finishedAt
+	s.recordID = record.ID()
+	return s, record, nil
+}
+
+// sameWords は b が a の並べ替えなら true を返す。
+func sameWords(a, b []HueWord) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sorted := slices.Clone(b)
+	slices.Sort(sorted)
+	for _, word := range a {
+		if _, ok := slices.BinarySearch(sorted, word); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// HueWordTiming は単語ごとの回答数と回答時間の中央値。
+type HueWordTiming struct {
+	word          HueWord
+	answers       int
+	medianLatency time.Duration
+}
+
+func NewHueWordTiming(word HueWord, answers int, medianLatency time.Duration) HueWordTiming {
+	return HueWordTiming{word: word, answers: answers, medianLatency: medianLatency}
+}
+
+func (t HueWordTiming) Word() HueWord {
+	return t.word
+}
+
+func (t HueWordTiming) Answers() int {
+	return t.answers
+}
+
+func (t HueWordTiming) MedianLatency() time.Duration {
+	return t.medianLatency
+}
+
+// HueSessionFunnel は開始したセッションがどこまで進んだかの集計。reached[i] は i+1 語以上に答えたセッション数で、
+// 離脱したセッションもそこまでの回答を数える。timings は単語の昇順。
+type HueSessionFunnel struct {
+	started    int
+	finished   int
+	abandoned  int
+	inProgress int
+	reached    []int
+	timings    []HueWordTiming
+}
+
+// NewHueSessionFunnel は状態ごとのセッション数と、回答数ごとのセッション数 (answered[n] は n 語に答えたセッション数) から集計を組み立てる。
+func NewHueSessionFunnel(statuses map[HueSessionStatus]int, answered map[int]int, timings []HueWordTiming) HueSessionFunnel {
+	f := HueSessionFunnel{
+		finished:   statuses[HueSessionFinished],
+		abandoned:  statuses[HueSessionAbandoned],
+		inProgress: statuses[HueSessionInProgress],
+		timings:    slices.Clone(timings),
+	}
+	f.started = f.finished + f.abandoned + f.inProgress
+
+	for n, sessions := range answered {
+		for len(f.reached) < n {
+			f.reached = append(f.reached, 0)
+		}
+		for i := range n {
+			f.reached[i] += sessions
+		}
+	}
+	slices.SortFunc(f.timings, func(a, b HueWordTiming) int {
+		return cmp.Compare(a.word, b.word)
+	})
+	return f
+}
+
+func (f HueSessionFunnel) Started() int {
+	return f.started
+}
+
+func (f HueSessionFunnel) Finished() int {
+	return f.finished
+}
+
+func (f HueSessionFunnel) Abandoned() int {
+	return f.abandoned
+}
+
+func (f HueSessionFunnel) InProgress() int {
+	return f.inProgress
+}
+
+// Reached は i 番目に i+1 語以上答えたセッション数を返す。
+func (f HueSessionFunnel) Reached() []int {
+	return slices.Clone(f.reached)
+}
+
+func (f HueSessionFunnel) Timings() []HueWordTiming {
+	return slices.Clone(f.timings)
+}
@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func buildTestHueSession(t *testing.T, userID uuid.UUID, now time.Time) HueSession {
+	t.Helper()
+	set, err := NewWordSet("標準", []string{"夜", "海", "森"}, now)
+	if err != nil {
+		t.Fatalf("word set error: %v", err)
+	}
+	order, err := NewWordOrder(WordOrderFixed, 0, set.Words())
+	if err != nil {
+		t.Fatalf("order error: %v", err)
+	}
+	session, err := NewHueSession(set, buildTestPalette(t, 3), order, userID, now)
+	if err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+	return session
+}
+
+func mustAnswer(t *testing.T, word HueWord, color HueColor, latency time.Duration, at time.Time) HueAnswer {
+	t.Helper()
+	answer, err := NewHueAnswer(word, color, latency, at)
+	if err != nil {
+		t.Fatalf("answer error: %v", err)
+	}
+	return answer
+}
+
+func TestNewHueAnswer_Latency(t *testing.T) {
+	now := time.Now()
+	for _, latency := range []time.Duration{-time.Millisecond, MaxHueAnswerLatency + time.Millisecond} {
+		if _, err := NewHueAnswer("夜", "black", latency, now); !errors.Is(err, ErrInvalidLatency) {
+			t.Fatalf("latency %v: expected ErrInvalidLatency, got %v", latency, err)
+		}
+	}
+	if _, err := NewHueAnswer("夜", "black", 0, now); err != nil {
+		t.Fatalf("expected zero latency to be accepted, got %v", err)
+	}
+}
+
+func TestHueSession_AnswerAndFinish(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tester, _ := NewName("Tester")
+	userID := uuid.New()
+	session := buildTestHueSession(t, userID, start)
+
+	if session.Status(start) != HueSessionInProgress || !slices.Equal(session.Words(), []HueWord{"夜", "海", "森"}) {
+		t.Fatalf("expected a new session in word set order, got %+v", session)
+	}
+	if _, err := session.Answer(mustAnswer(t, "空", "black", time.Second, start)); !errors.Is(err, ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice for a word outside the session, got %v", err)
+	}
+	if _, _, err := session.Finish(tester, start); !errors.Is(err, ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice when finishing without answers, got %v", err)
+	}
+
+	answered, err := session.Answer(mustAnswer(t, "海", "white", time.Second, start.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("answer error: %v", err)
+	}
+	// 前の単語に戻って答え直すと上書きする。
+	answered, err = answered.Answer(mustAnswer(t, "海", "blue", 2*time.Second, start.Add(2*time.Minute)))
+	if err != nil {
+		t.Fatalf("answer again error: %v", err)
+	}
+	answered, err = answered.Answer(mustAnswer(t, "夜", "black", 3*time.Second, start.Add(3*time.Minute)))
+	if err != nil {
+		t.Fatalf("answer error: %v", err)
+	}
+	if len(session.Answers()) != 0 {
+		t.Fatalf("expected the original session to be unchanged, got %+v", session.Answers())
+	}
+	answers := answered.Answers()
+	if len(answers) != 2 || answers[0].Word() != "夜" || answers[1].Color() != "blue" || answers[1].Latency() != 2*time.Second {
+		t.Fatalf("expected answers in word order with the latest color, got %+v", answers)
+	}
+	if !answered.LastActiveAt().Equal(start.Add(3 * time.Minute)) {
+		t.Fatalf("expected last activity at the latest answer, got %v", answered.LastActiveAt())
+	}
+
+	finishedAt := start.Add(4 * time.Minute)
+	finished, record, err := answered.Finish(tester, finishedAt)
+	if err != nil {
+		t.Fatalf("finish error: %v", err)
+	}
+	if finished.Status(finishedAt.Add(time.Hour)) != HueSessionFinished {
+		t.Fatalf("expected finished status, got %s", finished.Status(finishedAt))
+	}
+	if id, ok := finished.RecordID(); !ok || id != record.ID() {
+		t.Fatalf("expected the session to point at the record, got %v", id)
+	}
+	if got := record.ChoiceMap(); len(got) != 2 || got["海"] != "blue" {
+		t.Fatalf("unexpected choices: %+v", got)
+	}
+	if id, _ := record.WordSetID(); id != session.WordSetID() {
+		t.Fatalf("expected the record to keep the word set, got %v", id)
+	}
+	if version, _ := record.PaletteVersion(); version != 3 {
+		t.Fatalf("expected palette version 3, got %d", version)
+	}
+	if id, _ := record.UserID(); id != userID {
+		t.Fatalf("expected the record to belong to the user, got %v", id)
+	}
+	if order, ok := record.WordOrder(); !ok || order.Mode() != WordOrderFixed || !slices.Equal(order.Words(), session.Words()) {
+		t.Fatalf("expected the record to keep the presented order, got %+v", order)
+	}
+
+	if _, err := finished.Answer(mustAnswer(t, "森", "white", time.Second, finishedAt)); !errors.Is(err, ErrHueSessionClosed) {
+		t.Fatalf("expected ErrHueSessionClosed after finish, got %v", err)
+	}
+	if _, _, err := finished.Finish(tester, finishedAt); !errors.Is(err, ErrHueSessionClosed) {
+		t.Fatalf("expected ErrHueSessionClosed on second finish, got %v", err)
+	}
+}
+
+func TestHueSession_FinishAssessesServerTime(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tester, _ := NewName("Tester")
+	session := buildTestHueSession(t, uuid.Nil, start)
+
+	// クライアントが長い latency を名乗っても、開始から 1 秒で 3 語を受け付けていれば too_fast にする。
+	answered := session
+	colors := []HueColor{"black", "blue", "white"}
+	for i, word := range []HueWord{"夜", "海", "森"} {
+		var err error
+		answered, err = answered.Answer(mustAnswer(t, word, colors[i], 5*time.Second, start.Add(time.Duration(i+1)*300*time.Millisecond)))
+		if err != nil {
+			t.Fatalf("answer error: %v", err)
+		}
+	}
+	_, record, err := answered.Finish(tester, start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("finish error: %v", err)
+	}
+	if flags := record.QualityFlags(); !slices.Equal(flags, []HueQualityFlag{HueQualityTooFast}) {
+		t.Fatalf("expected too_fast from the server time, got %v", flags)
+	}
+
+	// 最後の回答から終了までの時間は数えない。
+	slow, err := session.Answer(mustAnswer(t, "夜", "black", 0, start.Add(2*time.Second)))
+	if err != nil {
+		t.Fatalf("answer error: %v", err)
+	}
+	_, record, err = slow.Finish(tester, start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("finish error: %v", err)
+	}
+	if flags := record.QualityFlags(); len(flags) != 0 {
+		t.Fatalf("expected no flags, got %v", flags)
+	}
+}
+
+func TestNewHueSession_RejectsForeignOrder(t *testing.T) {
+	now := time.Now()
+	set, _ := NewWordSet("標準", []string{"夜", "海"}, now)
+	for _, words := range [][]HueWord{{"夜"}, {"夜", "空"}, {"夜", "海", "森"}} {
+		order, _ := NewWordOrder(WordOrderFixed, 0, words)
+		if _, err := NewHueSession(set, buildTestPalette(t, 1), order, uuid.Nil, now); !errors.Is(err, ErrInvalidHueSession) {
+			t.Fatalf("%v: expected ErrInvalidHueSession, got %v", words, err)
+		}
+	}
+
+	order, _ := NewWordOrder(WordOrderLatinSquare, 1, set.Words())
+	session, err := NewHueSession(set, buildTestPalette(t, 1), order, uuid.Nil, now)
+	if err != nil || !slices.Equal(session.Words(), []HueWord{"海", "夜"}) {
+		t.Fatalf("expected the session to follow the order, got %v (%v)", session.Words(), err)
+	}
+}
+
+func TestHueSession_Abandoned(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tester, _ := NewName("Tester")
+	session := buildTestHueSession(t, uuid.Nil, start)
+
+	if _, ok := session.UserID(); ok {
+		t.Fatal("expected an anonymous session")
+	}
+	if session.Status(start.Add(HueSessionIdleTimeout)) != HueSessionInProgress {
+		t.Fatal("expected the session to be in progress until the idle timeout")
+	}
+
+	late := start.Add(HueSessionIdleTimeout + time.Second)
+	if session.Status(late) != HueSessionAbandoned {
+		t.Fatalf("expected abandoned after the idle timeout, got %s", session.Status(late))
+	}
+	if _, err := session.Answer(mustAnswer(t, "夜", "black", time.Second, late)); !errors.Is(err, ErrHueSessionClosed) {
+		t.Fatalf("expected ErrHueSessionClosed for an abandoned session, got %v", err)
+	}
+	if _, _, err := session.Finish(tester, late); !errors.Is(err, ErrHueSessionClosed) {
+		t.Fatalf("expected ErrHueSessionClosed when finishing an abandoned session, got %v", err)
+	}
+}
+
+func TestNewHueSessionFunnel(t *testing.T) {
+	statuses := map[HueSessionStatus]int{HueSessionFinished: 2, HueSessionAbandoned: 3, HueSessionInProgress: 1}
+	// 0 語で離脱 1 件、1 語 2 件、3 語 3 件。
+	answered := map[int]int{0: 1, 1: 2, 3: 3}
+	timings := []HueWordTiming{NewHueWordTiming("海", 4, time.Second), NewHueWordTiming("夜", 5, 2*time.Second)}
+
+	funnel := NewHueSessionFunnel(statuses, answered, timings)
+	if funnel.Started() != 6 || funnel.Finished() != 2 || funnel.Abandoned() != 3 || funnel.InProgress() != 1 {
+		t.Fatalf("unexpected counts: %+v", funnel)
+	}
+	if got := funnel.Reached(); !slices.Equal(got, []int{5, 3, 3}) {
+		t.Fatalf("expected reached [5 3 3], got %v", got)
+	}
+	if got := funnel.Timings(); got[0].Word() != "夜" || got[1].Word() != "海" {
+		t.Fatalf("expected timings sorted by word, got %+v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"math"
+	"slices"
+)
+
+// HueWordStats は 1 つの単語に対する色ごとの回答数を集計した結果。colors は集計したパレットの色の表示順。
+type HueWordStats struct {
+	word   HueWord
+	colors []HueColor
+	counts map[HueColor]int
+	total  int
+}
+
+// NewHueWordStats は colors に無い色や負の件数を含む集計を拒否する。
+func NewHueWordStats(word HueWord, colors []HueColor, counts map[HueColor]int) (HueWordStats, error) {
+	if word == "" || len(colors) == 0 {
+		return HueWordStats{}, ErrInvalidChoice
+	}
+
+	copied := make(map[HueColor]int, len(counts))
+	total := 0
+	for color, n := range counts {
+		if !slices.Contains(colors, color) || n < 0 {
+			return HueWordStats{}, ErrInvalidChoice
+		}
+		copied[color] = n
+		total += n
+	}
+
+	return HueWordStats{word: word, colors: slices.Clone(colors), counts: copied, total: total}, nil
+}
+
+func (s HueWordStats) Word() HueWord {
+	return s.word
+}
+
+func (s HueWordStats) Total() int {
+	return s.total
+}
+
+// Count は指定色の回答数を返す。回答がなければ 0。
+func (s HueWordStats) Count(color HueColor) int {
+	return s.counts[color]
+}
+
+// Mode は最も多く選ばれた色を返す。同数の場合はパレットの表示順で先の色を採る。回答がなければ false。
+func (s HueWordStats) Mode() (HueColor, bool) {
+	var (
+		mode HueColor
+		best int
+	)
+	for _, color := range s.colors {
+		if n := s.counts[color]; n > best {
+			mode, best = color, n
+		}
+	}
+	return mode, best > 0
+}
+
+// NormalizedEntropy は色分布のシャノンエントロピーを log(色数) で割った値 (0〜1)。
+// 全員が同じ色なら 0、パレットの全色に均等に割れれば 1 になる。回答がなければ 0。
+func (s HueWordStats) NormalizedEntropy() float64 {
+	if s.total == 0 {
+		return 0
+	}
+
+	var entropy float64
+	for _, n := range s.counts {
+		if n == 0 {
+			continue
+		}
+		p := float64(n) / float64(s.total)
+		entropy -= p * math.Log(p)
+	}
+	if len(s.colors) < 2 {
+		return 0
+	}
+	return entropy / math.Log(float64(len(s.colors)))
+}
+
+// Agreement は回答の一致度 (1 - NormalizedEntropy)。
+func (s HueWordStats) Agreement() float64 {
+	return 1 - s.NormalizedEntropy()
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// elevenColors は統計の検証に使う 11 色のパレット順。
+var elevenColors = []HueColor{"black", "gray", "white", "pink", "red", "orange", "yellow", "green", "blue", "purple", "brown"}
+
+func TestNewHueWordStats(t *testing.T) {
+	stats, err := NewHueWordStats("夜", elevenColors, map[HueColor]int{"black": 3, "blue": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Total() != 4 {
+		t.Fatalf("expected total 4, got %d", stats.Total())
+	}
+	if stats.Count("black") != 3 || stats.Count("red") != 0 {
+		t.Fatalf("unexpected counts: black=%d red=%d", stats.Count("black"), stats.Count("red"))
+	}
+	if mode, ok := stats.Mode(); !ok || mode != "black" {
+		t.Fatalf("expected mode black, got %q (%v)", mode, ok)
+	}
+
+	// -(0.75 ln 0.75 + 0.25 ln 0.25) / ln 11
+	want := -(0.75*math.Log(0.75) + 0.25*math.Log(0.25)) / math.Log(11)
+	if math.Abs(stats.NormalizedEntropy()-want) > 1e-9 {
+		t.Fatalf("expected entropy %f, got %f", want, stats.NormalizedEntropy())
+	}
+	if math.Abs(stats.Agreement()-(1-want)) > 1e-9 {
+		t.Fatalf("expected agreement %f, got %f", 1-want, stats.Agreement())
+	}
+}
+
+func TestHueWordStats_Extremes(t *testing.T) {
+	unanimous, _ := NewHueWordStats("雪", elevenColors, map[HueColor]int{"white": 5})
+	if unanimous.NormalizedEntropy() != 0 || unanimous.Agreement() != 1 {
+		t.Fatalf("unanimous answers should have zero entropy, got %f", unanimous.NormalizedEntropy())
+	}
+
+	uniform := make(map[HueColor]int)
+	for _, c := range elevenColors {
+		uniform[c] = 2
+	}
+	spread, _ := NewHueWordStats("謎", elevenColors, uniform)
+	if math.Abs(spread.NormalizedEntropy()-1) > 1e-9 {
+		t.Fatalf("uniform answers should have entropy 1, got %f", spread.NormalizedEntropy())
+	}
+	if mode, _ := spread.Mode(); mode != "black" {
+		t.Fatalf("ties should resolve in palette order, got %q", mode)
+	}
+
+	empty, _ := NewHueWordStats("無", elevenColors, nil)
+	if _, ok := empty.Mode(); ok || empty.NormalizedEntropy() != 0 {
+		t.Fatalf("empty stats should have no mode and zero entropy")
+	}
+}
+
+func TestNewHueWordStats_Invalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		word   HueWord
+		counts map[HueColor]int
+	}{
+		{"empty word", "", map[HueColor]int{"black": 1}},
+		{"unknown color", "夜", map[HueColor]int{"gold": 1}},
+		{"negative count", "夜", map[HueColor]int{"black": -1}},
+	}
+	for _, tc := range cases {
+		if _, err := NewHueWordStats(tc.word, elevenColors, tc.counts); !errors.Is(err, ErrInvalidChoice) {
+			t.Fatalf("%s: expected ErrInvalidChoice, got %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "strings"
+
+// MaxIdempotencyKeyLength は Idempotency-Key ヘッダーとして受け付ける最長の長さ。
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKey はクライアントが 1 回の送信ごとに決める再送判定用のキー。同じキーで送り直された回答は保存し直さない。
+// UUID を推奨するが、空白や制御文字を含まない ASCII なら形式は問わない。
+type IdempotencyKey string
+
+func (k IdempotencyKey) String() string {
+	return string(k)
+}
+
+// NewIdempotencyKey は空か長すぎるか、表示可能な ASCII 以外を含めば ErrInvalidIdempotencyKey を返す。
+func NewIdempotencyKey(value string) (IdempotencyKey, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > MaxIdempotencyKeyLength {
+		return "", ErrInvalidIdempotencyKey
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < '!' || value[i] > '~' {
+			return "", ErrInvalidIdempotencyKey
+		}
+	}
+	return IdempotencyKey(value), nil
+}
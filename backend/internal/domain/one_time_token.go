@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const oneTimeTokenByteLength = 32
+
+// OneTimeToken はメールで送る "<selector>.<verifier>" 形式の使い捨てトークン。
+// ログインセッションと同じく、セレクタで 1 行を引いて検証子のハッシュを定数時間で比較する。
+type OneTimeToken struct {
+	id       uuid.UUID
+	verifier string
+}
+
+func NewOneTimeToken() (OneTimeToken, error) {
+	verifierBytes := make([]byte, oneTimeTokenByteLength)
+	if _, err := rand.Read(verifierBytes); err != nil {
+		return OneTimeToken{}, err
+	}
+	return OneTimeToken{id: uuid.New(), verifier: base64.RawURLEncoding.EncodeToString(verifierBytes)}, nil
+}
+
+// ParseOneTimeToken は形式が不正なら ErrInvalidToken を返す。
+func ParseOneTimeToken(value string) (OneTimeToken, error) {
+	selector, verifier, ok := strings.Cut(strings.TrimSpace(value), tokenSeparator)
+	if !ok {
+		return OneTimeToken{}, ErrInvalidToken
+	}
+
+	id, err := uuid.Parse(selector)
+	if err != nil || id == uuid.Nil {
+		return OneTimeToken{}, ErrInvalidToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(verifier)
+	if err != nil || len(decoded) != oneTimeTokenByteLength {
+		return OneTimeToken{}, ErrInvalidToken
+	}
+
+	return OneTimeToken{id: id, verifier: verifier}, nil
+}
+
+func (t OneTimeToken) String() string {
+	if t.isZero() {
+		return ""
+	}
+	return t.id.String() + tokenSeparator + t.verifier
+}
+
+// ID はトークンのセレクタ、すなわち保存先の行の主キーを返す。
+func (t OneTimeToken) ID() uuid.UUID {
+	return t.id
+}
+
+func (t OneTimeToken) isZero() bool {
+	return t.id == uuid.Nil || t.verifier == ""
+}
+
+// Hash は検証子の SHA-256 を16進で返す。
+func (t OneTimeToken) Hash() string {
+	sum := sha256.Sum256([]byte(t.verifier))
+	return hex.EncodeToString(sum[:])
+}
+
+// oneTimeTokenRecord は使い捨てトークンの保存形。検証子のハッシュだけを保持し、一度使うと usedAt が記録される。
+type oneTimeTokenRecord struct {
+	id        uuid.UUID
+	userID    uuid.UUID
+	tokenHash string
+	expiresAt time.Time
+	createdAt time.Time
+	usedAt    time.Time
+}
+
+func (r oneTimeTokenRecord) ID() uuid.UUID {
+	return r.id
+}
+
+func (r oneTimeTokenRecord) UserID() uuid.UUID {
+	return r.userID
+}
+
+func (r oneTimeTokenRecord) TokenHash() string {
+	return r.tokenHash
+}
+
+func (r oneTimeTokenRecord) ExpiresAt() time.Time {
+	return r.expiresAt
+}
+
+func (r oneTimeTokenRecord) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// UsedAt は使用済みならその時刻を返す。
+func (r oneTimeTokenRecord) UsedAt() (time.Time, bool) {
+	return r.usedAt, !r.usedAt.IsZero()
+}
+
+// accepts はトークンが一致し、未使用かつ now の時点で有効期限内かを返す。
+func (r oneTimeTokenRecord) accepts(token OneTimeToken, now time.Time) bool {
+	if token.isZero() || token.ID() != r.id {
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(r.tokenHash), []byte(token.Hash())) != 1 {
+		return false
+	}
+	return r.usedAt.IsZero() && now.UTC().Before(r.expiresAt)
+}
+
+func issueOneTimeTokenRecord(userID uuid.UUID, token OneTimeToken, issuedAt time.Time, ttl time.Duration) (oneTimeTokenRecord, bool) {
+	issued := issuedAt.UTC()
+	if issued.IsZero() || token.isZero() || ttl <= 0 {
+		return oneTimeTokenRecord{}, false
+	}
+	return buildOneTimeTokenRecord(token.ID(), userID, token.Hash(), issued, issued.Add(ttl), time.Time{})
+}
+
+func buildOneTimeTokenRecord(id, userID uuid.UUID, tokenHash string, createdAt, expiresAt, usedAt time.Time) (oneTimeTokenRecord, bool) {
+	if id == uuid.Nil || userID == uuid.Nil || strings.TrimSpace(tokenHash) == "" {
+		return oneTimeTokenRecord{}, false
+	}
+
+	created := createdAt.UTC()
+	expires := expiresAt.UTC()
+	if created.IsZero() || expires.IsZero() || !expires.After(created) {
+		return oneTimeTokenRecord{}, false
+	}
+
+	return oneTimeTokenRecord{
+		id:        id,
+		userID:    userID,
+		tokenHash: strings.TrimSpace(tokenHash),
+		createdAt: created,
+		expiresAt: expires,
+		usedAt:    usedAt.UTC(),
+	}, true
+}
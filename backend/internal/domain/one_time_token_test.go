@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestOneTimeToken_RoundTrip(t *testing.T) {
+	token, err := NewOneTimeToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+
+	parsed, err := ParseOneTimeToken(token.String())
+	if err != nil || parsed != token {
+		t.Fatalf("expected round trip, got %v (%v)", parsed, err)
+	}
+
+	for _, bad := range []string{"", "no-separator", uuid.Nil.String() + ".abc", uuid.NewString() + ".short"} {
+		if _, err := ParseOneTimeToken(bad); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken for %q, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultRecordPageSize は件数を指定しなかった場合のページサイズ。
+	DefaultRecordPageSize = 25
+	// MaxRecordPageSize は 1 ページで返す最大件数。
+	MaxRecordPageSize = 100
+)
+
+// recordCursorSize は created_at (UnixNano, 8 バイト) と id (16 バイト) を並べたカーソルの長さ。
+const recordCursorSize = 8 + 16
+
+// RecordCursor は (created_at, id) 昇順で並べたときの直前のレコードの位置を表す。
+// クライアントには中身を解釈させない不透明な文字列として渡す。
+type RecordCursor struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+// RecordCursorAfter は record の直後から読み進めるためのカーソルを返す。
+func RecordCursorAfter(record HueRecord) RecordCursor {
+	return RecordCursor{createdAt: record.CreatedAt(), id: record.ID()}
+}
+
+// ParseRecordCursor は String で生成した文字列を復元する。空文字列はゼロ値 (先頭から) として扱う。
+func ParseRecordCursor(value string) (RecordCursor, error) {
+	if value == "" {
+		return RecordCursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) != recordCursorSize {
+		return RecordCursor{}, ErrInvalidCursor
+	}
+
+	id, err := uuid.FromBytes(raw[8:])
+	if err != nil || id == uuid.Nil {
+		return RecordCursor{}, ErrInvalidCursor
+	}
+
+	nanos := int64(binary.BigEndian.Uint64(raw[:8]))
+	return RecordCursor{createdAt: time.Unix(0, nanos).UTC(), id: id}, nil
+}
+
+func (c RecordCursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+
+	raw := make([]byte, recordCursorSize)
+	binary.BigEndian.PutUint64(raw[:8], uint64(c.createdAt.UnixNano()))
+	copy(raw[8:], c.id[:])
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (c RecordCursor) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+func (c RecordCursor) ID() uuid.UUID {
+	return c.id
+}
+
+// IsZero は先頭ページを指すカーソルかどうかを返す。
+func (c RecordCursor) IsZero() bool {
+	return c.id == uuid.Nil
+}
+
+// RecordPageRequest は filter に一致するレコードを after の直後から limit 件取得する要求。
+type RecordPageRequest struct {
+	filter       RecordFilter
+	after        RecordCursor
+	limit        int
+	includeTotal bool
+}
+
+// NewRecordPageRequest は limit が 0 なら DefaultRecordPageSize を使い、負や MaxRecordPageSize 超過は ErrInvalidRange を返す。
+func NewRecordPageRequest(filter RecordFilter, after RecordCursor, limit int, includeTotal bool) (RecordPageRequest, error) {
+	if limit == 0 {
+		limit = DefaultRecordPageSize
+	}
+	if limit < 0 || limit > MaxRecordPageSize {
+		return RecordPageRequest{}, ErrInvalidRange
+	}
+
+	return RecordPageRequest{filter: filter, after: after, limit: limit, includeTotal: includeTotal}, nil
+}
+
+func (r RecordPageRequest) Filter() RecordFilter {
+	return r.filter
+}
+
+func (r RecordPageRequest) After() RecordCursor {
+	return r.after
+}
+
+func (r RecordPageRequest) Limit() int {
+	return r.limit
+}
+
+// IncludeTotal は filter に一致する件数の COUNT を合わせて求めるかを返す。
+func (r RecordPageRequest) IncludeTotal() bool {
+	return r.includeTotal
+}
+
+// RecordPage は 1 ページ分のレコードと、続きがあればその位置を保持する。
+type RecordPage struct {
+	records  []HueRecord
+	next     RecordCursor
+	total    int
+	hasTotal bool
+}
+
+// NewRecordPage は fetched に limit+1 件まで受け取り、limit を超えた分があれば次ページのカーソルを設定する。
+// total が負の場合は件数を求めなかったものとする。
+func NewRecordPage(fetched []HueRecord, limit int, total int) RecordPage {
+	page := RecordPage{records: fetched, total: total, hasTotal: total >= 0}
+	if len(fetched) > limit {
+		page.records = fetched[:limit]
+		page.next = RecordCursorAfter(fetched[limit-1])
+	}
+	return page
+}
+
+func (p RecordPage) Records() []HueRecord {
+	return p.records
+}
+
+// Next は次ページのカーソルを返す。最後のページならゼロ値。
+func (p RecordPage) Next() RecordCursor {
+	return p.next
+}
+
+// Total は全件数を返す。求めていなければ false。
+func (p RecordPage) Total() (int, bool) {
+	return p.total, p.hasTotal
+}
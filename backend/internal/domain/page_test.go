@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRecordCursor_RoundTrip(t *testing.T) {
+	record := buildPersistedHueRecord(t, time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC))
+	cursor := RecordCursorAfter(record)
+
+	parsed, err := ParseRecordCursor(cursor.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parsed.ID() != record.ID() || !parsed.CreatedAt().Equal(record.CreatedAt()) {
+		t.Fatalf("expected round trip, got %v / %v", parsed.ID(), parsed.CreatedAt())
+	}
+}
+
+func TestParseRecordCursor_Empty(t *testing.T) {
+	cursor, err := ParseRecordCursor("")
+	if err != nil || !cursor.IsZero() || cursor.String() != "" {
+		t.Fatalf("empty cursor should mean the first page, got %v (%v)", cursor, err)
+	}
+}
+
+func TestParseRecordCursor_Invalid(t *testing.T) {
+	for _, value := range []string{"!!!", "AAAA", RecordCursor{}.String() + "x"} {
+		if _, err := ParseRecordCursor(value); !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("%q: expected ErrInvalidCursor, got %v", value, err)
+		}
+	}
+}
+
+func TestNewRecordPageRequest(t *testing.T) {
+	req, err := NewRecordPageRequest(RecordFilter{}, RecordCursor{}, 0, false)
+	if err != nil || req.Limit() != DefaultRecordPageSize {
+		t.Fatalf("expected default page size, got %d (%v)", req.Limit(), err)
+	}
+
+	if _, err := NewRecordPageRequest(RecordFilter{}, RecordCursor{}, MaxRecordPageSize, false); err != nil {
+		t.Fatalf("max page size should be accepted: %v", err)
+	}
+
+	for _, limit := range []int{-1, MaxRecordPageSize + 1} {
+		if _, err := NewRecordPageRequest(RecordFilter{}, RecordCursor{}, limit, false); !errors.Is(err, ErrInvalidRange) {
+			t.Fatalf("limit %d: expected ErrInvalidRange, got %v", limit, err)
+		}
+	}
+}
+
+func TestNewRecordPage(t *testing.T) {
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	var fetched []HueRecord
+	for i := 0; i < 3; i++ {
+		fetched = append(fetched, buildPersistedHueRecord(t, base.Add(time.Duration(i)*time.Second)))
+	}
+
+	page := NewRecordPage(fetched, 2, -1)
+	if len(page.Records()) != 2 {
+		t.Fatalf("expected page to be trimmed to limit, got %d", len(page.Records()))
+	}
+	if page.Next().ID() != fetched[1].ID() {
+		t.Fatalf("expected next cursor to point at the last returned record")
+	}
+	if _, ok := page.Total(); ok {
+		t.Fatalf("negative total should mean not requested")
+	}
+
+	last := NewRecordPage(fetched[:2], 2, 2)
+	if !last.Next().IsZero() {
+		t.Fatalf("expected no next cursor on the last page")
+	}
+	if total, ok := last.Total(); !ok || total != 2 {
+		t.Fatalf("expected total 2, got %d (%v)", total, ok)
+	}
+}
+
+func buildPersistedHueRecord(t *testing.T, createdAt time.Time) HueRecord {
+	t.Helper()
+	name, _ := NewName("Tester")
+	choices, err := NewHueChoices(map[string]string{"夜": "黒"})
+	if err != nil {
+		t.Fatalf("choices error: %v", err)
+	}
+	record, err := NewHueRecordFromPersistence(uuid.New(), name, choices, uuid.Nil, createdAt)
+	if err != nil {
+		t.Fatalf("record error: %v", err)
+	}
+	return record
+}
@@ -0,0 +1,272 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// パレットの上限。色名は回答画面のボタンに収まる長さに抑える。
+const (
+	MinPaletteColors     = 2
+	MaxPaletteColors     = 64
+	MaxPaletteColorName  = 32
+	PaletteDefaultLocale = "ja"
+)
+
+var (
+	paletteColorIDRe = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+	paletteLocaleRe  = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+)
+
+// RGB は sRGB の 8 bit 値。
+type RGB struct {
+	R, G, B uint8
+}
+
+// ParseRGB は "#rrggbb" 形式を読む。形式が違えば ErrInvalidPalette を返す。
+func ParseRGB(hex string) (RGB, error) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return RGB{}, ErrInvalidPalette
+	}
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return RGB{}, ErrInvalidPalette
+	}
+	return RGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+// Hex は "#rrggbb" (小文字) を返す。
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Lab は CIE L*a*b* (D65) の値。
+type Lab struct {
+	L, A, B float64
+}
+
+func (l Lab) valid() bool {
+	return l.L >= 0 && l.L <= 100 && !math.IsNaN(l.A) && !math.IsNaN(l.B) && math.Abs(l.A) <= 200 && math.Abs(l.B) <= 200
+}
+
+// LabFromRGB は sRGB を D65 白色点の L*a*b* に変換し、小数第 2 位に丸める。
+func LabFromRGB(c RGB) Lab {
+	linear := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+
+	round := func(v float64) float64 { return math.Round(v*100) / 100 }
+	return Lab{L: round(116*fy - 16), A: round(500 * (fx - fy)), B: round(200 * (fy - fz))}
+}
+
+// PaletteColor はパレットの 1 色。id は回答に保存する安定した識別子で、表示名はロケールごとに持つ。
+type PaletteColor struct {
+	id    HueColor
+	names map[string]string
+	rgb   RGB
+	lab   Lab
+}
+
+// NewPaletteColor は id が英小文字・数字・ハイフンでない、既定ロケール (ja) の名前が無い、
+// 名前や Lab 値が不正な場合に ErrInvalidPalette を返す。
+func NewPaletteColor(id string, names map[string]string, rgb RGB, lab Lab) (PaletteColor, error) {
+	if !paletteColorIDRe.MatchString(id) || !lab.valid() {
+		return PaletteColor{}, ErrInvalidPalette
+	}
+
+	trimmed := make(map[string]string, len(names))
+	for locale, name := range names {
+		name = strings.TrimSpace(name)
+		if !paletteLocaleRe.MatchString(locale) || name == "" || utf8.RuneCountInString(name) > MaxPaletteColorName {
+			return PaletteColor{}, ErrInvalidPalette
+		}
+		trimmed[locale] = name
+	}
+	if _, ok := trimmed[PaletteDefaultLocale]; !ok {
+		return PaletteColor{}, ErrInvalidPalette
+	}
+
+	return PaletteColor{id: HueColor(id), names: trimmed, rgb: rgb, lab: lab}, nil
+}
+
+func (c PaletteColor) ID() HueColor {
+	return c.id
+}
+
+// Names はロケールごとの表示名を返す。
+func (c PaletteColor) Names() map[string]string {
+	return maps.Clone(c.names)
+}
+
+// Name は locale の表示名を返す。無ければ既定ロケールの名前を返す。
+func (c PaletteColor) Name(locale string) string {
+	if name, ok := c.names[locale]; ok {
+		return name
+	}
+	return c.names[PaletteDefaultLocale]
+}
+
+func (c PaletteColor) RGB() RGB {
+	return c.rgb
+}
+
+func (c PaletteColor) Lab() Lab {
+	return c.lab
+}
+
+// Palette は回答で選べる色の一覧の 1 つの版。作った後は色を変えず、色を変えたいときは新しい版を作る。
+// 出題に使うのは有効な 1 つだけ。色の順は回答画面と統計の表示順。
+type Palette struct {
+	id        uuid.UUID
+	version   int
+	colors    []PaletteColor
+	active    bool
+	createdAt time.Time
+}
+
+// NewPalette は新しい版を無効な状態で作る。
+func NewPalette(version int, colors []PaletteColor, now time.Time) (Palette, error) {
+	return buildPalette(uuid.New(), version, colors, false, now)
+}
+
+// NewPaletteFromPersistence は永続化済みのパレットを再構築する。
+func NewPaletteFromPersistence(id uuid.UUID, version int, colors []PaletteColor, active bool, createdAt time.Time) (Palette, error) {
+	return buildPalette(id, version, colors, active, createdAt)
+}
+
+func (p Palette) ID() uuid.UUID {
+	return p.id
+}
+
+func (p Palette) Version() int {
+	return p.version
+}
+
+// Colors は表示順の色を返す。
+func (p Palette) Colors() []PaletteColor {
+	return slices.Clone(p.colors)
+}
+
+// ColorIDs は表示順の色 ID を返す。
+func (p Palette) ColorIDs() []HueColor {
+	ids := make([]HueColor, len(p.colors))
+	for i, c := range p.colors {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+func (p Palette) IsActive() bool {
+	return p.active
+}
+
+func (p Palette) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// Activate は有効にしたコピーを返す。他の版を無効にするのはリポジトリの役目。
+func (p Palette) Activate() Palette {
+	p.active = true
+	return p
+}
+
+// Resolve は色 ID かいずれかのロケールの表示名から色 ID を引く。見つからなければ false。
+func (p Palette) Resolve(value string) (HueColor, bool) {
+	for _, c := range p.colors {
+		if string(c.id) == value {
+			return c.id, true
+		}
+	}
+	for _, c := range p.colors {
+		for _, name := range c.names {
+			if name == value {
+				return c.id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Paint は record の色を色 ID にそろえ、この版で回答したものとして紐づけたコピーを返す。
+// 回答にこの版に無い色があれば ErrInvalidChoice を返す。
+func (p Palette) Paint(record HueRecord) (HueRecord, error) {
+	resolved := make(map[HueWord]HueColor, len(record.choices.values))
+	for word, color := range record.choices.values {
+		id, ok := p.Resolve(string(color))
+		if !ok {
+			return HueRecord{}, ErrInvalidChoice
+		}
+		resolved[word] = id
+	}
+
+	record.choices = HueChoices{values: resolved}
+	record.paletteVersion = p.version
+	return record, nil
+}
+
+func buildPalette(id uuid.UUID, version int, colors []PaletteColor, active bool, createdAt time.Time) (Palette, error) {
+	if id == uuid.Nil || version <= 0 || createdAt.IsZero() {
+		return Palette{}, ErrInvalidPalette
+	}
+	if len(colors) < MinPaletteColors || len(colors) > MaxPaletteColors {
+		return Palette{}, ErrInvalidPalette
+	}
+
+	// Resolve が一意に引けるよう、ID と表示名はパレット全体で重複させない。
+	ids := make(map[HueColor]struct{}, len(colors))
+	for _, c := range colors {
+		if c.id == "" {
+			return Palette{}, ErrInvalidPalette
+		}
+		if _, dup := ids[c.id]; dup {
+			return Palette{}, ErrInvalidPalette
+		}
+		ids[c.id] = struct{}{}
+	}
+	owners := make(map[string]HueColor)
+	for _, c := range colors {
+		for _, name := range c.names {
+			if _, isID := ids[HueColor(name)]; isID && HueColor(name) != c.id {
+				return Palette{}, ErrInvalidPalette
+			}
+			if owner, seen := owners[name]; seen && owner != c.id {
+				return Palette{}, ErrInvalidPalette
+			}
+			owners[name] = c.id
+		}
+	}
+
+	return Palette{
+		id:        id,
+		version:   version,
+		colors:    slices.Clone(colors),
+		active:    active,
+		createdAt: createdAt.UTC(),
+	}, nil
+}
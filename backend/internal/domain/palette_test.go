@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// buildTestPalette は黒・青・白の 3 色で version の版を作る。
+func buildTestPalette(t *testing.T, version int) Palette {
+	t.Helper()
+
+	var colors []PaletteColor
+	for _, c := range []struct{ id, ja, en, hex string }{
+		{"black", "黒", "Black", "#444444"},
+		{"blue", "青", "Blue", "#4f86e2"},
+		{"white", "白", "White", "#f5f5f5"},
+	} {
+		rgb, err := ParseRGB(c.hex)
+		if err != nil {
+			t.Fatalf("parse rgb: %v", err)
+		}
+		color, err := NewPaletteColor(c.id, map[string]string{"ja": c.ja, "en": c.en}, rgb, LabFromRGB(rgb))
+		if err != nil {
+			t.Fatalf("palette color: %v", err)
+		}
+		colors = append(colors, color)
+	}
+
+	palette, err := NewPalette(version, colors, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("palette: %v", err)
+	}
+	return palette
+}
+
+func TestParseRGB(t *testing.T) {
+	rgb, err := ParseRGB("#4F86e2")
+	if err != nil || rgb != (RGB{R: 0x4f, G: 0x86, B: 0xe2}) || rgb.Hex() != "#4f86e2" {
+		t.Fatalf("unexpected rgb %+v (%v)", rgb, err)
+	}
+
+	for _, raw := range []string{"", "4f86e2", "#4f86e", "#4f86e2ff", "#4g86e2", "#+f86e2"} {
+		if _, err := ParseRGB(raw); !errors.Is(err, ErrInvalidPalette) {
+			t.Fatalf("%q: expected ErrInvalidPalette, got %v", raw, err)
+		}
+	}
+}
+
+func TestLabFromRGB(t *testing.T) {
+	cases := []struct {
+		rgb  RGB
+		want Lab
+	}{
+		{RGB{0, 0, 0}, Lab{0, 0, 0}},
+		{RGB{255, 255, 255}, Lab{100, 0, 0}},
+		{RGB{255, 0, 0}, Lab{53.24, 80.09, 67.2}},
+	}
+	for _, tc := range cases {
+		if got := LabFromRGB(tc.rgb); got != tc.want {
+			t.Fatalf("%s: expected %+v, got %+v", tc.rgb.Hex(), tc.want, got)
+		}
+	}
+}
+
+func TestNewPaletteColor_Invalid(t *testing.T) {
+	cases := []struct {
+		name  string
+		id    string
+		names map[string]string
+		lab   Lab
+	}{
+		{"upper case id", "Black", map[string]string{"ja": "黒"}, Lab{}},
+		{"blank id", "", map[string]string{"ja": "黒"}, Lab{}},
+		{"missing default locale", "black", map[string]string{"en": "Black"}, Lab{}},
+		{"blank name", "black", map[string]string{"ja": " "}, Lab{}},
+		{"bad locale", "black", map[string]string{"ja": "黒", "English": "Black"}, Lab{}},
+		{"lightness out of range", "black", map[string]string{"ja": "黒"}, Lab{L: 120}},
+	}
+	for _, tc := range cases {
+		if _, err := NewPaletteColor(tc.id, tc.names, RGB{}, tc.lab); !errors.Is(err, ErrInvalidPalette) {
+			t.Fatalf("%s: expected ErrInvalidPalette, got %v", tc.name, err)
+		}
+	}
+
+	color, err := NewPaletteColor("black", map[string]string{"ja": " 黒 ", "en": "Black"}, RGB{}, Lab{})
+	if err != nil || color.Name("ja") != "黒" || color.Name("fr") != "黒" || color.Name("en") != "Black" {
+		t.Fatalf("expected trimmed names with ja fallback, got %+v (%v)", color.Names(), err)
+	}
+}
+
+func TestNewPalette_Invalid(t *testing.T) {
+	now := time.Now()
+	black, _ := NewPaletteColor("black", map[string]string{"ja": "黒"}, RGB{}, Lab{})
+	otherBlack, _ := NewPaletteColor("charcoal", map[string]string{"ja": "黒"}, RGB{}, Lab{})
+	namedLikeID, _ := NewPaletteColor("ink", map[string]string{"ja": "black"}, RGB{}, Lab{})
+
+	cases := []struct {
+		name    string
+		version int
+		colors  []PaletteColor
+	}{
+		{"zero version", 0, []PaletteColor{black, otherBlack}},
+		{"single color", 1, []PaletteColor{black}},
+		{"duplicate id", 1, []PaletteColor{black, black}},
+		{"duplicate name", 1, []PaletteColor{black, otherBlack}},
+		{"name shadows id", 1, []PaletteColor{black, namedLikeID}},
+	}
+	for _, tc := range cases {
+		if _, err := NewPalette(tc.version, tc.colors, now); !errors.Is(err, ErrInvalidPalette) {
+			t.Fatalf("%s: expected ErrInvalidPalette, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestPalette_Paint(t *testing.T) {
+	palette := buildTestPalette(t, 2)
+
+	record, _ := NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒", "海": "Blue", "雪": "white"})
+	if _, ok := record.PaletteVersion(); ok {
+		t.Fatalf("a new record should not have a palette yet")
+	}
+
+	painted, err := palette.Paint(record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version, ok := painted.PaletteVersion(); !ok || version != 2 {
+		t.Fatalf("expected palette version 2, got %d (%v)", version, ok)
+	}
+	want := map[string]string{"夜": "black", "海": "blue", "雪": "white"}
+	for word, color := range want {
+		if painted.ChoiceMap()[word] != color {
+			t.Fatalf("expected %s=%s, got %v", word, color, painted.ChoiceMap())
+		}
+	}
+	if record.ChoiceMap()["夜"] != "黒" {
+		t.Fatalf("Paint should not modify the original record")
+	}
+
+	unknown, _ := NewHueRecordFromRaw("Tester", map[string]string{"夜": "金"})
+	if _, err := palette.Paint(unknown); !errors.Is(err, ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHashAlgorithm は新しく作るハッシュの方式。照合は保存済みハッシュの形式から方式を判断する。
+type PasswordHashAlgorithm string
+
+const (
+	PasswordHashBcrypt   PasswordHashAlgorithm = "bcrypt"
+	PasswordHashArgon2id PasswordHashAlgorithm = "argon2id"
+)
+
+func (a PasswordHashAlgorithm) String() string {
+	return string(a)
+}
+
+// NewPasswordHashAlgorithm は未知の方式なら ErrInvalidPasswordHasher を返す。
+func NewPasswordHashAlgorithm(value string) (PasswordHashAlgorithm, error) {
+	switch algorithm := PasswordHashAlgorithm(strings.ToLower(strings.TrimSpace(value))); algorithm {
+	case PasswordHashBcrypt, PasswordHashArgon2id:
+		return algorithm, nil
+	default:
+		return "", ErrInvalidPasswordHasher
+	}
+}
+
+// 既定の argon2id パラメータ (OWASP の推奨値)。メモリは KiB 単位。
+const (
+	DefaultArgon2Memory      uint32 = 19 * 1024
+	DefaultArgon2Iterations  uint32 = 2
+	DefaultArgon2Parallelism uint8  = 1
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	argon2Prefix     = "$argon2id$"
+	bcryptPrefix     = "$2"
+)
+
+// PasswordHasher は新しいパスワードのハッシュを作り、保存済みハッシュが現在の設定より古いかを判定する。
+type PasswordHasher interface {
+	Hash(password string) (HashedPassword, error)
+	// NeedsRehash は方式かパラメータが現在の設定と違えば true を返す。
+	NeedsRehash(hashed HashedPassword) bool
+}
+
+// BcryptHasher は bcrypt の $2a$ 形式でハッシュを作る。
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher は cost が bcrypt の範囲外なら ErrInvalidPasswordHasher を返す。
+func NewBcryptHasher(cost int) (BcryptHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return BcryptHasher{}, ErrInvalidPasswordHasher
+	}
+	return BcryptHasher{cost: cost}, nil
+}
+
+// DefaultBcryptHasher は bcrypt.DefaultCost の BcryptHasher を返す。
+func DefaultBcryptHasher() BcryptHasher {
+	return BcryptHasher{cost: bcrypt.DefaultCost}
+}
+
+func (h BcryptHasher) Cost() int {
+	return h.cost
+}
+
+func (h BcryptHasher) Hash(password string) (HashedPassword, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return HashedPassword{}, err
+	}
+	return NewHashedPassword(string(hashed))
+}
+
+func (h BcryptHasher) NeedsRehash(hashed HashedPassword) bool {
+	if hashed.isArgon2id() {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hashed.value))
+	return err != nil || cost != h.cost
+}
+
+// Argon2idHasher は PHC 形式 ($argon2id$v=19$m=...,t=...,p=...$salt$hash) でハッシュを作る。
+type Argon2idHasher struct {
+	params argon2Params
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// NewArgon2idHasher は memory (KiB)・iterations・parallelism のいずれかが不正なら ErrInvalidPasswordHasher を返す。
+// argon2 の制約どおり、memory は 8×parallelism KiB 以上でなければならない。
+func NewArgon2idHasher(memory, iterations uint32, parallelism uint8) (Argon2idHasher, error) {
+	if iterations < 1 || parallelism < 1 || memory < 8*uint32(parallelism) {
+		return Argon2idHasher{}, ErrInvalidPasswordHasher
+	}
+	return Argon2idHasher{params: argon2Params{memory: memory, iterations: iterations, parallelism: parallelism}}, nil
+}
+
+// DefaultArgon2idHasher は既定のパラメータの Argon2idHasher を返す。
+func DefaultArgon2idHasher() Argon2idHasher {
+	return Argon2idHasher{params: argon2Params{memory: DefaultArgon2Memory, iterations: DefaultArgon2Iterations, parallelism: DefaultArgon2Parallelism}}
+}
+
+func (h Argon2idHasher) Memory() uint32 {
+	return h.params.memory
+}
+
+func (h Argon2idHasher) Iterations() uint32 {
+	return h.params.iterations
+}
+
+func (h Argon2idHasher) Parallelism() uint8 {
+	return h.params.parallelism
+}
+
+func (h Argon2idHasher) Hash(password string) (HashedPassword, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return HashedPassword{}, err
+	}
+	key := h.params.key(password, salt, argon2KeyLength)
+
+	encoded := fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix, argon2.Version, h.params.memory, h.params.iterations, h.params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+	return NewHashedPassword(encoded)
+}
+
+func (h Argon2idHasher) NeedsRehash(hashed HashedPassword) bool {
+	if !hashed.isArgon2id() {
+		return true
+	}
+	params, _, key, err := parseArgon2id(hashed.value)
+	return err != nil || params != h.params || len(key) != argon2KeyLength
+}
+
+func (p argon2Params) key(password string, salt []byte, length uint32) []byte {
+	return argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, length)
+}
+
+// parseArgon2id は PHC 形式の argon2id ハッシュを分解する。形式が違えば ErrInvalidPasswordHash を返す。
+func parseArgon2id(encoded string) (argon2Params, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return argon2Params{}, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return argon2Params{}, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var params argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return argon2Params{}, nil, nil, ErrInvalidPasswordHash
+	}
+	if params.iterations < 1 || params.parallelism < 1 {
+		return argon2Params{}, nil, nil, ErrInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return argon2Params{}, nil, nil, ErrInvalidPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return argon2Params{}, nil, nil, ErrInvalidPasswordHash
+	}
+	return params, salt, key, nil
+}
+
+func verifyArgon2id(encoded, plain string) error {
+	params, salt, key, err := parseArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(params.key(plain, salt, uint32(len(key))), key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
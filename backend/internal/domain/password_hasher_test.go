@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHashers_HashAndVerify(t *testing.T) {
+	fastBcrypt, err := NewBcryptHasher(bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt hasher error: %v", err)
+	}
+	fastArgon2, err := NewArgon2idHasher(64, 1, 1)
+	if err != nil {
+		t.Fatalf("argon2id hasher error: %v", err)
+	}
+
+	for _, hasher := range []PasswordHasher{fastBcrypt, fastArgon2} {
+		hashed, err := hasher.Hash("correct horse")
+		if err != nil {
+			t.Fatalf("%T: hash error: %v", hasher, err)
+		}
+		if err := hashed.Verify("correct horse"); err != nil {
+			t.Fatalf("%T: expected password to match: %v", hasher, err)
+		}
+		if err := hashed.Verify("wrong horse"); !errors.Is(err, ErrPasswordMismatch) {
+			t.Fatalf("%T: expected ErrPasswordMismatch, got %v", hasher, err)
+		}
+		if hasher.NeedsRehash(hashed) {
+			t.Fatalf("%T: expected fresh hash not to need rehash", hasher)
+		}
+		if hashed.IsLegacyTrimmed() {
+			t.Fatalf("%T: expected fresh hash not to be marked as legacy", hasher)
+		}
+		// 空白を除いてからハッシュ化していたのは bcrypt だけのため、ほかの方式には印を付けない。
+		if _, isBcrypt := hasher.(BcryptHasher); hashed.LegacyTrimmed().IsLegacyTrimmed() != isBcrypt {
+			t.Fatalf("%T: expected legacy mark to be %v for %q", hasher, isBcrypt, hashed)
+		}
+	}
+
+	hashed, _ := fastArgon2.Hash("correct horse")
+	phc := regexp.MustCompile(`^\$argon2id\$v=19\$m=64,t=1,p=1\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`)
+	if !phc.MatchString(hashed.String()) {
+		t.Fatalf("expected PHC formatted hash, got %q", hashed)
+	}
+}
+
+func TestPasswordHashers_NeedsRehash(t *testing.T) {
+	bcryptMin, _ := NewBcryptHasher(bcrypt.MinCost)
+	bcryptMore, _ := NewBcryptHasher(bcrypt.MinCost + 1)
+	argonSmall, _ := NewArgon2idHasher(64, 1, 1)
+	argonLarge, _ := NewArgon2idHasher(128, 2, 1)
+
+	bcryptHash, _ := bcryptMin.Hash("secret")
+	argonHash, _ := argonSmall.Hash("secret")
+
+	cases := []struct {
+		name   string
+		hasher PasswordHasher
+		hashed HashedPassword
+		want   bool
+	}{
+		{name: "bcrypt same cost", hasher: bcryptMin, hashed: bcryptHash, want: false},
+		{name: "bcrypt higher cost", hasher: bcryptMore, hashed: bcryptHash, want: true},
+		{name: "bcrypt to argon2id", hasher: argonSmall, hashed: bcryptHash, want: true},
+		{name: "argon2id to bcrypt", hasher: bcryptMin, hashed: argonHash, want: true},
+		{name: "argon2id same params", hasher: argonSmall, hashed: argonHash, want: false},
+		{name: "argon2id new params", hasher: argonLarge, hashed: argonHash, want: true},
+	}
+	for _, tc := range cases {
+		if got := tc.hasher.NeedsRehash(tc.hashed); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestPasswordHashers_Invalid(t *testing.T) {
+	if _, err := NewBcryptHasher(bcrypt.MaxCost + 1); !errors.Is(err, ErrInvalidPasswordHasher) {
+		t.Fatalf("expected ErrInvalidPasswordHasher for bcrypt cost, got %v", err)
+	}
+	for _, params := range [][3]uint32{{0, 1, 1}, {64, 0, 1}, {64, 1, 0}, {15, 1, 2}} {
+		if _, err := NewArgon2idHasher(params[0], params[1], uint8(params[2])); !errors.Is(err, ErrInvalidPasswordHasher) {
+			t.Fatalf("expected ErrInvalidPasswordHasher for %v, got %v", params, err)
+		}
+	}
+	if _, err := NewPasswordHashAlgorithm("md5"); !errors.Is(err, ErrInvalidPasswordHasher) {
+		t.Fatalf("expected ErrInvalidPasswordHasher for unknown algorithm, got %v", err)
+	}
+
+	for _, encoded := range []string{
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=64,t=1$c2FsdHNhbHRzYWx0c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$aGFzaA",
+	} {
+		hashed, _ := NewHashedPassword(encoded)
+		if err := hashed.Verify("secret"); !errors.Is(err, ErrInvalidPasswordHash) {
+			t.Fatalf("expected ErrInvalidPasswordHash for %q, got %v", encoded, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxPasswordBytes は bcrypt が扱える入力の上限。これを超えた部分は黙って無視されるため受け付けない。
+const MaxPasswordBytes = 72
+
+// 既定のパスワードポリシー。文字種は 1 種類以上、つまり組み合わせを求めない。
+const (
+	DefaultPasswordMinLength  = 8
+	DefaultPasswordMinClasses = 1
+)
+
+// passwordClassCount は数える文字種 (小文字・大文字・数字・それ以外) の数。
+const passwordClassCount = 4
+
+// PasswordRule はパスワードが満たさなかった規則。API のエラー原因にも使う。
+type PasswordRule string
+
+const (
+	PasswordRuleRequired         PasswordRule = "required"
+	PasswordRuleTooShort         PasswordRule = "too_short"
+	PasswordRuleTooLong          PasswordRule = "too_long"
+	PasswordRuleCharacterClasses PasswordRule = "character_classes"
+	PasswordRuleCommon           PasswordRule = "common"
+)
+
+func (r PasswordRule) String() string {
+	return string(r)
+}
+
+// PasswordPolicyError は満たさなかった規則を伴う ErrInvalidPassword。
+type PasswordPolicyError struct {
+	rule    PasswordRule
+	message string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidPassword, e.message)
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrInvalidPassword
+}
+
+func (e *PasswordPolicyError) Rule() PasswordRule {
+	return e.rule
+}
+
+// Message は利用者に見せられる英語の説明を返す。
+func (e *PasswordPolicyError) Message() string {
+	return e.message
+}
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords は小文字にした漏洩頻出パスワードの集合。
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordList, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	return set
+}()
+
+// PasswordPolicy は新しく設定するパスワードに課す規則。既存のパスワードでのログインには適用しない。
+// ゼロ値は空でないことと MaxPasswordBytes 以下であることだけを求める。
+type PasswordPolicy struct {
+	minLength  int
+	minClasses int
+	denyCommon bool
+}
+
+// NewPasswordPolicy は minLength 文字以上、minClasses 種類以上の文字種を求め、denyCommon なら頻出パスワードを拒否するポリシーを返す。
+func NewPasswordPolicy(minLength, minClasses int, denyCommon bool) (PasswordPolicy, error) {
+	if minLength < 0 || minLength > MaxPasswordBytes || minClasses < 0 || minClasses > passwordClassCount {
+		return PasswordPolicy{}, ErrInvalidPasswordPolicy
+	}
+	return PasswordPolicy{minLength: minLength, minClasses: minClasses, denyCommon: denyCommon}, nil
+}
+
+// DefaultPasswordPolicy は 8 文字以上で頻出パスワードを拒否するポリシーを返す。
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{minLength: DefaultPasswordMinLength, minClasses: DefaultPasswordMinClasses, denyCommon: true}
+}
+
+func (p PasswordPolicy) MinLength() int {
+	return p.minLength
+}
+
+func (p PasswordPolicy) MinClasses() int {
+	return p.minClasses
+}
+
+func (p PasswordPolicy) DeniesCommon() bool {
+	return p.denyCommon
+}
+
+// errPasswordRequired はポリシーに関係なく空白だけのパスワードを拒むときに使う。
+func errPasswordRequired() error {
+	return &PasswordPolicyError{rule: PasswordRuleRequired, message: "password is required"}
+}
+
+// Check は password が規則を満たさなければ *PasswordPolicyError を返す。password は加工せず、入力されたとおりに評価する。
+// 長さは文字数で数え、上限だけは bcrypt に合わせてバイト数で数える。
+func (p PasswordPolicy) Check(password string) error {
+	if strings.TrimSpace(password) == "" {
+		return errPasswordRequired()
+	}
+	if len(password) > MaxPasswordBytes {
+		return &PasswordPolicyError{rule: PasswordRuleTooLong, message: fmt.Sprintf("password must be at most %d bytes", MaxPasswordBytes)}
+	}
+	if utf8.RuneCountInString(password) < p.minLength {
+		return &PasswordPolicyError{rule: PasswordRuleTooShort, message: fmt.Sprintf("password must be at least %d characters", p.minLength)}
+	}
+	if passwordClasses(password) < p.minClasses {
+		return &PasswordPolicyError{
+			rule:    PasswordRuleCharacterClasses,
+			message: fmt.Sprintf("password must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.minClasses),
+		}
+	}
+	if p.denyCommon {
+		if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+			return &PasswordPolicyError{rule: PasswordRuleCommon, message: "password is too common"}
+		}
+	}
+	return nil
+}
+
+// passwordClasses は password に含まれる文字種の数を返す。大文字・小文字の区別が無い文字は記号と同じ扱いにする。
+func passwordClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	strict, err := NewPasswordPolicy(10, 3, true)
+	if err != nil {
+		t.Fatalf("policy error: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		rule     PasswordRule
+	}{
+		{name: "blank", policy: DefaultPasswordPolicy(), password: "   ", rule: PasswordRuleRequired},
+		{name: "too short", policy: DefaultPasswordPolicy(), password: "abc12", rule: PasswordRuleTooShort},
+		{name: "multibyte counts characters", policy: DefaultPasswordPolicy(), password: "あいうえおかきく"},
+		{name: "over bcrypt limit", policy: DefaultPasswordPolicy(), password: strings.Repeat("a", MaxPasswordBytes+1), rule: PasswordRuleTooLong},
+		{name: "multibyte over bcrypt limit", policy: DefaultPasswordPolicy(), password: strings.Repeat("あ", 25), rule: PasswordRuleTooLong},
+		{name: "common", policy: DefaultPasswordPolicy(), password: "Password123", rule: PasswordRuleCommon},
+		{name: "ok", policy: DefaultPasswordPolicy(), password: "correct horse battery"},
+		{name: "surrounding spaces kept", policy: DefaultPasswordPolicy(), password: " 1234567 "},
+		{name: "single class", policy: strict, password: "onlylowercase", rule: PasswordRuleCharacterClasses},
+		{name: "three classes", policy: strict, password: "Mixed-case words"},
+		{name: "zero value", policy: PasswordPolicy{}, password: "x"},
+		{name: "zero value ignores deny-list", policy: PasswordPolicy{}, password: "password"},
+	}
+
+	for _, tc := range cases {
+		err := tc.policy.Check(tc.password)
+		if tc.rule == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+
+		var policyErr *PasswordPolicyError
+		if !errors.As(err, &policyErr) || policyErr.Rule() != tc.rule {
+			t.Fatalf("%s: expected rule %s, got %v", tc.name, tc.rule, err)
+		}
+		if !errors.Is(err, ErrInvalidPassword) {
+			t.Fatalf("%s: expected to wrap ErrInvalidPassword", tc.name)
+		}
+	}
+}
+
+func TestNewPasswordPolicy_Invalid(t *testing.T) {
+	for _, tc := range []struct{ minLength, minClasses int }{{-1, 1}, {MaxPasswordBytes + 1, 1}, {8, 5}, {8, -1}} {
+		if _, err := NewPasswordPolicy(tc.minLength, tc.minClasses, true); !errors.Is(err, ErrInvalidPasswordPolicy) {
+			t.Fatalf("expected ErrInvalidPasswordPolicy for %+v, got %v", tc, err)
+		}
+	}
+}
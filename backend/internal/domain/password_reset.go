@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultPasswordResetTTL はリセット用トークンの既定の有効期間。
+const DefaultPasswordResetTTL = time.Hour
+
+// PasswordReset はパスワード再設定の申請。
+type PasswordReset struct {
+	oneTimeTokenRecord
+}
+
+// NewPasswordReset は token のセレクタを ID とし、発行時刻から ttl の間有効な申請を構築する。
+func NewPasswordReset(userID uuid.UUID, token OneTimeToken, issuedAt time.Time, ttl time.Duration) (PasswordReset, error) {
+	record, ok := issueOneTimeTokenRecord(userID, token, issuedAt, ttl)
+	if !ok {
+		return PasswordReset{}, ErrInvalidPasswordReset
+	}
+	return PasswordReset{record}, nil
+}
+
+// NewPasswordResetFromPersistence は既存レコードから申請を再構築する。未使用なら usedAt はゼロ値。
+func NewPasswordResetFromPersistence(id, userID uuid.UUID, tokenHash string, expiresAt, createdAt, usedAt time.Time) (PasswordReset, error) {
+	record, ok := buildOneTimeTokenRecord(id, userID, tokenHash, createdAt, expiresAt, usedAt)
+	if !ok {
+		return PasswordReset{}, ErrInvalidPasswordReset
+	}
+	return PasswordReset{record}, nil
+}
+
+// Verify はトークンが一致し、未使用かつ now の時点で有効期限内かを確かめる。
+// どの理由で失敗しても ErrInvalidResetToken を返し、理由を呼び出し元に漏らさない。
+func (r PasswordReset) Verify(token OneTimeToken, now time.Time) error {
+	if !r.accepts(token, now) {
+		return ErrInvalidResetToken
+	}
+	return nil
+}
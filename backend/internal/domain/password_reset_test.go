@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPasswordReset_Verify(t *testing.T) {
+	token, _ := NewOneTimeToken()
+	other, _ := NewOneTimeToken()
+	issued := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	reset, err := NewPasswordReset(uuid.New(), token, issued, time.Hour)
+	if err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	if reset.ID() != token.ID() || reset.TokenHash() == "" {
+		t.Fatalf("expected selector as id and stored hash")
+	}
+
+	if err := reset.Verify(token, issued.Add(time.Minute)); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if err := reset.Verify(other, issued.Add(time.Minute)); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("expected mismatch to fail, got %v", err)
+	}
+	if err := reset.Verify(token, issued.Add(time.Hour)); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("expected expired token to fail, got %v", err)
+	}
+
+	used, err := NewPasswordResetFromPersistence(reset.ID(), reset.UserID(), reset.TokenHash(), reset.ExpiresAt(), reset.CreatedAt(), issued.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("persistence error: %v", err)
+	}
+	if err := used.Verify(token, issued.Add(2*time.Minute)); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("expected used token to fail, got %v", err)
+	}
+
+	if _, err := NewPasswordReset(uuid.Nil, token, issued, time.Hour); !errors.Is(err, ErrInvalidPasswordReset) {
+		t.Fatalf("expected ErrInvalidPasswordReset, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitError は再試行までの待ち時間を伴う ErrRateLimited。
+type RateLimitError struct {
+	retryAfter time.Duration
+}
+
+// NewRateLimitError は負の待ち時間を 0 に丸めて RateLimitError を返す。
+func NewRateLimitError(retryAfter time.Duration) error {
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	return &RateLimitError{retryAfter: retryAfter}
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", ErrRateLimited, e.retryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RetryAfter は次の試行まで待つべき時間を返す。
+func (e *RateLimitError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
@@ -2,69 +2,90 @@ package domain
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
-	"golang.org/x/crypto/bcrypt"
+	"encoding/hex"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// DefaultLoginSessionTTL は login_sessions.expires_at のデフォルト(30分)に合わせる。
+// DefaultLoginSessionTTL は login_sessions.expires_at のデフォルト(30分)に合わせる。設定で上書きされない場合に使う。
 const DefaultLoginSessionTTL = 30 * time.Minute
 
 const loginSessionTokenByteLength = 32
 
+// bearerSeparator は Bearer 資格情報の user_id とトークンを区切る。UUID と base64url のどちらにも現れない。
+// トークン自体も "." を含むため、解析時は最初の区切りだけを見る。
+const bearerSeparator = "."
+
+// tokenSeparator はトークン内のセレクタ(セッションID)と検証子を区切る。
+const tokenSeparator = "."
+
+// LoginSessionToken はセレクタ(login_sessions.id)と検証子からなる "<session_id>.<verifier>" 形式のトークン。
+// セレクタで 1 行を引き、検証子のハッシュを定数時間で比較する。
 type LoginSessionToken struct {
-	value string
+	id       uuid.UUID
+	verifier string
 }
 
+// HashedLoginSessionToken は検証子の SHA-256 を16進で保持する。
 type HashedLoginSessionToken struct {
 	value string
 }
 
 func NewLoginSessionToken() (LoginSessionToken, error) {
-	tokenBytes := make([]byte, loginSessionTokenByteLength)
-	_, err := rand.Read(tokenBytes)
+	verifierBytes := make([]byte, loginSessionTokenByteLength)
+	_, err := rand.Read(verifierBytes)
 	if err != nil {
 		return LoginSessionToken{}, err
 	}
-	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+	verifier := base64.RawURLEncoding.EncodeToString(verifierBytes)
 
-	return LoginSessionToken{value: token}, nil
+	return LoginSessionToken{id: uuid.New(), verifier: verifier}, nil
 }
 
 func ParseLoginSessionToken(value string) (LoginSessionToken, error) {
-	trimmed := strings.TrimSpace(value)
-	if trimmed == "" {
+	selector, verifier, ok := strings.Cut(strings.TrimSpace(value), tokenSeparator)
+	if !ok {
+		return LoginSessionToken{}, ErrInvalidSessionToken
+	}
+
+	id, err := uuid.Parse(selector)
+	if err != nil || id == uuid.Nil {
 		return LoginSessionToken{}, ErrInvalidSessionToken
 	}
 
-	decoded, err := base64.RawURLEncoding.DecodeString(trimmed)
+	decoded, err := base64.RawURLEncoding.DecodeString(verifier)
 	if err != nil || len(decoded) != loginSessionTokenByteLength {
 		return LoginSessionToken{}, ErrInvalidSessionToken
 	}
 
-	return LoginSessionToken{value: trimmed}, nil
+	return LoginSessionToken{id: id, verifier: verifier}, nil
 }
 
 func (t LoginSessionToken) String() string {
-	return t.value
+	if t.isZero() {
+		return ""
+	}
+	return t.id.String() + tokenSeparator + t.verifier
 }
 
-func (t LoginSessionToken) isZero() bool {
-	return t.value == ""
+// ID はトークンのセレクタ、すなわち対応する login_sessions.id を返す。
+func (t LoginSessionToken) ID() uuid.UUID {
+	return t.id
 }
 
-func (t LoginSessionToken) Hash() (HashedLoginSessionToken, error) {
-	result := HashedLoginSessionToken{}
+func (t LoginSessionToken) isZero() bool {
+	return t.id == uuid.Nil || t.verifier == ""
+}
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte(t.value), bcrypt.DefaultCost)
-	if err != nil {
-		return HashedLoginSessionToken{}, err
-	}
-	result.value = string(hashed)
-	return result, nil
+// Hash は検証子の SHA-256 を返す。乱数 32 バイトの検証子に低速ハッシュは不要。
+func (t LoginSessionToken) Hash() HashedLoginSessionToken {
+	sum := sha256.Sum256([]byte(t.verifier))
+	return HashedLoginSessionToken{value: hex.EncodeToString(sum[:])}
 }
 
 func ParseHashedLoginSessionToken(value string) (HashedLoginSessionToken, error) {
@@ -88,14 +109,14 @@ type LoginSession struct {
 	createdAt time.Time
 }
 
-// NewLoginSession はセッションを発行時間を基準に構築する。
-func NewLoginSession(userID uuid.UUID, token HashedLoginSessionToken, issuedAt time.Time) (LoginSession, error) {
+// NewLoginSession はトークンのセレクタを ID とし、検証子のハッシュだけを保持するセッションを発行時間から ttl の間有効として構築する。
+func NewLoginSession(userID uuid.UUID, token LoginSessionToken, issuedAt time.Time, ttl time.Duration) (LoginSession, error) {
 	issued := issuedAt.UTC()
-	if issued.IsZero() {
+	if issued.IsZero() || token.isZero() || ttl <= 0 {
 		return LoginSession{}, ErrInvalidLoginSession
 	}
 
-	return buildLoginSession(uuid.New(), userID, token, issued, issued.Add(DefaultLoginSessionTTL))
+	return buildLoginSession(token.ID(), userID, token.Hash(), issued, issued.Add(ttl))
 }
 
 // NewLoginSessionFromPersistence は既存レコードからセッションを再構築する。
@@ -119,9 +140,17 @@ func (s LoginSession) HashedToken() string {
 	return s.token.String()
 }
 
-// Verify は保存済みハッシュと入力トークンを照合する。
+// Verify はセレクタの一致と、保存済みハッシュと入力検証子のハッシュを定数時間で照合する。
 func (s LoginSession) Verify(token LoginSessionToken) error {
-	return bcrypt.CompareHashAndPassword([]byte(s.token.value), []byte(token.value))
+	if token.isZero() || token.ID() != s.id {
+		return ErrInvalidSessionToken
+	}
+
+	if subtle.ConstantTimeCompare([]byte(s.token.value), []byte(token.Hash().value)) != 1 {
+		return ErrInvalidSessionToken
+	}
+
+	return nil
 }
 
 func (s LoginSession) CreatedAt() time.Time {
@@ -158,9 +187,11 @@ func buildLoginSession(id uuid.UUID, userID uuid.UUID, token HashedLoginSessionT
 }
 
 // SessionData は API へ返却する session-data-struct を表現する。
+// 発行時に組み立てた場合のみ有効期限を保持し、Bearer から復元した場合は ExpiresAt がゼロ値になる。
 type SessionData struct {
-	userID uuid.UUID
-	token  LoginSessionToken
+	userID    uuid.UUID
+	token     LoginSessionToken
+	expiresAt time.Time
 }
 
 func NewSessionData(userID uuid.UUID, token LoginSessionToken) (SessionData, error) {
@@ -171,6 +202,21 @@ func NewSessionData(userID uuid.UUID, token LoginSessionToken) (SessionData, err
 	return SessionData{userID: userID, token: token}, nil
 }
 
+// NewIssuedSessionData は発行したセッションと生トークンから、有効期限付きの SessionData を組み立てる。
+func NewIssuedSessionData(session LoginSession, token LoginSessionToken) (SessionData, error) {
+	if err := session.Verify(token); err != nil {
+		return SessionData{}, ErrInvalidSessionData
+	}
+
+	data, err := NewSessionData(session.UserID(), token)
+	if err != nil {
+		return SessionData{}, err
+	}
+	data.expiresAt = session.ExpiresAt()
+
+	return data, nil
+}
+
 func (s SessionData) UserID() uuid.UUID {
 	return s.userID
 }
@@ -178,3 +224,32 @@ func (s SessionData) UserID() uuid.UUID {
 func (s SessionData) Token() LoginSessionToken {
 	return s.token
 }
+
+func (s SessionData) ExpiresAt() time.Time {
+	return s.expiresAt
+}
+
+// BearerValue は Authorization: Bearer に載せる "<user_id>.<token>" 形式の資格情報を返す。
+func (s SessionData) BearerValue() string {
+	return s.userID.String() + bearerSeparator + s.token.String()
+}
+
+// ParseBearerSessionData は "<user_id>.<token>" 形式の資格情報を SessionData へ変換する。
+func ParseBearerSessionData(value string) (SessionData, error) {
+	userPart, tokenPart, ok := strings.Cut(strings.TrimSpace(value), bearerSeparator)
+	if !ok {
+		return SessionData{}, ErrInvalidSessionData
+	}
+
+	userID, err := uuid.Parse(userPart)
+	if err != nil {
+		return SessionData{}, ErrInvalidSessionData
+	}
+
+	token, err := ParseLoginSessionToken(tokenPart)
+	if err != nil {
+		return SessionData{}, err
+	}
+
+	return NewSessionData(userID, token)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,8 +24,44 @@ func TestParseLoginSessionToken(t *testing.T) {
 		t.Fatalf("expected %s got %s", generated.String(), parsed.String())
 	}
 
-	if _, err := ParseLoginSessionToken("invalid-base64"); !errors.Is(err, ErrInvalidSessionToken) {
-		t.Fatalf("expected ErrInvalidSessionToken, got %v", err)
+	if parsed.ID() != generated.ID() {
+		t.Fatalf("expected selector %s got %s", generated.ID(), parsed.ID())
+	}
+
+	invalid := []string{
+		"invalid-base64",
+		uuid.NewString() + ".invalid-base64",
+		"not-a-uuid." + strings.Split(generated.String(), ".")[1],
+		uuid.Nil.String() + "." + strings.Split(generated.String(), ".")[1],
+	}
+	for _, value := range invalid {
+		if _, err := ParseLoginSessionToken(value); !errors.Is(err, ErrInvalidSessionToken) {
+			t.Fatalf("%q: expected ErrInvalidSessionToken, got %v", value, err)
+		}
+	}
+}
+
+func TestLoginSessionToken_Hash(t *testing.T) {
+	token, err := NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+
+	if token.Hash().String() != token.Hash().String() {
+		t.Fatalf("expected deterministic hash")
+	}
+
+	other, err := NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+
+	if token.Hash().String() == other.Hash().String() {
+		t.Fatalf("expected distinct hashes for distinct tokens")
+	}
+
+	if strings.Contains(token.Hash().String(), strings.Split(token.String(), ".")[1]) {
+		t.Fatalf("hash must not contain the raw verifier")
 	}
 }
 
@@ -49,19 +86,15 @@ func TestNewLoginSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("token error: %v", err)
 	}
-	hashed, err := token.Hash()
-	if err != nil {
-		t.Fatalf("hash error: %v", err)
-	}
 
 	issuedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
-	session, err := NewLoginSession(userID, hashed, issuedAt)
+	session, err := NewLoginSession(userID, token, issuedAt, DefaultLoginSessionTTL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if session.ID() == uuid.Nil {
-		t.Fatalf("expected non-nil session id")
+	if session.ID() != token.ID() {
+		t.Fatalf("expected session id to equal token selector")
 	}
 
 	if session.UserID() != userID {
@@ -72,6 +105,22 @@ func TestNewLoginSession(t *testing.T) {
 		t.Fatalf("unexpected hashed token: %s", err)
 	}
 
+	other, err := NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	if err := session.Verify(other); !errors.Is(err, ErrInvalidSessionToken) {
+		t.Fatalf("expected ErrInvalidSessionToken for foreign token, got %v", err)
+	}
+
+	forged, err := ParseLoginSessionToken(token.ID().String() + "." + strings.Split(other.String(), ".")[1])
+	if err != nil {
+		t.Fatalf("forge error: %v", err)
+	}
+	if err := session.Verify(forged); !errors.Is(err, ErrInvalidSessionToken) {
+		t.Fatalf("expected ErrInvalidSessionToken for mismatched verifier, got %v", err)
+	}
+
 	if !session.CreatedAt().Equal(issuedAt) {
 		t.Fatalf("expected created_at %v, got %v", issuedAt, session.CreatedAt())
 	}
@@ -93,17 +142,16 @@ func TestNewLoginSession_InvalidInput(t *testing.T) {
 		userID uuid.UUID
 		token  LoginSessionToken
 		issued time.Time
+		ttl    time.Duration
 	}{
-		{"zero user", uuid.Nil, token, time.Now()},
-		{"zero issued", uuid.New(), token, time.Time{}},
+		{"zero user", uuid.Nil, token, time.Now(), DefaultLoginSessionTTL},
+		{"zero issued", uuid.New(), token, time.Time{}, DefaultLoginSessionTTL},
+		{"zero token", uuid.New(), LoginSessionToken{}, time.Now(), DefaultLoginSessionTTL},
+		{"zero ttl", uuid.New(), token, time.Now(), 0},
 	}
 
 	for _, tc := range cases {
-		hashed, err := tc.token.Hash()
-		if err != nil {
-			t.Fatalf("hash error: %v", err)
-		}
-		if _, err := NewLoginSession(tc.userID, hashed, tc.issued); !errors.Is(err, ErrInvalidLoginSession) {
+		if _, err := NewLoginSession(tc.userID, tc.token, tc.issued, tc.ttl); !errors.Is(err, ErrInvalidLoginSession) {
 			t.Fatalf("%s: expected ErrInvalidLoginSession, got %v", tc.name, err)
 		}
 	}
@@ -114,10 +162,7 @@ func TestNewLoginSessionFromPersistence_InvalidTimes(t *testing.T) {
 	if err != nil {
 		t.Fatalf("token error: %v", err)
 	}
-	hashed, err := token.Hash()
-	if err != nil {
-		t.Fatalf("hash error: %v", err)
-	}
+	hashed := token.Hash()
 
 	created := time.Now().UTC()
 	if _, err := NewLoginSessionFromPersistence(uuid.New(), uuid.New(), hashed, created, created); !errors.Is(err, ErrInvalidLoginSession) {
@@ -134,10 +179,7 @@ func TestLoginSession_IsExpired(t *testing.T) {
 	if err != nil {
 		t.Fatalf("token error: %v", err)
 	}
-	hashed, err := token.Hash()
-	if err != nil {
-		t.Fatalf("hash error: %v", err)
-	}
+	hashed := token.Hash()
 
 	created := time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
 	expires := created.Add(time.Minute)
@@ -183,3 +225,64 @@ func TestNewSessionData(t *testing.T) {
 		t.Fatalf("expected ErrInvalidSessionData, got %v", err)
 	}
 }
+
+func TestParseBearerSessionData(t *testing.T) {
+	userID := uuid.New()
+	token, err := NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	data, err := NewSessionData(userID, token)
+	if err != nil {
+		t.Fatalf("session data error: %v", err)
+	}
+
+	parsed, err := ParseBearerSessionData(data.BearerValue())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parsed.UserID() != userID || parsed.Token().String() != token.String() {
+		t.Fatalf("unexpected parsed session data")
+	}
+
+	invalid := []string{"", token.String(), "bad." + token.String(), userID.String() + ".short"}
+	for _, value := range invalid {
+		if _, err := ParseBearerSessionData(value); err == nil {
+			t.Fatalf("%q: expected error", value)
+		}
+	}
+}
+
+func TestNewIssuedSessionData(t *testing.T) {
+	userID := uuid.New()
+	token, err := NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	session, err := NewLoginSession(userID, token, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+
+	data, err := NewIssuedSessionData(session, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data.UserID() != userID || data.Token().String() != token.String() {
+		t.Fatalf("unexpected session data")
+	}
+
+	if !data.ExpiresAt().Equal(session.ExpiresAt()) {
+		t.Fatalf("expected expires_at %v, got %v", session.ExpiresAt(), data.ExpiresAt())
+	}
+
+	other, err := NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	if _, err := NewIssuedSessionData(session, other); !errors.Is(err, ErrInvalidSessionData) {
+		t.Fatalf("expected ErrInvalidSessionData, got %v", err)
+	}
+}
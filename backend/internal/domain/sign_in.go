@@ -9,11 +9,11 @@ type SignInCredential struct {
 	password string
 }
 
-// NewSignInCredential は name/email/password を検証して正規化する。
+// NewSignInCredential は name/email を検証して正規化する。password は入力されたとおりに保持し、
+// 空でないことだけを確かめる。強度の検査は PasswordPolicy が行う。
 func NewSignInCredential(name, email, password string) (SignInCredential, error) {
-	trimmedPassword := strings.TrimSpace(password)
-	if trimmedPassword == "" {
-		return SignInCredential{}, ErrInvalidPassword
+	if strings.TrimSpace(password) == "" {
+		return SignInCredential{}, errPasswordRequired()
 	}
 
 	parsedName, err := NewName(name)
@@ -29,7 +29,7 @@ func NewSignInCredential(name, email, password string) (SignInCredential, error)
 	return SignInCredential{
 		name:     parsedName,
 		email:    parsedEmail,
-		password: trimmedPassword,
+		password: password,
 	}, nil
 }
 
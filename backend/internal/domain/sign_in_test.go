@@ -1,6 +1,9 @@
 package domain
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestNewSignInCredential(t *testing.T) {
 	credential, err := NewSignInCredential(" Alice ", "alice@example.com", "  secret  ")
@@ -16,8 +19,8 @@ func TestNewSignInCredential(t *testing.T) {
 		t.Fatalf("unexpected email: %s", credential.Email())
 	}
 
-	if credential.Password() != "secret" {
-		t.Fatalf("expected trimmed password, got %s", credential.Password())
+	if credential.Password() != "  secret  " {
+		t.Fatalf("expected password as typed, got %q", credential.Password())
 	}
 }
 
@@ -30,7 +33,7 @@ func TestNewSignInCredential_InvalidInputs(t *testing.T) {
 		t.Fatalf("expected error for invalid email")
 	}
 
-	if _, err := NewSignInCredential("Alice", "alice@example.com", "   "); err != ErrInvalidPassword {
+	if _, err := NewSignInCredential("Alice", "alice@example.com", "   "); !errors.Is(err, ErrInvalidPassword) {
 		t.Fatalf("expected ErrInvalidPassword, got %v", err)
 	}
 }
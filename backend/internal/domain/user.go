@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"golang.org/x/crypto/bcrypt"
 	"net/mail"
 	"strings"
@@ -36,9 +37,11 @@ func (e Email) isZero() bool {
 	return e.value == ""
 }
 
-// HashedPassword は bcrypt などでハッシュ化済みのパスワードを保持する。
+// HashedPassword は bcrypt ($2a$ 形式) か argon2id (PHC 形式) でハッシュ化済みのパスワードを保持する。
+// legacyTrim は前後の空白を除いた平文から作った以前の bcrypt ハッシュであることを示す。
 type HashedPassword struct {
-	value string
+	value      string
+	legacyTrim bool
 }
 
 func NewHashedPassword(value string) (HashedPassword, error) {
@@ -58,8 +61,36 @@ func (p HashedPassword) isZero() bool {
 	return p.value == ""
 }
 
+// Verify はハッシュの形式から方式を選んで照合する。一致しなければ ErrPasswordMismatch を返す。
 func (p HashedPassword) Verify(plain string) error {
-	return bcrypt.CompareHashAndPassword([]byte(p.value), []byte(plain))
+	if p.isArgon2id() {
+		return verifyArgon2id(p.value, plain)
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(p.value), []byte(plain))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	return err
+}
+
+func (p HashedPassword) isArgon2id() bool {
+	return strings.HasPrefix(p.value, argon2Prefix)
+}
+
+func (p HashedPassword) isBcrypt() bool {
+	return strings.HasPrefix(p.value, bcryptPrefix)
+}
+
+// LegacyTrimmed は前後の空白を除いた平文から作ったハッシュとして印を付けたコピーを返す。
+// 以前は bcrypt でしかそうしていなかったため、bcrypt 以外のハッシュには印を付けない。
+func (p HashedPassword) LegacyTrimmed() HashedPassword {
+	p.legacyTrim = p.isBcrypt()
+	return p
+}
+
+// IsLegacyTrimmed は LegacyTrimmed で印を付けたハッシュなら true を返す。
+func (p HashedPassword) IsLegacyTrimmed() bool {
+	return p.legacyTrim
 }
 
 // UserRole は users.role の列挙を表す。
@@ -110,6 +141,9 @@ type User struct {
 	role           UserRole
 	createdAt      time.Time
 	updatedAt      time.Time
+	disabledAt     time.Time
+	// emailVerifiedAt はメールアドレスの所有を確認した時刻。未確認ならゼロ値。
+	emailVerifiedAt time.Time
 }
 
 // NewUser は新規登録時に UUID とタイムスタンプを生成する。
@@ -119,12 +153,13 @@ func NewUser(username Name, email Email, hashedPassword HashedPassword, role Use
 		return User{}, ErrInvalidUser
 	}
 
-	return buildUser(uuid.New(), username, email, hashedPassword, role, issued, issued)
+	return buildUser(uuid.New(), username, email, hashedPassword, role, issued, issued, time.Time{}, time.Time{})
 }
 
-// NewUserFromPersistence は永続化済みデータから再構築する。
-func NewUserFromPersistence(id uuid.UUID, username Name, email Email, hashedPassword HashedPassword, role UserRole, createdAt, updatedAt time.Time) (User, error) {
-	return buildUser(id, username, email, hashedPassword, role, createdAt, updatedAt)
+// NewUserFromPersistence は永続化済みデータから再構築する。有効なアカウントでは disabledAt に、
+// メールアドレスが未確認なら emailVerifiedAt にゼロ値を渡す。
+func NewUserFromPersistence(id uuid.UUID, username Name, email Email, hashedPassword HashedPassword, role UserRole, createdAt, updatedAt, disabledAt, emailVerifiedAt time.Time) (User, error) {
+	return buildUser(id, username, email, hashedPassword, role, createdAt, updatedAt, disabledAt, emailVerifiedAt)
 }
 
 func (u User) ID() uuid.UUID {
@@ -155,7 +190,80 @@ func (u User) UpdatedAt() time.Time {
 	return u.updatedAt
 }
 
-func buildUser(id uuid.UUID, username Name, email Email, hashedPassword HashedPassword, role UserRole, createdAt, updatedAt time.Time) (User, error) {
+// DisabledAt は管理者に無効化された時刻を返す。有効なアカウントなら false。
+func (u User) DisabledAt() (time.Time, bool) {
+	return u.disabledAt, !u.disabledAt.IsZero()
+}
+
+// IsDisabled は無効化されていてログインできないアカウントかを返す。
+func (u User) IsDisabled() bool {
+	return !u.disabledAt.IsZero()
+}
+
+// EmailVerifiedAt はメールアドレスを確認済みならその時刻を返す。
+func (u User) EmailVerifiedAt() (time.Time, bool) {
+	return u.emailVerifiedAt, !u.emailVerifiedAt.IsZero()
+}
+
+func (u User) IsEmailVerified() bool {
+	return !u.emailVerifiedAt.IsZero()
+}
+
+// VerifyEmail は now の時点でメールアドレスを確認済みにしたコピーを返す。確認済みなら時刻は変えない。
+func (u User) VerifyEmail(now time.Time) User {
+	if u.emailVerifiedAt.IsZero() {
+		u.emailVerifiedAt = now.UTC()
+	}
+	u.updatedAt = now.UTC()
+	return u
+}
+
+// ChangeRole は role に変更したコピーを返す。
+func (u User) ChangeRole(role UserRole, now time.Time) (User, error) {
+	if !role.valid() {
+		return User{}, ErrInvalidUserRole
+	}
+	u.role = role
+	u.updatedAt = now.UTC()
+	return u, nil
+}
+
+// ChangePassword はパスワードハッシュを置き換えたコピーを返す。
+func (u User) ChangePassword(hashedPassword HashedPassword, now time.Time) (User, error) {
+	if hashedPassword.isZero() {
+		return User{}, ErrInvalidPasswordHash
+	}
+	u.hashedPassword = hashedPassword
+	u.updatedAt = now.UTC()
+	return u, nil
+}
+
+// RehashPassword は同じパスワードを作り直したハッシュに置き換えたコピーを返す。利用者から見た変更ではないため updatedAt は変えない。
+func (u User) RehashPassword(hashedPassword HashedPassword) (User, error) {
+	if hashedPassword.isZero() {
+		return User{}, ErrInvalidPasswordHash
+	}
+	u.hashedPassword = hashedPassword
+	return u, nil
+}
+
+// Disable は now の時点で無効化したコピーを返す。既に無効なら無効化した時刻は変えない。
+func (u User) Disable(now time.Time) User {
+	if u.disabledAt.IsZero() {
+		u.disabledAt = now.UTC()
+	}
+	u.updatedAt = now.UTC()
+	return u
+}
+
+// Enable は無効化を解除したコピーを返す。
+func (u User) Enable(now time.Time) User {
+	u.disabledAt = time.Time{}
+	u.updatedAt = now.UTC()
+	return u
+}
+
+func buildUser(id uuid.UUID, username Name, email Email, hashedPassword HashedPassword, role UserRole, createdAt, updatedAt, disabledAt, emailVerifiedAt time.Time) (User, error) {
 	if id == uuid.Nil || username.String() == "" || email.isZero() || hashedPassword.isZero() || role.isZero() {
 		return User{}, ErrInvalidUser
 	}
@@ -167,12 +275,14 @@ func buildUser(id uuid.UUID, username Name, email Email, hashedPassword HashedPa
 	}
 
 	return User{
-		id:             id,
-		username:       username,
-		email:          email,
-		hashedPassword: hashedPassword,
-		role:           role,
-		createdAt:      created,
-		updatedAt:      updated,
+		id:              id,
+		username:        username,
+		email:           email,
+		hashedPassword:  hashedPassword,
+		role:            role,
+		createdAt:       created,
+		updatedAt:       updated,
+		disabledAt:      disabledAt.UTC(),
+		emailVerifiedAt: emailVerifiedAt.UTC(),
 	}, nil
 }
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+)
+
+const (
+	// DefaultUserPageSize は件数を指定しなかった場合のユーザー一覧の件数。
+	DefaultUserPageSize = 50
+	// MaxUserPageSize はユーザー一覧で 1 回に返す最大件数。
+	MaxUserPageSize = 200
+)
+
+// UserQuery は管理画面のユーザー一覧の検索条件。search はユーザー名かメールアドレスの部分一致。
+type UserQuery struct {
+	search string
+	role   UserRole
+	limit  int
+	offset int
+}
+
+// NewUserQuery は role が空ならロールで絞り込まない。limit が 0 なら DefaultUserPageSize を使う。
+// 不正なロールや範囲外の limit / offset には ErrInvalidUserQuery を返す。
+func NewUserQuery(search, role string, limit, offset int) (UserQuery, error) {
+	query := UserQuery{search: strings.TrimSpace(search), limit: limit, offset: offset}
+
+	if strings.TrimSpace(role) != "" {
+		parsed, err := NewUserRole(role)
+		if err != nil {
+			return UserQuery{}, ErrInvalidUserQuery
+		}
+		query.role = parsed
+	}
+
+	if query.limit == 0 {
+		query.limit = DefaultUserPageSize
+	}
+	if query.limit < 0 || query.limit > MaxUserPageSize || query.offset < 0 {
+		return UserQuery{}, ErrInvalidUserQuery
+	}
+
+	return query, nil
+}
+
+func (q UserQuery) Search() string {
+	return q.search
+}
+
+// Role は絞り込むロールを返す。指定がなければ false。
+func (q UserQuery) Role() (UserRole, bool) {
+	return q.role, q.role != ""
+}
+
+func (q UserQuery) Limit() int {
+	return q.limit
+}
+
+func (q UserQuery) Offset() int {
+	return q.offset
+}
+
+// Matches は user が検索条件に一致するかを返す。大文字・小文字は区別しない。
+func (q UserQuery) Matches(user User) bool {
+	if q.role != "" && user.Role() != q.role {
+		return false
+	}
+	if q.search == "" {
+		return true
+	}
+
+	search := strings.ToLower(q.search)
+	return strings.Contains(strings.ToLower(user.Username().String()), search) ||
+		strings.Contains(strings.ToLower(user.Email().String()), search)
+}
@@ -132,11 +132,54 @@ func TestNewUserFromPersistence_Invalid(t *testing.T) {
 	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
 	updated := created.Add(-time.Minute)
 
-	if _, err := NewUserFromPersistence(uuid.Nil, name, email, password, role, created, created); !errors.Is(err, ErrInvalidUser) {
+	if _, err := NewUserFromPersistence(uuid.Nil, name, email, password, role, created, created, time.Time{}, time.Time{}); !errors.Is(err, ErrInvalidUser) {
 		t.Fatalf("expected ErrInvalidUser for zero id, got %v", err)
 	}
 
-	if _, err := NewUserFromPersistence(uuid.New(), name, email, password, role, created, updated); !errors.Is(err, ErrInvalidUser) {
+	if _, err := NewUserFromPersistence(uuid.New(), name, email, password, role, created, updated, time.Time{}, time.Time{}); !errors.Is(err, ErrInvalidUser) {
 		t.Fatalf("expected ErrInvalidUser when updated<created, got %v", err)
 	}
 }
+
+func TestUser_AdminChanges(t *testing.T) {
+	name, _ := NewName("Alice")
+	email, _ := NewEmail("alice@example.com")
+	password, _ := NewHashedPassword("hashed")
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	user, err := NewUser(name, email, password, UserRoleUser, created)
+	if err != nil {
+		t.Fatalf("user error: %v", err)
+	}
+
+	promoted, err := user.ChangeRole(UserRoleAdmin, created.Add(time.Minute))
+	if err != nil || promoted.Role() != UserRoleAdmin || !promoted.UpdatedAt().Equal(created.Add(time.Minute)) {
+		t.Fatalf("unexpected role change: %v (%v)", promoted.Role(), err)
+	}
+	if user.Role() != UserRoleUser {
+		t.Fatalf("ChangeRole should not modify the receiver")
+	}
+	if _, err := user.ChangeRole("owner", created); !errors.Is(err, ErrInvalidUserRole) {
+		t.Fatalf("expected ErrInvalidUserRole, got %v", err)
+	}
+
+	newHash, _ := NewHashedPassword("rehashed")
+	changed, err := user.ChangePassword(newHash, created.Add(time.Minute))
+	if err != nil || changed.HashedPassword().String() != "rehashed" || user.HashedPassword().String() != "hashed" {
+		t.Fatalf("unexpected password change: %v", err)
+	}
+	if _, err := user.ChangePassword(HashedPassword{}, created); !errors.Is(err, ErrInvalidPasswordHash) {
+		t.Fatalf("expected ErrInvalidPasswordHash, got %v", err)
+	}
+
+	disabledAt := created.Add(time.Hour)
+	disabled := user.Disable(disabledAt)
+	if at, ok := disabled.DisabledAt(); !ok || !at.Equal(disabledAt) || !disabled.IsDisabled() {
+		t.Fatalf("expected user to be disabled at %v", disabledAt)
+	}
+	if at, _ := disabled.Disable(disabledAt.Add(time.Hour)).DisabledAt(); !at.Equal(disabledAt) {
+		t.Fatalf("disabling twice should keep the original time")
+	}
+	if disabled.Enable(disabledAt.Add(time.Hour)).IsDisabled() {
+		t.Fatalf("expected user to be enabled")
+	}
+}
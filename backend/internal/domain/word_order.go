@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// WordOrderMode はセッションごとの出題順の決め方。
+//   - fixed: 単語セットに登録した順のまま出す。
+//   - shuffle: seed で初期化した乱数で並べ替える。
+//   - latin_square: Williams 型のラテン方格の seed 行目の順に出す。参加者に行を順番に割り当てると、
+//     各単語が各位置に同じ回数ずつ現れ、直前の単語の組み合わせも偏らない。
+type WordOrderMode string
+
+const (
+	WordOrderFixed       WordOrderMode = "fixed"
+	WordOrderShuffle     WordOrderMode = "shuffle"
+	WordOrderLatinSquare WordOrderMode = "latin_square"
+)
+
+func (m WordOrderMode) String() string {
+	return string(m)
+}
+
+// NewWordOrderMode は未知の決め方なら ErrInvalidWordOrder を返す。
+func NewWordOrderMode(value string) (WordOrderMode, error) {
+	switch mode := WordOrderMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case WordOrderFixed, WordOrderShuffle, WordOrderLatinSquare:
+		return mode, nil
+	default:
+		return "", ErrInvalidWordOrder
+	}
+}
+
+// WordOrder は 1 回のセッションで実際に出した単語の順と、それを決めた mode と seed。
+// 順序効果を分析できるよう、並べ替えた結果そのものも保存して再計算に頼らない。
+type WordOrder struct {
+	mode  WordOrderMode
+	seed  int64
+	words []HueWord
+}
+
+// NewWordOrder は words を mode と seed で並べ替える。fixed では seed を使わない。
+// mode が未知か words が空なら ErrInvalidWordOrder を返す。
+func NewWordOrder(mode WordOrderMode, seed int64, words []HueWord) (WordOrder, error) {
+	if len(words) == 0 {
+		return WordOrder{}, ErrInvalidWordOrder
+	}
+
+	ordered := slices.Clone(words)
+	switch mode {
+	case WordOrderFixed:
+	case WordOrderShuffle:
+		rng := rand.New(rand.NewPCG(uint64(seed), 0))
+		rng.Shuffle(len(ordered), func(i, j int) {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		})
+	case WordOrderLatinSquare:
+		for i, index := range williamsRow(len(words), seed) {
+			ordered[i] = words[index]
+		}
+	default:
+		return WordOrder{}, ErrInvalidWordOrder
+	}
+
+	return WordOrder{mode: mode, seed: seed, words: ordered}, nil
+}
+
+// NewWordOrderFromPersistence は保存済みの出題順を並べ替えずに再構築する。
+func NewWordOrderFromPersistence(mode WordOrderMode, seed int64, words []HueWord) (WordOrder, error) {
+	if _, err := NewWordOrderMode(mode.String()); err != nil || len(words) == 0 {
+		return WordOrder{}, ErrInvalidWordOrder
+	}
+	return WordOrder{mode: mode, seed: seed, words: slices.Clone(words)}, nil
+}
+
+// LatinSquareRows は n 語の Williams 型ラテン方格の行数。n が奇数なら各行の逆順も加えて 2n 行にする。
+func LatinSquareRows(n int) int {
+	if n%2 == 1 {
+		return 2 * n
+	}
+	return n
+}
+
+// williamsRow は n 語の Williams 型ラテン方格の row 行目 (LatinSquareRows(n) で割った余り) を、元の並びの添字で返す。
+// 1 行目は 0, 1, n-1, 2, n-2, ... で、以降の行は各要素に行番号を足して n で割った余り。
+func williamsRow(n int, row int64) []int {
+	rows := int64(LatinSquareRows(n))
+	r := int(((row % rows) + rows) % rows)
+	reverse := r >= n
+	if reverse {
+		r -= n
+	}
+
+	indexes := make([]int, n)
+	for j := range n {
+		base := 0
+		switch {
+		case j == 0:
+		case j%2 == 1:
+			base = (j + 1) / 2
+		default:
+			base = n - j/2
+		}
+		indexes[j] = (base + r) % n
+	}
+	if reverse {
+		slices.Reverse(indexes)
+	}
+	return indexes
+}
+
+func (o WordOrder) Mode() WordOrderMode {
+	return o.mode
+}
+
+func (o WordOrder) Seed() int64 {
+	return o.seed
+}
+
+// Words は出題した順の単語を返す。
+func (o WordOrder) Words() []HueWord {
+	return slices.Clone(o.words)
+}
+
+// Position は word を何番目に出したか (1 始まり) を返す。出していなければ false。
+func (o WordOrder) Position(word HueWord) (int, bool) {
+	i := slices.Index(o.words, word)
+	return i + 1, i >= 0
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewWordOrderMode(t *testing.T) {
+	if mode, err := NewWordOrderMode(" Latin_Square "); err != nil || mode != WordOrderLatinSquare {
+		t.Fatalf("expected latin_square, got %q (%v)", mode, err)
+	}
+	if _, err := NewWordOrderMode("random"); !errors.Is(err, ErrInvalidWordOrder) {
+		t.Fatalf("expected ErrInvalidWordOrder, got %v", err)
+	}
+}
+
+func TestNewWordOrder(t *testing.T) {
+	words := []HueWord{"夜", "海", "森", "空"}
+
+	fixed, err := NewWordOrder(WordOrderFixed, 99, words)
+	if err != nil || !slices.Equal(fixed.Words(), words) {
+		t.Fatalf("expected the original order, got %v (%v)", fixed.Words(), err)
+	}
+
+	shuffled, err := NewWordOrder(WordOrderShuffle, 7, words)
+	if err != nil {
+		t.Fatalf("shuffle error: %v", err)
+	}
+	again, _ := NewWordOrder(WordOrderShuffle, 7, words)
+	if !slices.Equal(shuffled.Words(), again.Words()) {
+		t.Fatalf("expected the same seed to give the same order, got %v and %v", shuffled.Words(), again.Words())
+	}
+	sorted := shuffled.Words()
+	slices.Sort(sorted)
+	want := slices.Clone(words)
+	slices.Sort(want)
+	if !slices.Equal(sorted, want) {
+		t.Fatalf("expected a permutation, got %v", shuffled.Words())
+	}
+	if pos, ok := shuffled.Position(shuffled.Words()[2]); !ok || pos != 3 {
+		t.Fatalf("expected position 3, got %d", pos)
+	}
+	if _, ok := shuffled.Position("雪"); ok {
+		t.Fatal("expected no position for a word that was not presented")
+	}
+
+	if _, err := NewWordOrder("random", 0, words); !errors.Is(err, ErrInvalidWordOrder) {
+		t.Fatalf("expected ErrInvalidWordOrder for an unknown mode, got %v", err)
+	}
+	if _, err := NewWordOrder(WordOrderFixed, 0, nil); !errors.Is(err, ErrInvalidWordOrder) {
+		t.Fatalf("expected ErrInvalidWordOrder without words, got %v", err)
+	}
+}
+
+func TestNewWordOrder_LatinSquare(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5} {
+		words := make([]HueWord, n)
+		for i := range n {
+			words[i] = HueWord(rune('a' + i))
+		}
+
+		rows := LatinSquareRows(n)
+		atPosition := make([]map[HueWord]int, n)
+		follows := make(map[[2]HueWord]int)
+		for i := range atPosition {
+			atPosition[i] = make(map[HueWord]int)
+		}
+		for row := range rows {
+			order, err := NewWordOrder(WordOrderLatinSquare, int64(row), words)
+			if err != nil {
+				t.Fatalf("n=%d row=%d: %v", n, row, err)
+			}
+			got := order.Words()
+			for i, word := range got {
+				atPosition[i][word]++
+				if i > 0 {
+					follows[[2]HueWord{got[i-1], word}]++
+				}
+			}
+		}
+
+		// どの単語もどの位置にも rows/n 回ずつ現れ、直前の単語の組み合わせも均等になる。
+		for i, counts := range atPosition {
+			for _, word := range words {
+				if counts[word] != rows/n {
+					t.Fatalf("n=%d: expected %s %d times at position %d, got %d", n, word, rows/n, i, counts[word])
+				}
+			}
+		}
+		for pair, count := range follows {
+			if count != rows/n {
+				t.Fatalf("n=%d: expected %v to follow %d times, got %d", n, pair, rows/n, count)
+			}
+		}
+	}
+
+	words := []HueWord{"夜", "海", "森"}
+	first, _ := NewWordOrder(WordOrderLatinSquare, 0, words)
+	wrapped, _ := NewWordOrder(WordOrderLatinSquare, int64(LatinSquareRows(3)), words)
+	negative, _ := NewWordOrder(WordOrderLatinSquare, -int64(LatinSquareRows(3)), words)
+	if !slices.Equal(first.Words(), wrapped.Words()) || !slices.Equal(first.Words(), negative.Words()) {
+		t.Fatalf("expected seeds to wrap around the rows, got %v, %v and %v", first.Words(), wrapped.Words(), negative.Words())
+	}
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// 単語セットの上限。単語の長さは hue_records の choices に入れる前提で文字数で数える。
+const (
+	MaxWordSetNameLength = 64
+	MaxWordSetWords      = 500
+	MaxHueWordLength     = 32
+)
+
+// WordSet は Hue テストで出題する単語の一覧。出題順は words の順で、同時に有効にできるのは 1 つだけ。
+type WordSet struct {
+	id        uuid.UUID
+	name      string
+	words     []HueWord
+	active    bool
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// NewWordSet は新しい単語セットを無効な状態で作る。名前か単語が不正なら ErrInvalidWordSet を返す。
+func NewWordSet(name string, words []string, now time.Time) (WordSet, error) {
+	return buildWordSet(uuid.New(), name, words, false, now, now)
+}
+
+// NewWordSetFromPersistence は永続化済みの単語セットを再構築する。
+func NewWordSetFromPersistence(id uuid.UUID, name string, words []string, active bool, createdAt, updatedAt time.Time) (WordSet, error) {
+	return buildWordSet(id, name, words, active, createdAt, updatedAt)
+}
+
+func (s WordSet) ID() uuid.UUID {
+	return s.id
+}
+
+func (s WordSet) Name() string {
+	return s.name
+}
+
+// Words は出題順の単語を返す。
+func (s WordSet) Words() []HueWord {
+	return slices.Clone(s.words)
+}
+
+func (s WordSet) IsActive() bool {
+	return s.active
+}
+
+func (s WordSet) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s WordSet) UpdatedAt() time.Time {
+	return s.updatedAt
+}
+
+// Contains は word がこのセットの単語なら true を返す。
+func (s WordSet) Contains(word HueWord) bool {
+	return slices.Contains(s.words, word)
+}
+
+// CheckChoices は choices の単語がすべてこのセットに含まれていなければ ErrInvalidChoice を返す。
+// 途中までの回答も受け付けるため、セットのすべての単語に答えていることは求めない。
+func (s WordSet) CheckChoices(choices HueChoices) error {
+	for word := range choices.values {
+		if !s.Contains(word) {
+			return ErrInvalidChoice
+		}
+	}
+	return nil
+}
+
+// Edit は名前と単語を置き換えたコピーを返す。
+func (s WordSet) Edit(name string, words []string, now time.Time) (WordSet, error) {
+	return buildWordSet(s.id, name, words, s.active, s.createdAt, now)
+}
+
+// Activate は有効にしたコピーを返す。他のセットを無効にするのはリポジトリの役目。
+func (s WordSet) Activate(now time.Time) WordSet {
+	s.active = true
+	s.updatedAt = now.UTC()
+	return s
+}
+
+// SameWords は other と単語と出題順が同じなら true を返す。
+func (s WordSet) SameWords(other WordSet) bool {
+	return slices.Equal(s.words, other.words)
+}
+
+func buildWordSet(id uuid.UUID, name string, raw []string, active bool, createdAt, updatedAt time.Time) (WordSet, error) {
+	name = strings.TrimSpace(name)
+	if id == uuid.Nil || name == "" || utf8.RuneCountInString(name) > MaxWordSetNameLength {
+		return WordSet{}, ErrInvalidWordSet
+	}
+	if len(raw) == 0 || len(raw) > MaxWordSetWords {
+		return WordSet{}, ErrInvalidWordSet
+	}
+
+	words := make([]HueWord, 0, len(raw))
+	for _, w := range raw {
+		word := HueWord(strings.TrimSpace(w))
+		if word == "" || utf8.RuneCountInString(string(word)) > MaxHueWordLength || slices.Contains(words, word) {
+			return WordSet{}, ErrInvalidWordSet
+		}
+		words = append(words, word)
+	}
+
+	created := createdAt.UTC()
+	updated := updatedAt.UTC()
+	if created.IsZero() || updated.Before(created) {
+		return WordSet{}, ErrInvalidWordSet
+	}
+
+	return WordSet{
+		id:        id,
+		name:      name,
+		words:     words,
+		active:    active,
+		createdAt: created,
+		updatedAt: updated,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWordSet_Validation(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name    string
+		setName string
+		words   []string
+	}{
+		{"blank name", " ", []string{"夜"}},
+		{"long name", strings.Repeat("あ", MaxWordSetNameLength+1), []string{"夜"}},
+		{"no words", "標準", nil},
+		{"blank word", "標準", []string{"夜", " "}},
+		{"long word", "標準", []string{strings.Repeat("あ", MaxHueWordLength+1)}},
+		{"duplicate word", "標準", []string{"夜", " 夜"}},
+	}
+
+	for _, tc := range cases {
+		if _, err := NewWordSet(tc.setName, tc.words, now); !errors.Is(err, ErrInvalidWordSet) {
+			t.Fatalf("%s: expected ErrInvalidWordSet, got %v", tc.name, err)
+		}
+	}
+
+	set, err := NewWordSet(" 標準 ", []string{" 夜", "海 "}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if set.Name() != "標準" || set.IsActive() || len(set.Words()) != 2 || set.Words()[0] != "夜" {
+		t.Fatalf("expected trimmed inactive set, got %+v", set)
+	}
+}
+
+func TestWordSet_CheckChoices(t *testing.T) {
+	set, _ := NewWordSet("標準", []string{"夜", "海"}, time.Now())
+
+	partial, _ := NewHueChoices(map[string]string{"夜": "黒"})
+	if err := set.CheckChoices(partial); err != nil {
+		t.Fatalf("partial answers should be accepted, got %v", err)
+	}
+
+	unknown, _ := NewHueChoices(map[string]string{"夜": "黒", "森": "緑"})
+	if err := set.CheckChoices(unknown); !errors.Is(err, ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice, got %v", err)
+	}
+}
+
+func TestWordSet_EditKeepsIdentity(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	set, _ := NewWordSet("標準", []string{"夜", "海"}, created)
+	set = set.Activate(created)
+
+	renamed, err := set.Edit("初期", []string{"夜", "海"}, created.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if renamed.ID() != set.ID() || !renamed.IsActive() || !renamed.SameWords(set) || !renamed.CreatedAt().Equal(created) {
+		t.Fatalf("expected rename to keep id, state and words, got %+v", renamed)
+	}
+
+	reordered, _ := set.Edit("標準", []string{"海", "夜"}, created.Add(time.Hour))
+	if reordered.SameWords(set) {
+		t.Fatalf("changing the order should count as changing the words")
+	}
+}
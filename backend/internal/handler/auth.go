@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"backend/internal/domain"
+)
+
+const bearerScheme = "Bearer"
+
+// Authenticator は Bearer 資格情報からセッションとユーザーを解決するユースケース境界。
+type Authenticator interface {
+	Authenticate(ctx context.Context, session domain.SessionData) (domain.LoginSession, domain.User, error)
+}
+
+// AuthMiddleware は Authorization: Bearer <user_id>.<token> を検証し、結果をリクエストコンテキストへ格納する。
+type AuthMiddleware struct {
+	authenticator Authenticator
+}
+
+func NewAuthMiddleware(authenticator Authenticator) *AuthMiddleware {
+	return &AuthMiddleware{authenticator: authenticator}
+}
+
+// Require は有効なセッションを必須とし、roles が指定されていればそのいずれかを要求する。
+func (m *AuthMiddleware) Require(next http.Handler, roles ...domain.UserRole) http.Handler {
+	return withMethods(next, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := parseBearerSession(r)
+		if err != nil {
+			respondMissingBearer(w)
+			return
+		}
+
+		loginSession, user, err := m.authenticator.Authenticate(r.Context(), session)
+		if err != nil {
+			handleAuthError(w, err)
+			return
+		}
+
+		if len(roles) > 0 && !slices.Contains(roles, user.Role()) {
+			respondForbidden(w)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withAuth(r.Context(), loginSession, user)))
+	}))
+}
+
+// Optional は Authorization ヘッダーがあればセッションを検証してコンテキストへ格納し、無ければ匿名のまま通す。
+// ヘッダーを送ったのに無効な場合は、匿名扱いにせず Require と同じく 401 を返す。
+func (m *AuthMiddleware) Optional(next http.Handler) http.Handler {
+	return withMethods(next, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session, err := parseBearerSession(r)
+		if err != nil {
+			respondMissingBearer(w)
+			return
+		}
+
+		loginSession, user, err := m.authenticator.Authenticate(r.Context(), session)
+		if err != nil {
+			handleAuthError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withAuth(r.Context(), loginSession, user)))
+	}))
+}
+
+// LoginSessionFromContext は AuthMiddleware が解決したセッションを返す。
+func LoginSessionFromContext(ctx context.Context) (domain.LoginSession, bool) {
+	state, ok := ctx.Value(authContextKey{}).(authState)
+	return state.session, ok
+}
+
+// UserFromContext は AuthMiddleware が解決したユーザーを返す。
+func UserFromContext(ctx context.Context) (domain.User, bool) {
+	state, ok := ctx.Value(authContextKey{}).(authState)
+	return state.user, ok
+}
+
+type authContextKey struct{}
+
+type authState struct {
+	session domain.LoginSession
+	user    domain.User
+}
+
+func withAuth(ctx context.Context, session domain.LoginSession, user domain.User) context.Context {
+	return context.WithValue(ctx, authContextKey{}, authState{session: session, user: user})
+}
+
+func parseBearerSession(r *http.Request) (domain.SessionData, error) {
+	scheme, credential, ok := strings.Cut(strings.TrimSpace(r.Header.Get("Authorization")), " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return domain.SessionData{}, domain.ErrInvalidSessionData
+	}
+
+	return domain.ParseBearerSessionData(credential)
+}
+
+func handleAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidSessionToken),
+		errors.Is(err, domain.ErrInvalidLoginSession),
+		errors.Is(err, domain.ErrExpiredToken):
+		w.Header().Set("WWW-Authenticate", bearerScheme)
+		respondUnauthorizedSession(w)
+	default:
+		respondInternalServerError(w)
+	}
+}
+
+func respondMissingBearer(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", bearerScheme)
+	respondAPIError(w, http.StatusUnauthorized, causeUnauthorized, "authorization", "bearer token is required")
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthMiddleware_Require_Success(t *testing.T) {
+	user := buildUser(t, domain.UserRoleAdmin)
+	session := buildSessionData(t, user.ID())
+	auth := &fakeAuthenticator{user: user}
+
+	var gotUser domain.User
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = UserFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+session.BearerValue())
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(auth).Require(next, domain.UserRoleAdmin).ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", res.Code)
+	}
+
+	if auth.session.UserID() != user.ID() || auth.session.Token().String() != session.Token().String() {
+		t.Fatalf("authenticator received unexpected session data")
+	}
+
+	if gotUser.ID() != user.ID() {
+		t.Fatalf("expected user in context")
+	}
+}
+
+func TestAuthMiddleware_Require_MissingHeader(t *testing.T) {
+	auth := &fakeAuthenticator{}
+	req := httptest.NewRequest(http.MethodPost, "/api/protected", nil)
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(auth).Require(unreachableHandler(t)).ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", res.Code)
+	}
+
+	if got := res.Header().Get("WWW-Authenticate"); got != "Bearer" {
+		t.Fatalf("expected WWW-Authenticate Bearer, got %q", got)
+	}
+
+	if auth.called {
+		t.Fatalf("authenticator should not be called without credentials")
+	}
+}
+
+func TestAuthMiddleware_Require_MalformedHeader(t *testing.T) {
+	cases := []string{
+		"Basic abc",
+		"Bearer",
+		"Bearer not-a-uuid.token",
+		"Bearer " + uuid.NewString(),
+	}
+
+	for _, header := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/protected", nil)
+		req.Header.Set("Authorization", header)
+		res := httptest.NewRecorder()
+
+		NewAuthMiddleware(&fakeAuthenticator{}).Require(unreachableHandler(t)).ServeHTTP(res, req)
+
+		if res.Code != http.StatusUnauthorized {
+			t.Fatalf("%q: expected 401, got %d", header, res.Code)
+		}
+	}
+}
+
+func TestAuthMiddleware_Require_ExpiredSession(t *testing.T) {
+	session := buildSessionData(t, uuid.New())
+	req := httptest.NewRequest(http.MethodPost, "/api/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+session.BearerValue())
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(&fakeAuthenticator{err: domain.ErrExpiredToken}).Require(unreachableHandler(t)).ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", res.Code)
+	}
+}
+
+func TestAuthMiddleware_Require_Forbidden(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	session := buildSessionData(t, user.ID())
+	req := httptest.NewRequest(http.MethodPost, "/api/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+session.BearerValue())
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(&fakeAuthenticator{user: user}).Require(unreachableHandler(t), domain.UserRoleAdmin).ServeHTTP(res, req)
+
+	if res.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", res.Code)
+	}
+}
+
+func TestAuthMiddleware_Require_InternalError(t *testing.T) {
+	session := buildSessionData(t, uuid.New())
+	req := httptest.NewRequest(http.MethodPost, "/api/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+session.BearerValue())
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(&fakeAuthenticator{err: errors.New("boom")}).Require(unreachableHandler(t)).ServeHTTP(res, req)
+
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", res.Code)
+	}
+}
+
+func TestAuthMiddleware_Optional_Anonymous(t *testing.T) {
+	auth := &fakeAuthenticator{}
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if _, ok := UserFromContext(r.Context()); ok {
+			t.Fatalf("anonymous request should not carry a user")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optional", nil)
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(auth).Optional(next).ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent || !called {
+		t.Fatalf("expected anonymous request to pass through, got %d", res.Code)
+	}
+	if auth.called {
+		t.Fatalf("authenticator should not be called without a header")
+	}
+}
+
+func TestAuthMiddleware_Optional_Authenticated(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	session := buildSessionData(t, user.ID())
+
+	var gotUser domain.User
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = UserFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optional", nil)
+	req.Header.Set("Authorization", "Bearer "+session.BearerValue())
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(&fakeAuthenticator{user: user}).Optional(next).ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent || gotUser.ID() != user.ID() {
+		t.Fatalf("expected user in context, got %d", res.Code)
+	}
+}
+
+func TestAuthMiddleware_Optional_InvalidSession(t *testing.T) {
+	session := buildSessionData(t, uuid.New())
+	req := httptest.NewRequest(http.MethodPost, "/api/optional", nil)
+	req.Header.Set("Authorization", "Bearer "+session.BearerValue())
+	res := httptest.NewRecorder()
+
+	NewAuthMiddleware(&fakeAuthenticator{err: domain.ErrExpiredToken}).Optional(unreachableHandler(t)).ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a presented but invalid session, got %d", res.Code)
+	}
+}
+
+type fakeAuthenticator struct {
+	session domain.SessionData
+	user    domain.User
+	err     error
+	called  bool
+}
+
+func (f *fakeAuthenticator) Authenticate(_ context.Context, session domain.SessionData) (domain.LoginSession, domain.User, error) {
+	f.called = true
+	f.session = session
+	if f.err != nil {
+		return domain.LoginSession{}, domain.User{}, f.err
+	}
+	return domain.LoginSession{}, f.user, nil
+}
+
+func unreachableHandler(t *testing.T) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("next handler should not be called")
+	})
+}
+
+func buildUser(t *testing.T, role domain.UserRole) domain.User {
+	t.Helper()
+	email, err := domain.NewEmail("tester@example.com")
+	if err != nil {
+		t.Fatalf("email error: %v", err)
+	}
+	password, err := domain.NewHashedPassword("hashed")
+	if err != nil {
+		t.Fatalf("password error: %v", err)
+	}
+	user, err := domain.NewUser(buildName(t, "Tester"), email, password, role, time.Now())
+	if err != nil {
+		t.Fatalf("user error: %v", err)
+	}
+	return user
+}
+
+func buildSessionData(t *testing.T, userID uuid.UUID) domain.SessionData {
+	t.Helper()
+	token, err := domain.NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	session, err := domain.NewSessionData(userID, token)
+	if err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+	return session
+}
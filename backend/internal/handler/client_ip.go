@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver はリクエストを送ったクライアントのアドレスを決める。
+// 直接の接続元が信頼するプロキシのときだけ X-Forwarded-For / X-Real-IP を読み、それ以外は接続元のアドレスを使う。
+// 信頼しない接続元の転送ヘッダーは送り主が自由に書けるため、レート制限のキーには使わない。
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// NewClientIPResolver は "10.0.0.1" のような IP か "172.16.0.0/12" のような CIDR を解析する。
+// proxies が空なら転送ヘッダーは読まない。
+func NewClientIPResolver(proxies []string) (*ClientIPResolver, error) {
+	r := &ClientIPResolver{trusted: make([]netip.Prefix, 0, len(proxies))}
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			r.trusted = append(r.trusted, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy: %q must be an IP address or CIDR", proxy)
+		}
+		r.trusted = append(r.trusted, prefix.Masked())
+	}
+	return r, nil
+}
+
+// ClientIP は r のクライアントのアドレスを返す。X-Forwarded-For は右 (接続元に近い側) から読み、
+// 信頼するプロキシを飛ばした最初のアドレスを使う。X-Forwarded-For が無ければ X-Real-IP を使う。
+func (c *ClientIPResolver) ClientIP(r *http.Request) string {
+	peer := remoteIP(r)
+	addr, err := netip.ParseAddr(peer)
+	if err != nil || !c.isTrusted(addr) {
+		return peer
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// 壊れた値より左は信用できないため、そこで止める。
+				break
+			}
+			if !c.isTrusted(hop) {
+				return hop.Unmap().String()
+			}
+			peer = hop.Unmap().String()
+		}
+		return peer
+	}
+
+	if real, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return real.Unmap().String()
+	}
+	return peer
+}
+
+func (c *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range c.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIPResolver_ClientIP(t *testing.T) {
+	ips, err := NewClientIPResolver([]string{"10.0.0.2", "172.16.0.0/12"})
+	if err != nil {
+		t.Fatalf("resolver error: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		realIP    string
+		want      string
+	}{
+		{"direct", "192.0.2.1:1234", nil, "", "192.0.2.1"},
+		{"untrusted peer ignores headers", "192.0.2.1:1234", []string{"198.51.100.7"}, "198.51.100.8", "192.0.2.1"},
+		{"trusted proxy", "10.0.0.2:1234", []string{"198.51.100.7"}, "", "198.51.100.7"},
+		{"spoofed hops are skipped", "10.0.0.2:1234", []string{"203.0.113.9, 198.51.100.7"}, "", "198.51.100.7"},
+		{"chained proxies", "10.0.0.2:1234", []string{"198.51.100.7, 172.16.3.4", "172.20.0.1"}, "", "198.51.100.7"},
+		{"real ip", "172.16.0.5:1234", nil, "198.51.100.8", "198.51.100.8"},
+		{"broken header", "10.0.0.2:1234", []string{"198.51.100.7, unknown"}, "", "10.0.0.2"},
+		{"ipv6 peer", "[2001:db8::1]:1234", []string{"198.51.100.7"}, "", "2001:db8::1"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tc.remote
+		for _, value := range tc.forwarded {
+			req.Header.Add("X-Forwarded-For", value)
+		}
+		if tc.realIP != "" {
+			req.Header.Set("X-Real-IP", tc.realIP)
+		}
+		if got := ips.ClientIP(req); got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestNewClientIPResolver_Invalid(t *testing.T) {
+	for _, proxy := range []string{"proxy.local", "10.0.0.0/33", ""} {
+		if _, err := NewClientIPResolver([]string{proxy}); err == nil {
+			t.Fatalf("%q: expected an error", proxy)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// corsAllowedHeaders はブラウザから送られてくるリクエストヘッダーのうち許可するもの。
+const corsAllowedHeaders = "Content-Type, Authorization, Idempotency-Key"
+
+// defaultCORSMethods は AllowedMethods を公開していないハンドラーに使う。
+var defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+
+// MethodLister は受け付ける HTTP メソッドを公開する。CORS のプリフライト応答に使う。
+type MethodLister interface {
+	AllowedMethods() []string
+}
+
+// CORSPolicy は許可リストに一致する Origin に対してだけ CORS ヘッダーを返す。
+// 許可リストには "https://app.example.com" のような完全一致と、"https://*.example.com" のようなサブドメインのワイルドカードを書ける。
+type CORSPolicy struct {
+	exact     map[string]struct{}
+	wildcards []originPattern
+	maxAge    time.Duration
+}
+
+// originPattern は "*.example.com" 形式のオリジン。apex ドメイン自体には一致しない。
+type originPattern struct {
+	scheme string
+	suffix string
+	port   string
+}
+
+// NewCORSPolicy は許可オリジンを解析する。maxAge が 0 の場合は Access-Control-Max-Age を返さない。
+func NewCORSPolicy(origins []string, maxAge time.Duration) (*CORSPolicy, error) {
+	p := &CORSPolicy{exact: make(map[string]struct{}, len(origins)), maxAge: maxAge}
+	for _, origin := range origins {
+		u, err := parseOrigin(origin)
+		if err != nil {
+			return nil, err
+		}
+
+		host := u.Hostname()
+		if !strings.Contains(host, "*") {
+			p.exact[formatOrigin(u.Scheme, host, u.Port())] = struct{}{}
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(host, "*.")
+		if !ok || rest == "" || strings.Contains(rest, "*") {
+			return nil, fmt.Errorf("cors: wildcard must be the leftmost label, got %q", origin)
+		}
+		p.wildcards = append(p.wildcards, originPattern{scheme: u.Scheme, suffix: "." + rest, port: u.Port()})
+	}
+	return p, nil
+}
+
+// Allows は Origin ヘッダーの値が許可リストに含まれるかを判定する。
+func (p *CORSPolicy) Allows(origin string) bool {
+	u, err := parseOrigin(origin)
+	if err != nil {
+		return false
+	}
+
+	host := u.Hostname()
+	if _, ok := p.exact[formatOrigin(u.Scheme, host, u.Port())]; ok {
+		return true
+	}
+	for _, w := range p.wildcards {
+		if u.Scheme == w.scheme && u.Port() == w.port && len(host) > len(w.suffix) && strings.HasSuffix(host, w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrap は next に CORS ヘッダーを付与し、OPTIONS のプリフライトにはここで応答する。
+// 許可メソッドは next が MethodLister を実装していればそこから取る。
+func (p *CORSPolicy) Wrap(next http.Handler) http.Handler {
+	methods := defaultCORSMethods
+	if lister, ok := next.(MethodLister); ok {
+		methods = lister.AllowedMethods()
+	}
+	allowMethods := strings.Join(append(append([]string(nil), methods...), http.MethodOptions), ", ")
+
+	return withMethods(next, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && p.Allows(origin)
+		if allowed {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if r.Method != http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Set("Allow", allowMethods)
+		if allowed && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", allowMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			if p.maxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(p.maxAge/time.Second)))
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+// withMethods はミドルウェアで包んだ後も inner の AllowedMethods を外側から参照できるようにする。
+func withMethods(inner, wrapped http.Handler) http.Handler {
+	lister, ok := inner.(MethodLister)
+	if !ok {
+		return wrapped
+	}
+	return methodHandler{Handler: wrapped, methods: lister.AllowedMethods()}
+}
+
+type methodHandler struct {
+	http.Handler
+	methods []string
+}
+
+func (h methodHandler) AllowedMethods() []string {
+	return h.methods
+}
+
+func parseOrigin(origin string) (*url.URL, error) {
+	u, err := url.Parse(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/")))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.User != nil {
+		return nil, fmt.Errorf("cors: invalid origin %q", origin)
+	}
+	return u, nil
+}
+
+func formatOrigin(scheme, host, port string) string {
+	if port == "" {
+		return scheme + "://" + host
+	}
+	return scheme + "://" + host + ":" + port
+}
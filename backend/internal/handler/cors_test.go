@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCORSPolicy_Allows(t *testing.T) {
+	policy, err := NewCORSPolicy([]string{"https://www.example.com", "https://*.example.org", "http://localhost:3000"}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://www.example.com", true},
+		{"HTTPS://WWW.EXAMPLE.COM", true},
+		{"http://www.example.com", false},
+		{"https://example.com", false},
+		{"https://api.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://evilexample.org", false},
+		{"https://api.example.org:8443", false},
+		{"http://localhost:3000", true},
+		{"http://localhost:3001", false},
+		{"null", false},
+	}
+	for _, tc := range cases {
+		if got := policy.Allows(tc.origin); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.origin, tc.want, got)
+		}
+	}
+}
+
+func TestNewCORSPolicy_InvalidOrigin(t *testing.T) {
+	for _, origin := range []string{"*", "example.com", "https://a.*.example.com", "https://example.com/path"} {
+		if _, err := NewCORSPolicy([]string{origin}, 0); err == nil {
+			t.Fatalf("%s: expected error", origin)
+		}
+	}
+}
+
+func TestCORSPolicy_Preflight(t *testing.T) {
+	policy, _ := NewCORSPolicy([]string{"https://app.example.com"}, 10*time.Minute)
+	h := policy.Wrap(NewLogoutHandler(&fakeSessionService{}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/logout", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", res.Code)
+	}
+	for header, want := range map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "POST, OPTIONS",
+		"Access-Control-Allow-Headers":     "Content-Type, Authorization, Idempotency-Key",
+		"Access-Control-Max-Age":           "600",
+	} {
+		if got := res.Header().Get(header); got != want {
+			t.Fatalf("%s: expected %q, got %q", header, want, got)
+		}
+	}
+	if vary := res.Header().Values("Vary"); !slices.Contains(vary, "Origin") || !slices.Contains(vary, "Access-Control-Request-Method") {
+		t.Fatalf("unexpected Vary: %v", vary)
+	}
+}
+
+func TestCORSPolicy_DisallowedOrigin(t *testing.T) {
+	policy, _ := NewCORSPolicy([]string{"https://app.example.com"}, time.Minute)
+	h := policy.Wrap(NewLogoutHandler(&fakeSessionService{}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/logout", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	if got := res.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no allow-origin, got %q", got)
+	}
+	if got := res.Header().Get("Access-Control-Allow-Methods"); got != "" {
+		t.Fatalf("expected no allow-methods, got %q", got)
+	}
+	if !slices.Contains(res.Header().Values("Vary"), "Origin") {
+		t.Fatalf("expected Vary: Origin even when rejected")
+	}
+}
+
+func TestCORSPolicy_SimpleRequestPassesThrough(t *testing.T) {
+	policy, _ := NewCORSPolicy([]string{"https://app.example.com"}, 0)
+	called := false
+	h := policy.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/anything", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	if !called {
+		t.Fatalf("expected next handler to be called")
+	}
+	if got := res.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected allow-origin, got %q", got)
+	}
+}
+
+func TestCORSPolicy_MethodsSurviveMiddleware(t *testing.T) {
+	policy, _ := NewCORSPolicy([]string{"https://app.example.com"}, 0)
+	auth := NewAuthMiddleware(&fakeAuthenticator{})
+	h := policy.Wrap((&ClientIPResolver{}).RateLimitByIP(failingLimiter{}, auth.Require(NewRefreshHandler(&fakeSessionService{}))))
+
+	lister, ok := h.(MethodLister)
+	if !ok {
+		t.Fatalf("expected wrapped handler to expose allowed methods")
+	}
+	if methods := lister.AllowedMethods(); !slices.Equal(methods, []string{http.MethodPost}) {
+		t.Fatalf("unexpected methods: %v", methods)
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+)
+
+// EmailVerificationService はメールアドレス確認のユースケース境界。
+type EmailVerificationService interface {
+	Verify(ctx context.Context, token string) (domain.User, error)
+	ResendVerification(ctx context.Context, email domain.Email) error
+}
+
+// EmailVerifyHandler は /api/email/verify でメールのトークンを使ってアドレスを確認済みにする。
+type EmailVerifyHandler struct {
+	service EmailVerificationService
+}
+
+func NewEmailVerifyHandler(service EmailVerificationService) *EmailVerifyHandler {
+	return &EmailVerifyHandler{service: service}
+}
+
+func (h *EmailVerifyHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *EmailVerifyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req api.EmailVerifyRequest
+	if !decodeStrictJSON(w, r, &req) {
+		return
+	}
+
+	if _, err := h.service.Verify(r.Context(), req.Token); err != nil {
+		if errors.Is(err, domain.ErrInvalidVerificationToken) {
+			respondInvalidField(w, "token")
+			return
+		}
+		respondInternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerificationHandler は /api/email/resend-verification で確認メールを送り直す。
+// ログインできない未確認のユーザーも使えるよう認証は求めず、登録の有無を漏らさないため形式が正しければ常に 202 を返す。
+type ResendVerificationHandler struct {
+	service EmailVerificationService
+}
+
+func NewResendVerificationHandler(service EmailVerificationService) *ResendVerificationHandler {
+	return &ResendVerificationHandler{service: service}
+}
+
+func (h *ResendVerificationHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *ResendVerificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req api.ResendVerificationRequest
+	if !decodeStrictJSON(w, r, &req) {
+		return
+	}
+
+	email, err := req.ToDomain()
+	if err != nil {
+		respondInvalidField(w, "email")
+		return
+	}
+
+	if err := h.service.ResendVerification(r.Context(), email); err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+)
+
+type fakeEmailVerificationService struct {
+	err   error
+	token string
+	email domain.Email
+}
+
+func (f *fakeEmailVerificationService) Verify(_ context.Context, token string) (domain.User, error) {
+	f.token = token
+	return domain.User{}, f.err
+}
+
+func (f *fakeEmailVerificationService) ResendVerification(_ context.Context, email domain.Email) error {
+	f.email = email
+	return f.err
+}
+
+func TestEmailVerifyHandler_ServeHTTP(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		field  string
+	}{
+		{name: "success", status: http.StatusNoContent},
+		{name: "invalid token", err: domain.ErrInvalidVerificationToken, status: http.StatusBadRequest, field: "token"},
+		{name: "internal", err: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		svc := &fakeEmailVerificationService{err: tc.err}
+		req := httptest.NewRequest(http.MethodPost, "/api/email/verify", strings.NewReader(`{"token":"abc"}`))
+		res := httptest.NewRecorder()
+
+		NewEmailVerifyHandler(svc).ServeHTTP(res, req)
+
+		if res.Code != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.status, res.Code)
+		}
+		if svc.token != "abc" {
+			t.Fatalf("%s: unexpected token %q", tc.name, svc.token)
+		}
+		if tc.field != "" {
+			var apiErr api.ErrorResponse
+			if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || apiErr.Field != tc.field {
+				t.Fatalf("%s: expected field %q, got %+v (%v)", tc.name, tc.field, apiErr, err)
+			}
+		}
+	}
+}
+
+func TestResendVerificationHandler_ServeHTTP(t *testing.T) {
+	svc := &fakeEmailVerificationService{}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/email/resend-verification", strings.NewReader(`{"email":"alice@example.com"}`))
+	res := httptest.NewRecorder()
+	NewResendVerificationHandler(svc).ServeHTTP(res, req)
+	if res.Code != http.StatusAccepted || svc.email.String() != "alice@example.com" {
+		t.Fatalf("expected 202 for alice, got %d (%q)", res.Code, svc.email.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/email/resend-verification", strings.NewReader(`{"email":"not-an-email"}`))
+	res = httptest.NewRecorder()
+	NewResendVerificationHandler(svc).ServeHTTP(res, req)
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid email, got %d", res.Code)
+	}
+}
@@ -2,8 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"backend/internal/domain"
 	"backend/pkg/api"
@@ -15,8 +19,15 @@ const (
 	causeMethodNotAllowed  = "method_not_allowed"
 	causeInvalidCredential = "invalid_credential"
 	causeUnauthorized      = "unauthorized"
+	causeForbidden         = "forbidden"
+	causeRateLimited       = "rate_limited"
 	causeDuplicate         = "duplicate"
+	causeNotAcceptable     = "not_acceptable"
+	causeNotFound          = "not_found"
+	causeConflict          = "conflict"
 	causeInternalError     = "internal_error"
+	// causePasswordPrefix に domain.PasswordRule を続けたもの (password_too_short など) を、パスワードが規則を満たさないときの cause にする。
+	causePasswordPrefix = "password_"
 )
 
 func respondInvalidJSON(w http.ResponseWriter) {
@@ -27,11 +38,25 @@ func respondInvalidField(w http.ResponseWriter, field string) {
 	respondAPIError(w, http.StatusBadRequest, causeInvalidRequest, field, fmt.Sprintf("%s is invalid", field))
 }
 
+// respondInvalidPassword は err が *domain.PasswordPolicyError なら満たさなかった規則を cause と message に載せる。
+func respondInvalidPassword(w http.ResponseWriter, field string, err error) {
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		respondAPIError(w, http.StatusBadRequest, causePasswordPrefix+policyErr.Rule().String(), field, policyErr.Message())
+		return
+	}
+	respondInvalidField(w, field)
+}
+
 func respondMethodNotAllowed(w http.ResponseWriter, allowed string) {
 	w.Header().Set("Allow", allowed)
 	respondAPIError(w, http.StatusMethodNotAllowed, causeMethodNotAllowed, "method", fmt.Sprintf("use %s", allowed))
 }
 
+func respondNotAcceptable(w http.ResponseWriter, supported string) {
+	respondAPIError(w, http.StatusNotAcceptable, causeNotAcceptable, "accept", fmt.Sprintf("supported types: %s", supported))
+}
+
 func respondDuplicateField(w http.ResponseWriter, field string) {
 	respondAPIError(w, http.StatusConflict, causeDuplicate, field, fmt.Sprintf("%s already exists", field))
 }
@@ -44,6 +69,36 @@ func respondUnauthorizedSession(w http.ResponseWriter) {
 	respondAPIError(w, http.StatusUnauthorized, causeUnauthorized, "session", "invalid or expired session")
 }
 
+func respondForbidden(w http.ResponseWriter) {
+	respondAPIError(w, http.StatusForbidden, causeForbidden, "role", "insufficient role")
+}
+
+func respondAccountDisabled(w http.ResponseWriter) {
+	respondAPIError(w, http.StatusForbidden, causeForbidden, "account", "account is disabled")
+}
+
+func respondEmailNotVerified(w http.ResponseWriter) {
+	respondAPIError(w, http.StatusForbidden, causeForbidden, "email", "email address is not verified")
+}
+
+func respondNotFound(w http.ResponseWriter, field string) {
+	respondAPIError(w, http.StatusNotFound, causeNotFound, field, fmt.Sprintf("%s not found", field))
+}
+
+func respondConflict(w http.ResponseWriter, field, message string) {
+	respondAPIError(w, http.StatusConflict, causeConflict, field, message)
+}
+
+// respondRateLimited は Retry-After に次の試行までの秒数(切り上げ、最低 1 秒)を設定する。
+func respondRateLimited(w http.ResponseWriter, field string, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	respondAPIError(w, http.StatusTooManyRequests, causeRateLimited, field, "too many attempts, retry later")
+}
+
 func respondInternalServerError(w http.ResponseWriter) {
 	respondAPIError(w, http.StatusInternalServerError, causeInternalError, "server", "internal server error")
 }
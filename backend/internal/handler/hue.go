@@ -9,18 +9,68 @@ import (
 
 	"backend/internal/domain"
 	"backend/pkg/api"
+
+	"github.com/google/uuid"
 )
 
-// HueSaveService は Hue 結果保存のユースケース境界。
+// HueSaveService は Hue 結果保存のユースケース境界。challenge は IssueChallenge で発行したトークン。
+// 同じ Idempotency-Key の回答が保存済みなら、それを返して replayed を true にする。
 type HueSaveService interface {
-	SaveResult(ctx context.Context, record domain.HueRecord) error
+	IssueChallenge(ctx context.Context) (domain.HueChallenge, string)
+	SaveResult(ctx context.Context, record domain.HueRecord, challenge string) (saved domain.HueRecord, replayed bool, err error)
 }
 
-// HueGetService は Hue データ取得のユースケース境界。
+// HueGetService は Hue データ取得のユースケース境界。呼び出し元の認可は AuthMiddleware で済ませておく。
 type HueGetService interface {
-	GetData(ctx context.Context, session domain.SessionData, recordRange domain.RecordRange) ([]domain.HueRecord, error)
+	GetData(ctx context.Context, req domain.RecordPageRequest) (domain.RecordPage, error)
+}
+
+// HueMyResultsService はログイン中のユーザー自身の回答を取得するユースケース境界。
+type HueMyResultsService interface {
+	GetUserResults(ctx context.Context, userID uuid.UUID, after domain.RecordCursor, limit int) (domain.RecordPage, error)
+}
+
+// HueStatsService は Hue 集計のユースケース境界。version が 0 なら有効な版を集計する。
+type HueStatsService interface {
+	GetStats(ctx context.Context, version int) (domain.Palette, []domain.HueWordStats, error)
+}
+
+// idempotencyKeyHeader は save-result の再送を見分けるためにクライアントが付けるヘッダー。
+// idempotentReplayedHeader は保存済みの回答を返したときに付けるヘッダー。
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// HueChallengeHandler は /api/hue-are-you/challenge で save-result に添えるチャレンジを発行する。
+type HueChallengeHandler struct {
+	service HueSaveService
+}
+
+func NewHueChallengeHandler(service HueSaveService) *HueChallengeHandler {
+	return &HueChallengeHandler{service: service}
+}
+
+func (h *HueChallengeHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
 }
 
+func (h *HueChallengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	challenge, token := h.service.IssueChallenge(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(api.HueChallengeResponse{Challenge: token, ExpiresAt: challenge.ExpiresAt()})
+}
+
+// HueSaveHandler は /api/hue-are-you/save-result で回答をまとめて保存する。
+// Idempotency-Key ヘッダーが付いていれば、同じキーの再送には保存済みの回答で 200 を返す。
 type HueSaveHandler struct {
 	service HueSaveService
 }
@@ -29,6 +79,10 @@ func NewHueSaveHandler(service HueSaveService) *HueSaveHandler {
 	return &HueSaveHandler{service: service}
 }
 
+func (h *HueSaveHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
 func (h *HueSaveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		respondMethodNotAllowed(w, http.MethodPost)
@@ -44,21 +98,76 @@ func (h *HueSaveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	key, err := idempotencyKeyFrom(r)
+	if err != nil {
+		respondInvalidField(w, "idempotency_key")
+		return
+	}
+
 	submission, err := req.ToDomain()
 	if err != nil {
 		log.Print("error: ", err)
-		respondInvalidField(w, "record")
+		switch {
+		case errors.Is(err, domain.ErrInvalidWordSet):
+			respondInvalidField(w, "word_set_id")
+		case errors.Is(err, domain.ErrInvalidPalette):
+			respondInvalidField(w, "palette_version")
+		default:
+			respondInvalidField(w, "record")
+		}
 		return
 	}
 
-	if err := h.service.SaveResult(r.Context(), submission); err != nil {
-		handleHueServiceError(w, err)
+	// AuthMiddleware.Optional でログイン済みと分かっていれば、そのユーザーの回答として保存する。
+	if user, ok := UserFromContext(r.Context()); ok {
+		submission = submission.SubmittedBy(user.ID())
+	}
+
+	if key != "" {
+		submission = submission.KeyedBy(key)
+	}
+
+	_, replayed, err := h.service.SaveResult(r.Context(), submission, req.Challenge)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrWordSetNotFound):
+			respondInvalidField(w, "word_set_id")
+		case errors.Is(err, domain.ErrPaletteNotFound):
+			respondInvalidField(w, "palette_version")
+		case errors.Is(err, domain.ErrInvalidChoice):
+			respondInvalidField(w, "choice")
+		case errors.Is(err, domain.ErrInvalidHueChallenge):
+			respondInvalidField(w, "challenge")
+		case errors.Is(err, domain.ErrHueChallengeUsed):
+			respondConflict(w, "challenge", "challenge has already been used")
+		case errors.Is(err, domain.ErrIdempotencyKeyReused):
+			respondConflict(w, "idempotency_key", "idempotency key was already used for a different submission")
+		default:
+			handleHueServiceError(w, err)
+		}
 		return
 	}
 
+	if replayed {
+		w.Header().Set(idempotentReplayedHeader, "true")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
 	w.WriteHeader(http.StatusCreated)
 }
 
+// idempotencyKeyFrom は Idempotency-Key ヘッダーを読む。付いていなければ空のキーを返し、不正か複数付いていればエラーを返す。
+func idempotencyKeyFrom(r *http.Request) (domain.IdempotencyKey, error) {
+	values := r.Header.Values(idempotencyKeyHeader)
+	if len(values) == 0 {
+		return "", nil
+	}
+	if len(values) > 1 {
+		return "", domain.ErrInvalidIdempotencyKey
+	}
+	return domain.NewIdempotencyKey(values[0])
+}
+
 type HueGetHandler struct {
 	service HueGetService
 }
@@ -67,6 +176,10 @@ func NewHueGetHandler(service HueGetService) *HueGetHandler {
 	return &HueGetHandler{service: service}
 }
 
+func (h *HueGetHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
 func (h *HueGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		respondMethodNotAllowed(w, http.MethodPost)
@@ -81,29 +194,126 @@ func (h *HueGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session, recordRange, err := req.ToDomain()
+	pageRequest, err := req.ToDomain()
 	if err != nil {
 		switch {
-		case errors.Is(err, domain.ErrInvalidSessionToken),
-			errors.Is(err, domain.ErrInvalidSessionData):
-			respondInvalidField(w, "session")
-		case errors.Is(err, domain.ErrInvalidRange):
-			respondInvalidField(w, "data-range")
+		case errors.Is(err, domain.ErrInvalidFilter):
+			respondInvalidField(w, "filter")
+		case errors.Is(err, domain.ErrInvalidCursor):
+			respondInvalidField(w, "cursor")
 		default:
-			respondInvalidField(w, "request")
+			respondInvalidField(w, "limit")
+		}
+		return
+	}
+
+	page, err := h.service.GetData(r.Context(), pageRequest)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidFilter) {
+			respondInvalidField(w, "filter")
+			return
 		}
+		handleHueServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewGetDataResponse(page))
+}
+
+// HueMyResultsHandler は /api/hue-are-you/my-results でログイン中のユーザーの回答を作成順に返す。
+// ページは ?limit= と ?cursor= (前回の next_cursor) で指定する。
+type HueMyResultsHandler struct {
+	service HueMyResultsService
+}
+
+func NewHueMyResultsHandler(service HueMyResultsService) *HueMyResultsHandler {
+	return &HueMyResultsHandler{service: service}
+}
+
+func (h *HueMyResultsHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *HueMyResultsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
 		return
 	}
 
-	records, err := h.service.GetData(r.Context(), session, recordRange)
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		respondMissingBearer(w)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, ok := queryInt(query, "limit")
+	if !ok {
+		respondInvalidField(w, "limit")
+		return
+	}
+
+	after, err := domain.ParseRecordCursor(query.Get("cursor"))
 	if err != nil {
+		respondInvalidField(w, "cursor")
+		return
+	}
+
+	page, err := h.service.GetUserResults(r.Context(), user.ID(), after, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRange) {
+			respondInvalidField(w, "limit")
+			return
+		}
+		handleHueServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewMyResultsResponse(page))
+}
+
+// HueStatsHandler は /api/hue-are-you/stats で単語ごとの色分布を返す。?palette= で集計する版を選び、省略時は有効な版。
+type HueStatsHandler struct {
+	service HueStatsService
+}
+
+func NewHueStatsHandler(service HueStatsService) *HueStatsHandler {
+	return &HueStatsHandler{service: service}
+}
+
+func (h *HueStatsHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *HueStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	version, ok := queryInt(r.URL.Query(), "palette")
+	if !ok || version < 0 {
+		respondInvalidField(w, "palette")
+		return
+	}
+
+	palette, stats, err := h.service.GetStats(r.Context(), version)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaletteNotFound) {
+			respondNotFound(w, "palette")
+			return
+		}
 		handleHueServiceError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(api.NewGetDataResponse(records))
+	_ = json.NewEncoder(w).Encode(api.NewHueStatsResponse(palette, stats))
 }
 
 func handleHueServiceError(w http.ResponseWriter, err error) {
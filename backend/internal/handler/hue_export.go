@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+)
+
+const (
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+
+	// hueExportFlushEvery 行ごとにクライアントへ送り出し、バッファが膨らまないようにする。
+	hueExportFlushEvery = 500
+)
+
+// HueExportService は Hue レコード一括エクスポートのユースケース境界。
+type HueExportService interface {
+	Export(ctx context.Context, begin func(words []domain.HueWord) error, write func(domain.HueRecord) error) error
+}
+
+type hueExportFormat int
+
+const (
+	// hueExportCSVLong は 1 レコード・1 単語ごとに 1 行の CSV。
+	hueExportCSVLong hueExportFormat = iota
+	// hueExportCSVWide は 1 レコード 1 行で、単語ごとに列を持つ CSV。
+	hueExportCSVWide
+	hueExportNDJSON
+)
+
+// HueExportHandler は /api/hue-are-you/export で全レコードをストリーミングで返す。
+// 形式は ?format=csv|ndjson (CSV は ?layout=long|wide) か、指定がなければ Accept で決める。
+type HueExportHandler struct {
+	service HueExportService
+}
+
+func NewHueExportHandler(service HueExportService) *HueExportHandler {
+	return &HueExportHandler{service: service}
+}
+
+func (h *HueExportHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *HueExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	format, field, ok := negotiateHueExportFormat(r)
+	if !ok {
+		if field == "accept" {
+			respondNotAcceptable(w, contentTypeCSV+", "+contentTypeNDJSON)
+			return
+		}
+		respondInvalidField(w, field)
+		return
+	}
+
+	// 大きなテーブルでは Server.WriteTimeout を超えるため、このレスポンスだけ書き込み期限を外す。
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	exporter := newHueExporter(w, rc, format)
+	var begin func([]domain.HueWord) error
+	if format == hueExportCSVWide {
+		begin = exporter.begin
+	}
+
+	if err := h.service.Export(r.Context(), begin, exporter.write); err != nil {
+		if !exporter.started {
+			handleHueServiceError(w, err)
+			return
+		}
+		// ステータスは送信済みのため、接続を切って途中までのデータであることをクライアントに伝える。
+		log.Print("hue export aborted: ", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if err := exporter.finish(); err != nil {
+		log.Print("hue export flush: ", err)
+	}
+}
+
+// negotiateHueExportFormat は不正な指定があれば、問題のあるフィールド名と false を返す。
+func negotiateHueExportFormat(r *http.Request) (hueExportFormat, string, bool) {
+	query := r.URL.Query()
+
+	var format string
+	switch query.Get("format") {
+	case "csv":
+		format = contentTypeCSV
+	case "ndjson":
+		format = contentTypeNDJSON
+	case "":
+		var ok bool
+		if format, ok = acceptedExportType(r.Header.Values("Accept")); !ok {
+			return 0, "accept", false
+		}
+	default:
+		return 0, "format", false
+	}
+
+	if format == contentTypeNDJSON {
+		return hueExportNDJSON, "", true
+	}
+
+	switch query.Get("layout") {
+	case "", "long":
+		return hueExportCSVLong, "", true
+	case "wide":
+		return hueExportCSVWide, "", true
+	default:
+		return 0, "layout", false
+	}
+}
+
+// acceptedExportType は Accept の中から最初に対応できるメディアタイプを選ぶ。未指定やワイルドカードは CSV とする。
+func acceptedExportType(values []string) (string, bool) {
+	if len(values) == 0 {
+		return contentTypeCSV, true
+	}
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			switch mediaType {
+			case contentTypeCSV, "*/*", "text/*":
+				return contentTypeCSV, true
+			case contentTypeNDJSON, "application/ndjson", "application/jsonl":
+				return contentTypeNDJSON, true
+			}
+		}
+	}
+	return "", false
+}
+
+// hueExporter はレスポンスヘッダーを最初の書き込みまで遅らせ、取得前のエラーなら通常のエラー応答を返せるようにする。
+type hueExporter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	format  hueExportFormat
+	csv     *csv.Writer
+	json    *json.Encoder
+	words   []domain.HueWord
+	started bool
+	rows    int
+}
+
+func newHueExporter(w http.ResponseWriter, rc *http.ResponseController, format hueExportFormat) *hueExporter {
+	return &hueExporter{w: w, rc: rc, format: format}
+}
+
+func (e *hueExporter) begin(words []domain.HueWord) error {
+	e.words = words
+	return nil
+}
+
+func (e *hueExporter) start() error {
+	e.started = true
+
+	contentType, filename := contentTypeCSV+"; charset=utf-8", "hue-records.csv"
+	if e.format == hueExportNDJSON {
+		contentType, filename = contentTypeNDJSON, "hue-records.ndjson"
+	}
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	e.w.WriteHeader(http.StatusOK)
+
+	switch e.format {
+	case hueExportNDJSON:
+		e.json = json.NewEncoder(e.w)
+		return nil
+	case hueExportCSVWide:
+		e.csv = csv.NewWriter(e.w)
+		header := []string{"record_id", "name", "created_at", "order_mode", "order_seed", "quality_flags", "word_set_id", "palette_version"}
+		for _, word := range e.words {
+			header = append(header, csvText(string(word)))
+		}
+		return e.csv.Write(header)
+	default:
+		e.csv = csv.NewWriter(e.w)
+		return e.csv.Write([]string{"record_id", "name", "created_at", "word", "color", "position", "order_mode", "order_seed", "quality_flags", "word_set_id", "palette_version"})
+	}
+}
+
+func (e *hueExporter) write(record domain.HueRecord) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	if err := e.writeRecord(record); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%hueExportFlushEvery == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *hueExporter) writeRecord(record domain.HueRecord) error {
+	if e.format == hueExportNDJSON {
+		return e.json.Encode(api.NewHueExportRecord(record))
+	}
+
+	id := record.ID().String()
+	name := csvText(record.Name().String())
+	createdAt := record.CreatedAt().Format(time.RFC3339Nano)
+	choices := record.ChoiceMap()
+	// セッションを通さない回答は出題順が分からないため、順序の列を空にする。
+	order, presented := record.WordOrder()
+	var mode, seed string
+	if presented {
+		mode, seed = order.Mode().String(), strconv.FormatInt(order.Seed(), 10)
+	}
+	flags := make([]string, 0, len(record.QualityFlags()))
+	for _, flag := range record.QualityFlags() {
+		flags = append(flags, flag.String())
+	}
+	quality := strings.Join(flags, ";")
+	// 単語セット・パレットを導入する前の回答は、どちらも空欄にする。
+	var wordSetID, paletteVersion string
+	if setID, ok := record.WordSetID(); ok {
+		wordSetID = setID.String()
+	}
+	if version, ok := record.PaletteVersion(); ok {
+		paletteVersion = strconv.Itoa(version)
+	}
+
+	if e.format == hueExportCSVWide {
+		row := []string{id, name, createdAt, mode, seed, quality, wordSetID, paletteVersion}
+		for _, word := range e.words {
+			row = append(row, csvText(choices[string(word)]))
+		}
+		return e.csv.Write(row)
+	}
+
+	for _, word := range slices.Sorted(maps.Keys(choices)) {
+		var position string
+		if p, ok := order.Position(domain.HueWord(word)); ok {
+			position = strconv.Itoa(p)
+		}
+		if err := e.csv.Write([]string{id, name, createdAt, csvText(word), csvText(choices[word]), position, mode, seed, quality, wordSetID, paletteVersion}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvText は利用者が入力した文字列を、表計算ソフトで数式として評価されないよう先頭に ' を付けて返す。
+// 数値の列 (order_seed など) は負の値も数値のまま読めるよう、この処理を通さない。
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *hueExporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := e.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// finish はレコードが 0 件でもヘッダー行だけは返す。
+func (e *hueExporter) finish() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+func TestHueExportHandler_CSVLong(t *testing.T) {
+	records := buildExportRecords(t)
+	res := serveExport(t, &fakeHueExportService{records: records}, "/api/hue-are-you/export?format=csv", "")
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+	if ct := res.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if cd := res.Header().Get("Content-Disposition"); cd != `attachment; filename=hue-records.csv` {
+		t.Fatalf("unexpected content disposition %q", cd)
+	}
+
+	rows, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("csv error: %v", err)
+	}
+
+	if !slices.Equal(rows[0], []string{"record_id", "name", "created_at", "word", "color", "position", "order_mode", "order_seed", "quality_flags", "word_set_id", "palette_version"}) {
+		t.Fatalf("unexpected header: %v", rows[0])
+	}
+	// alice: 夜, 海 / bob: 夜
+	if len(rows) != 4 {
+		t.Fatalf("expected 3 data rows, got %d", len(rows)-1)
+	}
+	if rows[1][0] != records[0].ID().String() || rows[1][3] != "夜" || rows[1][4] != "黒" || rows[2][3] != "海" {
+		t.Fatalf("unexpected rows: %v", rows[1:])
+	}
+	if rows[1][2] != "2025-01-02T03:04:05Z" {
+		t.Fatalf("unexpected created_at: %s", rows[1][2])
+	}
+	if !slices.Equal(rows[1][5:9], []string{"2", "shuffle", "42", ""}) || !slices.Equal(rows[2][5:9], []string{"1", "shuffle", "42", ""}) {
+		t.Fatalf("expected alice's positions in the presented order, got %v", rows[1:3])
+	}
+	if !slices.Equal(rows[3][5:9], []string{"", "", "", "too_fast"}) {
+		t.Fatalf("expected empty order columns without a session and the quality flag, got %v", rows[3])
+	}
+	if !slices.Equal(rows[1][9:], []string{exportWordSetID.String(), "3"}) || !slices.Equal(rows[3][9:], []string{"", ""}) {
+		t.Fatalf("expected the word set and palette version only for alice, got %v", rows[1:])
+	}
+}
+
+func TestHueExportHandler_CSVWide(t *testing.T) {
+	svc := &fakeHueExportService{records: buildExportRecords(t), words: []domain.HueWord{"夜", "海"}}
+	res := serveExport(t, svc, "/api/hue-are-you/export?format=csv&layout=wide", "")
+
+	rows, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("csv error: %v", err)
+	}
+
+	if !slices.Equal(rows[0], []string{"record_id", "name", "created_at", "order_mode", "order_seed", "quality_flags", "word_set_id", "palette_version", "夜", "海"}) {
+		t.Fatalf("unexpected header: %v", rows[0])
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 2 data rows, got %d", len(rows)-1)
+	}
+	if !slices.Equal(rows[1][3:], []string{"shuffle", "42", "", exportWordSetID.String(), "3", "黒", "青"}) || !slices.Equal(rows[2][3:], []string{"", "", "too_fast", "", "", "紫", ""}) {
+		t.Fatalf("unexpected rows: %v", rows[1:])
+	}
+	if !svc.beganWithWords {
+		t.Fatalf("wide layout should request the word list")
+	}
+}
+
+func TestHueExportHandler_CSVEscapesFormulas(t *testing.T) {
+	var records []domain.HueRecord
+	for _, name := range []string{"=HYPERLINK(\"http://example.com\")", "+1", "-1", "@SUM(A1)", "alice"} {
+		record, err := domain.NewHueRecordFromRaw(name, map[string]string{"夜": "=黒"})
+		if err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+		records = append(records, record)
+	}
+	svc := &fakeHueExportService{records: records, words: []domain.HueWord{"夜"}}
+
+	for _, target := range []string{"/api/hue-are-you/export?format=csv", "/api/hue-are-you/export?format=csv&layout=wide"} {
+		rows, err := csv.NewReader(serveExport(t, svc, target, "").Body).ReadAll()
+		if err != nil {
+			t.Fatalf("%s: csv error: %v", target, err)
+		}
+		for _, row := range rows[1:] {
+			if name := row[1]; name != "alice" && !strings.HasPrefix(name, "'") {
+				t.Fatalf("%s: expected formula-like name to be prefixed, got %q", target, name)
+			}
+			if !slices.Contains(row, "'=黒") {
+				t.Fatalf("%s: expected formula-like color to be prefixed, got %v", target, row)
+			}
+		}
+		if rows[len(rows)-1][1] != "alice" {
+			t.Fatalf("%s: expected plain names to stay unchanged, got %q", target, rows[len(rows)-1][1])
+		}
+	}
+}
+
+func TestHueExportHandler_NDJSONByAccept(t *testing.T) {
+	records := buildExportRecords(t)
+	svc := &fakeHueExportService{records: records}
+	res := serveExport(t, svc, "/api/hue-are-you/export", "application/x-ndjson")
+
+	if ct := res.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if svc.beganWithWords {
+		t.Fatalf("ndjson should not request the word list")
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	var lines []api.HueExportRecord
+	for scanner.Scan() {
+		var line api.HueExportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line decode error: %v", err)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 2 || lines[1].Name != "bob" || lines[0].Choice["海"] != "青" {
+		t.Fatalf("unexpected lines: %+v", lines)
+	}
+	if order := lines[0].WordOrder; order == nil || order.Mode != "shuffle" || order.Seed != 42 || !slices.Equal(order.Words, []string{"海", "夜"}) {
+		t.Fatalf("unexpected word order: %+v", order)
+	}
+	if lines[1].WordOrder != nil {
+		t.Fatalf("expected no word order without a session, got %+v", lines[1].WordOrder)
+	}
+	if lines[0].WordSetID != exportWordSetID.String() || lines[0].PaletteVersion != 3 || lines[1].WordSetID != "" || lines[1].PaletteVersion != 0 {
+		t.Fatalf("unexpected word set or palette version: %+v", lines)
+	}
+	if lines[0].QualityFlags != nil || !slices.Equal(lines[1].QualityFlags, []string{"too_fast"}) {
+		t.Fatalf("unexpected quality flags: %v and %v", lines[0].QualityFlags, lines[1].QualityFlags)
+	}
+}
+
+func TestHueExportHandler_EmptyTableReturnsHeader(t *testing.T) {
+	res := serveExport(t, &fakeHueExportService{}, "/api/hue-are-you/export", "")
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+	if body := strings.TrimSpace(res.Body.String()); body != "record_id,name,created_at,word,color,position,order_mode,order_seed,quality_flags,word_set_id,palette_version" {
+		t.Fatalf("expected header only, got %q", body)
+	}
+}
+
+func TestHueExportHandler_InvalidRequest(t *testing.T) {
+	cases := []struct {
+		target string
+		accept string
+		status int
+	}{
+		{"/api/hue-are-you/export?format=xlsx", "", http.StatusBadRequest},
+		{"/api/hue-are-you/export?format=csv&layout=tall", "", http.StatusBadRequest},
+		{"/api/hue-are-you/export", "application/vnd.ms-excel", http.StatusNotAcceptable},
+	}
+	for _, tc := range cases {
+		if res := serveExport(t, &fakeHueExportService{}, tc.target, tc.accept); res.Code != tc.status {
+			t.Fatalf("%s (%s): expected %d, got %d", tc.target, tc.accept, tc.status, res.Code)
+		}
+	}
+}
+
+func TestHueExportHandler_ErrorBeforeFirstRow(t *testing.T) {
+	res := serveExport(t, &fakeHueExportService{err: errors.New("boom")}, "/api/hue-are-you/export", "")
+
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", res.Code)
+	}
+}
+
+func TestHueExportHandler_ErrorMidStreamAborts(t *testing.T) {
+	svc := &fakeHueExportService{records: buildExportRecords(t), err: errors.New("boom")}
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler panic, got %v", recovered)
+		}
+	}()
+	serveExport(t, svc, "/api/hue-are-you/export", "")
+}
+
+func TestHueExportHandler_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/hue-are-you/export", nil)
+	res := httptest.NewRecorder()
+
+	NewHueExportHandler(&fakeHueExportService{}).ServeHTTP(res, req)
+
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", res.Code)
+	}
+}
+
+// fakeHueExportService は records を流し終えた後に err を返す。records が空なら書き込み前のエラーになる。
+type fakeHueExportService struct {
+	records        []domain.HueRecord
+	words          []domain.HueWord
+	err            error
+	beganWithWords bool
+}
+
+func (f *fakeHueExportService) Export(_ context.Context, begin func([]domain.HueWord) error, write func(domain.HueRecord) error) error {
+	if begin != nil {
+		f.beganWithWords = true
+		if err := begin(f.words); err != nil {
+			return err
+		}
+	}
+	for _, record := range f.records {
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func serveExport(t *testing.T, svc HueExportService, target, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	res := httptest.NewRecorder()
+	NewHueExportHandler(svc).ServeHTTP(res, req)
+	return res
+}
+
+// exportWordSetID は buildExportRecords で alice が答えた単語セット。
+var exportWordSetID = uuid.MustParse("6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f")
+
+func buildExportRecords(t *testing.T) []domain.HueRecord {
+	t.Helper()
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var records []domain.HueRecord
+	for i, raw := range []struct {
+		name    string
+		choices map[string]string
+	}{
+		{"alice", map[string]string{"夜": "黒", "海": "青"}},
+		{"bob", map[string]string{"夜": "紫"}},
+	} {
+		choices, err := domain.NewHueChoices(raw.choices)
+		if err != nil {
+			t.Fatalf("choices error: %v", err)
+		}
+		record, err := domain.NewHueRecordFromPersistence(uuid.New(), buildName(t, raw.name), choices, uuid.Nil, createdAt.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+		records = append(records, record)
+	}
+	// alice はセッションで 海, 夜 の順に答えた。
+	order, err := domain.NewWordOrderFromPersistence(domain.WordOrderShuffle, 42, []domain.HueWord{"海", "夜"})
+	if err != nil {
+		t.Fatalf("order error: %v", err)
+	}
+	records[0] = records[0].PresentedIn(order).AnsweredFrom(exportWordSetID).ColoredWith(3)
+	// bob は 1 語だけ一瞬で答えた。
+	records[1] = records[1].FlaggedAs(domain.HueQualityTooFast)
+	return records
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+// HueSessionService は Hue テストのセッションを進めるユースケース境界。userID が uuid.Nil なら匿名で始める。
+// Finish の challenge は HueSaveService.IssueChallenge で発行したトークンで、同じ Idempotency-Key の再送には保存済みの回答を返して replayed を true にする。
+type HueSessionService interface {
+	Start(ctx context.Context, userID uuid.UUID) (domain.HueSession, error)
+	Answer(ctx context.Context, sessionID uuid.UUID, word, color string, latency time.Duration) (domain.HueSession, error)
+	Finish(ctx context.Context, sessionID uuid.UUID, name domain.Name, key domain.IdempotencyKey, challenge string) (record domain.HueRecord, replayed bool, err error)
+	Funnel(ctx context.Context) (domain.HueSessionFunnel, error)
+}
+
+// HueSessionStartHandler は /api/hue-are-you/sessions/start でセッションを始め、出題順の単語を返す。
+// AuthMiddleware.Optional でログイン済みと分かっていれば、そのユーザーのセッションにする。
+type HueSessionStartHandler struct {
+	service HueSessionService
+}
+
+func NewHueSessionStartHandler(service HueSessionService) *HueSessionStartHandler {
+	return &HueSessionStartHandler{service: service}
+}
+
+func (h *HueSessionStartHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *HueSessionStartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	userID := uuid.Nil
+	if user, ok := UserFromContext(r.Context()); ok {
+		userID = user.ID()
+	}
+
+	session, err := h.service.Start(r.Context(), userID)
+	if err != nil {
+		handleHueSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(api.NewHueSessionResponse(session))
+}
+
+// HueSessionAnswerHandler は /api/hue-are-you/sessions/answer で 1 語への回答を受け付ける。
+type HueSessionAnswerHandler struct {
+	service HueSessionService
+}
+
+func NewHueSessionAnswerHandler(service HueSessionService) *HueSessionAnswerHandler {
+	return &HueSessionAnswerHandler{service: service}
+}
+
+func (h *HueSessionAnswerHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *HueSessionAnswerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req api.HueAnswerRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondInvalidJSON(w)
+		return
+	}
+
+	id, err := req.TargetID()
+	if err != nil {
+		respondInvalidField(w, "session_id")
+		return
+	}
+
+	session, err := h.service.Answer(r.Context(), id, req.Word, req.Color, req.Latency())
+	if err != nil {
+		handleHueSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewHueSessionResponse(session))
+}
+
+// HueSessionFinishHandler は /api/hue-are-you/sessions/finish でセッションを終了し、回答を保存する。
+// save-result と同じくチャレンジを求め、Idempotency-Key ヘッダーが付いていれば、同じキーの再送には保存済みの回答で 200 を返す。
+type HueSessionFinishHandler struct {
+	service HueSessionService
+}
+
+func NewHueSessionFinishHandler(service HueSessionService) *HueSessionFinishHandler {
+	return &HueSessionFinishHandler{service: service}
+}
+
+func (h *HueSessionFinishHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *HueSessionFinishHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req api.FinishHueSessionRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondInvalidJSON(w)
+		return
+	}
+
+	key, err := idempotencyKeyFrom(r)
+	if err != nil {
+		respondInvalidField(w, "idempotency_key")
+		return
+	}
+
+	id, name, err := req.ToDomain()
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidHueSession) {
+			respondInvalidField(w, "session_id")
+		} else {
+			respondInvalidField(w, "name")
+		}
+		return
+	}
+
+	record, replayed, err := h.service.Finish(r.Context(), id, name, key, req.Challenge)
+	if err != nil {
+		handleHueSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if replayed {
+		w.Header().Set(idempotentReplayedHeader, "true")
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	_ = json.NewEncoder(w).Encode(api.FinishHueSessionResponse{RecordID: record.ID().String()})
+}
+
+// HueSessionFunnelHandler は /api/hue-are-you/sessions/funnel でセッションの進み具合と回答時間の集計を返す。
+type HueSessionFunnelHandler struct {
+	service HueSessionService
+}
+
+func NewHueSessionFunnelHandler(service HueSessionService) *HueSessionFunnelHandler {
+	return &HueSessionFunnelHandler{service: service}
+}
+
+func (h *HueSessionFunnelHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *HueSessionFunnelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	funnel, err := h.service.Funnel(r.Context())
+	if err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewHueSessionFunnelResponse(funnel))
+}
+
+func handleHueSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWordSetNotFound):
+		respondNotFound(w, "word_set")
+	case errors.Is(err, domain.ErrPaletteNotFound):
+		respondNotFound(w, "palette")
+	case errors.Is(err, domain.ErrHueSessionNotFound):
+		respondNotFound(w, "session_id")
+	case errors.Is(err, domain.ErrHueSessionClosed):
+		respondConflict(w, "session_id", "session is already finished or abandoned")
+	case errors.Is(err, domain.ErrInvalidLatency):
+		respondInvalidField(w, "latency_ms")
+	case errors.Is(err, domain.ErrInvalidChoice):
+		respondInvalidField(w, "choice")
+	case errors.Is(err, domain.ErrInvalidHueChallenge):
+		respondInvalidField(w, "challenge")
+	case errors.Is(err, domain.ErrHueChallengeUsed):
+		respondConflict(w, "challenge", "challenge has already been used")
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		respondConflict(w, "idempotency_key", "idempotency key was already used for a different submission")
+	default:
+		respondInternalServerError(w)
+	}
+}
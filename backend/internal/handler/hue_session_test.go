@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+func buildHueSession(t *testing.T) domain.HueSession {
+	t.Helper()
+	set, err := domain.NewWordSet("標準", []string{"夜", "海"}, time.Now())
+	if err != nil {
+		t.Fatalf("word set error: %v", err)
+	}
+	order, err := domain.NewWordOrder(domain.WordOrderLatinSquare, 2, set.Words())
+	if err != nil {
+		t.Fatalf("order error: %v", err)
+	}
+	session, err := domain.NewHueSession(set, buildPalette(t, 2), order, uuid.Nil, time.Now())
+	if err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+	return session
+}
+
+func TestHueSessionStartHandler_ServeHTTP(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	svc := &fakeHueSessionService{session: buildHueSession(t)}
+	req := httptest.NewRequest(http.MethodPost, "/api/hue-are-you/sessions/start", nil)
+	req = req.WithContext(withAuth(req.Context(), domain.LoginSession{}, user))
+	res := httptest.NewRecorder()
+
+	NewHueSessionStartHandler(svc).ServeHTTP(res, req)
+
+	var resp api.HueSessionResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil || res.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d (%v)", res.Code, err)
+	}
+	if svc.userID != user.ID() {
+		t.Fatalf("expected the session to be started for the user, got %v", svc.userID)
+	}
+	if resp.SessionID != svc.session.ID().String() || len(resp.Words) != 2 || resp.Words[0] != "夜" || resp.PaletteVersion != 2 ||
+		resp.WordOrder.Mode != "latin_square" || resp.WordOrder.Seed != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	res = httptest.NewRecorder()
+	NewHueSessionStartHandler(&fakeHueSessionService{err: domain.ErrWordSetNotFound}).ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/hue-are-you/sessions/start", nil))
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without an active word set, got %d", res.Code)
+	}
+}
+
+func TestHueSessionAnswerHandler_ServeHTTP(t *testing.T) {
+	session := buildHueSession(t)
+	send := func(svc *fakeHueSessionService, body string) (*httptest.ResponseRecorder, api.ErrorResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/api/hue-are-you/sessions/answer", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		NewHueSessionAnswerHandler(svc).ServeHTTP(res, req)
+		var apiErr api.ErrorResponse
+		if res.Code >= http.StatusBadRequest {
+			_ = json.NewDecoder(res.Body).Decode(&apiErr)
+		}
+		return res, apiErr
+	}
+
+	svc := &fakeHueSessionService{session: session}
+	body := `{"session_id":"` + session.ID().String() + `","word":"夜","color":"黒","latency_ms":1500}`
+	if res, _ := send(svc, body); res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+	if svc.sessionID != session.ID() || svc.word != "夜" || svc.color != "黒" || svc.latency != 1500*time.Millisecond {
+		t.Fatalf("unexpected call: %+v", svc)
+	}
+
+	cases := []struct {
+		name   string
+		err    error
+		body   string
+		status int
+		field  string
+	}{
+		{"bad session id", nil, `{"session_id":"x","word":"夜","color":"黒"}`, http.StatusBadRequest, "session_id"},
+		{"unknown session", domain.ErrHueSessionNotFound, body, http.StatusNotFound, "session_id"},
+		{"closed session", domain.ErrHueSessionClosed, body, http.StatusConflict, "session_id"},
+		{"bad latency", domain.ErrInvalidLatency, body, http.StatusBadRequest, "latency_ms"},
+		{"unknown color", domain.ErrInvalidChoice, body, http.StatusBadRequest, "choice"},
+	}
+	for _, tc := range cases {
+		res, apiErr := send(&fakeHueSessionService{err: tc.err}, tc.body)
+		if res.Code != tc.status || apiErr.Field != tc.field {
+			t.Fatalf("%s: expected %d on %s, got %d %+v", tc.name, tc.status, tc.field, res.Code, apiErr)
+		}
+	}
+}
+
+func TestHueSessionFinishHandler_ServeHTTP(t *testing.T) {
+	session := buildHueSession(t)
+	record, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "black"})
+	svc := &fakeHueSessionService{record: record}
+	finish := func(body string, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/hue-are-you/sessions/finish", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		res := httptest.NewRecorder()
+		NewHueSessionFinishHandler(svc).ServeHTTP(res, req)
+		return res
+	}
+	body := `{"session_id":"` + session.ID().String() + `","name":" Tester ","challenge":"token"}`
+
+	res := finish(body, "key-1")
+	var resp api.FinishHueSessionResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil || res.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d (%v)", res.Code, err)
+	}
+	if resp.RecordID != record.ID().String() || svc.name.String() != "Tester" || svc.key != "key-1" || svc.challenge != "token" {
+		t.Fatalf("unexpected response %+v for name %q, key %q, challenge %q", resp, svc.name.String(), svc.key, svc.challenge)
+	}
+
+	svc.replayed = true
+	res = finish(body, "key-1")
+	if res.Code != http.StatusOK || res.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Fatalf("expected 200 with the replayed header, got %d %v", res.Code, res.Header())
+	}
+	svc.replayed = false
+
+	if res := finish(`{"session_id":"`+session.ID().String()+`","name":" ","challenge":"token"}`, ""); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a blank name, got %d", res.Code)
+	}
+	if res := finish(body, " "); res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a blank idempotency key, got %d", res.Code)
+	}
+
+	cases := []struct {
+		err    error
+		status int
+		field  string
+	}{
+		{domain.ErrInvalidHueChallenge, http.StatusBadRequest, "challenge"},
+		{domain.ErrHueChallengeUsed, http.StatusConflict, "challenge"},
+		{domain.ErrIdempotencyKeyReused, http.StatusConflict, "idempotency_key"},
+		{domain.ErrHueSessionClosed, http.StatusConflict, "session_id"},
+	}
+	for _, tc := range cases {
+		svc.err = tc.err
+		res := finish(body, "")
+		var apiErr api.ErrorResponse
+		_ = json.NewDecoder(res.Body).Decode(&apiErr)
+		if res.Code != tc.status || apiErr.Field != tc.field {
+			t.Fatalf("%v: expected %d on %s, got %d %+v", tc.err, tc.status, tc.field, res.Code, apiErr)
+		}
+	}
+}
+
+func TestHueSessionFunnelHandler_ServeHTTP(t *testing.T) {
+	funnel := domain.NewHueSessionFunnel(
+		map[domain.HueSessionStatus]int{domain.HueSessionFinished: 1, domain.HueSessionAbandoned: 1},
+		map[int]int{2: 1, 1: 1},
+		[]domain.HueWordTiming{domain.NewHueWordTiming("夜", 2, 1500*time.Millisecond)},
+	)
+	res := httptest.NewRecorder()
+	NewHueSessionFunnelHandler(&fakeHueSessionService{funnel: funnel}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/hue-are-you/sessions/funnel", nil))
+
+	var resp api.HueSessionFunnelResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil || res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%v)", res.Code, err)
+	}
+	if resp.Started != 2 || resp.Abandoned != 1 || len(resp.Reached) != 2 || resp.Reached[0] != 2 || resp.Words[0].MedianLatencyMS != 1500 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+type fakeHueSessionService struct {
+	session   domain.HueSession
+	record    domain.HueRecord
+	funnel    domain.HueSessionFunnel
+	err       error
+	userID    uuid.UUID
+	sessionID uuid.UUID
+	word      string
+	color     string
+	latency   time.Duration
+	name      domain.Name
+	key       domain.IdempotencyKey
+	challenge string
+	replayed  bool
+}
+
+func (f *fakeHueSessionService) Start(_ context.Context, userID uuid.UUID) (domain.HueSession, error) {
+	f.userID = userID
+	return f.session, f.err
+}
+
+func (f *fakeHueSessionService) Answer(_ context.Context, sessionID uuid.UUID, word, color string, latency time.Duration) (domain.HueSession, error) {
+	f.sessionID, f.word, f.color, f.latency = sessionID, word, color, latency
+	return f.session, f.err
+}
+
+func (f *fakeHueSessionService) Finish(_ context.Context, sessionID uuid.UUID, name domain.Name, key domain.IdempotencyKey, challenge string) (domain.HueRecord, bool, error) {
+	f.sessionID, f.name, f.key, f.challenge = sessionID, name, key, challenge
+	return f.record, f.replayed, f.err
+}
+
+func (f *fakeHueSessionService) Funnel(_ context.Context) (domain.HueSessionFunnel, error) {
+	return f.funnel, f.err
+}
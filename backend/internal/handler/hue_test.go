@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"backend/internal/domain"
 	"backend/pkg/api"
@@ -23,8 +24,8 @@ func TestHueSaveHandler_ServeHTTP_Success(t *testing.T) {
 
 	reqBody := marshal(t, api.SaveResultRequest{
 		HueRecordPayload: api.HueRecordPayload{
-			record.Name().String(),
-			record.ChoiceMap(),
+			Name:   record.Name().String(),
+			Choice: record.ChoiceMap(),
 		},
 	})
 	req := httptest.NewRequest(http.MethodPost, "/api/hue/save", strings.NewReader(reqBody))
@@ -39,6 +40,133 @@ func TestHueSaveHandler_ServeHTTP_Success(t *testing.T) {
 	if !svc.called {
 		t.Fatalf("service.SaveResult not called")
 	}
+	if _, ok := svc.record.UserID(); ok {
+		t.Fatalf("anonymous submission should not be linked to a user")
+	}
+}
+
+func TestHueSaveHandler_AttachesLoggedInUser(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	svc := &fakeHueSaveService{}
+
+	reqBody := marshal(t, api.SaveResultRequest{
+		HueRecordPayload: api.HueRecordPayload{Name: "Tester", Choice: map[string]string{"夜": "黒"}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/hue/save", strings.NewReader(reqBody))
+	req = req.WithContext(withAuth(req.Context(), domain.LoginSession{}, user))
+	res := httptest.NewRecorder()
+
+	NewHueSaveHandler(svc).ServeHTTP(res, req)
+
+	if res.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", res.Code)
+	}
+	if owner, ok := svc.record.UserID(); !ok || owner != user.ID() {
+		t.Fatalf("expected record to be linked to the logged-in user")
+	}
+}
+
+func TestHueSaveHandler_PaletteVersion(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		svcErr error
+	}{
+		{"negative version", `{"name":"Tester","choice":{"夜":"黒"},"palette_version":-1}`, nil},
+		{"unknown version", `{"name":"Tester","choice":{"夜":"黒"},"palette_version":9}`, domain.ErrPaletteNotFound},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/hue/save", strings.NewReader(tc.body))
+		res := httptest.NewRecorder()
+		NewHueSaveHandler(&fakeHueSaveService{err: tc.svcErr}).ServeHTTP(res, req)
+
+		var apiErr api.ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || res.Code != http.StatusBadRequest || apiErr.Field != "palette_version" {
+			t.Fatalf("%s: expected 400 on palette_version, got %d %+v", tc.name, res.Code, apiErr)
+		}
+	}
+
+	svc := &fakeHueSaveService{}
+	req := httptest.NewRequest(http.MethodPost, "/api/hue/save", strings.NewReader(`{"name":"Tester","choice":{"夜":"黒"},"palette_version":2}`))
+	NewHueSaveHandler(svc).ServeHTTP(httptest.NewRecorder(), req)
+	if version, ok := svc.record.PaletteVersion(); !ok || version != 2 {
+		t.Fatalf("expected the claimed palette version to reach the service, got %d (%v)", version, ok)
+	}
+}
+
+func TestHueSaveHandler_IdempotencyKey(t *testing.T) {
+	body := `{"name":"Tester","choice":{"夜":"黒"},"challenge":"token"}`
+	send := func(svc *fakeHueSaveService, keys ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/hue/save", strings.NewReader(body))
+		for _, key := range keys {
+			req.Header.Add("Idempotency-Key", key)
+		}
+		res := httptest.NewRecorder()
+		NewHueSaveHandler(svc).ServeHTTP(res, req)
+		return res
+	}
+
+	svc := &fakeHueSaveService{}
+	if res := send(svc, " key-1 "); res.Code != http.StatusCreated || res.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("expected 201 for a new submission, got %d", res.Code)
+	}
+	if key, ok := svc.record.IdempotencyKey(); !ok || key.String() != "key-1" || svc.challenge != "token" {
+		t.Fatalf("expected the key and challenge to reach the service, got %q %q", key, svc.challenge)
+	}
+
+	if res := send(&fakeHueSaveService{replayed: true}, "key-1"); res.Code != http.StatusOK || res.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected 200 with Idempotent-Replayed for a replay, got %d %v", res.Code, res.Header())
+	}
+
+	for _, keys := range [][]string{{" "}, {"キー"}, {"a", "b"}} {
+		res := send(&fakeHueSaveService{}, keys...)
+		var apiErr api.ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || res.Code != http.StatusBadRequest || apiErr.Field != "idempotency_key" {
+			t.Fatalf("%q: expected 400 on idempotency_key, got %d %+v", keys, res.Code, apiErr)
+		}
+	}
+}
+
+func TestHueSaveHandler_SubmissionErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		field  string
+	}{
+		{"invalid challenge", domain.ErrInvalidHueChallenge, http.StatusBadRequest, "challenge"},
+		{"used challenge", domain.ErrHueChallengeUsed, http.StatusConflict, "challenge"},
+		{"reused key", domain.ErrIdempotencyKeyReused, http.StatusConflict, "idempotency_key"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/hue/save", strings.NewReader(`{"name":"Tester","choice":{"夜":"黒"}}`))
+		res := httptest.NewRecorder()
+		NewHueSaveHandler(&fakeHueSaveService{err: tc.err}).ServeHTTP(res, req)
+
+		var apiErr api.ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || res.Code != tc.status || apiErr.Field != tc.field {
+			t.Fatalf("%s: expected %d on %s, got %d %+v", tc.name, tc.status, tc.field, res.Code, apiErr)
+		}
+	}
+}
+
+func TestHueChallengeHandler_ServeHTTP(t *testing.T) {
+	res := httptest.NewRecorder()
+	NewHueChallengeHandler(&fakeHueSaveService{}).ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/hue-are-you/challenge", nil))
+
+	var resp api.HueChallengeResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil || res.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d (%v)", res.Code, err)
+	}
+	if resp.Challenge == "" || !resp.ExpiresAt.After(time.Now()) || res.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("unexpected response: %+v %v", resp, res.Header())
+	}
+
+	res = httptest.NewRecorder()
+	NewHueChallengeHandler(&fakeHueSaveService{}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/hue-are-you/challenge", nil))
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", res.Code)
+	}
 }
 
 func TestHueSaveHandler_InvalidJSON(t *testing.T) {
@@ -71,8 +199,8 @@ func TestHueSaveHandler_InternalError(t *testing.T) {
 	record := buildHueRecord(t)
 	req := httptest.NewRequest(http.MethodPost, "/api/hue/save", strings.NewReader(marshal(t, api.SaveResultRequest{
 		HueRecordPayload: api.HueRecordPayload{
-			record.Name().String(),
-			record.ChoiceMap(),
+			Name:   record.Name().String(),
+			Choice: record.ChoiceMap(),
 		},
 	})))
 	res := httptest.NewRecorder()
@@ -97,15 +225,13 @@ func TestHueSaveHandler_MethodNotAllowed(t *testing.T) {
 }
 
 func TestHueGetHandler_ServeHTTP_Success(t *testing.T) {
-	token, _ := domain.NewLoginSessionToken()
-	session, _ := domain.NewSessionData(uuid.New(), token)
 	record := buildHueRecord(t)
-	svc := &fakeHueGetService{records: []domain.HueRecord{record}}
+	svc := &fakeHueGetService{records: []domain.HueRecord{record, buildHueRecord(t)}}
 	handler := NewHueGetHandler(svc)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/hue/get", strings.NewReader(marshal(t, api.GetDataRequest{
-		Session:   api.NewSessionPayload(session),
-		DataRange: []int{0, 0},
+	req := httptest.NewRequest(http.MethodPost, "/api/hue/get", strings.NewReader(marshal(t, api.GetDataRequest{
+		Limit:        1,
+		IncludeTotal: true,
 	})))
 	res := httptest.NewRecorder()
 
@@ -127,23 +253,45 @@ func TestHueGetHandler_ServeHTTP_Success(t *testing.T) {
 	if len(resp.Records) != 1 || resp.Records[0].Name != record.Name().String() {
 		t.Fatalf("unexpected response payload")
 	}
+
+	if resp.NextCursor == "" {
+		t.Fatalf("expected next_cursor when more records remain")
+	}
+	if cursor, err := domain.ParseRecordCursor(resp.NextCursor); err != nil || cursor.ID() != record.ID() {
+		t.Fatalf("expected next_cursor to point after the last returned record, got %v", err)
+	}
+	if resp.Total == nil || *resp.Total != 2 {
+		t.Fatalf("expected total 2, got %v", resp.Total)
+	}
+	if svc.request.Limit() != 1 || !svc.request.IncludeTotal() {
+		t.Fatalf("unexpected page request passed to service")
+	}
 }
 
-func TestHueGetHandler_InvalidJSON(t *testing.T) {
-	handler := NewHueGetHandler(&fakeHueGetService{})
-	req := httptest.NewRequest(http.MethodGet, "/api/hue/get", strings.NewReader(`{"session":1}`))
+func TestHueGetHandler_Filter(t *testing.T) {
+	svc := &fakeHueGetService{}
+	body := `{"filter":{"created_from":"2025-01-01T00:00:00Z","name":"ali","name_match":"prefix","choices":{"孤独":"紫"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/hue/get", strings.NewReader(body))
 	res := httptest.NewRecorder()
 
-	handler.ServeHTTP(res, req)
+	NewHueGetHandler(svc).ServeHTTP(res, req)
 
-	if res.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", res.Code)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+
+	filter := svc.request.Filter()
+	if !filter.CreatedFrom().Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !filter.CreatedTo().IsZero() {
+		t.Fatalf("unexpected created_at window: %v - %v", filter.CreatedFrom(), filter.CreatedTo())
+	}
+	if filter.Name() != "ali" || filter.NameMatch() != domain.NameMatchPrefix || filter.Choices()["孤独"] != "紫" {
+		t.Fatalf("unexpected filter passed to service: %+v", filter)
 	}
 }
 
-func TestHueGetHandler_InvalidDomain(t *testing.T) {
+func TestHueGetHandler_InvalidJSON(t *testing.T) {
 	handler := NewHueGetHandler(&fakeHueGetService{})
-	req := httptest.NewRequest(http.MethodGet, "/api/hue/get", strings.NewReader(`{"session":{"user_id":"bad","token":""},"data-range":[0,0]}`))
+	req := httptest.NewRequest(http.MethodPost, "/api/hue/get", strings.NewReader(`{"data-range":1}`))
 	res := httptest.NewRecorder()
 
 	handler.ServeHTTP(res, req)
@@ -153,14 +301,39 @@ func TestHueGetHandler_InvalidDomain(t *testing.T) {
 	}
 }
 
+func TestHueGetHandler_InvalidDomain(t *testing.T) {
+	cases := []struct {
+		body  string
+		field string
+	}{
+		{`{"limit":1000}`, "limit"},
+		{`{"limit":-1}`, "limit"},
+		{`{"cursor":"not-a-cursor"}`, "cursor"},
+		{`{"filter":{"name_match":"regex","name":"a"}}`, "filter"},
+		{`{"filter":{"choices":{"孤独":" "}}}`, "filter"},
+		{`{"filter":{"created_from":"2025-02-01T00:00:00Z","created_to":"2025-01-01T00:00:00Z"}}`, "filter"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/hue/get", strings.NewReader(tc.body))
+		res := httptest.NewRecorder()
+
+		NewHueGetHandler(&fakeHueGetService{}).ServeHTTP(res, req)
+
+		if res.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", tc.body, res.Code)
+		}
+		var apiErr api.ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || apiErr.Field != tc.field {
+			t.Fatalf("%s: expected field %s, got %+v (%v)", tc.body, tc.field, apiErr, err)
+		}
+	}
+}
+
 func TestHueGetHandler_Unauthorized(t *testing.T) {
 	svc := &fakeHueGetService{err: domain.ErrExpiredToken}
 	handler := NewHueGetHandler(svc)
-	token, _ := domain.NewLoginSessionToken()
-	session, _ := domain.NewSessionData(uuid.New(), token)
-	req := httptest.NewRequest(http.MethodGet, "/api/hue/get", strings.NewReader(marshal(t, api.GetDataRequest{
-		Session:   api.NewSessionPayload(session),
-		DataRange: []int{0, 0},
+	req := httptest.NewRequest(http.MethodPost, "/api/hue/get", strings.NewReader(marshal(t, api.GetDataRequest{
+		Limit: 1,
 	})))
 	res := httptest.NewRecorder()
 
@@ -174,11 +347,8 @@ func TestHueGetHandler_Unauthorized(t *testing.T) {
 func TestHueGetHandler_InternalError(t *testing.T) {
 	svc := &fakeHueGetService{err: errors.New("boom")}
 	handler := NewHueGetHandler(svc)
-	token, _ := domain.NewLoginSessionToken()
-	session, _ := domain.NewSessionData(uuid.New(), token)
-	req := httptest.NewRequest(http.MethodGet, "/api/hue/get", strings.NewReader(marshal(t, api.GetDataRequest{
-		Session:   api.NewSessionPayload(session),
-		DataRange: []int{0, 0},
+	req := httptest.NewRequest(http.MethodPost, "/api/hue/get", strings.NewReader(marshal(t, api.GetDataRequest{
+		Limit: 1,
 	})))
 	res := httptest.NewRecorder()
 
@@ -191,7 +361,152 @@ func TestHueGetHandler_InternalError(t *testing.T) {
 
 func TestHueGetHandler_MethodNotAllowed(t *testing.T) {
 	handler := NewHueGetHandler(&fakeHueGetService{})
-	req := httptest.NewRequest(http.MethodPost, "/api/hue/get", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/hue/get", nil)
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", res.Code)
+	}
+}
+
+func TestHueMyResultsHandler_ServeHTTP_Success(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	record := buildHueRecord(t)
+	svc := &fakeHueGetService{records: []domain.HueRecord{record, buildHueRecord(t)}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/hue-are-you/my-results?limit=1", nil)
+	req = req.WithContext(withAuth(req.Context(), domain.LoginSession{}, user))
+	res := httptest.NewRecorder()
+
+	NewHueMyResultsHandler(svc).ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+	if svc.userID != user.ID() {
+		t.Fatalf("expected results to be requested for the logged-in user")
+	}
+
+	var resp api.MyResultsResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != record.ID().String() || resp.NextCursor == "" {
+		t.Fatalf("unexpected response payload: %+v", resp)
+	}
+}
+
+func TestHueMyResultsHandler_InvalidQuery(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	cases := []struct {
+		query string
+		field string
+	}{
+		{"limit=abc", "limit"},
+		{"limit=1000", "limit"},
+		{"cursor=not-a-cursor", "cursor"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/hue-are-you/my-results?"+tc.query, nil)
+		req = req.WithContext(withAuth(req.Context(), domain.LoginSession{}, user))
+		res := httptest.NewRecorder()
+
+		NewHueMyResultsHandler(&fakeHueGetService{}).ServeHTTP(res, req)
+
+		var apiErr api.ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || res.Code != http.StatusBadRequest || apiErr.Field != tc.field {
+			t.Fatalf("%s: expected 400 on %s, got %d %+v", tc.query, tc.field, res.Code, apiErr)
+		}
+	}
+}
+
+func TestHueGetHandler_UnknownFilterColor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/hue-are-you/get-data", strings.NewReader(`{"filter":{"choices":{"孤独":"金"}}}`))
+	res := httptest.NewRecorder()
+
+	NewHueGetHandler(&fakeHueGetService{err: domain.ErrInvalidFilter}).ServeHTTP(res, req)
+
+	var apiErr api.ErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || res.Code != http.StatusBadRequest || apiErr.Field != "filter" {
+		t.Fatalf("expected 400 on filter, got %d %+v", res.Code, apiErr)
+	}
+}
+
+func TestHueStatsHandler_ServeHTTP_Success(t *testing.T) {
+	palette := buildPalette(t, 2)
+	stats, err := domain.NewHueWordStats("夜", palette.ColorIDs(), map[domain.HueColor]int{"black": 3, "blue": 1})
+	if err != nil {
+		t.Fatalf("stats error: %v", err)
+	}
+	service := &fakeHueGetService{palette: palette, stats: []domain.HueWordStats{stats}}
+	handler := NewHueStatsHandler(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/hue-are-you/stats?palette=2", nil)
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+
+	var resp api.HueStatsResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if service.version != 2 || resp.PaletteVersion != 2 {
+		t.Fatalf("expected palette 2 to be requested and reported, got %d / %d", service.version, resp.PaletteVersion)
+	}
+	if len(resp.Colors) != 3 || resp.Colors[0] != "black" {
+		t.Fatalf("unexpected colors: %v", resp.Colors)
+	}
+	if len(resp.Words) != 1 {
+		t.Fatalf("expected 1 word, got %d", len(resp.Words))
+	}
+	word := resp.Words[0]
+	if word.Word != "夜" || word.Total != 4 || word.Mode != "black" {
+		t.Fatalf("unexpected word stats: %+v", word)
+	}
+	if len(word.Distribution) != 3 || word.Distribution["blue"] != 1 || word.Distribution["white"] != 0 {
+		t.Fatalf("unexpected distribution: %v", word.Distribution)
+	}
+	if word.Agreement <= 0 || word.Agreement >= 1 {
+		t.Fatalf("unexpected agreement: %f", word.Agreement)
+	}
+}
+
+func TestHueStatsHandler_Palette(t *testing.T) {
+	res := httptest.NewRecorder()
+	NewHueStatsHandler(&fakeHueGetService{}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/hue-are-you/stats?palette=x", nil))
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a malformed palette, got %d", res.Code)
+	}
+
+	res = httptest.NewRecorder()
+	NewHueStatsHandler(&fakeHueGetService{err: domain.ErrPaletteNotFound}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/hue-are-you/stats?palette=9", nil))
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown palette, got %d", res.Code)
+	}
+}
+
+func TestHueStatsHandler_InternalError(t *testing.T) {
+	handler := NewHueStatsHandler(&fakeHueGetService{err: errors.New("boom")})
+	req := httptest.NewRequest(http.MethodGet, "/api/hue-are-you/stats", nil)
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", res.Code)
+	}
+}
+
+func TestHueStatsHandler_MethodNotAllowed(t *testing.T) {
+	handler := NewHueStatsHandler(&fakeHueGetService{})
+	req := httptest.NewRequest(http.MethodPost, "/api/hue-are-you/stats", nil)
 	res := httptest.NewRecorder()
 
 	handler.ServeHTTP(res, req)
@@ -202,30 +517,65 @@ func TestHueGetHandler_MethodNotAllowed(t *testing.T) {
 }
 
 type fakeHueSaveService struct {
-	record domain.HueRecord
-	err    error
-	called bool
+	record    domain.HueRecord
+	challenge string
+	replayed  bool
+	err       error
+	called    bool
+}
+
+func (f *fakeHueSaveService) IssueChallenge(_ context.Context) (domain.HueChallenge, string) {
+	signer, _ := domain.NewHueChallengeSigner([]byte(strings.Repeat("k", domain.MinHueChallengeSecretBytes)))
+	return signer.Issue(time.Now())
 }
 
-func (f *fakeHueSaveService) SaveResult(_ context.Context, record domain.HueRecord) error {
+func (f *fakeHueSaveService) SaveResult(_ context.Context, record domain.HueRecord, challenge string) (domain.HueRecord, bool, error) {
 	f.called = true
 	f.record = record
+	f.challenge = challenge
 	if f.err != nil {
-		return f.err
+		return domain.HueRecord{}, false, f.err
 	}
-	return nil
+	return record, f.replayed, nil
 }
 
 type fakeHueGetService struct {
 	records []domain.HueRecord
+	palette domain.Palette
+	version int
+	stats   []domain.HueWordStats
 	err     error
+	request domain.RecordPageRequest
+	userID  uuid.UUID
+}
+
+func (f *fakeHueGetService) GetData(_ context.Context, req domain.RecordPageRequest) (domain.RecordPage, error) {
+	f.request = req
+	if f.err != nil {
+		return domain.RecordPage{}, f.err
+	}
+	total := -1
+	if req.IncludeTotal() {
+		total = len(f.records)
+	}
+	return domain.NewRecordPage(f.records[:min(len(f.records), req.Limit()+1)], req.Limit(), total), nil
+}
+
+func (f *fakeHueGetService) GetUserResults(ctx context.Context, userID uuid.UUID, after domain.RecordCursor, limit int) (domain.RecordPage, error) {
+	f.userID = userID
+	req, err := domain.NewRecordPageRequest(domain.RecordFilter{}.ForUser(userID), after, limit, false)
+	if err != nil {
+		return domain.RecordPage{}, err
+	}
+	return f.GetData(ctx, req)
 }
 
-func (f *fakeHueGetService) GetData(_ context.Context, _ domain.SessionData, _ domain.RecordRange) ([]domain.HueRecord, error) {
+func (f *fakeHueGetService) GetStats(_ context.Context, version int) (domain.Palette, []domain.HueWordStats, error) {
+	f.version = version
 	if f.err != nil {
-		return nil, f.err
+		return domain.Palette{}, nil, f.err
 	}
-	return f.records, nil
+	return f.palette, f.stats, nil
 }
 
 func marshal(t *testing.T, v interface{}) string {
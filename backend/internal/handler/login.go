@@ -12,20 +12,26 @@ import (
 
 // LoginService は認証処理を司るユースケース層の抽象インターフェース。
 type LoginService interface {
-	Login(ctx context.Context, credential domain.AdminCredential) (domain.SessionData, domain.UserRole, error)
+	Login(ctx context.Context, credential domain.AdminCredential, clientIP string) (domain.SessionData, domain.UserRole, error)
 }
 
 // LoginHandler は /api/login の HTTP リクエストを処理する。
 type LoginHandler struct {
-	service LoginService
+	service   LoginService
+	clientIPs *ClientIPResolver
 }
 
-// NewLoginHandler はログイン用ハンドラを初期化する。
-func NewLoginHandler(service LoginService) *LoginHandler {
-	return &LoginHandler{service: service}
+// NewLoginHandler はログイン用ハンドラを初期化する。ログインの失敗は clientIPs で決めた接続元ごとに数える。
+// clientIPs が nil なら転送ヘッダーは読まず、直接の接続元のアドレスを使う。
+func NewLoginHandler(service LoginService, clientIPs *ClientIPResolver) *LoginHandler {
+	return &LoginHandler{service: service, clientIPs: clientIPs}
 }
 
 // ServeHTTP は JSON リクエストをデコードし、ドメインに変換してサービスへ委譲する。
+func (h *LoginHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
 func (h *LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		respondMethodNotAllowed(w, http.MethodPost)
@@ -46,11 +52,24 @@ func (h *LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session, role, err := h.service.Login(r.Context(), credential)
+	clientIP := remoteIP(r)
+	if h.clientIPs != nil {
+		clientIP = h.clientIPs.ClientIP(r)
+	}
+
+	session, role, err := h.service.Login(r.Context(), credential, clientIP)
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidCredential) {
+		var rateErr *domain.RateLimitError
+		switch {
+		case errors.As(err, &rateErr):
+			respondRateLimited(w, "credential", rateErr.RetryAfter())
+		case errors.Is(err, domain.ErrInvalidCredential):
 			respondInvalidCredential(w, http.StatusUnauthorized)
-		} else {
+		case errors.Is(err, domain.ErrUserDisabled):
+			respondAccountDisabled(w)
+		case errors.Is(err, domain.ErrEmailNotVerified):
+			respondEmailNotVerified(w)
+		default:
 			respondInternalServerError(w)
 		}
 		return
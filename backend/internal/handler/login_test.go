@@ -6,11 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"backend/internal/domain"
 	"backend/pkg/api"
@@ -23,7 +23,7 @@ func TestLoginHandler_ServeHTTP_Success(t *testing.T) {
 	}
 
 	svc := &fakeLoginService{token: token, userID: uuid.New(), role: domain.UserRoleAdmin}
-	handler := NewLoginHandler(svc)
+	handler := NewLoginHandler(svc, nil)
 
 	name := "admin"
 	password := "secret"
@@ -63,8 +63,8 @@ func TestLoginHandler_ServeHTTP_Success(t *testing.T) {
 		t.Fatalf("service.Login was not called")
 	}
 
-	if got := bcrypt.CompareHashAndPassword([]byte(svc.credential.HashedPassword()), []byte(password)); got != nil {
-		t.Fatalf("expected hashed password 'secret', got %s", got)
+	if got := svc.credential.Password(); got != password {
+		t.Fatalf("expected password %q, got %q", password, got)
 	}
 
 	if got := svc.credential.Name().String(); got != "admin" {
@@ -72,9 +72,28 @@ func TestLoginHandler_ServeHTTP_Success(t *testing.T) {
 	}
 }
 
+func TestLoginHandler_ServeHTTP_PassesClientIP(t *testing.T) {
+	clientIPs, err := NewClientIPResolver([]string{"192.0.2.1"})
+	if err != nil {
+		t.Fatalf("resolver error: %v", err)
+	}
+	svc := &fakeLoginService{userID: uuid.New()}
+	handler := NewLoginHandler(svc, clientIPs)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"name":"admin","password":"secret"}`))
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if svc.clientIP != "203.0.113.9" {
+		t.Fatalf("expected client IP from trusted proxy, got %q", svc.clientIP)
+	}
+}
+
 func TestLoginHandler_ServeHTTP_InvalidJSON(t *testing.T) {
 	svc := &fakeLoginService{}
-	handler := NewLoginHandler(svc)
+	handler := NewLoginHandler(svc, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"name":1}`))
 	res := httptest.NewRecorder()
@@ -92,7 +111,7 @@ func TestLoginHandler_ServeHTTP_InvalidJSON(t *testing.T) {
 
 func TestLoginHandler_ServeHTTP_InvalidDomainInput(t *testing.T) {
 	svc := &fakeLoginService{}
-	handler := NewLoginHandler(svc)
+	handler := NewLoginHandler(svc, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"name":" ","hashed_password":"secret"}`))
 	res := httptest.NewRecorder()
@@ -110,7 +129,7 @@ func TestLoginHandler_ServeHTTP_InvalidDomainInput(t *testing.T) {
 
 func TestLoginHandler_ServeHTTP_InvalidCredential(t *testing.T) {
 	svc := &fakeLoginService{err: domain.ErrInvalidCredential}
-	handler := NewLoginHandler(svc)
+	handler := NewLoginHandler(svc, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"name":"admin","password":"secret"}`))
 	res := httptest.NewRecorder()
@@ -122,9 +141,54 @@ func TestLoginHandler_ServeHTTP_InvalidCredential(t *testing.T) {
 	}
 }
 
+func TestLoginHandler_ServeHTTP_EmailNotVerified(t *testing.T) {
+	svc := &fakeLoginService{err: domain.ErrEmailNotVerified}
+	handler := NewLoginHandler(svc, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"name":"alice","password":"secret"}`))
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", res.Code)
+	}
+	var apiErr api.ErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || apiErr.Field != "email" {
+		t.Fatalf("expected field email, got %+v (%v)", apiErr, err)
+	}
+}
+
+func TestLoginHandler_ServeHTTP_RateLimited(t *testing.T) {
+	svc := &fakeLoginService{err: domain.NewRateLimitError(1500 * time.Millisecond)}
+	handler := NewLoginHandler(svc, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"name":"admin","password":"secret"}`))
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", res.Code)
+	}
+
+	if got := res.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("expected Retry-After 2, got %q", got)
+	}
+
+	var resp api.ErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Error != "rate_limited" {
+		t.Fatalf("expected cause rate_limited, got %s", resp.Error)
+	}
+}
+
 func TestLoginHandler_ServeHTTP_InternalError(t *testing.T) {
 	svc := &fakeLoginService{err: errors.New("boom")}
-	handler := NewLoginHandler(svc)
+	handler := NewLoginHandler(svc, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"name":"admin","password":"secret"}`))
 	res := httptest.NewRecorder()
@@ -138,7 +202,7 @@ func TestLoginHandler_ServeHTTP_InternalError(t *testing.T) {
 
 func TestLoginHandler_ServeHTTP_MethodNotAllowed(t *testing.T) {
 	svc := &fakeLoginService{}
-	handler := NewLoginHandler(svc)
+	handler := NewLoginHandler(svc, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/login", nil)
 	res := httptest.NewRecorder()
@@ -156,6 +220,7 @@ func TestLoginHandler_ServeHTTP_MethodNotAllowed(t *testing.T) {
 
 type fakeLoginService struct {
 	credential domain.AdminCredential
+	clientIP   string
 	userID     uuid.UUID
 	token      domain.LoginSessionToken
 	role       domain.UserRole
@@ -163,9 +228,10 @@ type fakeLoginService struct {
 	called     bool
 }
 
-func (f *fakeLoginService) Login(_ context.Context, credential domain.AdminCredential) (domain.SessionData, domain.UserRole, error) {
+func (f *fakeLoginService) Login(_ context.Context, credential domain.AdminCredential, clientIP string) (domain.SessionData, domain.UserRole, error) {
 	f.called = true
 	f.credential = credential
+	f.clientIP = clientIP
 	if f.err != nil {
 		return domain.SessionData{}, "", f.err
 	}
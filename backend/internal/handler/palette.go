@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+// PaletteService はパレットの取得と管理のユースケース境界。version が 0 なら有効な版を指す。actorID は操作した管理者。
+type PaletteService interface {
+	Palette(ctx context.Context, version int) (domain.Palette, error)
+	ListPalettes(ctx context.Context) ([]domain.Palette, error)
+	CreatePalette(ctx context.Context, actorID uuid.UUID, colors []domain.PaletteColor, activate bool) (domain.Palette, error)
+	ActivatePalette(ctx context.Context, actorID uuid.UUID, version int) (domain.Palette, error)
+}
+
+// PaletteHandler は /api/hue-are-you/palette で有効な版を返す。?version= を付けるとその版を返す。認証は不要。
+type PaletteHandler struct {
+	service PaletteService
+}
+
+func NewPaletteHandler(service PaletteService) *PaletteHandler {
+	return &PaletteHandler{service: service}
+}
+
+func (h *PaletteHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *PaletteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	version, ok := queryInt(r.URL.Query(), "version")
+	if !ok || version < 0 {
+		respondInvalidField(w, "version")
+		return
+	}
+
+	palette, err := h.service.Palette(r.Context(), version)
+	respondPalette(w, http.StatusOK, palette, err)
+}
+
+// AdminPaletteListHandler は /api/admin/palettes ですべての版を版の昇順で返す。
+type AdminPaletteListHandler struct {
+	service PaletteService
+}
+
+func NewAdminPaletteListHandler(service PaletteService) *AdminPaletteListHandler {
+	return &AdminPaletteListHandler{service: service}
+}
+
+func (h *AdminPaletteListHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *AdminPaletteListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	palettes, err := h.service.ListPalettes(r.Context())
+	if err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewListPalettesResponse(palettes))
+}
+
+// AdminPaletteActionHandler は /api/admin/palettes/<action> で新しい版を作る、または版を有効にする。
+type AdminPaletteActionHandler struct {
+	service PaletteService
+	action  domain.AdminAction
+}
+
+func NewAdminPaletteActionHandler(service PaletteService, action domain.AdminAction) *AdminPaletteActionHandler {
+	return &AdminPaletteActionHandler{service: service, action: action}
+}
+
+func (h *AdminPaletteActionHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *AdminPaletteActionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	actor, ok := UserFromContext(r.Context())
+	if !ok {
+		respondUnauthorizedSession(w)
+		return
+	}
+
+	var req api.AdminPaletteRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondInvalidJSON(w)
+		return
+	}
+
+	ctx := r.Context()
+	switch h.action {
+	case domain.AdminActionCreatePalette:
+		colors, err := req.PaletteColors()
+		if err != nil {
+			respondInvalidField(w, "colors")
+			return
+		}
+		palette, err := h.service.CreatePalette(ctx, actor.ID(), colors, req.Activate)
+		respondPalette(w, http.StatusCreated, palette, err)
+	case domain.AdminActionActivatePalette:
+		if req.Version <= 0 {
+			respondInvalidField(w, "version")
+			return
+		}
+		palette, err := h.service.ActivatePalette(ctx, actor.ID(), req.Version)
+		respondPalette(w, http.StatusOK, palette, err)
+	default:
+		respondInternalServerError(w)
+	}
+}
+
+func respondPalette(w http.ResponseWriter, status int, palette domain.Palette, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPalette):
+			respondInvalidField(w, "colors")
+		case errors.Is(err, domain.ErrPaletteNotFound):
+			respondNotFound(w, "version")
+		default:
+			respondInternalServerError(w)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(api.NewPalettePayload(palette))
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+// buildPalette は黒・青・白の 3 色で version の版を作る。
+func buildPalette(t *testing.T, version int) domain.Palette {
+	t.Helper()
+
+	var colors []domain.PaletteColor
+	for _, c := range []struct{ id, ja, hex string }{{"black", "黒", "#444444"}, {"blue", "青", "#4f86e2"}, {"white", "白", "#f5f5f5"}} {
+		rgb, _ := domain.ParseRGB(c.hex)
+		color, err := domain.NewPaletteColor(c.id, map[string]string{"ja": c.ja}, rgb, domain.LabFromRGB(rgb))
+		if err != nil {
+			t.Fatalf("palette color: %v", err)
+		}
+		colors = append(colors, color)
+	}
+
+	palette, err := domain.NewPalette(version, colors, time.Now())
+	if err != nil {
+		t.Fatalf("palette: %v", err)
+	}
+	return palette.Activate()
+}
+
+func TestPaletteHandler_ServeHTTP(t *testing.T) {
+	svc := &fakePaletteService{palette: buildPalette(t, 3)}
+	req := httptest.NewRequest(http.MethodGet, "/api/hue-are-you/palette?version=3", nil)
+	res := httptest.NewRecorder()
+
+	NewPaletteHandler(svc).ServeHTTP(res, req)
+
+	var resp api.PalettePayload
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil || res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%v)", res.Code, err)
+	}
+	if svc.version != 3 || resp.Version != 3 || len(resp.Colors) != 3 {
+		t.Fatalf("unexpected palette: %+v", resp)
+	}
+	if first := resp.Colors[0]; first.ID != "black" || first.Names["ja"] != "黒" || first.RGB != "#444444" || first.Lab == nil {
+		t.Fatalf("unexpected color: %+v", first)
+	}
+
+	res = httptest.NewRecorder()
+	NewPaletteHandler(&fakePaletteService{err: domain.ErrPaletteNotFound}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/hue-are-you/palette", nil))
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without an active palette, got %d", res.Code)
+	}
+}
+
+func TestAdminPaletteActionHandler_Create(t *testing.T) {
+	admin := buildUser(t, domain.UserRoleAdmin)
+	send := func(svc *fakePaletteService, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/palettes/create", strings.NewReader(body))
+		req = req.WithContext(withAuth(req.Context(), domain.LoginSession{}, admin))
+		res := httptest.NewRecorder()
+		NewAdminPaletteActionHandler(svc, domain.AdminActionCreatePalette).ServeHTTP(res, req)
+		return res
+	}
+
+	svc := &fakePaletteService{palette: buildPalette(t, 2)}
+	res := send(svc, `{"colors":[{"id":"black","names":{"ja":"黒"},"rgb":"#444444"},{"id":"gold","names":{"ja":"金","en":"Gold"},"rgb":"#d4af37","lab":{"l":73.4,"a":1.2,"b":60.1}}],"activate":true}`)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", res.Code)
+	}
+	if svc.actorID != admin.ID() || !svc.activate || len(svc.colors) != 2 {
+		t.Fatalf("unexpected call: actor=%v activate=%v colors=%d", svc.actorID, svc.activate, len(svc.colors))
+	}
+	if lab := svc.colors[0].Lab(); lab != domain.LabFromRGB(svc.colors[0].RGB()) {
+		t.Fatalf("expected Lab to be derived from RGB when omitted, got %+v", lab)
+	}
+	if lab := svc.colors[1].Lab(); lab.L != 73.4 {
+		t.Fatalf("expected the given Lab to be kept, got %+v", lab)
+	}
+
+	res = send(&fakePaletteService{}, `{"colors":[{"id":"Black","names":{"ja":"黒"},"rgb":"#444444"}]}`)
+	var apiErr api.ErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || res.Code != http.StatusBadRequest || apiErr.Field != "colors" {
+		t.Fatalf("expected 400 on colors, got %d %+v", res.Code, apiErr)
+	}
+}
+
+type fakePaletteService struct {
+	palette  domain.Palette
+	err      error
+	version  int
+	actorID  uuid.UUID
+	colors   []domain.PaletteColor
+	activate bool
+}
+
+func (f *fakePaletteService) Palette(_ context.Context, version int) (domain.Palette, error) {
+	f.version = version
+	return f.palette, f.err
+}
+
+func (f *fakePaletteService) ListPalettes(_ context.Context) ([]domain.Palette, error) {
+	return []domain.Palette{f.palette}, f.err
+}
+
+func (f *fakePaletteService) CreatePalette(_ context.Context, actorID uuid.UUID, colors []domain.PaletteColor, activate bool) (domain.Palette, error) {
+	f.actorID, f.colors, f.activate = actorID, colors, activate
+	return f.palette, f.err
+}
+
+func (f *fakePaletteService) ActivatePalette(_ context.Context, actorID uuid.UUID, version int) (domain.Palette, error) {
+	f.actorID, f.version = actorID, version
+	return f.palette, f.err
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+)
+
+// PasswordService はパスワード変更と再設定のユースケース境界。
+type PasswordService interface {
+	ChangePassword(ctx context.Context, session domain.LoginSession, user domain.User, oldPassword, newPassword string) error
+	RequestPasswordReset(ctx context.Context, email domain.Email) error
+	ConfirmPasswordReset(ctx context.Context, token, newPassword string) error
+}
+
+// PasswordChangeHandler は /api/password/change でログイン中のユーザーのパスワードを変更する。AuthMiddleware の内側で使う。
+type PasswordChangeHandler struct {
+	service PasswordService
+}
+
+func NewPasswordChangeHandler(service PasswordService) *PasswordChangeHandler {
+	return &PasswordChangeHandler{service: service}
+}
+
+func (h *PasswordChangeHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *PasswordChangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	session, ok := LoginSessionFromContext(r.Context())
+	user, userOK := UserFromContext(r.Context())
+	if !ok || !userOK {
+		respondUnauthorizedSession(w)
+		return
+	}
+
+	var req api.ChangePasswordRequest
+	if !decodeStrictJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.service.ChangePassword(r.Context(), session, user, req.OldPassword, req.NewPassword); err != nil {
+		var rateErr *domain.RateLimitError
+		switch {
+		case errors.As(err, &rateErr):
+			respondRateLimited(w, "old_password", rateErr.RetryAfter())
+		case errors.Is(err, domain.ErrInvalidCredential):
+			// 401 はクライアントにログアウトさせてしまうため、セッションは有効なまま 403 で返す。
+			respondAPIError(w, http.StatusForbidden, causeInvalidCredential, "old_password", "current password mismatch")
+		case errors.Is(err, domain.ErrInvalidPassword):
+			respondInvalidPassword(w, "new_password", err)
+		default:
+			respondInternalServerError(w)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PasswordResetRequestHandler は /api/password/reset-request で再設定メールを送る。
+// 登録の有無を漏らさないため、アドレスの形式が正しければ常に 202 を返す。
+type PasswordResetRequestHandler struct {
+	service PasswordService
+}
+
+func NewPasswordResetRequestHandler(service PasswordService) *PasswordResetRequestHandler {
+	return &PasswordResetRequestHandler{service: service}
+}
+
+func (h *PasswordResetRequestHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *PasswordResetRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req api.PasswordResetRequest
+	if !decodeStrictJSON(w, r, &req) {
+		return
+	}
+
+	email, err := req.ToDomain()
+	if err != nil {
+		respondInvalidField(w, "email")
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(r.Context(), email); err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// PasswordResetConfirmHandler は /api/password/reset-confirm でメールのトークンを使ってパスワードを設定し直す。
+type PasswordResetConfirmHandler struct {
+	service PasswordService
+}
+
+func NewPasswordResetConfirmHandler(service PasswordService) *PasswordResetConfirmHandler {
+	return &PasswordResetConfirmHandler{service: service}
+}
+
+func (h *PasswordResetConfirmHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *PasswordResetConfirmHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req api.PasswordResetConfirmRequest
+	if !decodeStrictJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.service.ConfirmPasswordReset(r.Context(), req.Token, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidResetToken):
+			respondInvalidField(w, "token")
+		case errors.Is(err, domain.ErrInvalidPassword):
+			respondInvalidPassword(w, "new_password", err)
+		default:
+			respondInternalServerError(w)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeStrictJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		respondInvalidJSON(w)
+		return false
+	}
+	return true
+}
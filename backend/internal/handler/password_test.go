@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+)
+
+type fakePasswordService struct {
+	err          error
+	changedFor   domain.User
+	oldPassword  string
+	newPassword  string
+	requestedFor domain.Email
+	token        string
+}
+
+func (f *fakePasswordService) ChangePassword(_ context.Context, _ domain.LoginSession, user domain.User, oldPassword, newPassword string) error {
+	f.changedFor, f.oldPassword, f.newPassword = user, oldPassword, newPassword
+	return f.err
+}
+
+func (f *fakePasswordService) RequestPasswordReset(_ context.Context, email domain.Email) error {
+	f.requestedFor = email
+	return f.err
+}
+
+func (f *fakePasswordService) ConfirmPasswordReset(_ context.Context, token, newPassword string) error {
+	f.token, f.newPassword = token, newPassword
+	return f.err
+}
+
+func TestPasswordChangeHandler_ServeHTTP(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	session := buildLoginSession(t, user.ID())
+
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		field  string
+	}{
+		{name: "success", status: http.StatusNoContent},
+		{name: "wrong old password", err: domain.ErrInvalidCredential, status: http.StatusForbidden, field: "old_password"},
+		{name: "blank new password", err: domain.ErrInvalidPassword, status: http.StatusBadRequest, field: "new_password"},
+		{name: "internal", err: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		svc := &fakePasswordService{err: tc.err}
+		req := httptest.NewRequest(http.MethodPost, "/api/password/change", strings.NewReader(`{"old_password":"old","new_password":"new"}`))
+		req = req.WithContext(withAuth(req.Context(), session, user))
+		res := httptest.NewRecorder()
+
+		NewPasswordChangeHandler(svc).ServeHTTP(res, req)
+
+		if res.Code != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.status, res.Code)
+		}
+		if svc.changedFor.ID() != user.ID() || svc.oldPassword != "old" || svc.newPassword != "new" {
+			t.Fatalf("%s: unexpected service call", tc.name)
+		}
+		if tc.field != "" {
+			var apiErr api.ErrorResponse
+			if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || apiErr.Field != tc.field {
+				t.Fatalf("%s: expected field %q, got %+v (%v)", tc.name, tc.field, apiErr, err)
+			}
+		}
+	}
+}
+
+func TestPasswordChangeHandler_Unauthenticated(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/password/change", strings.NewReader(`{}`))
+	res := httptest.NewRecorder()
+
+	NewPasswordChangeHandler(&fakePasswordService{}).ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", res.Code)
+	}
+}
+
+func TestPasswordResetRequestHandler_ServeHTTP(t *testing.T) {
+	svc := &fakePasswordService{}
+	req := httptest.NewRequest(http.MethodPost, "/api/password/reset-request", strings.NewReader(`{"email":"alice@example.com"}`))
+	res := httptest.NewRecorder()
+
+	NewPasswordResetRequestHandler(svc).ServeHTTP(res, req)
+
+	if res.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", res.Code)
+	}
+	if svc.requestedFor.String() != "alice@example.com" {
+		t.Fatalf("unexpected email: %s", svc.requestedFor)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/password/reset-request", strings.NewReader(`{"email":"not-an-email"}`))
+	res = httptest.NewRecorder()
+	NewPasswordResetRequestHandler(svc).ServeHTTP(res, req)
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid email, got %d", res.Code)
+	}
+}
+
+func TestPasswordResetConfirmHandler_ServeHTTP(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		field  string
+	}{
+		{name: "success", status: http.StatusNoContent},
+		{name: "invalid token", err: domain.ErrInvalidResetToken, status: http.StatusBadRequest, field: "token"},
+		{name: "blank password", err: domain.ErrInvalidPassword, status: http.StatusBadRequest, field: "new_password"},
+		{name: "internal", err: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		svc := &fakePasswordService{err: tc.err}
+		req := httptest.NewRequest(http.MethodPost, "/api/password/reset-confirm", strings.NewReader(`{"token":"abc","new_password":"new"}`))
+		res := httptest.NewRecorder()
+
+		NewPasswordResetConfirmHandler(svc).ServeHTTP(res, req)
+
+		if res.Code != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.status, res.Code)
+		}
+		if svc.token != "abc" || svc.newPassword != "new" {
+			t.Fatalf("%s: unexpected service call", tc.name)
+		}
+		if tc.field != "" {
+			var apiErr api.ErrorResponse
+			if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || apiErr.Field != tc.field {
+				t.Fatalf("%s: expected field %q, got %+v (%v)", tc.name, tc.field, apiErr, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// queryInt はクエリパラメーター key を整数として読む。未指定なら 0 を返し、整数でなければ false。
+func queryInt(query url.Values, key string) (int, bool) {
+	raw := query.Get(key)
+	if raw == "" {
+		return 0, true
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
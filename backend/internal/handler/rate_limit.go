@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"backend/internal/ratelimit"
+)
+
+// RateLimiter はキー単位で試行可否を判定する境界。
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (ratelimit.Decision, error)
+}
+
+// RateLimitByIP はクライアントの IP ごとに next への到達回数を制限し、超過時は 429 と Retry-After を返す。
+// ストアの障害時はリクエストを通し、ログだけを残す。
+func (c *ClientIPResolver) RateLimitByIP(limiter RateLimiter, next http.Handler) http.Handler {
+	return withMethods(next, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		decision, err := limiter.Allow(r.Context(), "ip:"+c.ClientIP(r))
+		if err != nil {
+			log.Print("rate limit error: ", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !decision.Allowed {
+			respondRateLimited(w, "ip", decision.RetryAfter)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}))
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/ratelimit"
+)
+
+func TestRateLimitByIP(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Burst: 1, Per: time.Minute})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := (&ClientIPResolver{}).RateLimitByIP(limiter, next)
+
+	first := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/login", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	handler.ServeHTTP(first, req)
+	if first.Code != http.StatusNoContent {
+		t.Fatalf("expected first request to pass, got %d", first.Code)
+	}
+
+	second := httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/login", nil)
+	req.RemoteAddr = "192.0.2.1:5678"
+	handler.ServeHTTP(second, req)
+	if second.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for same ip, got %d", second.Code)
+	}
+	if second.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
+	}
+
+	other := httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/login", nil)
+	req.RemoteAddr = "192.0.2.2:1234"
+	handler.ServeHTTP(other, req)
+	if other.Code != http.StatusNoContent {
+		t.Fatalf("expected other ip to pass, got %d", other.Code)
+	}
+}
+
+func TestRateLimitByIP_StoreErrorFailsOpen(t *testing.T) {
+	handler := (&ClientIPResolver{}).RateLimitByIP(failingLimiter{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/login", nil))
+
+	if res.Code != http.StatusNoContent {
+		t.Fatalf("expected request to pass on store error, got %d", res.Code)
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, context.DeadlineExceeded
+}
+
+func TestRateLimitByIP_BehindTrustedProxy(t *testing.T) {
+	ips, err := NewClientIPResolver([]string{"10.0.0.2"})
+	if err != nil {
+		t.Fatalf("resolver error: %v", err)
+	}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Burst: 1, Per: time.Minute})
+	handler := ips.RateLimitByIP(limiter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// プロキシ経由の別々のクライアントは枠を共有しない。
+	for _, client := range []string{"192.0.2.1", "192.0.2.2"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/login", nil)
+		req.RemoteAddr = "10.0.0.2:1234"
+		req.Header.Set("X-Forwarded-For", client)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		if res.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected to pass, got %d", client, res.Code)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+// SessionService はログアウトとトークン更新のユースケース境界。
+type SessionService interface {
+	Logout(ctx context.Context, session domain.LoginSession) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	Refresh(ctx context.Context, session domain.LoginSession) (domain.SessionData, error)
+}
+
+// LogoutHandler は /api/logout で現在のセッションを終了する。AuthMiddleware の内側で使う。
+type LogoutHandler struct {
+	service SessionService
+}
+
+func NewLogoutHandler(service SessionService) *LogoutHandler {
+	return &LogoutHandler{service: service}
+}
+
+func (h *LogoutHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *LogoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	session, ok := LoginSessionFromContext(r.Context())
+	if !ok {
+		respondUnauthorizedSession(w)
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), session); err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAllHandler は /api/logout-all で呼び出し元ユーザーの全セッションを終了する。
+type LogoutAllHandler struct {
+	service SessionService
+}
+
+func NewLogoutAllHandler(service SessionService) *LogoutAllHandler {
+	return &LogoutAllHandler{service: service}
+}
+
+func (h *LogoutAllHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *LogoutAllHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		respondUnauthorizedSession(w)
+		return
+	}
+
+	if err := h.service.LogoutAll(r.Context(), user.ID()); err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RefreshHandler は /api/token/refresh でトークンをローテーションし、新しい有効期限を返す。
+type RefreshHandler struct {
+	service SessionService
+}
+
+func NewRefreshHandler(service SessionService) *RefreshHandler {
+	return &RefreshHandler{service: service}
+}
+
+func (h *RefreshHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *RefreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	session, ok := LoginSessionFromContext(r.Context())
+	user, userOK := UserFromContext(r.Context())
+	if !ok || !userOK {
+		respondUnauthorizedSession(w)
+		return
+	}
+
+	refreshed, err := h.service.Refresh(r.Context(), session)
+	if err != nil {
+		// 並行した更新で既に消費されたセッションは、期限切れと同じく 401 にする。
+		if errors.Is(err, domain.ErrInvalidSessionToken) {
+			respondUnauthorizedSession(w)
+			return
+		}
+		respondInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewRefreshResponse(refreshed, user.Role()))
+}
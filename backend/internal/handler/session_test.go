@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+func TestLogoutHandler_ServeHTTP_Success(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	session := buildLoginSession(t, user.ID())
+	svc := &fakeSessionService{}
+
+	req := authenticatedRequest(http.MethodPost, "/api/logout", session, user)
+	res := httptest.NewRecorder()
+
+	NewLogoutHandler(svc).ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", res.Code)
+	}
+
+	if svc.loggedOut != session.ID() {
+		t.Fatalf("expected session %s to be logged out, got %s", session.ID(), svc.loggedOut)
+	}
+}
+
+func TestLogoutHandler_ServeHTTP_Unauthenticated(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+	res := httptest.NewRecorder()
+
+	NewLogoutHandler(&fakeSessionService{}).ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", res.Code)
+	}
+}
+
+func TestLogoutHandler_ServeHTTP_InternalError(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	req := authenticatedRequest(http.MethodPost, "/api/logout", buildLoginSession(t, user.ID()), user)
+	res := httptest.NewRecorder()
+
+	NewLogoutHandler(&fakeSessionService{err: errors.New("boom")}).ServeHTTP(res, req)
+
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", res.Code)
+	}
+}
+
+func TestLogoutAllHandler_ServeHTTP_Success(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	svc := &fakeSessionService{}
+
+	req := authenticatedRequest(http.MethodPost, "/api/logout-all", buildLoginSession(t, user.ID()), user)
+	res := httptest.NewRecorder()
+
+	NewLogoutAllHandler(svc).ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", res.Code)
+	}
+
+	if svc.loggedOutUser != user.ID() {
+		t.Fatalf("expected user %s to be logged out everywhere, got %s", user.ID(), svc.loggedOutUser)
+	}
+}
+
+func TestLogoutAllHandler_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/logout-all", nil)
+	res := httptest.NewRecorder()
+
+	NewLogoutAllHandler(&fakeSessionService{}).ServeHTTP(res, req)
+
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", res.Code)
+	}
+}
+
+func TestRefreshHandler_ServeHTTP_Success(t *testing.T) {
+	user := buildUser(t, domain.UserRoleAdmin)
+	session := buildLoginSession(t, user.ID())
+	refreshed := buildIssuedSessionData(t, user.ID())
+	svc := &fakeSessionService{refreshed: refreshed}
+
+	req := authenticatedRequest(http.MethodPost, "/api/token/refresh", session, user)
+	res := httptest.NewRecorder()
+
+	NewRefreshHandler(svc).ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+
+	var resp api.RefreshResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Token != refreshed.Token().String() {
+		t.Fatalf("expected rotated token %s, got %s", refreshed.Token(), resp.Token)
+	}
+
+	if !resp.ExpiresAt.Equal(refreshed.ExpiresAt()) {
+		t.Fatalf("expected expires_at %v, got %v", refreshed.ExpiresAt(), resp.ExpiresAt)
+	}
+
+	if resp.Role != domain.UserRoleAdmin.String() {
+		t.Fatalf("expected role admin, got %s", resp.Role)
+	}
+
+	if svc.refreshedFrom != session.ID() {
+		t.Fatalf("expected refresh from session %s, got %s", session.ID(), svc.refreshedFrom)
+	}
+}
+
+func TestRefreshHandler_ServeHTTP_InternalError(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	req := authenticatedRequest(http.MethodPost, "/api/token/refresh", buildLoginSession(t, user.ID()), user)
+	res := httptest.NewRecorder()
+
+	NewRefreshHandler(&fakeSessionService{err: errors.New("boom")}).ServeHTTP(res, req)
+
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", res.Code)
+	}
+}
+
+func TestRefreshHandler_ServeHTTP_ConsumedSession(t *testing.T) {
+	user := buildUser(t, domain.UserRoleUser)
+	req := authenticatedRequest(http.MethodPost, "/api/token/refresh", buildLoginSession(t, user.ID()), user)
+	res := httptest.NewRecorder()
+
+	NewRefreshHandler(&fakeSessionService{err: domain.ErrInvalidSessionToken}).ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", res.Code)
+	}
+}
+
+type fakeSessionService struct {
+	refreshed     domain.SessionData
+	err           error
+	loggedOut     uuid.UUID
+	loggedOutUser uuid.UUID
+	refreshedFrom uuid.UUID
+}
+
+func (f *fakeSessionService) Logout(_ context.Context, session domain.LoginSession) error {
+	f.loggedOut = session.ID()
+	return f.err
+}
+
+func (f *fakeSessionService) LogoutAll(_ context.Context, userID uuid.UUID) error {
+	f.loggedOutUser = userID
+	return f.err
+}
+
+func (f *fakeSessionService) Refresh(_ context.Context, session domain.LoginSession) (domain.SessionData, error) {
+	f.refreshedFrom = session.ID()
+	if f.err != nil {
+		return domain.SessionData{}, f.err
+	}
+	return f.refreshed, nil
+}
+
+func authenticatedRequest(method, target string, session domain.LoginSession, user domain.User) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	return req.WithContext(withAuth(req.Context(), session, user))
+}
+
+func buildLoginSession(t *testing.T, userID uuid.UUID) domain.LoginSession {
+	t.Helper()
+	token, err := domain.NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	session, err := domain.NewLoginSession(userID, token, time.Now(), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+	return session
+}
+
+func buildIssuedSessionData(t *testing.T, userID uuid.UUID) domain.SessionData {
+	t.Helper()
+	token, err := domain.NewLoginSessionToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	session, err := domain.NewLoginSession(userID, token, time.Now(), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+	data, err := domain.NewIssuedSessionData(session, token)
+	if err != nil {
+		t.Fatalf("session data error: %v", err)
+	}
+	return data
+}
@@ -24,6 +24,10 @@ func NewSignInHandler(service SignInService) *SignInHandler {
 	return &SignInHandler{service: service}
 }
 
+func (h *SignInHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
 func (h *SignInHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		respondMethodNotAllowed(w, http.MethodPost)
@@ -40,17 +44,35 @@ func (h *SignInHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	credential, err := req.ToDomain()
 	if err != nil {
-		respondInvalidField(w, "credential")
+		switch {
+		case errors.Is(err, domain.ErrEmptyName):
+			respondInvalidField(w, "name")
+		case errors.Is(err, domain.ErrInvalidEmail):
+			respondInvalidField(w, "email")
+		case errors.Is(err, domain.ErrInvalidPassword):
+			respondInvalidPassword(w, "password", err)
+		default:
+			respondInvalidField(w, "credential")
+		}
 		return
 	}
 
 	session, role, err := h.service.SignIn(r.Context(), credential)
+	if errors.Is(err, domain.ErrEmailNotVerified) {
+		// 登録は済んでいるが、確認が終わるまでセッションは発行しない。
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(api.NewSignInPendingResponse(role))
+		return
+	}
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrDuplicateUsername):
 			respondDuplicateField(w, "username")
 		case errors.Is(err, domain.ErrDuplicateEmail):
 			respondDuplicateField(w, "email")
+		case errors.Is(err, domain.ErrInvalidPassword):
+			respondInvalidPassword(w, "password", err)
 		case errors.Is(err, domain.ErrInvalidCredential):
 			respondInvalidCredential(w, http.StatusConflict)
 		default:
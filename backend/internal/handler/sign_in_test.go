@@ -60,6 +60,25 @@ func TestSignInHandler_ServeHTTP_Success(t *testing.T) {
 	}
 }
 
+func TestSignInHandler_ServeHTTP_VerificationRequired(t *testing.T) {
+	svc := &fakeSignInService{err: domain.ErrEmailNotVerified}
+	handler := NewSignInHandler(svc)
+
+	body := `{"name":"alice","email":"alice@example.com","password":"secret"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/sign-in", strings.NewReader(body))
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d", res.Code)
+	}
+	var resp api.SignInPendingResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil || !resp.VerificationRequired {
+		t.Fatalf("expected verification_required, got %+v (%v)", resp, err)
+	}
+}
+
 func TestSignInHandler_InvalidJSON(t *testing.T) {
 	svc := &fakeSignInService{}
 	handler := NewSignInHandler(svc)
@@ -96,6 +115,48 @@ func TestSignInHandler_InvalidDomain(t *testing.T) {
 	}
 }
 
+func TestSignInHandler_FieldErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		cause  string
+		field  string
+		called bool
+	}{
+		{name: "blank name", body: `{"name":" ","email":"alice@example.com","password":"secret"}`, cause: "invalid_request", field: "name"},
+		{name: "invalid email", body: `{"name":"alice","email":"bad","password":"secret"}`, cause: "invalid_request", field: "email"},
+		{name: "blank password", body: `{"name":"alice","email":"alice@example.com","password":"  "}`, cause: "password_required", field: "password"},
+		{
+			name:   "policy violation",
+			body:   `{"name":"alice","email":"alice@example.com","password":"short"}`,
+			err:    domain.DefaultPasswordPolicy().Check("short"),
+			cause:  "password_too_short",
+			field:  "password",
+			called: true,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := &fakeSignInService{err: tc.err}
+		req := httptest.NewRequest(http.MethodPost, "/api/sign-in", strings.NewReader(tc.body))
+		res := httptest.NewRecorder()
+
+		NewSignInHandler(svc).ServeHTTP(res, req)
+
+		if res.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", tc.name, res.Code)
+		}
+		var apiErr api.ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || apiErr.Error != tc.cause || apiErr.Field != tc.field {
+			t.Fatalf("%s: expected %s on %s, got %+v (%v)", tc.name, tc.cause, tc.field, apiErr, err)
+		}
+		if svc.called != tc.called {
+			t.Fatalf("%s: expected service called=%v", tc.name, tc.called)
+		}
+	}
+}
+
 func TestSignInHandler_Conflict(t *testing.T) {
 	svc := &fakeSignInService{err: domain.ErrInvalidCredential}
 	handler := NewSignInHandler(svc)
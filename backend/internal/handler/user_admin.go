@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAuditLogLimit = 100
+	maxAuditLogLimit     = 500
+)
+
+// UserAdminService は管理者によるユーザー管理のユースケース境界。actorID は操作した管理者。
+type UserAdminService interface {
+	ListUsers(ctx context.Context, q domain.UserQuery) ([]domain.User, int, error)
+	ChangeRole(ctx context.Context, actorID, targetID uuid.UUID, role domain.UserRole) (domain.User, error)
+	Disable(ctx context.Context, actorID, targetID uuid.UUID) (domain.User, error)
+	Enable(ctx context.Context, actorID, targetID uuid.UUID) (domain.User, error)
+	RevokeSessions(ctx context.Context, actorID, targetID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, actorID, targetID uuid.UUID) error
+	AuditLog(ctx context.Context, limit int) ([]domain.AdminAuditEntry, error)
+}
+
+// AdminUserListHandler は /api/admin/users でユーザーを検索する。
+// ?q= (ユーザー名・メールアドレスの部分一致)、?role=、?limit=、?offset= で絞り込む。
+type AdminUserListHandler struct {
+	service UserAdminService
+}
+
+func NewAdminUserListHandler(service UserAdminService) *AdminUserListHandler {
+	return &AdminUserListHandler{service: service}
+}
+
+func (h *AdminUserListHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *AdminUserListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, limitOK := queryInt(query, "limit")
+	offset, offsetOK := queryInt(query, "offset")
+	if !limitOK || !offsetOK {
+		respondInvalidField(w, "query")
+		return
+	}
+
+	q, err := domain.NewUserQuery(query.Get("q"), query.Get("role"), limit, offset)
+	if err != nil {
+		respondInvalidField(w, "query")
+		return
+	}
+
+	users, total, err := h.service.ListUsers(r.Context(), q)
+	if err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewListUsersResponse(users, total))
+}
+
+// AdminUserActionHandler は /api/admin/users/<action> で 1 人のユーザーに管理操作を行う。
+// ボディは {"user_id": "..."} で、ロール変更のときだけ "role" も渡す。
+type AdminUserActionHandler struct {
+	service UserAdminService
+	action  domain.AdminAction
+}
+
+func NewAdminUserActionHandler(service UserAdminService, action domain.AdminAction) *AdminUserActionHandler {
+	return &AdminUserActionHandler{service: service, action: action}
+}
+
+func (h *AdminUserActionHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *AdminUserActionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	actor, ok := UserFromContext(r.Context())
+	if !ok {
+		respondUnauthorizedSession(w)
+		return
+	}
+
+	var req api.AdminUserActionRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondInvalidJSON(w)
+		return
+	}
+
+	targetID, err := req.TargetID()
+	if err != nil {
+		respondInvalidField(w, "user_id")
+		return
+	}
+
+	ctx := r.Context()
+	switch h.action {
+	case domain.AdminActionChangeRole:
+		role, err := domain.NewUserRole(req.Role)
+		if err != nil {
+			respondInvalidField(w, "role")
+			return
+		}
+		user, err := h.service.ChangeRole(ctx, actor.ID(), targetID, role)
+		respondAdminUser(w, user, err)
+	case domain.AdminActionDisableUser:
+		user, err := h.service.Disable(ctx, actor.ID(), targetID)
+		respondAdminUser(w, user, err)
+	case domain.AdminActionEnableUser:
+		user, err := h.service.Enable(ctx, actor.ID(), targetID)
+		respondAdminUser(w, user, err)
+	case domain.AdminActionRevokeSessions:
+		revoked, err := h.service.RevokeSessions(ctx, actor.ID(), targetID)
+		if err != nil {
+			handleUserAdminError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(api.RevokeSessionsResponse{Revoked: revoked})
+	case domain.AdminActionDeleteUser:
+		if err := h.service.Delete(ctx, actor.ID(), targetID); err != nil {
+			handleUserAdminError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondInternalServerError(w)
+	}
+}
+
+// AdminAuditLogHandler は /api/admin/audit-log で新しい順に監査ログを返す。?limit= で件数を指定する。
+type AdminAuditLogHandler struct {
+	service UserAdminService
+}
+
+func NewAdminAuditLogHandler(service UserAdminService) *AdminAuditLogHandler {
+	return &AdminAuditLogHandler{service: service}
+}
+
+func (h *AdminAuditLogHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *AdminAuditLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	limit, ok := queryInt(r.URL.Query(), "limit")
+	if !ok || limit < 0 || limit > maxAuditLogLimit {
+		respondInvalidField(w, "limit")
+		return
+	}
+	if limit == 0 {
+		limit = defaultAuditLogLimit
+	}
+
+	entries, err := h.service.AuditLog(r.Context(), limit)
+	if err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewAuditLogResponse(entries))
+}
+
+func respondAdminUser(w http.ResponseWriter, user domain.User, err error) {
+	if err != nil {
+		handleUserAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewAdminUserPayload(user))
+}
+
+func handleUserAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		respondNotFound(w, "user_id")
+	case errors.Is(err, domain.ErrSelfAdministration):
+		respondConflict(w, "user_id", "cannot change your own account")
+	case errors.Is(err, domain.ErrInvalidUserRole):
+		respondInvalidField(w, "role")
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		respondConflict(w, "user_id", "email address is not verified")
+	default:
+		respondInternalServerError(w)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/pkg/api"
+
+	"github.com/google/uuid"
+)
+
+// WordSetService は単語セットの取得と管理のユースケース境界。actorID は操作した管理者。
+type WordSetService interface {
+	ActiveWordSet(ctx context.Context) (domain.WordSet, error)
+	ListWordSets(ctx context.Context) ([]domain.WordSet, error)
+	CreateWordSet(ctx context.Context, actorID uuid.UUID, name string, words []string, activate bool) (domain.WordSet, error)
+	UpdateWordSet(ctx context.Context, actorID, id uuid.UUID, name string, words []string) (domain.WordSet, error)
+	ActivateWordSet(ctx context.Context, actorID, id uuid.UUID) (domain.WordSet, error)
+	DeleteWordSet(ctx context.Context, actorID, id uuid.UUID) error
+}
+
+// ActiveWordSetHandler は /api/hue-are-you/words で出題中の単語セットを返す。認証は不要。
+type ActiveWordSetHandler struct {
+	service WordSetService
+}
+
+func NewActiveWordSetHandler(service WordSetService) *ActiveWordSetHandler {
+	return &ActiveWordSetHandler{service: service}
+}
+
+func (h *ActiveWordSetHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *ActiveWordSetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	set, err := h.service.ActiveWordSet(r.Context())
+	respondWordSet(w, set, err)
+}
+
+// AdminWordSetListHandler は /api/admin/word-sets ですべての単語セットを作成順に返す。
+type AdminWordSetListHandler struct {
+	service WordSetService
+}
+
+func NewAdminWordSetListHandler(service WordSetService) *AdminWordSetListHandler {
+	return &AdminWordSetListHandler{service: service}
+}
+
+func (h *AdminWordSetListHandler) AllowedMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *AdminWordSetListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	sets, err := h.service.ListWordSets(r.Context())
+	if err != nil {
+		respondInternalServerError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewListWordSetsResponse(sets))
+}
+
+// AdminWordSetActionHandler は /api/admin/word-sets/<action> で単語セットを作成・更新・有効化・削除する。
+type AdminWordSetActionHandler struct {
+	service WordSetService
+	action  domain.AdminAction
+}
+
+func NewAdminWordSetActionHandler(service WordSetService, action domain.AdminAction) *AdminWordSetActionHandler {
+	return &AdminWordSetActionHandler{service: service, action: action}
+}
+
+func (h *AdminWordSetActionHandler) AllowedMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *AdminWordSetActionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	actor, ok := UserFromContext(r.Context())
+	if !ok {
+		respondUnauthorizedSession(w)
+		return
+	}
+
+	var req api.AdminWordSetRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondInvalidJSON(w)
+		return
+	}
+
+	ctx := r.Context()
+	if h.action == domain.AdminActionCreateWordSet {
+		set, err := h.service.CreateWordSet(ctx, actor.ID(), req.Name, req.Words, req.Activate)
+		if err != nil {
+			handleWordSetError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(api.NewWordSetPayload(set))
+		return
+	}
+
+	id, err := req.TargetID()
+	if err != nil {
+		respondInvalidField(w, "id")
+		return
+	}
+
+	switch h.action {
+	case domain.AdminActionUpdateWordSet:
+		set, err := h.service.UpdateWordSet(ctx, actor.ID(), id, req.Name, req.Words)
+		respondWordSet(w, set, err)
+	case domain.AdminActionActivateWordSet:
+		set, err := h.service.ActivateWordSet(ctx, actor.ID(), id)
+		respondWordSet(w, set, err)
+	case domain.AdminActionDeleteWordSet:
+		if err := h.service.DeleteWordSet(ctx, actor.ID(), id); err != nil {
+			handleWordSetError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondInternalServerError(w)
+	}
+}
+
+func respondWordSet(w http.ResponseWriter, set domain.WordSet, err error) {
+	if err != nil {
+		handleWordSetError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(api.NewWordSetPayload(set))
+}
+
+func handleWordSetError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidWordSet):
+		respondInvalidField(w, "words")
+	case errors.Is(err, domain.ErrWordSetNotFound):
+		respondNotFound(w, "id")
+	case errors.Is(err, domain.ErrWordSetInUse):
+		respondConflict(w, "id", "word set already has responses")
+	case errors.Is(err, domain.ErrWordSetActive):
+		respondConflict(w, "id", "cannot delete the active word set")
+	default:
+		respondInternalServerError(w)
+	}
+}
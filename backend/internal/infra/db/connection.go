@@ -2,19 +2,22 @@ package db
 
 import (
 	"context"
+
 	"github.com/jackc/pgx/v5/pgxpool"
-	"os"
-)
 
-func NewConnection(ctx context.Context) (*pgxpool.Pool, error) {
-	dsn := os.Getenv("DATABASE_URL")
+	"backend/internal/config"
+)
 
-	config, err := pgxpool.ParseConfig(dsn)
+// NewConnection は設定の接続先とプールサイズでコネクションプールを作成する。
+func NewConnection(ctx context.Context, cfg config.Database) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
 	if err != nil {
 		return nil, err
 	}
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
 
-	pool, err := pgxpool.NewWithConfig(ctx, config)
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
 	}
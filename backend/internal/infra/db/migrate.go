@@ -0,0 +1,125 @@
+package db
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"backend/db/migrations"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Migrator は埋め込み済みのマイグレーションを適用する。
+// golang-migrate の pgx ドライバは実行中 pg_advisory_lock を保持するため、複数インスタンスが同時に起動しても直列に適用される。
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// MigrationStatus は適用済みバージョンと埋め込まれているマイグレーションの一覧。
+type MigrationStatus struct {
+	Version   uint
+	Dirty     bool
+	Applied   bool
+	Available []Migration
+}
+
+// Migration は埋め込まれた 1 つのマイグレーション。
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := pgxmigrate.WithInstance(stdlib.OpenDBFromPool(pool), &pgxmigrate.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx5", driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{m: m}, nil
+}
+
+// Up は未適用のマイグレーションを全て適用する。適用済みであればエラーにしない。
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Steps は n>0 なら n 件適用し、n<0 なら |n| 件ロールバックする。
+func (m *Migrator) Steps(n int) error {
+	return ignoreNoChange(m.m.Steps(n))
+}
+
+// Force は dirty 状態を解除し、バージョンを強制的に設定する。マイグレーション自体は実行しない。
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+func (m *Migrator) Status() (MigrationStatus, error) {
+	available, err := AvailableMigrations()
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return MigrationStatus{Available: available}, nil
+	}
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	return MigrationStatus{Version: version, Dirty: dirty, Applied: true, Available: available}, nil
+}
+
+// Close はソースとデータベースドライバを閉じる。プール自体は閉じない。
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.m.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+// AvailableMigrations は埋め込まれた *.up.sql をバージョン順に返す。
+func AvailableMigrations() ([]Migration, error) {
+	names, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Migration, 0, len(names))
+	for _, name := range names {
+		versionPart, rest, ok := strings.Cut(strings.TrimSuffix(name, ".up.sql"), "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseUint(versionPart, 10, 64)
+		if err != nil {
+			continue
+		}
+		result = append(result, Migration{Version: uint(version), Name: rest})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"backend/db/migrations"
+)
+
+func TestAvailableMigrations_ContiguousWithDownFiles(t *testing.T) {
+	available, err := AvailableMigrations()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(available) == 0 {
+		t.Fatalf("expected embedded migrations")
+	}
+
+	for i, m := range available {
+		if m.Version != uint(i+1) {
+			t.Fatalf("expected version %d at index %d, got %d", i+1, i, m.Version)
+		}
+
+		down := fmt.Sprintf("%06d_%s.down.sql", m.Version, m.Name)
+		if _, err := fs.Stat(migrations.FS, down); err != nil {
+			t.Fatalf("missing down migration %s: %v", down, err)
+		}
+	}
+}
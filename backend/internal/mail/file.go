@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileSender は送信する代わりにメールを読める形で w へ書き出す。ローカル開発やテストでリンクを確認するために使う。
+type FileSender struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewFileSender(w io.Writer, from string) *FileSender {
+	return &FileSender{w: w, from: from}
+}
+
+func (s *FileSender) Send(_ context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.w, "From: %s\nTo: %s\nSubject: %s\nDate: %s\n\n%s\n-- \n",
+		s.from, msg.To, msg.Subject, time.Now().UTC().Format(time.RFC3339), msg.Body)
+	return err
+}
+
+// NewAppendFileSender は送信のたびに path を追記モードで開いて書き出す。ファイルを開いたままにしない。
+func NewAppendFileSender(path, from string) *FileSender {
+	return NewFileSender(appendFile(path), from)
+}
+
+type appendFile string
+
+func (p appendFile) Write(b []byte) (int, error) {
+	f, err := os.OpenFile(string(p), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	n, err := f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
@@ -0,0 +1,56 @@
+// Package mail はアプリケーションが送るメールの配送を担う。本番は SMTP、ローカルではファイルや標準出力に書き出す。
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMessage は宛先や件名が空、またはヘッダーに改行を含むメッセージを表す。
+var ErrInvalidMessage = errors.New("mail: invalid message")
+
+// Message はプレーンテキストのメール 1 通。
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+func (m Message) validate() error {
+	if strings.TrimSpace(m.To) == "" || strings.TrimSpace(m.Subject) == "" {
+		return ErrInvalidMessage
+	}
+	// ヘッダーインジェクションを防ぐため、ヘッダーに入る値の改行は拒否する。
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidMessage
+	}
+	return nil
+}
+
+// encode は SMTP の DATA として送る RFC 5322 形式に変換する。本文は UTF-8 を base64 で送る。
+func (m Message) encode(from string, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(m.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
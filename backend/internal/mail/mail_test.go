@@ -0,0 +1,133 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessage_EncodeAndValidate(t *testing.T) {
+	msg := Message{To: "alice@example.com", Subject: "パスワードの再設定", Body: strings.Repeat("本文", 40)}
+	encoded := string(msg.encode("no-reply@example.com", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	header, body, ok := strings.Cut(encoded, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("expected header/body separator: %q", encoded)
+	}
+	if !strings.Contains(header, "Subject: =?UTF-8?b?") || !strings.Contains(header, "To: alice@example.com\r\n") {
+		t.Fatalf("unexpected header: %q", header)
+	}
+	for _, line := range strings.Split(strings.TrimRight(body, "\r\n"), "\r\n") {
+		if len(line) > 76 {
+			t.Fatalf("body line longer than 76: %d", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	if err != nil || string(decoded) != msg.Body {
+		t.Fatalf("body did not round-trip: %v", err)
+	}
+
+	for _, bad := range []Message{
+		{To: "", Subject: "s", Body: "b"},
+		{To: "a@example.com\r\nBcc: x@example.com", Subject: "s"},
+		{To: "a@example.com", Subject: "s\nX-Injected: 1"},
+	} {
+		if err := bad.validate(); !errors.Is(err, ErrInvalidMessage) {
+			t.Fatalf("expected ErrInvalidMessage for %+v, got %v", bad, err)
+		}
+	}
+}
+
+func TestFileSender_Send(t *testing.T) {
+	var buf bytes.Buffer
+	sender := NewFileSender(&buf, "no-reply@example.com")
+	if err := sender.Send(context.Background(), Message{To: "alice@example.com", Subject: "hello", Body: "https://example.com/reset?token=abc"}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "To: alice@example.com") || !strings.Contains(out, "token=abc") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go serveFakeSMTP(ln, received)
+
+	addr := ln.Addr().(*net.TCPAddr)
+	sender := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "no-reply@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sender.Send(ctx, Message{To: "alice@example.com", Subject: "hello", Body: "body"}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+
+	select {
+	case transcript := <-received:
+		for _, want := range []string{"MAIL FROM:<no-reply@example.com>", "RCPT TO:<alice@example.com>", "Subject: hello"} {
+			if !strings.Contains(transcript, want) {
+				t.Fatalf("transcript missing %q:\n%s", want, transcript)
+			}
+		}
+	case <-ctx.Done():
+		t.Fatalf("fake server did not receive the message")
+	}
+}
+
+// serveFakeSMTP は 1 接続だけを受け付ける最小限の SMTP サーバー。受け取った内容をすべて received に渡す。
+func serveFakeSMTP(ln net.Listener, received chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var transcript strings.Builder
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 fake ESMTP")
+	inData := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		transcript.WriteString(line)
+
+		if inData {
+			if line == ".\r\n" {
+				inData = false
+				reply("250 queued")
+			}
+			continue
+		}
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake")
+		case cmd == "DATA":
+			inData = true
+			reply("354 go ahead")
+		case cmd == "QUIT":
+			reply("221 bye")
+			received <- transcript.String()
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit はトークンバケットの容量と補充速度を表す。Per の間に Burst 個のトークンが補充される。
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+func (l Limit) ratePerSecond() float64 {
+	if l.Per <= 0 {
+		return 0
+	}
+	return float64(l.Burst) / l.Per.Seconds()
+}
+
+// Decision は 1 回の試行に対する判定結果。拒否時は RetryAfter だけ待てば次のトークンが得られる。
+type Decision struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// Store はキーごとのバケット状態を保持し、トークンを 1 つ消費できるかを判定する。
+// 複数インスタンスで共有したい場合は Redis などの実装に差し替える。
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+}
+
+// Limiter は Store に対して固定の Limit で判定する。
+type Limiter struct {
+	store Store
+	limit Limit
+}
+
+func NewLimiter(store Store, limit Limit) *Limiter {
+	return &Limiter{store: store, limit: limit}
+}
+
+// Allow は key のトークンを 1 つ消費する。
+func (l *Limiter) Allow(ctx context.Context, key string) (Decision, error) {
+	return l.store.Take(ctx, key, l.limit, time.Now())
+}
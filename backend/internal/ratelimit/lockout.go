@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type lockoutEntry struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// Lockout は連続した失敗回数をキーごとに数え、閾値に達したら一定時間ロックする。
+type Lockout struct {
+	mu        sync.Mutex
+	threshold int
+	duration  time.Duration
+	entries   map[string]*lockoutEntry
+	lastSweep time.Time
+}
+
+// NewLockout は threshold 回連続で失敗したキーを duration の間ロックする。threshold<=0 なら常に許可する。
+func NewLockout(threshold int, duration time.Duration) *Lockout {
+	return &Lockout{threshold: threshold, duration: duration, entries: make(map[string]*lockoutEntry)}
+}
+
+// Locked はロック中であれば残り時間と true を返す。
+func (l *Lockout) Locked(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || !now.Before(entry.lockedUntil) {
+		return 0, false
+	}
+	return entry.lockedUntil.Sub(now), true
+}
+
+// Fail は失敗を記録し、閾値に達した場合はロックを開始する。ロック期間を過ぎた古い失敗は数え直す。
+func (l *Lockout) Fail(key string, now time.Time) {
+	if l.threshold <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	entry, ok := l.entries[key]
+	if !ok || now.Sub(entry.lastFailure) >= l.duration {
+		entry = &lockoutEntry{}
+		l.entries[key] = entry
+	}
+
+	entry.failures++
+	entry.lastFailure = now
+	if entry.failures >= l.threshold {
+		entry.failures = 0
+		entry.lockedUntil = now.Add(l.duration)
+	}
+}
+
+// Reset は成功時に失敗回数とロックを解除する。
+func (l *Lockout) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, key)
+}
+
+// sweep はロックが明け、失敗も古くなったエントリを削除する。
+func (l *Lockout) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.duration {
+		return
+	}
+	l.lastSweep = now
+
+	for key, entry := range l.entries {
+		if !now.Before(entry.lockedUntil) && now.Sub(entry.lastFailure) >= l.duration {
+			delete(l.entries, key)
+		}
+	}
+}
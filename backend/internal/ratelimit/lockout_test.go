@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockout(t *testing.T) {
+	lockout := NewLockout(3, time.Minute)
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	lockout.Fail("alice", now)
+	lockout.Fail("alice", now)
+	if _, locked := lockout.Locked("alice", now); locked {
+		t.Fatalf("should not lock before threshold")
+	}
+
+	lockout.Fail("alice", now)
+	remaining, locked := lockout.Locked("alice", now.Add(10*time.Second))
+	if !locked || remaining != 50*time.Second {
+		t.Fatalf("expected lock with 50s remaining, got %v %s", locked, remaining)
+	}
+
+	if _, locked := lockout.Locked("alice", now.Add(time.Minute)); locked {
+		t.Fatalf("lock should expire after duration")
+	}
+}
+
+func TestLockout_Reset(t *testing.T) {
+	lockout := NewLockout(2, time.Minute)
+	now := time.Now()
+
+	lockout.Fail("bob", now)
+	lockout.Reset("bob")
+	lockout.Fail("bob", now)
+
+	if _, locked := lockout.Locked("bob", now); locked {
+		t.Fatalf("reset should clear previous failures")
+	}
+}
+
+func TestLockout_StaleFailuresAreForgotten(t *testing.T) {
+	lockout := NewLockout(2, time.Minute)
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	lockout.Fail("carol", now)
+	lockout.Fail("carol", now.Add(2*time.Minute))
+
+	if _, locked := lockout.Locked("carol", now.Add(2*time.Minute)); locked {
+		t.Fatalf("failures older than the lock duration should not count")
+	}
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"backend/internal/domain"
+)
+
+// LoginGuard はアカウント単位の試行回数制限と、アカウントと接続元の組ごとの連続失敗によるロックをまとめる。
+// ロックを接続元ごとにするのは、他人のユーザー名で失敗を重ねるだけでそのアカウントを締め出せないようにするため。
+// 試行回数制限はアカウント全体にかかるので、接続元を変えながらの総当たりも遅くなる。
+// scope はキーの接頭辞で、用途の違うガードが同じストアやアカウントを共有してもキーがぶつからない。
+type LoginGuard struct {
+	scope   string
+	limiter *Limiter
+	lockout *Lockout
+}
+
+func NewLoginGuard(scope string, limiter *Limiter, lockout *Lockout) *LoginGuard {
+	return &LoginGuard{scope: scope, limiter: limiter, lockout: lockout}
+}
+
+// Check は account が client からロック中、または account のバケットが空であれば domain.ErrRateLimited を包んだエラーを返す。
+func (g *LoginGuard) Check(ctx context.Context, account, client string) error {
+	if g.lockout != nil {
+		if remaining, locked := g.lockout.Locked(g.attemptKey(account, client), time.Now()); locked {
+			return domain.NewRateLimitError(remaining)
+		}
+	}
+
+	if g.limiter != nil {
+		decision, err := g.limiter.Allow(ctx, g.accountKey(account))
+		if err != nil {
+			return err
+		}
+		if !decision.Allowed {
+			return domain.NewRateLimitError(decision.RetryAfter)
+		}
+	}
+
+	return nil
+}
+
+// Failed は client からの account の認証失敗を記録する。
+func (g *LoginGuard) Failed(account, client string) {
+	if g.lockout != nil {
+		g.lockout.Fail(g.attemptKey(account, client), time.Now())
+	}
+}
+
+// Succeeded は認証成功時に client からの account の失敗回数を解除する。
+func (g *LoginGuard) Succeeded(account, client string) {
+	if g.lockout != nil {
+		g.lockout.Reset(g.attemptKey(account, client))
+	}
+}
+
+func (g *LoginGuard) accountKey(account string) string {
+	return g.scope + ":" + strings.ToLower(account)
+}
+
+func (g *LoginGuard) attemptKey(account, client string) string {
+	return g.accountKey(account) + "@" + client
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+)
+
+func TestLoginGuard_LocksPerClient(t *testing.T) {
+	ctx := context.Background()
+	guard := NewLoginGuard("login", nil, NewLockout(2, time.Minute))
+
+	guard.Failed("Alice", "203.0.113.1")
+	guard.Failed("alice", "203.0.113.1")
+
+	if err := guard.Check(ctx, "alice", "203.0.113.1"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("expected lock for the failing client, got %v", err)
+	}
+	if err := guard.Check(ctx, "alice", "198.51.100.2"); err != nil {
+		t.Fatalf("other clients should not be locked out, got %v", err)
+	}
+}
+
+func TestLoginGuard_ThrottlesAccountAcrossClients(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLimiter(NewMemoryStore(), Limit{Burst: 2, Per: time.Minute})
+	guard := NewLoginGuard("login", limiter, nil)
+
+	for _, client := range []string{"203.0.113.1", "198.51.100.2"} {
+		if err := guard.Check(ctx, "alice", client); err != nil {
+			t.Fatalf("client %s: expected attempt to be allowed, got %v", client, err)
+		}
+	}
+	if err := guard.Check(ctx, "alice", "192.0.2.3"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("expected account throttle across clients, got %v", err)
+	}
+}
+
+func TestLoginGuard_ScopesDoNotShareKeys(t *testing.T) {
+	ctx := context.Background()
+	lockout := NewLockout(1, time.Minute)
+	login := NewLoginGuard("login", nil, lockout)
+	change := NewLoginGuard("password-change", nil, lockout)
+
+	change.Failed("alice", "client")
+
+	if err := login.Check(ctx, "alice", "client"); err != nil {
+		t.Fatalf("password-change failures should not lock login, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Burst: 2, Per: 2 * time.Second}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		decision, err := store.Take(ctx, "k", limit, now)
+		if err != nil || !decision.Allowed {
+			t.Fatalf("attempt %d: expected allowed, got %+v (%v)", i, decision, err)
+		}
+	}
+
+	decision, err := store.Take(ctx, "k", limit, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allowed {
+		t.Fatalf("expected bucket to be empty")
+	}
+	if decision.RetryAfter != time.Second {
+		t.Fatalf("expected retry after 1s, got %s", decision.RetryAfter)
+	}
+
+	if decision, _ := store.Take(ctx, "other", limit, now); !decision.Allowed {
+		t.Fatalf("buckets must be independent per key")
+	}
+
+	if decision, _ := store.Take(ctx, "k", limit, now.Add(time.Second)); !decision.Allowed {
+		t.Fatalf("expected a token to be refilled after 1s")
+	}
+}
+
+func TestMemoryStore_Take_Unlimited(t *testing.T) {
+	store := NewMemoryStore()
+	for i := 0; i < 10; i++ {
+		decision, err := store.Take(context.Background(), "k", Limit{}, time.Now())
+		if err != nil || !decision.Allowed {
+			t.Fatalf("zero limit should always allow")
+		}
+	}
+}
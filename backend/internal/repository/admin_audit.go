@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdminAuditRepository は admin_audit_logs テーブルへ管理操作の記録を追記する。
+type AdminAuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAdminAuditRepository(db *pgxpool.Pool) *AdminAuditRepository {
+	return &AdminAuditRepository{db: db}
+}
+
+// Append は記録を 1 件追加する。記録は更新・削除しない。
+func (r *AdminAuditRepository) Append(ctx context.Context, entry domain.AdminAuditEntry) error {
+	const query = `
+		INSERT INTO admin_audit_logs (id, actor_id, target_id, action, detail, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		entry.ID(),
+		entry.ActorID(),
+		entry.TargetID(),
+		string(entry.Action()),
+		entry.Detail(),
+		entry.CreatedAt(),
+	)
+	return err
+}
+
+// ListRecent は新しい順に最大 limit 件を返す。
+func (r *AdminAuditRepository) ListRecent(ctx context.Context, limit int) ([]domain.AdminAuditEntry, error) {
+	const query = `
+		SELECT id, actor_id, target_id, action, detail, created_at
+		FROM admin_audit_logs
+		ORDER BY created_at DESC, id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.AdminAuditEntry, error) {
+		var (
+			id, actorID, targetID uuid.UUID
+			action, detail        string
+			createdAt             time.Time
+		)
+		if err := row.Scan(&id, &actorID, &targetID, &action, &detail, &createdAt); err != nil {
+			return domain.AdminAuditEntry{}, err
+		}
+		return domain.NewAdminAuditEntryFromPersistence(id, actorID, targetID, domain.AdminAction(action), detail, createdAt)
+	})
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EmailVerificationRepository は email_verifications テーブルを扱う。
+type EmailVerificationRepository struct {
+	tokens oneTimeTokenTable
+}
+
+func NewEmailVerificationRepository(db *pgxpool.Pool) *EmailVerificationRepository {
+	return &EmailVerificationRepository{tokens: oneTimeTokenTable{db: db, table: "email_verifications"}}
+}
+
+// Create は申請を永続化する。
+func (r *EmailVerificationRepository) Create(ctx context.Context, verification domain.EmailVerification) error {
+	return r.tokens.create(ctx, verification)
+}
+
+// FindByID はトークンのセレクタ(主キー)で 1 行を引き、見つからなければ pgx.ErrNoRows を返す。
+func (r *EmailVerificationRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.EmailVerification, error) {
+	row, err := r.tokens.findByID(ctx, id)
+	if err != nil {
+		return domain.EmailVerification{}, err
+	}
+	return domain.NewEmailVerificationFromPersistence(row.id, row.userID, row.tokenHash, row.expiresAt, row.createdAt, row.usedAt)
+}
+
+// MarkUsed は未使用かつ期限内の申請だけを使用済みにし、それ以外は pgx.ErrNoRows を返す。
+func (r *EmailVerificationRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return r.tokens.markUsed(ctx, id, usedAt)
+}
+
+// DeleteByUserID は指定ユーザーの申請をすべて削除し、削除件数を返す。
+func (r *EmailVerificationRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.tokens.deleteByUserID(ctx, userID)
+}
@@ -4,11 +4,22 @@ import (
 	"backend/internal/domain"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// hueExportFetchSize は Export がサーバー側カーソルから 1 回に読む行数。
+const hueExportFetchSize = 500
+
+var hueExportFetch = fmt.Sprintf("FETCH FORWARD %d FROM hue_export", hueExportFetchSize)
+
 type HueRepository struct {
 	db *pgxpool.Pool
 }
@@ -17,11 +28,17 @@ func NewHueRepository(db *pgxpool.Pool) *HueRepository {
 	return &HueRepository{db: db}
 }
 
-// Save は hue_records テーブルへ新しいレコードを保存する。
+// Save は hue_records テーブルへ新しいレコードを保存する。再送判定キーかチャレンジが保存済みのものと重なれば
+// domain.ErrDuplicateIdempotencyKey / domain.ErrHueChallengeUsed を返す。
 func (r *HueRepository) Save(ctx context.Context, record domain.HueRecord) error {
+	return insertHueRecord(ctx, r.db, record)
+}
+
+func insertHueRecord(ctx context.Context, db execer, record domain.HueRecord) error {
 	const query = `
-		INSERT INTO hue_records (id, user_name, choices)
-		VALUES ($1, $2, $3)
+		INSERT INTO hue_records (id, user_name, choices, user_id, word_set_id, palette_version, word_order_mode, word_order_seed, word_order,
+		                         quality_flags, idempotency_key, challenge_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
 	choiceJSON, err := json.Marshal(record.ChoiceMap())
@@ -29,50 +46,338 @@ func (r *HueRepository) Save(ctx context.Context, record domain.HueRecord) error
 		return err
 	}
 
-	_, err = r.db.Exec(ctx, query, record.ID(), record.Name().String(), choiceJSON)
-	return err
+	// 匿名の回答は user_id を NULL で保存する。
+	userID := optionalUUID(record.UserID())
+	wordSetID := optionalUUID(record.WordSetID())
+	// palette_version は NOT NULL のため、Palette.Paint を通していない回答は DB が拒否する。
+	paletteVersion, _ := record.PaletteVersion()
+	// セッションを通さない回答は出題順の 3 カラムをすべて NULL で保存する。
+	var (
+		orderMode  *string
+		orderSeed  *int64
+		orderWords []string
+	)
+	if order, ok := record.WordOrder(); ok {
+		mode, seed := order.Mode().String(), order.Seed()
+		orderMode, orderSeed, orderWords = &mode, &seed, wordStrings(order.Words())
+	}
+
+	var idempotencyKey *string
+	if key, ok := record.IdempotencyKey(); ok {
+		value := key.String()
+		idempotencyKey = &value
+	}
+
+	_, err = db.Exec(ctx, query,
+		record.ID(), record.Name().String(), choiceJSON, userID, wordSetID, paletteVersion, orderMode, orderSeed, orderWords,
+		qualityFlagStrings(record.QualityFlags()), idempotencyKey, optionalUUID(record.ChallengeID()),
+	)
+	return translateHueRecordConstraintError(err)
 }
 
-// FindRange は作成順で並んだレコードの指定範囲を返す。
-func (r *HueRepository) FindRange(ctx context.Context, recordRange domain.RecordRange) ([]domain.HueRecord, error) {
-	const query = `
-		SELECT id, user_name, choices
+const (
+	idempotencyKeyConstraintKey = "hue_records_idempotency_key_key"
+	challengeIDConstraintKey    = "hue_records_challenge_id_key"
+)
+
+func translateHueRecordConstraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationCode {
+		return err
+	}
+
+	switch pgErr.ConstraintName {
+	case idempotencyKeyConstraintKey:
+		return domain.ErrDuplicateIdempotencyKey
+	case challengeIDConstraintKey:
+		return domain.ErrHueChallengeUsed
+	default:
+		return err
+	}
+}
+
+func qualityFlagStrings(flags []domain.HueQualityFlag) []string {
+	values := make([]string, len(flags))
+	for i, flag := range flags {
+		values[i] = flag.String()
+	}
+	return values
+}
+
+// FindByIdempotencyKey は key で保存した回答を返す。見つからなければ pgx.ErrNoRows を返す。
+func (r *HueRepository) FindByIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) (domain.HueRecord, error) {
+	query := `SELECT ` + hueRecordColumns + ` FROM hue_records WHERE idempotency_key = $1`
+	return scanHueRecord(r.db.QueryRow(ctx, query, key.String()))
+}
+
+// FindPage は filter に一致するレコードを (created_at, id) のキーセットで after の直後から読み出す。
+// 続きの有無を知るため limit より 1 件多く取得し、次ページのカーソルは domain.NewRecordPage が決める。
+func (r *HueRepository) FindPage(ctx context.Context, req domain.RecordPageRequest) (domain.RecordPage, error) {
+	where, err := hueRecordConditions(req.Filter())
+	if err != nil {
+		return domain.RecordPage{}, err
+	}
+	if after := req.After(); !after.IsZero() {
+		where.add(fmt.Sprintf("(created_at, id) > (%s, %s)", where.arg(after.CreatedAt()), where.arg(after.ID())))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM hue_records
+		%s
 		ORDER BY created_at, id
-		OFFSET $1
-		LIMIT $2
+		LIMIT %s
+	`, hueRecordColumns, where.clause(), where.arg(req.Limit()+1))
+
+	rows, err := r.db.Query(ctx, query, where.args...)
+	if err != nil {
+		return domain.RecordPage{}, err
+	}
+
+	records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.HueRecord, error) {
+		return scanHueRecord(row)
+	})
+	if err != nil {
+		return domain.RecordPage{}, err
+	}
+
+	total := -1
+	if req.IncludeTotal() {
+		// 件数はカーソル位置に関係なく、絞り込み条件だけで数える。
+		count, err := hueRecordConditions(req.Filter())
+		if err != nil {
+			return domain.RecordPage{}, err
+		}
+		if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM hue_records `+count.clause(), count.args...).Scan(&total); err != nil {
+			return domain.RecordPage{}, err
+		}
+	}
+
+	return domain.NewRecordPage(records, req.Limit(), total), nil
+}
+
+// likeEscaper は LIKE のワイルドカードを文字として扱うためのエスケープ。
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// sqlConditions は AND でつなぐ条件と、そのプレースホルダーに対応する引数を順に積み上げる。
+type sqlConditions struct {
+	conds []string
+	args  []any
+}
+
+// arg は value を引数に加え、対応するプレースホルダー ($n) を返す。
+func (c *sqlConditions) arg(value any) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *sqlConditions) add(cond string) {
+	c.conds = append(c.conds, cond)
+}
+
+func (c *sqlConditions) clause() string {
+	if len(c.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(c.conds, " AND ")
+}
+
+// hueRecordConditions は filter を WHERE 条件に変換する。単語と色の条件は choices @> の包含検索にして GIN インデックスを使う。
+func hueRecordConditions(filter domain.RecordFilter) (*sqlConditions, error) {
+	where := &sqlConditions{}
+
+	if from := filter.CreatedFrom(); !from.IsZero() {
+		where.add("created_at >= " + where.arg(from))
+	}
+	if to := filter.CreatedTo(); !to.IsZero() {
+		where.add("created_at < " + where.arg(to))
+	}
+
+	if userID, ok := filter.UserID(); ok {
+		where.add("user_id = " + where.arg(userID))
+	}
+
+	if name := filter.Name(); name != "" {
+		pattern := likeEscaper.Replace(name) + "%"
+		if filter.NameMatch() == domain.NameMatchContains {
+			pattern = "%" + pattern
+		}
+		where.add("user_name LIKE " + where.arg(pattern))
+	}
+
+	if choices := filter.Choices(); len(choices) > 0 {
+		contained, err := json.Marshal(choices)
+		if err != nil {
+			return nil, err
+		}
+		where.add("choices @> " + where.arg(string(contained)) + "::jsonb")
+	}
+
+	return where, nil
+}
+
+// Stats は palette の版で回答したレコードの choices JSONB を単語と色に展開し、単語ごとの色別回答数を集計する。
+// 集計は SQL 側で行い、単語ごとに高々パレットの色数だけの行を受け取る。結果は単語の昇順。
+func (r *HueRepository) Stats(ctx context.Context, palette domain.Palette) ([]domain.HueWordStats, error) {
+	const query = `
+		SELECT c.key, c.value, COUNT(*)
+		FROM hue_records AS h
+		CROSS JOIN LATERAL jsonb_each_text(h.choices) AS c(key, value)
+		WHERE h.palette_version = $1 AND c.value = ANY($2)
+		GROUP BY c.key, c.value
+		ORDER BY c.key
 	`
 
-	rows, err := r.db.Query(ctx, query, recordRange.Begin(), recordRange.Count())
+	ids := palette.ColorIDs()
+	colors := make([]string, len(ids))
+	for i, color := range ids {
+		colors[i] = string(color)
+	}
+
+	rows, err := r.db.Query(ctx, query, palette.Version(), colors)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var records []domain.HueRecord
+	var (
+		words  []domain.HueWord
+		counts = make(map[domain.HueWord]map[domain.HueColor]int)
+	)
 	for rows.Next() {
-		record, err := scanHueRecord(rows)
-		if err != nil {
+		var (
+			word, color string
+			count       int64
+		)
+		if err := rows.Scan(&word, &color, &count); err != nil {
 			return nil, err
 		}
-		records = append(records, record)
+
+		w := domain.HueWord(word)
+		if _, ok := counts[w]; !ok {
+			words = append(words, w)
+			counts[w] = make(map[domain.HueColor]int)
+		}
+		counts[w][domain.HueColor(color)] = int(count)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return records, nil
+	stats := make([]domain.HueWordStats, 0, len(words))
+	for _, w := range words {
+		s, err := domain.NewHueWordStats(w, ids, counts[w])
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// Export は全レコードを (created_at, id) 昇順で write に渡す。読み出しは読み取り専用トランザクション内の
+// サーバー側カーソルから hueExportFetchSize 行ずつ行うため、テーブルの大きさに関わらずメモリ使用量は一定。
+// begin が nil でなければ、同じスナップショットで集めた単語一覧 (昇順) をレコードより先に渡す。
+func (r *HueRepository) Export(ctx context.Context, begin func(words []domain.HueWord) error, write func(domain.HueRecord) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if begin != nil {
+		words, err := exportWords(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if err := begin(words); err != nil {
+			return err
+		}
+	}
+
+	const declare = `
+		DECLARE hue_export NO SCROLL CURSOR FOR
+		SELECT ` + hueRecordColumns + `
+		FROM hue_records
+		ORDER BY created_at, id
+	`
+	if _, err := tx.Exec(ctx, declare); err != nil {
+		return err
+	}
+
+	for {
+		// FETCH の件数はバインドパラメータにできないため、定数を埋め込んだ文を使う。
+		rows, err := tx.Query(ctx, hueExportFetch)
+		if err != nil {
+			return err
+		}
+
+		fetched := 0
+		for rows.Next() {
+			fetched++
+			record, err := scanHueRecord(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			if err := write(record); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if fetched < hueExportFetchSize {
+			break
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func exportWords(ctx context.Context, tx pgx.Tx) ([]domain.HueWord, error) {
+	const query = `
+		SELECT DISTINCT w
+		FROM hue_records AS h
+		CROSS JOIN LATERAL jsonb_object_keys(h.choices) AS w
+		ORDER BY w
+	`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.HueWord, error) {
+		var word string
+		err := row.Scan(&word)
+		return domain.HueWord(word), err
+	})
 }
 
+// hueRecordColumns は scanHueRecord が読む順の列。
+const hueRecordColumns = "id, user_name, choices, user_id, word_set_id, palette_version, word_order_mode, word_order_seed, word_order, quality_flags, created_at"
+
 func scanHueRecord(row rowScanner) (domain.HueRecord, error) {
 	var (
-		id         uuid.UUID
-		userName   string
-		choiceJSON []byte
+		id             uuid.UUID
+		userName       string
+		choiceJSON     []byte
+		userID         *uuid.UUID
+		wordSetID      *uuid.UUID
+		paletteVersion int
+		orderMode      *string
+		orderSeed      *int64
+		orderWords     []string
+		qualityFlags   []string
+		createdAt      time.Time
 	)
 
-	if err := row.Scan(&id, &userName, &choiceJSON); err != nil {
+	if err := row.Scan(&id, &userName, &choiceJSON, &userID, &wordSetID, &paletteVersion, &orderMode, &orderSeed, &orderWords, &qualityFlags, &createdAt); err != nil {
 		return domain.HueRecord{}, err
 	}
 
@@ -91,5 +396,43 @@ func scanHueRecord(row rowScanner) (domain.HueRecord, error) {
 		return domain.HueRecord{}, err
 	}
 
-	return domain.NewHueRecordFromPersistence(id, name, choices)
+	owner := uuid.Nil
+	if userID != nil {
+		owner = *userID
+	}
+
+	record, err := domain.NewHueRecordFromPersistence(id, name, choices, owner, createdAt)
+	if err != nil {
+		return domain.HueRecord{}, err
+	}
+	if wordSetID != nil {
+		record = record.AnsweredFrom(*wordSetID)
+	}
+	if orderMode != nil && orderSeed != nil {
+		words := make([]domain.HueWord, len(orderWords))
+		for i, word := range orderWords {
+			words[i] = domain.HueWord(word)
+		}
+		order, err := domain.NewWordOrderFromPersistence(domain.WordOrderMode(*orderMode), *orderSeed, words)
+		if err != nil {
+			return domain.HueRecord{}, err
+		}
+		record = record.PresentedIn(order)
+	}
+	if len(qualityFlags) > 0 {
+		flags := make([]domain.HueQualityFlag, len(qualityFlags))
+		for i, flag := range qualityFlags {
+			flags[i] = domain.HueQualityFlag(flag)
+		}
+		record = record.FlaggedAs(flags...)
+	}
+	return record.ColoredWith(paletteVersion), nil
+}
+
+// optionalUUID は (ID, 有無) の組を NULL 許容カラムへ渡す値に変換する。
+func optionalUUID(id uuid.UUID, ok bool) *uuid.UUID {
+	if !ok {
+		return nil
+	}
+	return &id
 }
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HueSessionRepository は hue_sessions と hue_session_answers を読み書きする。
+type HueSessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewHueSessionRepository(db *pgxpool.Pool) *HueSessionRepository {
+	return &HueSessionRepository{db: db}
+}
+
+// Create は回答の無い開始直後のセッションを保存する。
+func (r *HueSessionRepository) Create(ctx context.Context, session domain.HueSession) error {
+	const query = `
+		INSERT INTO hue_sessions (id, user_id, word_set_id, palette_version, words, word_order_mode, word_order_seed, started_at, last_active_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	order := session.Order()
+	_, err := r.db.Exec(ctx, query,
+		session.ID(), optionalUUID(session.UserID()), session.WordSetID(), session.PaletteVersion(),
+		wordStrings(order.Words()), order.Mode().String(), order.Seed(), session.StartedAt(), session.LastActiveAt(),
+	)
+	return err
+}
+
+// FindByID は回答も含めて読み出す。見つからなければ pgx.ErrNoRows を返す。
+func (r *HueSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.HueSession, error) {
+	const sessionQuery = `
+		SELECT user_id, word_set_id, palette_version, words, word_order_mode, word_order_seed, started_at, last_active_at, finished_at, record_id
+		FROM hue_sessions
+		WHERE id = $1
+	`
+	const answerQuery = `
+		SELECT word, color, latency_ms, answered_at
+		FROM hue_session_answers
+		WHERE session_id = $1
+	`
+
+	var (
+		userID, wordSetID, recordID *uuid.UUID
+		paletteVersion              int
+		words                       []string
+		orderMode                   string
+		orderSeed                   int64
+		startedAt, lastActiveAt     time.Time
+		finishedAt                  *time.Time
+	)
+	err := r.db.QueryRow(ctx, sessionQuery, id).Scan(&userID, &wordSetID, &paletteVersion, &words, &orderMode, &orderSeed, &startedAt, &lastActiveAt, &finishedAt, &recordID)
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+
+	rows, err := r.db.Query(ctx, answerQuery, id)
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+	answers, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.HueAnswer, error) {
+		var (
+			word, color string
+			latencyMS   int
+			answeredAt  time.Time
+		)
+		if err := row.Scan(&word, &color, &latencyMS, &answeredAt); err != nil {
+			return domain.HueAnswer{}, err
+		}
+		return domain.NewHueAnswer(domain.HueWord(word), domain.HueColor(color), time.Duration(latencyMS)*time.Millisecond, answeredAt)
+	})
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+
+	hueWords := make([]domain.HueWord, len(words))
+	for i, word := range words {
+		hueWords[i] = domain.HueWord(word)
+	}
+	order, err := domain.NewWordOrderFromPersistence(domain.WordOrderMode(orderMode), orderSeed, hueWords)
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+	var finished time.Time
+	if finishedAt != nil {
+		finished = *finishedAt
+	}
+	return domain.NewHueSessionFromPersistence(id, derefUUID(userID), derefUUID(wordSetID), paletteVersion, order, answers, startedAt, lastActiveAt, finished, derefUUID(recordID))
+}
+
+// CountByWordSet は wordSetID の単語セットで始めたセッションの数を返す。
+func (r *HueSessionRepository) CountByWordSet(ctx context.Context, wordSetID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM hue_sessions WHERE word_set_id = $1`, wordSetID).Scan(&count)
+	return count, err
+}
+
+// SaveAnswer は回答を保存し、セッションの最終回答時刻を進める。同じ単語への回答は上書きする。
+// セッションが無いか終了していれば pgx.ErrNoRows を返す。
+func (r *HueSessionRepository) SaveAnswer(ctx context.Context, sessionID uuid.UUID, answer domain.HueAnswer) error {
+	const query = `
+		WITH touched AS (
+			UPDATE hue_sessions
+			SET last_active_at = GREATEST(last_active_at, $5)
+			WHERE id = $1 AND finished_at IS NULL
+			RETURNING id
+		)
+		INSERT INTO hue_session_answers (session_id, word, color, latency_ms, answered_at)
+		SELECT id, $2, $3, $4, $5 FROM touched
+		ON CONFLICT (session_id, word) DO UPDATE
+		SET color = EXCLUDED.color, latency_ms = EXCLUDED.latency_ms, answered_at = EXCLUDED.answered_at
+	`
+
+	tag, err := r.db.Exec(ctx, query,
+		sessionID, string(answer.Word()), string(answer.Color()), answer.Latency().Milliseconds(), answer.AnsweredAt(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Finish は record を hue_records へ保存し、同じトランザクションでセッションを終了済みにする。
+// セッションが無いか既に終了していれば何も保存せず pgx.ErrNoRows を返す。
+func (r *HueSessionRepository) Finish(ctx context.Context, session domain.HueSession, record domain.HueRecord) error {
+	finishedAt, ok := session.FinishedAt()
+	if !ok {
+		return domain.ErrInvalidHueSession
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := insertHueRecord(ctx, tx, record); err != nil {
+		return err
+	}
+
+	const query = `
+		UPDATE hue_sessions
+		SET finished_at = $2, last_active_at = $2, record_id = $3
+		WHERE id = $1 AND finished_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, query, session.ID(), finishedAt, record.ID())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Funnel は now 時点の状態ごとのセッション数、回答数ごとのセッション数、単語ごとの回答時間の中央値を集計する。
+// 最後の回答から domain.HueSessionIdleTimeout を過ぎた未終了のセッションを離脱として数える。
+func (r *HueSessionRepository) Funnel(ctx context.Context, now time.Time) (domain.HueSessionFunnel, error) {
+	const progressQuery = `
+		SELECT s.finished_at IS NOT NULL, s.last_active_at < $1, COUNT(a.word)
+		FROM hue_sessions AS s
+		LEFT JOIN hue_session_answers AS a ON a.session_id = s.id
+		GROUP BY s.id
+	`
+	const timingQuery = `
+		SELECT word, COUNT(*), percentile_disc(0.5) WITHIN GROUP (ORDER BY latency_ms)
+		FROM hue_session_answers
+		GROUP BY word
+	`
+
+	statuses := make(map[domain.HueSessionStatus]int)
+	answered := make(map[int]int)
+
+	rows, err := r.db.Query(ctx, progressQuery, now.Add(-domain.HueSessionIdleTimeout))
+	if err != nil {
+		return domain.HueSessionFunnel{}, err
+	}
+	for rows.Next() {
+		var (
+			finished, idle bool
+			count          int
+		)
+		if err := rows.Scan(&finished, &idle, &count); err != nil {
+			rows.Close()
+			return domain.HueSessionFunnel{}, err
+		}
+		switch {
+		case finished:
+			statuses[domain.HueSessionFinished]++
+		case idle:
+			statuses[domain.HueSessionAbandoned]++
+		default:
+			statuses[domain.HueSessionInProgress]++
+		}
+		answered[count]++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return domain.HueSessionFunnel{}, err
+	}
+
+	rows, err = r.db.Query(ctx, timingQuery)
+	if err != nil {
+		return domain.HueSessionFunnel{}, err
+	}
+	timings, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.HueWordTiming, error) {
+		var (
+			word      string
+			count     int
+			latencyMS int
+		)
+		if err := row.Scan(&word, &count, &latencyMS); err != nil {
+			return domain.HueWordTiming{}, err
+		}
+		return domain.NewHueWordTiming(domain.HueWord(word), count, time.Duration(latencyMS)*time.Millisecond), nil
+	})
+	if err != nil {
+		return domain.HueSessionFunnel{}, err
+	}
+
+	return domain.NewHueSessionFunnel(statuses, answered, timings), nil
+}
+
+func derefUUID(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"backend/internal/domain"
+)
+
+// AdminAuditRepository は admin_audit_logs のインメモリ実装。
+type AdminAuditRepository struct {
+	mu      sync.RWMutex
+	entries []domain.AdminAuditEntry
+}
+
+func NewAdminAuditRepository() *AdminAuditRepository {
+	return &AdminAuditRepository{}
+}
+
+func (r *AdminAuditRepository) Append(_ context.Context, entry domain.AdminAuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// ListRecent は追記の新しい順に最大 limit 件を返す。
+func (r *AdminAuditRepository) ListRecent(_ context.Context, limit int) ([]domain.AdminAuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recent := slices.Clone(r.entries)
+	slices.Reverse(recent)
+	return recent[:min(limit, len(recent))], nil
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationRepository は email_verifications のインメモリ実装。
+type EmailVerificationRepository struct {
+	tokens *oneTimeTokenTable
+}
+
+func NewEmailVerificationRepository() *EmailVerificationRepository {
+	return &EmailVerificationRepository{tokens: newOneTimeTokenTable()}
+}
+
+func (r *EmailVerificationRepository) Create(_ context.Context, verification domain.EmailVerification) error {
+	return r.tokens.create(verification)
+}
+
+// FindByID は見つからなければ pgx.ErrNoRows を返す。
+func (r *EmailVerificationRepository) FindByID(_ context.Context, id uuid.UUID) (domain.EmailVerification, error) {
+	row, err := r.tokens.findByID(id)
+	if err != nil {
+		return domain.EmailVerification{}, err
+	}
+	return domain.NewEmailVerificationFromPersistence(row.id, row.userID, row.tokenHash, row.expiresAt, row.createdAt, row.usedAt)
+}
+
+// MarkUsed は未使用かつ期限内の申請だけを使用済みにし、それ以外は pgx.ErrNoRows を返す。
+func (r *EmailVerificationRepository) MarkUsed(_ context.Context, id uuid.UUID, usedAt time.Time) error {
+	return r.tokens.markUsed(id, usedAt)
+}
+
+func (r *EmailVerificationRepository) DeleteByUserID(_ context.Context, userID uuid.UUID) (int64, error) {
+	return r.tokens.deleteByUserID(userID), nil
+}
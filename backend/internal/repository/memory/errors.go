@@ -0,0 +1,8 @@
+// Package memory は repository パッケージと同じ契約を持つインメモリ実装を提供する。
+// PostgreSQL なしでサービス層や HTTP スタック全体をプロセス内で動かすために使う。
+package memory
+
+import "errors"
+
+// errDuplicateKey は主キー重複を表す。PostgreSQL 実装では変換されない制約違反に相当する。
+var errDuplicateKey = errors.New("memory: duplicate primary key")
@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// HueRepository は hue_records のインメモリ実装。created_at は保存時刻で代用する。
+// 再送判定キーと使用済みチャレンジは PostgreSQL の一意制約の代わりに別の索引で持つ。
+type HueRepository struct {
+	mu         sync.RWMutex
+	records    []domain.HueRecord
+	keys       map[domain.IdempotencyKey]uuid.UUID
+	challenges map[uuid.UUID]struct{}
+	now        func() time.Time
+}
+
+func NewHueRepository() *HueRepository {
+	return &HueRepository{keys: make(map[domain.IdempotencyKey]uuid.UUID), challenges: make(map[uuid.UUID]struct{}), now: time.Now}
+}
+
+func (r *HueRepository) Save(_ context.Context, record domain.HueRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.records {
+		if stored.ID() == record.ID() {
+			return errDuplicateKey
+		}
+	}
+	key, keyed := record.IdempotencyKey()
+	if _, ok := r.keys[key]; keyed && ok {
+		return domain.ErrDuplicateIdempotencyKey
+	}
+	challengeID, challenged := record.ChallengeID()
+	if _, ok := r.challenges[challengeID]; challenged && ok {
+		return domain.ErrHueChallengeUsed
+	}
+
+	userID, _ := record.UserID()
+	stored, err := domain.NewHueRecordFromPersistence(record.ID(), record.Name(), record.Choices(), userID, r.now())
+	if err != nil {
+		return err
+	}
+	if wordSetID, ok := record.WordSetID(); ok {
+		stored = stored.AnsweredFrom(wordSetID)
+	}
+	if version, ok := record.PaletteVersion(); ok {
+		stored = stored.ColoredWith(version)
+	}
+	if order, ok := record.WordOrder(); ok {
+		stored = stored.PresentedIn(order)
+	}
+	stored = stored.FlaggedAs(record.QualityFlags()...)
+
+	r.records = append(r.records, stored)
+	if keyed {
+		r.keys[key] = record.ID()
+	}
+	if challenged {
+		r.challenges[challengeID] = struct{}{}
+	}
+	return nil
+}
+
+// FindByIdempotencyKey は見つからなければ pgx.ErrNoRows を返す。
+func (r *HueRepository) FindByIdempotencyKey(_ context.Context, key domain.IdempotencyKey) (domain.HueRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id, ok := r.keys[key]; ok {
+		for _, record := range r.records {
+			if record.ID() == id {
+				return record, nil
+			}
+		}
+	}
+	return domain.HueRecord{}, pgx.ErrNoRows
+}
+
+// FindPage は PostgreSQL 実装と同じく、filter に一致するものを (created_at, id) のキーセットで after の直後から読み出す。
+func (r *HueRepository) FindPage(_ context.Context, req domain.RecordPageRequest) (domain.RecordPage, error) {
+	filter := req.Filter()
+	sorted := slices.DeleteFunc(r.snapshot(), func(record domain.HueRecord) bool {
+		return !filter.Matches(record)
+	})
+
+	begin := 0
+	if after := req.After(); !after.IsZero() {
+		var found bool
+		begin, found = slices.BinarySearchFunc(sorted, after, func(record domain.HueRecord, cursor domain.RecordCursor) int {
+			if c := record.CreatedAt().Compare(cursor.CreatedAt()); c != 0 {
+				return c
+			}
+			ida, idb := record.ID(), cursor.ID()
+			return bytes.Compare(ida[:], idb[:])
+		})
+		if found {
+			begin++
+		}
+	}
+	end := min(begin+req.Limit()+1, len(sorted))
+
+	total := -1
+	if req.IncludeTotal() {
+		total = len(sorted)
+	}
+
+	var records []domain.HueRecord
+	records = append(records, sorted[begin:end]...)
+	return domain.NewRecordPage(records, req.Limit(), total), nil
+}
+
+// Stats は PostgreSQL 実装と同じく palette の版で回答したレコードを、単語の昇順で色別に数える。
+func (r *HueRepository) Stats(_ context.Context, palette domain.Palette) ([]domain.HueWordStats, error) {
+	ids := palette.ColorIDs()
+	counts := make(map[domain.HueWord]map[domain.HueColor]int)
+	for _, record := range r.snapshot() {
+		if version, _ := record.PaletteVersion(); version != palette.Version() {
+			continue
+		}
+		for word, color := range record.ChoiceMap() {
+			if !slices.Contains(ids, domain.HueColor(color)) {
+				continue
+			}
+			w := domain.HueWord(word)
+			if counts[w] == nil {
+				counts[w] = make(map[domain.HueColor]int)
+			}
+			counts[w][domain.HueColor(color)]++
+		}
+	}
+
+	words := slices.Sorted(maps.Keys(counts))
+	stats := make([]domain.HueWordStats, 0, len(words))
+	for _, w := range words {
+		s, err := domain.NewHueWordStats(w, ids, counts[w])
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// Export は呼び出し時点のスナップショットを (created_at, id) 昇順で write に渡す。
+func (r *HueRepository) Export(_ context.Context, begin func(words []domain.HueWord) error, write func(domain.HueRecord) error) error {
+	records := r.snapshot()
+
+	if begin != nil {
+		seen := make(map[domain.HueWord]struct{})
+		for _, record := range records {
+			for word := range record.ChoiceMap() {
+				seen[domain.HueWord(word)] = struct{}{}
+			}
+		}
+		if err := begin(slices.Sorted(maps.Keys(seen))); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range records {
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot は (created_at, id) 昇順に並べたコピーを返す。
+func (r *HueRepository) snapshot() []domain.HueRecord {
+	r.mu.RLock()
+	sorted := slices.Clone(r.records)
+	r.mu.RUnlock()
+
+	slices.SortFunc(sorted, compareHueRecords)
+	return sorted
+}
+
+func compareHueRecords(a, b domain.HueRecord) int {
+	if c := a.CreatedAt().Compare(b.CreatedAt()); c != 0 {
+		return c
+	}
+	ida, idb := a.ID(), b.ID()
+	return bytes.Compare(ida[:], idb[:])
+}
+
+// HasWordSet は wordSetID の単語セットに答えた回答が 1 件でもあれば true を返す。
+func (r *HueRepository) HasWordSet(_ context.Context, wordSetID uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.ContainsFunc(r.records, func(record domain.HueRecord) bool {
+		id, ok := record.WordSetID()
+		return ok && id == wordSetID
+	}), nil
+}
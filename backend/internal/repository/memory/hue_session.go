@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// HueSessionRepository は hue_sessions のインメモリ実装。終了時の回答は hues へ保存する。
+type HueSessionRepository struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]domain.HueSession
+	hues     *HueRepository
+}
+
+func NewHueSessionRepository(hues *HueRepository) *HueSessionRepository {
+	return &HueSessionRepository{sessions: make(map[uuid.UUID]domain.HueSession), hues: hues}
+}
+
+func (r *HueSessionRepository) Create(_ context.Context, session domain.HueSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[session.ID()]; ok {
+		return errDuplicateKey
+	}
+	r.sessions[session.ID()] = session
+	return nil
+}
+
+// FindByID は見つからなければ pgx.ErrNoRows を返す。
+func (r *HueSessionRepository) FindByID(_ context.Context, id uuid.UUID) (domain.HueSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return domain.HueSession{}, pgx.ErrNoRows
+	}
+	return session, nil
+}
+
+// CountByWordSet は wordSetID の単語セットで始めたセッションの数を返す。
+func (r *HueSessionRepository) CountByWordSet(_ context.Context, wordSetID uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, session := range r.sessions {
+		if session.WordSetID() == wordSetID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SaveAnswer はセッションが無いか終了していれば pgx.ErrNoRows を返す。PostgreSQL 実装と同じく、離脱したかどうかは見ない。
+func (r *HueSessionRepository) SaveAnswer(_ context.Context, sessionID uuid.UUID, answer domain.HueAnswer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+	if _, finished := session.FinishedAt(); finished {
+		return pgx.ErrNoRows
+	}
+
+	answers := slices.DeleteFunc(session.Answers(), func(a domain.HueAnswer) bool { return a.Word() == answer.Word() })
+	lastActiveAt := session.LastActiveAt()
+	if answer.AnsweredAt().After(lastActiveAt) {
+		lastActiveAt = answer.AnsweredAt()
+	}
+	updated, err := domain.NewHueSessionFromPersistence(
+		session.ID(), userIDOf(session), session.WordSetID(), session.PaletteVersion(), session.Order(),
+		append(answers, answer), session.StartedAt(), lastActiveAt, time.Time{}, uuid.Nil,
+	)
+	if err != nil {
+		return err
+	}
+	r.sessions[sessionID] = updated
+	return nil
+}
+
+// Finish はセッションが無いか既に終了していれば何も保存せず pgx.ErrNoRows を返す。
+func (r *HueSessionRepository) Finish(ctx context.Context, session domain.HueSession, record domain.HueRecord) error {
+	if _, ok := session.FinishedAt(); !ok {
+		return domain.ErrInvalidHueSession
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sessions[session.ID()]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+	if _, finished := stored.FinishedAt(); finished {
+		return pgx.ErrNoRows
+	}
+
+	if err := r.hues.Save(ctx, record); err != nil {
+		return err
+	}
+	r.sessions[session.ID()] = session
+	return nil
+}
+
+// Funnel は PostgreSQL 実装と同じく、now 時点の状態と回答数、単語ごとの回答時間の中央値 (下側) を集計する。
+func (r *HueSessionRepository) Funnel(_ context.Context, now time.Time) (domain.HueSessionFunnel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make(map[domain.HueSessionStatus]int)
+	answered := make(map[int]int)
+	latencies := make(map[domain.HueWord][]time.Duration)
+	for _, session := range r.sessions {
+		statuses[session.Status(now)]++
+		answers := session.Answers()
+		answered[len(answers)]++
+		for _, a := range answers {
+			latencies[a.Word()] = append(latencies[a.Word()], a.Latency())
+		}
+	}
+
+	timings := make([]domain.HueWordTiming, 0, len(latencies))
+	for word, values := range latencies {
+		slices.Sort(values)
+		timings = append(timings, domain.NewHueWordTiming(word, len(values), values[(len(values)-1)/2]))
+	}
+	return domain.NewHueSessionFunnel(statuses, answered, timings), nil
+}
+
+func userIDOf(session domain.HueSession) uuid.UUID {
+	id, _ := session.UserID()
+	return id
+}
@@ -0,0 +1,373 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestUserRepository_UniqueConstraints(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository()
+
+	alice := buildUser(t, "alice", "alice@example.com")
+	if err := repo.Create(ctx, alice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Create(ctx, buildUser(t, "alice", "other@example.com")); !errors.Is(err, domain.ErrDuplicateUsername) {
+		t.Fatalf("expected ErrDuplicateUsername, got %v", err)
+	}
+
+	if err := repo.Create(ctx, buildUser(t, "bob", "alice@example.com")); !errors.Is(err, domain.ErrDuplicateEmail) {
+		t.Fatalf("expected ErrDuplicateEmail, got %v", err)
+	}
+
+	found, err := repo.FindByName(ctx, alice.Username())
+	if err != nil || found.ID() != alice.ID() {
+		t.Fatalf("expected to find alice by name, got %v", err)
+	}
+
+	found, err = repo.FindByEmail(ctx, alice.Email())
+	if err != nil || found.ID() != alice.ID() {
+		t.Fatalf("expected to find alice by email, got %v", err)
+	}
+
+	if _, err := repo.FindByID(ctx, uuid.New()); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows, got %v", err)
+	}
+}
+
+func TestUserRepository_SetRoleAndDisabled(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository()
+	stale := buildUser(t, "alice", "alice@example.com")
+	if err := repo.Create(ctx, stale); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+
+	if _, err := repo.SetDisabled(ctx, stale.ID(), true, now); err != nil {
+		t.Fatalf("disable error: %v", err)
+	}
+	// stale を読み込んだ後の無効化を、ロールの変更で元に戻さない。
+	updated, err := repo.SetRole(ctx, stale.ID(), domain.UserRoleAdmin, now)
+	if err != nil {
+		t.Fatalf("set role error: %v", err)
+	}
+	if updated.Role() != domain.UserRoleAdmin || !updated.IsDisabled() {
+		t.Fatalf("expected an admin that stays disabled, got role %s disabled=%v", updated.Role(), updated.IsDisabled())
+	}
+
+	enabled, err := repo.SetDisabled(ctx, stale.ID(), false, now)
+	if err != nil || enabled.IsDisabled() || enabled.Role() != domain.UserRoleAdmin {
+		t.Fatalf("expected an enabled admin, got role %s disabled=%v (%v)", enabled.Role(), enabled.IsDisabled(), err)
+	}
+
+	if _, err := repo.SetRole(ctx, uuid.New(), domain.UserRoleAdmin, now); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows, got %v", err)
+	}
+}
+
+func TestUserRepository_UpdatePassword(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository()
+
+	alice := buildUser(t, "alice", "alice@example.com")
+	if err := repo.Create(ctx, alice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rehashed, _ := domain.NewHashedPassword("$argon2id$rehashed")
+	stale, _ := domain.NewHashedPassword("$2a$04$stale")
+
+	if err := repo.UpdatePassword(ctx, alice.ID(), stale, rehashed); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows for a changed hash, got %v", err)
+	}
+	if err := repo.UpdatePassword(ctx, alice.ID(), alice.HashedPassword(), rehashed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, _ := repo.FindByID(ctx, alice.ID())
+	if found.HashedPassword() != rehashed || !found.UpdatedAt().Equal(alice.UpdatedAt()) {
+		t.Fatalf("expected only the hash to change, got %q at %s", found.HashedPassword(), found.UpdatedAt())
+	}
+}
+
+func TestPasswordResetRepository_Redeem(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository()
+	resets := NewPasswordResetRepository(users)
+	now := time.Now()
+
+	alice := buildUser(t, "alice", "alice@example.com")
+	if err := users.Create(ctx, alice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	create := func(userID uuid.UUID) domain.PasswordReset {
+		t.Helper()
+		token, _ := domain.NewOneTimeToken()
+		reset, err := domain.NewPasswordReset(userID, token, now, time.Hour)
+		if err != nil {
+			t.Fatalf("reset error: %v", err)
+		}
+		if err := resets.Create(ctx, reset); err != nil {
+			t.Fatalf("create error: %v", err)
+		}
+		return reset
+	}
+	replacement, _ := domain.NewHashedPassword("$argon2id$replacement")
+
+	// パスワードを置き換えられなければ、申請も使用済みにしない。
+	orphan := create(uuid.New())
+	if _, err := resets.Redeem(ctx, orphan.ID(), replacement, now); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows for a missing user, got %v", err)
+	}
+	found, _ := resets.FindByID(ctx, orphan.ID())
+	if _, used := found.UsedAt(); used {
+		t.Fatal("expected the reset to stay unused")
+	}
+
+	reset := create(alice.ID())
+	updated, err := resets.Redeem(ctx, reset.ID(), replacement, now)
+	if err != nil || updated.HashedPassword() != replacement {
+		t.Fatalf("expected the password to be replaced, got %q (%v)", updated.HashedPassword(), err)
+	}
+	found, _ = resets.FindByID(ctx, reset.ID())
+	if _, used := found.UsedAt(); !used {
+		t.Fatal("expected the reset to be used")
+	}
+	if _, err := resets.Redeem(ctx, reset.ID(), replacement, now); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows for a used reset, got %v", err)
+	}
+}
+
+func TestHueRepository_FindPage(t *testing.T) {
+	ctx := context.Background()
+	repo := NewHueRepository()
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tick := 0
+	repo.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Second)
+	}
+
+	var saved []domain.HueRecord
+	for i := 0; i < 5; i++ {
+		record := buildHueRecord(t)
+		if err := repo.Save(ctx, record); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+		saved = append(saved, record)
+	}
+
+	first, _ := domain.NewRecordPageRequest(domain.RecordFilter{}, domain.RecordCursor{}, 2, true)
+	page, err := repo.FindPage(ctx, first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Records()) != 2 || page.Records()[0].ID() != saved[0].ID() || page.Records()[1].ID() != saved[1].ID() {
+		t.Fatalf("expected records 0..1 in insertion order")
+	}
+	if total, ok := page.Total(); !ok || total != 5 {
+		t.Fatalf("expected total 5, got %d (%v)", total, ok)
+	}
+
+	// 取得後に挿入されたレコードがあっても、次ページは前ページの続きから始まる。
+	if err := repo.Save(ctx, buildHueRecord(t)); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	second, _ := domain.NewRecordPageRequest(domain.RecordFilter{}, page.Next(), 3, false)
+	page, err = repo.FindPage(ctx, second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Records()) != 3 || page.Records()[0].ID() != saved[2].ID() {
+		t.Fatalf("expected page to resume after record 1")
+	}
+	if _, ok := page.Total(); ok {
+		t.Fatalf("total should not be computed unless requested")
+	}
+
+	last, _ := domain.NewRecordPageRequest(domain.RecordFilter{}, page.Next(), 10, false)
+	page, err = repo.FindPage(ctx, last)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Records()) != 1 || !page.Next().IsZero() {
+		t.Fatalf("expected final page with 1 record and no next cursor, got %d", len(page.Records()))
+	}
+}
+
+func TestHueRepository_FindPageFilter(t *testing.T) {
+	ctx := context.Background()
+	repo := NewHueRepository()
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tick := 0
+	repo.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Hour)
+	}
+
+	for _, raw := range []struct {
+		name    string
+		choices map[string]string
+	}{
+		{"alice", map[string]string{"孤独": "紫", "夜": "黒"}},
+		{"malice", map[string]string{"孤独": "紫"}},
+		{"alex", map[string]string{"孤独": "青"}},
+		{"alina", map[string]string{"孤独": "紫"}},
+	} {
+		record, err := domain.NewHueRecordFromRaw(raw.name, raw.choices)
+		if err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+		if err := repo.Save(ctx, record); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+
+	// 1 件目 (alice) は created_from より前なので除外される。
+	filter, err := domain.NewRecordFilter(base.Add(90*time.Minute), time.Time{}, "ali", domain.NameMatchContains, map[string]string{"孤独": "紫"})
+	if err != nil {
+		t.Fatalf("filter error: %v", err)
+	}
+	req, _ := domain.NewRecordPageRequest(filter, domain.RecordCursor{}, 1, true)
+	page, err := repo.FindPage(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Records()) != 1 || page.Records()[0].Name().String() != "malice" {
+		t.Fatalf("expected malice first, got %v", page.Records())
+	}
+	if total, _ := page.Total(); total != 2 {
+		t.Fatalf("expected 2 matching records, got %d", total)
+	}
+
+	next, _ := domain.NewRecordPageRequest(filter, page.Next(), 1, false)
+	page, err = repo.FindPage(ctx, next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Records()) != 1 || page.Records()[0].Name().String() != "alina" || !page.Next().IsZero() {
+		t.Fatalf("expected alina on the last page, got %v", page.Records())
+	}
+}
+
+func TestHueRepository_Export(t *testing.T) {
+	ctx := context.Background()
+	repo := NewHueRepository()
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tick := 0
+	repo.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Second)
+	}
+
+	var saved []domain.HueRecord
+	for _, raw := range []map[string]string{{"夜": "黒"}, {"海": "青", "夜": "紫"}} {
+		record, err := domain.NewHueRecordFromRaw("Tester", raw)
+		if err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+		if err := repo.Save(ctx, record); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+		saved = append(saved, record)
+	}
+
+	var (
+		words    []domain.HueWord
+		exported []domain.HueRecord
+	)
+	err := repo.Export(ctx, func(w []domain.HueWord) error {
+		words = w
+		return nil
+	}, func(record domain.HueRecord) error {
+		exported = append(exported, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(words) != 2 || words[0] != "夜" || words[1] != "海" {
+		t.Fatalf("unexpected words: %v", words)
+	}
+	if len(exported) != 2 || exported[0].ID() != saved[0].ID() || exported[1].ID() != saved[1].ID() {
+		t.Fatalf("expected records in insertion order")
+	}
+	if !exported[0].CreatedAt().Equal(base.Add(time.Second)) {
+		t.Fatalf("expected created_at to be assigned on save, got %v", exported[0].CreatedAt())
+	}
+}
+
+func TestLoginSessionRepository_DeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	repo := NewLoginSessionRepository()
+	userID := uuid.New()
+	issued := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		token, _ := domain.NewLoginSessionToken()
+		session, err := domain.NewLoginSession(userID, token, issued.Add(time.Duration(i)*time.Minute), domain.DefaultLoginSessionTTL)
+		if err != nil {
+			t.Fatalf("session error: %v", err)
+		}
+		if err := repo.Create(ctx, session); err != nil {
+			t.Fatalf("create error: %v", err)
+		}
+	}
+
+	now := issued.Add(domain.DefaultLoginSessionTTL + 90*time.Second)
+	removed, err := repo.DeleteExpired(ctx, now, 1)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected 1 removed with limit, got %d (%v)", removed, err)
+	}
+
+	removed, err = repo.DeleteExpired(ctx, now, 10)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected the second expired session removed, got %d (%v)", removed, err)
+	}
+
+	removed, err = repo.DeleteByUserID(ctx, userID)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected the live session to remain until DeleteByUserID, got %d (%v)", removed, err)
+	}
+}
+
+func buildUser(t *testing.T, name, email string) domain.User {
+	t.Helper()
+	n, err := domain.NewName(name)
+	if err != nil {
+		t.Fatalf("name error: %v", err)
+	}
+	e, err := domain.NewEmail(email)
+	if err != nil {
+		t.Fatalf("email error: %v", err)
+	}
+	p, err := domain.NewHashedPassword("hashed")
+	if err != nil {
+		t.Fatalf("password error: %v", err)
+	}
+	user, err := domain.NewUser(n, e, p, domain.UserRoleUser, time.Now())
+	if err != nil {
+		t.Fatalf("user error: %v", err)
+	}
+	return user
+}
+
+func buildHueRecord(t *testing.T) domain.HueRecord {
+	t.Helper()
+	record, err := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	if err != nil {
+		t.Fatalf("record error: %v", err)
+	}
+	return record
+}
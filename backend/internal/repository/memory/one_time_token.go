@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// oneTimeTokenSource は domain.PasswordReset と domain.EmailVerification に共通する、保存する値の読み出し口。
+type oneTimeTokenSource interface {
+	ID() uuid.UUID
+	UserID() uuid.UUID
+	TokenHash() string
+	ExpiresAt() time.Time
+	CreatedAt() time.Time
+}
+
+// oneTimeTokenRow は申請 1 件分の値。未使用なら usedAt はゼロ値。
+type oneTimeTokenRow struct {
+	id        uuid.UUID
+	userID    uuid.UUID
+	tokenHash string
+	expiresAt time.Time
+	createdAt time.Time
+	usedAt    time.Time
+}
+
+// oneTimeTokenTable は password_resets と email_verifications に共通する、使い捨てトークンの申請の保存先。
+type oneTimeTokenTable struct {
+	mu   sync.Mutex
+	rows map[uuid.UUID]oneTimeTokenRow
+}
+
+func newOneTimeTokenTable() *oneTimeTokenTable {
+	return &oneTimeTokenTable{rows: make(map[uuid.UUID]oneTimeTokenRow)}
+}
+
+func (t *oneTimeTokenTable) create(record oneTimeTokenSource) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.rows[record.ID()]; ok {
+		return errDuplicateKey
+	}
+	t.rows[record.ID()] = oneTimeTokenRow{
+		id:        record.ID(),
+		userID:    record.UserID(),
+		tokenHash: record.TokenHash(),
+		expiresAt: record.ExpiresAt(),
+		createdAt: record.CreatedAt(),
+	}
+	return nil
+}
+
+// findByID は見つからなければ pgx.ErrNoRows を返す。
+func (t *oneTimeTokenTable) findByID(id uuid.UUID) (oneTimeTokenRow, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	row, ok := t.rows[id]
+	if !ok {
+		return oneTimeTokenRow{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+// markUsed は未使用かつ期限内の申請だけを使用済みにし、それ以外は pgx.ErrNoRows を返す。
+func (t *oneTimeTokenTable) markUsed(id uuid.UUID, usedAt time.Time) error {
+	return t.markUsedWith(id, usedAt, nil)
+}
+
+// markUsedWith は markUsed と同じ条件で、使用済みにする前に申請したユーザーで apply を呼ぶ。apply が失敗すれば使用済みにしない。
+// ロックを持ったまま呼ぶため、同じ申請で同時に確定されても apply が成功するのは 1 回だけになる。
+func (t *oneTimeTokenTable) markUsedWith(id uuid.UUID, usedAt time.Time, apply func(userID uuid.UUID) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	row, ok := t.rows[id]
+	if !ok || !row.usedAt.IsZero() || !usedAt.Before(row.expiresAt) {
+		return pgx.ErrNoRows
+	}
+	if apply != nil {
+		if err := apply(row.userID); err != nil {
+			return err
+		}
+	}
+	row.usedAt = usedAt
+	t.rows[id] = row
+	return nil
+}
+
+func (t *oneTimeTokenTable) deleteByUserID(userID uuid.UUID) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var removed int64
+	for id, row := range t.rows {
+		if row.userID == userID {
+			delete(t.rows, id)
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PaletteRepository は palettes のインメモリ実装。
+type PaletteRepository struct {
+	mu       sync.RWMutex
+	palettes []domain.Palette
+}
+
+func NewPaletteRepository() *PaletteRepository {
+	return &PaletteRepository{}
+}
+
+func (r *PaletteRepository) Create(_ context.Context, palette domain.Palette) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index(palette.Version()) >= 0 || slices.ContainsFunc(r.palettes, func(p domain.Palette) bool { return p.ID() == palette.ID() }) {
+		return errDuplicateKey
+	}
+	if palette.IsActive() && slices.ContainsFunc(r.palettes, domain.Palette.IsActive) {
+		return errDuplicateKey
+	}
+	r.palettes = append(r.palettes, palette)
+	slices.SortFunc(r.palettes, func(a, b domain.Palette) int { return a.Version() - b.Version() })
+	return nil
+}
+
+// FindByVersion は見つからなければ pgx.ErrNoRows を返す。
+func (r *PaletteRepository) FindByVersion(_ context.Context, version int) (domain.Palette, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.index(version)
+	if i < 0 {
+		return domain.Palette{}, pgx.ErrNoRows
+	}
+	return r.palettes[i], nil
+}
+
+// FindActive は有効なパレットを返す。無ければ pgx.ErrNoRows を返す。
+func (r *PaletteRepository) FindActive(_ context.Context) (domain.Palette, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.palettes, domain.Palette.IsActive)
+	if i < 0 {
+		return domain.Palette{}, pgx.ErrNoRows
+	}
+	return r.palettes[i], nil
+}
+
+// List はすべての版を版の昇順で返す。
+func (r *PaletteRepository) List(_ context.Context) ([]domain.Palette, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.palettes), nil
+}
+
+// Activate は version の版を有効にし、他の版をすべて無効にする。
+func (r *PaletteRepository) Activate(_ context.Context, version int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target := r.index(version)
+	if target < 0 {
+		return pgx.ErrNoRows
+	}
+	for i, p := range r.palettes {
+		switch {
+		case i == target:
+			r.palettes[i] = p.Activate()
+		case p.IsActive():
+			inactive, err := domain.NewPaletteFromPersistence(p.ID(), p.Version(), p.Colors(), false, p.CreatedAt())
+			if err != nil {
+				return err
+			}
+			r.palettes[i] = inactive
+		}
+	}
+	return nil
+}
+
+func (r *PaletteRepository) index(version int) int {
+	return slices.IndexFunc(r.palettes, func(p domain.Palette) bool { return p.Version() == version })
+}
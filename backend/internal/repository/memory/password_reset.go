@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetRepository は password_resets のインメモリ実装。Redeem でパスワードを置き換えるため users を持つ。
+type PasswordResetRepository struct {
+	tokens *oneTimeTokenTable
+	users  *UserRepository
+}
+
+func NewPasswordResetRepository(users *UserRepository) *PasswordResetRepository {
+	return &PasswordResetRepository{tokens: newOneTimeTokenTable(), users: users}
+}
+
+func (r *PasswordResetRepository) Create(_ context.Context, reset domain.PasswordReset) error {
+	return r.tokens.create(reset)
+}
+
+// FindByID は見つからなければ pgx.ErrNoRows を返す。
+func (r *PasswordResetRepository) FindByID(_ context.Context, id uuid.UUID) (domain.PasswordReset, error) {
+	row, err := r.tokens.findByID(id)
+	if err != nil {
+		return domain.PasswordReset{}, err
+	}
+	return domain.NewPasswordResetFromPersistence(row.id, row.userID, row.tokenHash, row.expiresAt, row.createdAt, row.usedAt)
+}
+
+// Redeem は PostgreSQL 実装と同じく、未使用かつ期限内の申請を使用済みにしてパスワードを置き換える。
+// 申請が使えないかユーザーがいなければ、どちらも変えずに pgx.ErrNoRows を返す。
+func (r *PasswordResetRepository) Redeem(ctx context.Context, id uuid.UUID, hashed domain.HashedPassword, usedAt time.Time) (domain.User, error) {
+	var user domain.User
+	err := r.tokens.markUsedWith(id, usedAt, func(userID uuid.UUID) error {
+		var err error
+		user, err = r.users.SetPassword(ctx, userID, hashed, usedAt)
+		return err
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func (r *PasswordResetRepository) DeleteByUserID(_ context.Context, userID uuid.UUID) (int64, error) {
+	return r.tokens.deleteByUserID(userID), nil
+}
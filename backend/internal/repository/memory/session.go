@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// LoginSessionRepository は login_sessions のインメモリ実装。
+type LoginSessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[uuid.UUID]domain.LoginSession
+}
+
+func NewLoginSessionRepository() *LoginSessionRepository {
+	return &LoginSessionRepository{sessions: make(map[uuid.UUID]domain.LoginSession)}
+}
+
+func (r *LoginSessionRepository) Create(_ context.Context, session domain.LoginSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[session.ID()]; ok {
+		return errDuplicateKey
+	}
+
+	r.sessions[session.ID()] = session
+	return nil
+}
+
+// FindByID は見つからなければ pgx.ErrNoRows を返す。
+func (r *LoginSessionRepository) FindByID(_ context.Context, id uuid.UUID) (domain.LoginSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return domain.LoginSession{}, pgx.ErrNoRows
+	}
+	return session, nil
+}
+
+// Rotate は oldID が無ければ何も保存せず pgx.ErrNoRows を返す。
+func (r *LoginSessionRepository) Rotate(_ context.Context, oldID uuid.UUID, replacement domain.LoginSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[oldID]; !ok {
+		return pgx.ErrNoRows
+	}
+	if _, ok := r.sessions[replacement.ID()]; ok {
+		return errDuplicateKey
+	}
+
+	delete(r.sessions, oldID)
+	r.sessions[replacement.ID()] = replacement
+	return nil
+}
+
+func (r *LoginSessionRepository) DeleteByID(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, id)
+	return nil
+}
+
+func (r *LoginSessionRepository) DeleteByUserID(_ context.Context, userID uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var removed int64
+	for id, session := range r.sessions {
+		if session.UserID() == userID {
+			delete(r.sessions, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// DeleteByUserIDExcept は keepID 以外の指定ユーザーのセッションを削除する。
+func (r *LoginSessionRepository) DeleteByUserIDExcept(_ context.Context, userID, keepID uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var removed int64
+	for id, session := range r.sessions {
+		if session.UserID() == userID && id != keepID {
+			delete(r.sessions, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// CountByUserID は指定ユーザーのセッション数を返す。テストで残存件数を確かめるための補助で、行は変更しない。
+func (r *LoginSessionRepository) CountByUserID(userID uuid.UUID) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, session := range r.sessions {
+		if session.UserID() == userID {
+			count++
+		}
+	}
+	return count
+}
+
+// DeleteExpired は expires_at の古い順に最大 limit 件を削除する。
+func (r *LoginSessionRepository) DeleteExpired(_ context.Context, before time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []domain.LoginSession
+	for _, session := range r.sessions {
+		if !session.ExpiresAt().After(before) {
+			expired = append(expired, session)
+		}
+	}
+
+	slices.SortFunc(expired, func(a, b domain.LoginSession) int {
+		return a.ExpiresAt().Compare(b.ExpiresAt())
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	for _, session := range expired {
+		delete(r.sessions, session.ID())
+	}
+	return int64(len(expired)), nil
+}
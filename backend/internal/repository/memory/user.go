@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// UserRepository は users テーブルの制約(username / email の一意性)を再現するインメモリ実装。
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]domain.User
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: make(map[uuid.UUID]domain.User)}
+}
+
+// FindByID は primary key でユーザーを検索し、見つからなければ pgx.ErrNoRows を返す。
+func (r *UserRepository) FindByID(_ context.Context, id uuid.UUID) (domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return domain.User{}, pgx.ErrNoRows
+	}
+	return user, nil
+}
+
+// FindByEmail はメールアドレスでユーザーを検索し、見つからなければ pgx.ErrNoRows を返す。
+func (r *UserRepository) FindByEmail(_ context.Context, email domain.Email) (domain.User, error) {
+	return r.findFirst(func(u domain.User) bool { return u.Email().String() == email.String() })
+}
+
+// FindByName は username でユーザーを検索し、見つからなければ pgx.ErrNoRows を返す。
+func (r *UserRepository) FindByName(_ context.Context, name domain.Name) (domain.User, error) {
+	return r.findFirst(func(u domain.User) bool { return u.Username().String() == name.String() })
+}
+
+// Create は username / email の重複を domain.ErrDuplicateUsername / domain.ErrDuplicateEmail として拒否する。
+func (r *UserRepository) Create(_ context.Context, user domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.ID()]; ok {
+		return errDuplicateKey
+	}
+
+	for _, existing := range r.users {
+		if existing.Username().String() == user.Username().String() {
+			return domain.ErrDuplicateUsername
+		}
+		if existing.Email().String() == user.Email().String() {
+			return domain.ErrDuplicateEmail
+		}
+	}
+
+	r.users[user.ID()] = user
+	return nil
+}
+
+// List は PostgreSQL 実装と同じく作成順に並べ、offset から limit 件と一致した全件数を返す。
+func (r *UserRepository) List(_ context.Context, q domain.UserQuery) ([]domain.User, int, error) {
+	r.mu.RLock()
+	var matched []domain.User
+	for _, user := range r.users {
+		if q.Matches(user) {
+			matched = append(matched, user)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(matched, func(a, b domain.User) int {
+		if c := a.CreatedAt().Compare(b.CreatedAt()); c != 0 {
+			return c
+		}
+		ida, idb := a.ID(), b.ID()
+		return bytes.Compare(ida[:], idb[:])
+	})
+
+	begin := min(q.Offset(), len(matched))
+	end := min(begin+q.Limit(), len(matched))
+	return slices.Clone(matched[begin:end]), len(matched), nil
+}
+
+// SetRole は保存済みのユーザーのロールだけを変え、変えた後のユーザーを返す。見つからなければ pgx.ErrNoRows を返す。
+func (r *UserRepository) SetRole(_ context.Context, id uuid.UUID, role domain.UserRole, now time.Time) (domain.User, error) {
+	return r.modify(id, func(user domain.User) (domain.User, error) {
+		return user.ChangeRole(role, now)
+	})
+}
+
+// SetDisabled は保存済みのユーザーの無効化だけを切り替える。
+func (r *UserRepository) SetDisabled(_ context.Context, id uuid.UUID, disabled bool, now time.Time) (domain.User, error) {
+	return r.modify(id, func(user domain.User) (domain.User, error) {
+		if disabled {
+			return user.Disable(now), nil
+		}
+		return user.Enable(now), nil
+	})
+}
+
+// MarkEmailVerified は保存済みのユーザーのメールアドレスを確認済みにする。
+func (r *UserRepository) MarkEmailVerified(_ context.Context, id uuid.UUID, now time.Time) (domain.User, error) {
+	return r.modify(id, func(user domain.User) (domain.User, error) {
+		return user.VerifyEmail(now), nil
+	})
+}
+
+// SetPassword は保存済みのユーザーのパスワードハッシュだけを置き換える。
+func (r *UserRepository) SetPassword(_ context.Context, id uuid.UUID, hashed domain.HashedPassword, now time.Time) (domain.User, error) {
+	return r.modify(id, func(user domain.User) (domain.User, error) {
+		return user.ChangePassword(hashed, now)
+	})
+}
+
+// modify は保存済みのユーザーに change を当てて保存し直す。呼び出し側が読み込んだ古いユーザーで上書きしないよう、常にロック中の値を使う。
+func (r *UserRepository) modify(id uuid.UUID, change func(domain.User) (domain.User, error)) (domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return domain.User{}, pgx.ErrNoRows
+	}
+	updated, err := change(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	r.users[id] = updated
+	return updated, nil
+}
+
+// UpdatePassword は保存済みのハッシュが current のときだけ replacement に置き換え、それ以外は pgx.ErrNoRows を返す。
+func (r *UserRepository) UpdatePassword(_ context.Context, id uuid.UUID, current, replacement domain.HashedPassword) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.HashedPassword().String() != current.String() {
+		return pgx.ErrNoRows
+	}
+	updated, err := user.RehashPassword(replacement)
+	if err != nil {
+		return err
+	}
+	r.users[id] = updated
+	return nil
+}
+
+// Delete はユーザーを削除し、見つからなければ pgx.ErrNoRows を返す。セッションは別リポジトリのため呼び出し側で消す。
+func (r *UserRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return pgx.ErrNoRows
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *UserRepository) findFirst(match func(domain.User) bool) (domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if match(user) {
+			return user, nil
+		}
+	}
+	return domain.User{}, pgx.ErrNoRows
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// WordSetRepository は word_sets のインメモリ実装。回答からの参照は hues で、テストセッションからの参照は sessions で確かめる。
+type WordSetRepository struct {
+	mu       sync.RWMutex
+	sets     []domain.WordSet
+	hues     *HueRepository
+	sessions *HueSessionRepository
+}
+
+// NewWordSetRepository は hues・sessions が nil なら、単語の変更や削除のときにそれぞれからの参照を確かめない。
+func NewWordSetRepository(hues *HueRepository, sessions *HueSessionRepository) *WordSetRepository {
+	return &WordSetRepository{hues: hues, sessions: sessions}
+}
+
+func (r *WordSetRepository) Create(_ context.Context, set domain.WordSet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index(set.ID()) >= 0 {
+		return errDuplicateKey
+	}
+	if set.IsActive() && slices.ContainsFunc(r.sets, domain.WordSet.IsActive) {
+		return errDuplicateKey
+	}
+	r.sets = append(r.sets, set)
+	return nil
+}
+
+// FindByID は見つからなければ pgx.ErrNoRows を返す。
+func (r *WordSetRepository) FindByID(_ context.Context, id uuid.UUID) (domain.WordSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.index(id)
+	if i < 0 {
+		return domain.WordSet{}, pgx.ErrNoRows
+	}
+	return r.sets[i], nil
+}
+
+// FindActive は有効な単語セットを返す。無ければ pgx.ErrNoRows を返す。
+func (r *WordSetRepository) FindActive(_ context.Context) (domain.WordSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.sets, domain.WordSet.IsActive)
+	if i < 0 {
+		return domain.WordSet{}, pgx.ErrNoRows
+	}
+	return r.sets[i], nil
+}
+
+// List はすべての単語セットを作成順に返す。
+func (r *WordSetRepository) List(_ context.Context) ([]domain.WordSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.sets), nil
+}
+
+// Update は名前・単語・更新時刻を保存し、有効かどうかは保存済みのものを残す。
+// 単語を変える場合、回答かテストセッションから参照されていれば domain.ErrWordSetInUse を返す。
+func (r *WordSetRepository) Update(ctx context.Context, set domain.WordSet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(set.ID())
+	if i < 0 {
+		return pgx.ErrNoRows
+	}
+	if !set.SameWords(r.sets[i]) {
+		used, err := r.used(ctx, set.ID())
+		if err != nil {
+			return err
+		}
+		if used {
+			return domain.ErrWordSetInUse
+		}
+	}
+	updated, err := domain.NewWordSetFromPersistence(set.ID(), set.Name(), wordStrings(set.Words()), r.sets[i].IsActive(), set.CreatedAt(), set.UpdatedAt())
+	if err != nil {
+		return err
+	}
+	r.sets[i] = updated
+	return nil
+}
+
+// Activate は id のセットを有効にし、他のセットをすべて無効にする。
+func (r *WordSetRepository) Activate(_ context.Context, id uuid.UUID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target := r.index(id)
+	if target < 0 {
+		return pgx.ErrNoRows
+	}
+	for i, set := range r.sets {
+		switch {
+		case i == target:
+			r.sets[i] = set.Activate(now)
+		case set.IsActive():
+			inactive, err := domain.NewWordSetFromPersistence(set.ID(), set.Name(), wordStrings(set.Words()), false, set.CreatedAt(), now)
+			if err != nil {
+				return err
+			}
+			r.sets[i] = inactive
+		}
+	}
+	return nil
+}
+
+// Delete は回答かテストセッションから参照されていれば domain.ErrWordSetInUse、対象が無ければ pgx.ErrNoRows を返す。
+func (r *WordSetRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, err := r.used(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return domain.ErrWordSetInUse
+	}
+
+	i := r.index(id)
+	if i < 0 {
+		return pgx.ErrNoRows
+	}
+	r.sets = slices.Delete(r.sets, i, i+1)
+	return nil
+}
+
+// used は id のセットが回答かテストセッションから参照されているかを返す。
+func (r *WordSetRepository) used(ctx context.Context, id uuid.UUID) (bool, error) {
+	if r.hues != nil {
+		if used, err := r.hues.HasWordSet(ctx, id); err != nil || used {
+			return used, err
+		}
+	}
+	if r.sessions != nil {
+		count, err := r.sessions.CountByWordSet(ctx, id)
+		return count > 0, err
+	}
+	return false, nil
+}
+
+func (r *WordSetRepository) index(id uuid.UUID) int {
+	return slices.IndexFunc(r.sets, func(set domain.WordSet) bool { return set.ID() == id })
+}
+
+func wordStrings(words []domain.HueWord) []string {
+	values := make([]string, len(words))
+	for i, word := range words {
+		values[i] = string(word)
+	}
+	return values
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// oneTimeTokenSource は domain.PasswordReset と domain.EmailVerification に共通する、保存する列の読み出し口。
+type oneTimeTokenSource interface {
+	ID() uuid.UUID
+	UserID() uuid.UUID
+	TokenHash() string
+	ExpiresAt() time.Time
+	CreatedAt() time.Time
+}
+
+// oneTimeTokenRow は申請テーブルの 1 行。未使用なら usedAt はゼロ値。
+type oneTimeTokenRow struct {
+	id        uuid.UUID
+	userID    uuid.UUID
+	tokenHash string
+	expiresAt time.Time
+	createdAt time.Time
+	usedAt    time.Time
+}
+
+// oneTimeTokenTable は password_resets と email_verifications のように、同じ列を持つ使い捨てトークンの申請テーブルを扱う。
+// table は SQL に埋め込むため、定数だけを渡す。
+type oneTimeTokenTable struct {
+	db    *pgxpool.Pool
+	table string
+}
+
+func (t oneTimeTokenTable) create(ctx context.Context, record oneTimeTokenSource) error {
+	query := `
+		INSERT INTO ` + t.table + ` (id, user_id, token, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := t.db.Exec(ctx, query,
+		record.ID(),
+		record.UserID(),
+		record.TokenHash(),
+		record.ExpiresAt(),
+		record.CreatedAt(),
+	)
+	return err
+}
+
+// findByID はトークンのセレクタ(主キー)で 1 行を引き、見つからなければ pgx.ErrNoRows を返す。
+func (t oneTimeTokenTable) findByID(ctx context.Context, id uuid.UUID) (oneTimeTokenRow, error) {
+	query := `
+		SELECT id, user_id, token, expires_at, created_at, used_at
+		FROM ` + t.table + `
+		WHERE id = $1
+	`
+
+	var (
+		row    oneTimeTokenRow
+		usedAt *time.Time
+	)
+	if err := t.db.QueryRow(ctx, query, id).Scan(&row.id, &row.userID, &row.tokenHash, &row.expiresAt, &row.createdAt, &usedAt); err != nil {
+		return oneTimeTokenRow{}, err
+	}
+	if usedAt != nil {
+		row.usedAt = *usedAt
+	}
+	return row, nil
+}
+
+// markUsed は未使用かつ期限内の申請だけを使用済みにする。条件を満たす行が無ければ pgx.ErrNoRows を返すため、
+// 同じトークンで同時に確定されても成功するのは 1 回だけになる。
+func (t oneTimeTokenTable) markUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	_, err := t.markUsedIn(ctx, t.db, id, usedAt)
+	return err
+}
+
+// markUsedIn は markUsed を db で行い、申請したユーザーを返す。トランザクションの中で使うときは tx を渡す。
+func (t oneTimeTokenTable) markUsedIn(ctx context.Context, db queryRower, id uuid.UUID, usedAt time.Time) (uuid.UUID, error) {
+	query := `
+		UPDATE ` + t.table + `
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING user_id
+	`
+
+	var userID uuid.UUID
+	if err := db.QueryRow(ctx, query, id, usedAt.UTC()).Scan(&userID); err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+func (t oneTimeTokenTable) deleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `
+		DELETE FROM ` + t.table + `
+		WHERE user_id = $1
+	`
+
+	tag, err := t.db.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PaletteRepository は palettes テーブルを読み書きする。色は表示順の JSON 配列で 1 列に持つ。
+type PaletteRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPaletteRepository(db *pgxpool.Pool) *PaletteRepository {
+	return &PaletteRepository{db: db}
+}
+
+// paletteColorRow は palettes.colors の 1 要素。
+type paletteColorRow struct {
+	ID    string            `json:"id"`
+	Names map[string]string `json:"names"`
+	RGB   string            `json:"rgb"`
+	Lab   struct {
+		L float64 `json:"l"`
+		A float64 `json:"a"`
+		B float64 `json:"b"`
+	} `json:"lab"`
+}
+
+func (r *PaletteRepository) Create(ctx context.Context, palette domain.Palette) error {
+	const query = `
+		INSERT INTO palettes (id, version, colors, active, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	colors, err := marshalPaletteColors(palette.Colors())
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, palette.ID(), palette.Version(), colors, palette.IsActive(), palette.CreatedAt())
+	return err
+}
+
+// FindByVersion は見つからなければ pgx.ErrNoRows を返す。
+func (r *PaletteRepository) FindByVersion(ctx context.Context, version int) (domain.Palette, error) {
+	const query = `
+		SELECT id, version, colors, active, created_at
+		FROM palettes
+		WHERE version = $1
+	`
+
+	return scanPalette(r.db.QueryRow(ctx, query, version))
+}
+
+// FindActive は有効なパレットを返す。無ければ pgx.ErrNoRows を返す。
+func (r *PaletteRepository) FindActive(ctx context.Context) (domain.Palette, error) {
+	const query = `
+		SELECT id, version, colors, active, created_at
+		FROM palettes
+		WHERE active
+	`
+
+	return scanPalette(r.db.QueryRow(ctx, query))
+}
+
+// List はすべての版を版の昇順で返す。
+func (r *PaletteRepository) List(ctx context.Context) ([]domain.Palette, error) {
+	const query = `
+		SELECT id, version, colors, active, created_at
+		FROM palettes
+		ORDER BY version
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Palette, error) {
+		return scanPalette(row)
+	})
+}
+
+// Activate は同じトランザクションで他の版を無効にしてから version の版を有効にする。対象が無ければ pgx.ErrNoRows を返す。
+func (r *PaletteRepository) Activate(ctx context.Context, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `UPDATE palettes SET active = FALSE WHERE active AND version <> $1`, version); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE palettes SET active = TRUE WHERE version = $1`, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanPalette(row rowScanner) (domain.Palette, error) {
+	var (
+		id        uuid.UUID
+		version   int
+		colorJSON []byte
+		active    bool
+		createdAt time.Time
+	)
+
+	if err := row.Scan(&id, &version, &colorJSON, &active, &createdAt); err != nil {
+		return domain.Palette{}, err
+	}
+
+	colors, err := unmarshalPaletteColors(colorJSON)
+	if err != nil {
+		return domain.Palette{}, err
+	}
+
+	return domain.NewPaletteFromPersistence(id, version, colors, active, createdAt)
+}
+
+func marshalPaletteColors(colors []domain.PaletteColor) ([]byte, error) {
+	rows := make([]paletteColorRow, len(colors))
+	for i, c := range colors {
+		rows[i].ID = string(c.ID())
+		rows[i].Names = c.Names()
+		rows[i].RGB = c.RGB().Hex()
+		lab := c.Lab()
+		rows[i].Lab.L, rows[i].Lab.A, rows[i].Lab.B = lab.L, lab.A, lab.B
+	}
+	return json.Marshal(rows)
+}
+
+func unmarshalPaletteColors(raw []byte) ([]domain.PaletteColor, error) {
+	var rows []paletteColorRow
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil, err
+	}
+
+	colors := make([]domain.PaletteColor, len(rows))
+	for i, row := range rows {
+		rgb, err := domain.ParseRGB(row.RGB)
+		if err != nil {
+			return nil, err
+		}
+		colors[i], err = domain.NewPaletteColor(row.ID, row.Names, rgb, domain.Lab{L: row.Lab.L, A: row.Lab.A, B: row.Lab.B})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return colors, nil
+}
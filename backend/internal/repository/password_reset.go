@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PasswordResetRepository は password_resets テーブルを扱う。
+type PasswordResetRepository struct {
+	tokens oneTimeTokenTable
+}
+
+func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{tokens: oneTimeTokenTable{db: db, table: "password_resets"}}
+}
+
+// Create は申請を永続化する。
+func (r *PasswordResetRepository) Create(ctx context.Context, reset domain.PasswordReset) error {
+	return r.tokens.create(ctx, reset)
+}
+
+// FindByID はトークンのセレクタ(主キー)で 1 行を引き、見つからなければ pgx.ErrNoRows を返す。
+func (r *PasswordResetRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.PasswordReset, error) {
+	row, err := r.tokens.findByID(ctx, id)
+	if err != nil {
+		return domain.PasswordReset{}, err
+	}
+	return domain.NewPasswordResetFromPersistence(row.id, row.userID, row.tokenHash, row.expiresAt, row.createdAt, row.usedAt)
+}
+
+// Redeem は未使用かつ期限内の申請を使用済みにし、同じトランザクションで申請したユーザーのパスワードを hashed に置き換えて、
+// 置き換えた後のユーザーを返す。申請が使えないかユーザーがいなければ、どちらも変えずに pgx.ErrNoRows を返す。
+func (r *PasswordResetRepository) Redeem(ctx context.Context, id uuid.UUID, hashed domain.HashedPassword, usedAt time.Time) (domain.User, error) {
+	tx, err := r.tokens.db.Begin(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	userID, err := r.tokens.markUsedIn(ctx, tx, id, usedAt)
+	if err != nil {
+		return domain.User{}, err
+	}
+	user, err := setUserPassword(ctx, tx, userID, hashed, usedAt)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// DeleteByUserID は指定ユーザーの申請をすべて削除し、削除件数を返す。
+func (r *PasswordResetRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.tokens.deleteByUserID(ctx, userID)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // LoginSessionRepository は login_sessions テーブルを扱う。
@@ -22,12 +21,44 @@ func NewLoginSessionRepository(db *pgxpool.Pool) *LoginSessionRepository {
 
 // Create はセッションを永続化する。
 func (r *LoginSessionRepository) Create(ctx context.Context, session domain.LoginSession) error {
+	return insertLoginSession(ctx, r.db, session)
+}
+
+// Rotate は oldID のセッションを削除し、同じトランザクションで replacement を保存する。
+// oldID が既に無ければ何も保存せず pgx.ErrNoRows を返す。
+func (r *LoginSessionRepository) Rotate(ctx context.Context, oldID uuid.UUID, replacement domain.LoginSession) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const query = `
+		DELETE FROM login_sessions
+		WHERE id = $1
+	`
+	tag, err := tx.Exec(ctx, query, oldID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() != 1 {
+		return pgx.ErrNoRows
+	}
+
+	if err := insertLoginSession(ctx, tx, replacement); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func insertLoginSession(ctx context.Context, db execer, session domain.LoginSession) error {
 	const query = `
 		INSERT INTO login_sessions (id, user_id, token, expires_at, created_at)
 		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := db.Exec(ctx, query,
 		session.ID(),
 		session.UserID(),
 		session.HashedToken(),
@@ -37,47 +68,77 @@ func (r *LoginSessionRepository) Create(ctx context.Context, session domain.Logi
 	return err
 }
 
-// Find は指定ユーザーのセッション群から入力トークンと一致するハッシュを探索する。
-func (r *LoginSessionRepository) Find(ctx context.Context, userID uuid.UUID, token domain.LoginSessionToken) (domain.LoginSession, error) {
+// FindByID はトークンのセレクタ(主キー)で 1 行だけを引き、見つからなければ pgx.ErrNoRows を返す。
+// 検証子の照合は domain.LoginSession.Verify に任せる。
+func (r *LoginSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.LoginSession, error) {
 	const query = `
 		SELECT id, user_id, token, expires_at, created_at
 		FROM login_sessions
+		WHERE id = $1
+	`
+
+	row := r.db.QueryRow(ctx, query, id)
+	return scanLoginSession(row)
+}
+
+// DeleteByID は指定したセッションを削除する。
+func (r *LoginSessionRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		DELETE FROM login_sessions
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// DeleteByUserID は指定ユーザーの全セッションを削除し、削除件数を返す。
+func (r *LoginSessionRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	const query = `
+		DELETE FROM login_sessions
 		WHERE user_id = $1
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	tag, err := r.db.Exec(ctx, query, userID)
 	if err != nil {
-		return domain.LoginSession{}, err
+		return 0, err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		session, err := scanLoginSession(rows)
-		if err != nil {
-			return domain.LoginSession{}, err
-		}
+	return tag.RowsAffected(), nil
+}
 
-		if bcrypt.CompareHashAndPassword([]byte(session.HashedToken()), []byte(token.String())) == nil {
-			return session, nil
-		}
-	}
+// DeleteByUserIDExcept は keepID 以外の指定ユーザーのセッションを削除し、削除件数を返す。
+func (r *LoginSessionRepository) DeleteByUserIDExcept(ctx context.Context, userID, keepID uuid.UUID) (int64, error) {
+	const query = `
+		DELETE FROM login_sessions
+		WHERE user_id = $1 AND id <> $2
+	`
 
-	if err := rows.Err(); err != nil {
-		return domain.LoginSession{}, err
+	tag, err := r.db.Exec(ctx, query, userID, keepID)
+	if err != nil {
+		return 0, err
 	}
-
-	return domain.LoginSession{}, pgx.ErrNoRows
+	return tag.RowsAffected(), nil
 }
 
-// DeleteByID は指定したセッションを削除する。
-func (r *LoginSessionRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+// DeleteExpired は before 時点で期限切れのセッションを expires_at 順に最大 limit 件削除し、削除件数を返す。
+// login_sessions_expires_at_idx を使って古いものから削除する。
+func (r *LoginSessionRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
 	const query = `
 		DELETE FROM login_sessions
-		WHERE id = $1
+		WHERE id IN (
+			SELECT id
+			FROM login_sessions
+			WHERE expires_at <= $1
+			ORDER BY expires_at
+			LIMIT $2
+		)
 	`
 
-	_, err := r.db.Exec(ctx, query, id)
-	return err
+	tag, err := r.db.Exec(ctx, query, before.UTC(), limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
 }
 
 func scanLoginSession(row rowScanner) (domain.LoginSession, error) {
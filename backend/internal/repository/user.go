@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"backend/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -24,7 +26,7 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 // FindByID は primary key でユーザーを検索する。
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.User, error) {
 	const query = `
-		SELECT id, username, email, hashed_password, role, created_at, updated_at
+		SELECT id, username, email, hashed_password, role, created_at, updated_at, disabled_at, email_verified_at, password_legacy_trim
 		FROM users
 		WHERE id = $1
 	`
@@ -36,7 +38,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Use
 // FindByEmail はメールアドレスでユーザーを検索し、見つからなければ pgx.ErrNoRows を返す。
 func (r *UserRepository) FindByEmail(ctx context.Context, email domain.Email) (domain.User, error) {
 	const query = `
-		SELECT id, username, email, hashed_password, role, created_at, updated_at
+		SELECT id, username, email, hashed_password, role, created_at, updated_at, disabled_at, email_verified_at, password_legacy_trim
 		FROM users
 		WHERE email = $1
 	`
@@ -48,7 +50,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email domain.Email) (d
 // FindByName は username 列をユニークキーとして検索する。
 func (r *UserRepository) FindByName(ctx context.Context, name domain.Name) (domain.User, error) {
 	const query = `
-		SELECT id, username, email, hashed_password, role, created_at, updated_at
+		SELECT id, username, email, hashed_password, role, created_at, updated_at, disabled_at, email_verified_at, password_legacy_trim
 		FROM users
 		WHERE username = $1
 	`
@@ -60,8 +62,8 @@ func (r *UserRepository) FindByName(ctx context.Context, name domain.Name) (doma
 // Create はユーザーを挿入し、ユニーク制約違反をドメインエラーへ変換する。
 func (r *UserRepository) Create(ctx context.Context, user domain.User) error {
 	const query = `
-		INSERT INTO users (id, username, email, hashed_password, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, username, email, hashed_password, role, created_at, updated_at, email_verified_at, password_legacy_trim)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	_, err := r.db.Exec(ctx, query,
@@ -72,6 +74,8 @@ func (r *UserRepository) Create(ctx context.Context, user domain.User) error {
 		user.Role().String(),
 		user.CreatedAt(),
 		user.UpdatedAt(),
+		optionalTime(user.EmailVerifiedAt()),
+		user.HashedPassword().IsLegacyTrimmed(),
 	)
 	if err != nil {
 		return translateUserConstraintError(err)
@@ -79,18 +83,146 @@ func (r *UserRepository) Create(ctx context.Context, user domain.User) error {
 	return nil
 }
 
+// List は検索条件に一致するユーザーを作成順に返し、合わせて一致した全件数を返す。
+func (r *UserRepository) List(ctx context.Context, q domain.UserQuery) ([]domain.User, int, error) {
+	where := userQueryConditions(q)
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users `+where.clause(), where.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, username, email, hashed_password, role, created_at, updated_at, disabled_at, email_verified_at, password_legacy_trim
+		FROM users
+		%s
+		ORDER BY created_at, id
+		LIMIT %s OFFSET %s
+	`, where.clause(), where.arg(q.Limit()), where.arg(q.Offset()))
+
+	rows, err := r.db.Query(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.User, error) {
+		return scanUser(row)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// userColumns は scanUser が読む順の列。
+const userColumns = "id, username, email, hashed_password, role, created_at, updated_at, disabled_at, email_verified_at, password_legacy_trim"
+
+// SetRole は role 列だけを書き換え、書き換えた後のユーザーを返す。読み込んだ後に別の操作で変わった列は上書きしない。
+// 対象が無ければ pgx.ErrNoRows を返す。
+func (r *UserRepository) SetRole(ctx context.Context, id uuid.UUID, role domain.UserRole, now time.Time) (domain.User, error) {
+	query := `UPDATE users SET role = $2, updated_at = $3 WHERE id = $1 RETURNING ` + userColumns
+	return scanUser(r.db.QueryRow(ctx, query, id, role.String(), now.UTC()))
+}
+
+// SetDisabled は disabled_at 列だけを書き換える。無効化済みなら無効化した時刻は変えない。
+func (r *UserRepository) SetDisabled(ctx context.Context, id uuid.UUID, disabled bool, now time.Time) (domain.User, error) {
+	query := `
+		UPDATE users
+		SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, $3) END, updated_at = $3
+		WHERE id = $1
+		RETURNING ` + userColumns
+	return scanUser(r.db.QueryRow(ctx, query, id, disabled, now.UTC()))
+}
+
+// MarkEmailVerified は email_verified_at 列だけを書き換える。確認済みなら時刻は変えない。
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, now time.Time) (domain.User, error) {
+	query := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, $2), updated_at = $2 WHERE id = $1 RETURNING ` + userColumns
+	return scanUser(r.db.QueryRow(ctx, query, id, now.UTC()))
+}
+
+// SetPassword は hashed_password 列とその password_legacy_trim 列だけを書き換える。
+func (r *UserRepository) SetPassword(ctx context.Context, id uuid.UUID, hashed domain.HashedPassword, now time.Time) (domain.User, error) {
+	return setUserPassword(ctx, r.db, id, hashed, now)
+}
+
+// setUserPassword は SetPassword を db で行う。パスワードの再設定では申請を使用済みにするのと同じトランザクションで使う。
+func setUserPassword(ctx context.Context, db queryRower, id uuid.UUID, hashed domain.HashedPassword, now time.Time) (domain.User, error) {
+	query := `UPDATE users SET hashed_password = $2, password_legacy_trim = $4, updated_at = $3 WHERE id = $1 RETURNING ` + userColumns
+	return scanUser(db.QueryRow(ctx, query, id, hashed.String(), now.UTC(), hashed.IsLegacyTrimmed()))
+}
+
+// UpdatePassword はハッシュの形式だけを更新するため updated_at は変えない。保存済みのハッシュが current と違えば pgx.ErrNoRows を返す。
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, current, replacement domain.HashedPassword) error {
+	const query = `
+		UPDATE users
+		SET hashed_password = $3, password_legacy_trim = $4
+		WHERE id = $1 AND hashed_password = $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, id, current.String(), replacement.String(), replacement.IsLegacyTrimmed())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Delete はユーザーを削除する。login_sessions は外部キーで参照しているため同じトランザクションで先に消し、
+// hue_records.user_id は ON DELETE SET NULL で匿名の回答として残る。対象が無ければ pgx.ErrNoRows を返す。
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM login_sessions WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return tx.Commit(ctx)
+}
+
+func userQueryConditions(q domain.UserQuery) *sqlConditions {
+	where := &sqlConditions{}
+
+	if role, ok := q.Role(); ok {
+		where.add("role = " + where.arg(role.String()))
+	}
+
+	if search := q.Search(); search != "" {
+		pattern := where.arg("%" + likeEscaper.Replace(search) + "%")
+		where.add(fmt.Sprintf("(username ILIKE %s OR email ILIKE %s)", pattern, pattern))
+	}
+
+	return where
+}
+
 func scanUser(row rowScanner) (domain.User, error) {
 	var (
-		id        uuid.UUID
-		username  string
-		email     string
-		hash      string
-		role      string
-		createdAt time.Time
-		updatedAt time.Time
+		id         uuid.UUID
+		username   string
+		email      string
+		hash       string
+		role       string
+		createdAt  time.Time
+		updatedAt  time.Time
+		disabledAt *time.Time
+		verifiedAt *time.Time
+		legacyTrim bool
 	)
 
-	if err := row.Scan(&id, &username, &email, &hash, &role, &createdAt, &updatedAt); err != nil {
+	if err := row.Scan(&id, &username, &email, &hash, &role, &createdAt, &updatedAt, &disabledAt, &verifiedAt, &legacyTrim); err != nil {
 		return domain.User{}, err
 	}
 
@@ -108,13 +240,24 @@ func scanUser(row rowScanner) (domain.User, error) {
 	if err != nil {
 		return domain.User{}, err
 	}
+	if legacyTrim {
+		password = password.LegacyTrimmed()
+	}
 
 	userRole, err := domain.NewUserRole(role)
 	if err != nil {
 		return domain.User{}, err
 	}
 
-	return domain.NewUserFromPersistence(id, name, domainEmail, password, userRole, createdAt, updatedAt)
+	var disabled, verified time.Time
+	if disabledAt != nil {
+		disabled = *disabledAt
+	}
+	if verifiedAt != nil {
+		verified = *verifiedAt
+	}
+
+	return domain.NewUserFromPersistence(id, name, domainEmail, password, userRole, createdAt, updatedAt, disabled, verified)
 }
 
 const (
@@ -142,3 +285,11 @@ func translateUserConstraintError(err error) error {
 		return err
 	}
 }
+
+// optionalTime は (時刻, 有無) の組を NULL 許容カラムへ渡す値に変換する。
+func optionalTime(at time.Time, ok bool) *time.Time {
+	if !ok {
+		return nil
+	}
+	return &at
+}
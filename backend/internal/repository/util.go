@@ -1,5 +1,22 @@
 package repository
 
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
 type rowScanner interface {
 	Scan(dest ...interface{}) error
 }
+
+// execer は pgxpool.Pool と pgx.Tx に共通する書き込み口。同じ INSERT をトランザクションの内外で使うために受け取る。
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// queryRower は pgxpool.Pool と pgx.Tx に共通する 1 行の読み出し口。RETURNING 付きの更新をトランザクションの内外で使うために受け取る。
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const foreignKeyViolationCode = "23503"
+
+// WordSetRepository は word_sets テーブルを読み書きする。
+type WordSetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWordSetRepository(db *pgxpool.Pool) *WordSetRepository {
+	return &WordSetRepository{db: db}
+}
+
+func (r *WordSetRepository) Create(ctx context.Context, set domain.WordSet) error {
+	const query = `
+		INSERT INTO word_sets (id, name, words, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(ctx, query, set.ID(), set.Name(), wordStrings(set.Words()), set.IsActive(), set.CreatedAt(), set.UpdatedAt())
+	return err
+}
+
+// FindByID は見つからなければ pgx.ErrNoRows を返す。
+func (r *WordSetRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.WordSet, error) {
+	const query = `
+		SELECT id, name, words, active, created_at, updated_at
+		FROM word_sets
+		WHERE id = $1
+	`
+
+	return scanWordSet(r.db.QueryRow(ctx, query, id))
+}
+
+// FindActive は有効な単語セットを返す。無ければ pgx.ErrNoRows を返す。
+func (r *WordSetRepository) FindActive(ctx context.Context) (domain.WordSet, error) {
+	const query = `
+		SELECT id, name, words, active, created_at, updated_at
+		FROM word_sets
+		WHERE active
+	`
+
+	return scanWordSet(r.db.QueryRow(ctx, query))
+}
+
+// List はすべての単語セットを作成順に返す。
+func (r *WordSetRepository) List(ctx context.Context) ([]domain.WordSet, error) {
+	const query = `
+		SELECT id, name, words, active, created_at, updated_at
+		FROM word_sets
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.WordSet, error) {
+		return scanWordSet(row)
+	})
+}
+
+// Update は名前・単語・更新時刻を保存する。有効かどうかは Activate でだけ変える。対象が無ければ pgx.ErrNoRows を返す。
+// 単語を変える場合、回答かテストセッションから参照されていれば domain.ErrWordSetInUse を返す。
+// 先に行を FOR UPDATE でロックし、参照を確かめてから書き換えるまでの間に参照する行が挿入されないようにする。
+func (r *WordSetRepository) Update(ctx context.Context, set domain.WordSet) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var stored []string
+	if err := tx.QueryRow(ctx, `SELECT words FROM word_sets WHERE id = $1 FOR UPDATE`, set.ID()).Scan(&stored); err != nil {
+		return err
+	}
+
+	words := wordStrings(set.Words())
+	if !slices.Equal(stored, words) {
+		const usedQuery = `
+			SELECT EXISTS (SELECT 1 FROM hue_records WHERE word_set_id = $1)
+				OR EXISTS (SELECT 1 FROM hue_sessions WHERE word_set_id = $1)
+		`
+		var used bool
+		if err := tx.QueryRow(ctx, usedQuery, set.ID()).Scan(&used); err != nil {
+			return err
+		}
+		if used {
+			return domain.ErrWordSetInUse
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE word_sets SET name = $2, words = $3, updated_at = $4 WHERE id = $1`, set.ID(), set.Name(), words, set.UpdatedAt()); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Activate は同じトランザクションで他のセットを無効にしてから id のセットを有効にする。対象が無ければ pgx.ErrNoRows を返す。
+func (r *WordSetRepository) Activate(ctx context.Context, id uuid.UUID, now time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `UPDATE word_sets SET active = FALSE, updated_at = $2 WHERE active AND id <> $1`, id, now); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE word_sets SET active = TRUE, updated_at = $2 WHERE id = $1`, id, now)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete は単語セットを削除する。回答かテストセッションから参照されていれば domain.ErrWordSetInUse、対象が無ければ pgx.ErrNoRows を返す。
+func (r *WordSetRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM word_sets WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return domain.ErrWordSetInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func scanWordSet(row rowScanner) (domain.WordSet, error) {
+	var (
+		id        uuid.UUID
+		name      string
+		words     []string
+		active    bool
+		createdAt time.Time
+		updatedAt time.Time
+	)
+
+	if err := row.Scan(&id, &name, &words, &active, &createdAt, &updatedAt); err != nil {
+		return domain.WordSet{}, err
+	}
+
+	return domain.NewWordSetFromPersistence(id, name, words, active, createdAt, updatedAt)
+}
+
+func wordStrings(words []domain.HueWord) []string {
+	values := make([]string, len(words))
+	for i, word := range words {
+		values[i] = string(word)
+	}
+	return values
+}
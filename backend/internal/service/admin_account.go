@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AdminAccountService は CLI から管理者アカウントを用意する。HTTP を経由しないため監査ログは残さない。
+type AdminAccountService struct {
+	userRepo    UserRepository
+	sessionRepo LoginSessionRepository
+	policy      domain.PasswordPolicy
+	hasher      domain.PasswordHasher
+	logger      *log.Logger
+}
+
+// NewAdminAccountService は hasher が nil なら domain.DefaultBcryptHasher を使う。設定するパスワードには policy を適用する。
+func NewAdminAccountService(userRepo UserRepository, sessionRepo LoginSessionRepository, policy domain.PasswordPolicy, hasher domain.PasswordHasher, logger *log.Logger) *AdminAccountService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &AdminAccountService{userRepo: userRepo, sessionRepo: sessionRepo, policy: policy, hasher: passwordHasherOrDefault(hasher), logger: logger}
+}
+
+// FindByName は name のユーザーを返す。見つからなければ ErrUserNotFound。
+func (s *AdminAccountService) FindByName(ctx context.Context, name domain.Name) (domain.User, error) {
+	user, err := s.userRepo.FindByName(ctx, name)
+	if err != nil {
+		return domain.User{}, s.translateNotFound("find user by name", err)
+	}
+	return user, nil
+}
+
+// CreateAdmin は admin ロールのユーザーを新規作成する。アドレスは CLI の実行者が指定したものなので確認済みとして扱う。
+func (s *AdminAccountService) CreateAdmin(ctx context.Context, name domain.Name, email domain.Email, password string) (domain.User, error) {
+	hashed, err := s.hashPassword(password)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	now := time.Now()
+	user, err := domain.NewUser(name, email, hashed, domain.UserRoleAdmin, now)
+	if err != nil {
+		s.logError("build user domain", err)
+		return domain.User{}, err
+	}
+	user = user.VerifyEmail(now)
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		s.logError("create user", err)
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// Promote は既存ユーザーを admin にし、無効化されていれば解除する。
+func (s *AdminAccountService) Promote(ctx context.Context, user domain.User) (domain.User, error) {
+	now := time.Now()
+	if _, err := s.userRepo.SetRole(ctx, user.ID(), domain.UserRoleAdmin, now); err != nil {
+		return domain.User{}, s.translateNotFound("promote user", err)
+	}
+	promoted, err := s.userRepo.SetDisabled(ctx, user.ID(), false, now)
+	if err != nil {
+		return domain.User{}, s.translateNotFound("promote user", err)
+	}
+	return promoted, nil
+}
+
+// ResetPassword は name のユーザーのパスワードを置き換え、既存のセッションをすべて破棄する。
+func (s *AdminAccountService) ResetPassword(ctx context.Context, name domain.Name, password string) (domain.User, error) {
+	user, err := s.FindByName(ctx, name)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	hashed, err := s.hashPassword(password)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	updated, err := s.userRepo.SetPassword(ctx, user.ID(), hashed, time.Now())
+	if err != nil {
+		return domain.User{}, s.translateNotFound("update password", err)
+	}
+
+	if _, err := s.sessionRepo.DeleteByUserID(ctx, user.ID()); err != nil {
+		s.logError("revoke sessions", err)
+		return domain.User{}, err
+	}
+	return updated, nil
+}
+
+// ListAdmins は admin ロールのユーザーを作成日時順にすべて返す。
+func (s *AdminAccountService) ListAdmins(ctx context.Context) ([]domain.User, error) {
+	var admins []domain.User
+	for offset := 0; ; offset += domain.MaxUserPageSize {
+		q, err := domain.NewUserQuery("", domain.UserRoleAdmin.String(), domain.MaxUserPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		users, total, err := s.userRepo.List(ctx, q)
+		if err != nil {
+			s.logError("list admins", err)
+			return nil, err
+		}
+
+		admins = append(admins, users...)
+		if len(users) == 0 || len(admins) >= total {
+			return admins, nil
+		}
+	}
+}
+
+func (s *AdminAccountService) hashPassword(password string) (domain.HashedPassword, error) {
+	hashed, err := hashPassword(s.policy, s.hasher, password)
+	if err != nil && !errors.Is(err, domain.ErrInvalidPassword) {
+		s.logError("hash password", err)
+	}
+	return hashed, err
+}
+
+func (s *AdminAccountService) translateNotFound(action string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrUserNotFound
+	}
+	s.logError(action, err)
+	return err
+}
+
+func (s *AdminAccountService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[AdminAccountService] %s: %v", action, err)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+)
+
+func TestAdminAccountService_ResetPasswordRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	createUser(t, users, "admin", "old-secret", domain.UserRoleAdmin)
+
+	login := NewLoginService(users, sessions, nil, domain.EmailVerificationPolicy{}, nil, 0, nil)
+	data, _, err := login.Login(ctx, buildCredential(t, "admin", "old-secret"), testClientIP)
+	if err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+
+	svc := NewAdminAccountService(users, sessions, domain.PasswordPolicy{}, testHasher, nil)
+	name, _ := domain.NewName("admin")
+	if _, err := svc.ResetPassword(ctx, name, "new-secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := sessions.FindByID(ctx, data.Token().ID()); err == nil {
+		t.Fatalf("expected existing sessions to be revoked")
+	}
+	if _, _, err := login.Login(ctx, buildCredential(t, "admin", "old-secret"), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected old password to be rejected, got %v", err)
+	}
+	if _, _, err := login.Login(ctx, buildCredential(t, "admin", "new-secret"), testClientIP); err != nil {
+		t.Fatalf("expected new password to be accepted, got %v", err)
+	}
+
+	if _, err := svc.ResetPassword(ctx, name, "   "); !errors.Is(err, domain.ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword for a blank password, got %v", err)
+	}
+}
+
+func TestAdminAccountService_KeepsConcurrentChanges(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	stale := createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+	svc := NewAdminAccountService(users, memory.NewLoginSessionRepository(), domain.PasswordPolicy{}, testHasher, nil)
+
+	// stale を読み込んだ後でパスワードが置き換えられても、昇格で古いハッシュに戻さない。
+	if _, err := svc.ResetPassword(ctx, stale.Username(), "new-secret"); err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	promoted, err := svc.Promote(ctx, stale)
+	if err != nil {
+		t.Fatalf("promote error: %v", err)
+	}
+	if promoted.Role() != domain.UserRoleAdmin || promoted.HashedPassword().Verify("new-secret") != nil {
+		t.Fatalf("expected the promotion to keep the new password, got role %s", promoted.Role())
+	}
+
+	// パスワードの置き換えも、読み込んだ後に変わったロールを元に戻さない。
+	if _, err := users.SetRole(ctx, stale.ID(), domain.UserRoleUser, time.Now()); err != nil {
+		t.Fatalf("set role error: %v", err)
+	}
+	updated, err := svc.ResetPassword(ctx, stale.Username(), "newer-secret")
+	if err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	if updated.Role() != domain.UserRoleUser || updated.HashedPassword().Verify("newer-secret") != nil {
+		t.Fatalf("expected the role change to survive, got %s", updated.Role())
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// appendAudit は管理操作の記録を残す。操作自体は完了しているため、記録に失敗しても取り消さず logError に渡すだけにする。
+func appendAudit(ctx context.Context, repo AdminAuditRepository, logError func(action string, err error), actorID, targetID uuid.UUID, action domain.AdminAction, detail string, now time.Time) {
+	entry, err := domain.NewAdminAuditEntry(actorID, targetID, action, detail, now)
+	if err == nil {
+		err = repo.Append(ctx, entry)
+	}
+	if err != nil {
+		logError(fmt.Sprintf("append audit entry (%s by %s on %s)", action, actorID, targetID), err)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AuthService は Bearer 資格情報からログインセッションとユーザーを解決する。
+type AuthService struct {
+	sessionRepo LoginSessionRepository
+	userRepo    UserRepository
+	logger      *log.Logger
+}
+
+func NewAuthService(sessionRepo LoginSessionRepository, userRepo UserRepository, logger *log.Logger) *AuthService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &AuthService{sessionRepo: sessionRepo, userRepo: userRepo, logger: logger}
+}
+
+// Authenticate はセッションの存在と有効期限を確認し、紐づくユーザーを読み込む。
+func (s *AuthService) Authenticate(ctx context.Context, session domain.SessionData) (domain.LoginSession, domain.User, error) {
+	loginSession, err := s.sessionRepo.FindByID(ctx, session.Token().ID())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logError("session not found", err)
+			return domain.LoginSession{}, domain.User{}, domain.ErrInvalidLoginSession
+		}
+		s.logError("find session", err)
+		return domain.LoginSession{}, domain.User{}, err
+	}
+
+	if loginSession.UserID() != session.UserID() || loginSession.Verify(session.Token()) != nil {
+		s.logError("session verification failed", domain.ErrInvalidSessionToken)
+		return domain.LoginSession{}, domain.User{}, domain.ErrInvalidLoginSession
+	}
+
+	if loginSession.IsExpired(time.Now()) {
+		s.logError("session expired", domain.ErrExpiredToken)
+		if delErr := s.sessionRepo.DeleteByID(ctx, loginSession.ID()); delErr != nil {
+			s.logError("cleanup expired session", delErr)
+		}
+		return domain.LoginSession{}, domain.User{}, domain.ErrExpiredToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, loginSession.UserID())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logError("user not found", err)
+			return domain.LoginSession{}, domain.User{}, domain.ErrInvalidLoginSession
+		}
+		s.logError("find user by id", err)
+		return domain.LoginSession{}, domain.User{}, err
+	}
+
+	// 無効化時にセッションは破棄しているが、その後に残ったものがあっても通さない。
+	if user.IsDisabled() {
+		s.logError("user disabled", domain.ErrUserDisabled)
+		return domain.LoginSession{}, domain.User{}, domain.ErrInvalidLoginSession
+	}
+
+	return loginSession, user, nil
+}
+
+func (s *AuthService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[AuthService] %s: %v", action, err)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	user := createUser(t, users, "alice", "secret", domain.UserRoleUser)
+
+	data, err := issueLoginSession(ctx, sessions, user.ID(), time.Now(), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("issue error: %v", err)
+	}
+
+	svc := NewAuthService(sessions, users, nil)
+	session, got, err := svc.Authenticate(ctx, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.ID() != data.Token().ID() || got.ID() != user.ID() {
+		t.Fatalf("unexpected session or user")
+	}
+}
+
+func TestAuthService_Authenticate_Rejects(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	user := createUser(t, users, "alice", "secret", domain.UserRoleUser)
+	svc := NewAuthService(sessions, users, nil)
+
+	data, err := issueLoginSession(ctx, sessions, user.ID(), time.Now(), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("issue error: %v", err)
+	}
+
+	otherUser, _ := domain.NewSessionData(uuid.New(), data.Token())
+	if _, _, err := svc.Authenticate(ctx, otherUser); !errors.Is(err, domain.ErrInvalidLoginSession) {
+		t.Fatalf("expected ErrInvalidLoginSession for mismatched user, got %v", err)
+	}
+
+	unknownToken, _ := domain.NewLoginSessionToken()
+	unknown, _ := domain.NewSessionData(user.ID(), unknownToken)
+	if _, _, err := svc.Authenticate(ctx, unknown); !errors.Is(err, domain.ErrInvalidLoginSession) {
+		t.Fatalf("expected ErrInvalidLoginSession for unknown session, got %v", err)
+	}
+}
+
+func TestAuthService_Authenticate_Expired(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	user := createUser(t, users, "alice", "secret", domain.UserRoleUser)
+	svc := NewAuthService(sessions, users, nil)
+
+	data, err := issueLoginSession(ctx, sessions, user.ID(), time.Now().Add(-2*domain.DefaultLoginSessionTTL), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("issue error: %v", err)
+	}
+
+	if _, _, err := svc.Authenticate(ctx, data); !errors.Is(err, domain.ErrExpiredToken) {
+		t.Fatalf("expected ErrExpiredToken, got %v", err)
+	}
+
+	if _, err := sessions.FindByID(ctx, data.Token().ID()); err == nil {
+		t.Fatalf("expired session should be deleted")
+	}
+}
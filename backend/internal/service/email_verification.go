@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/mail"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EmailVerificationService はメールアドレスの確認用リンクの送信と、その確定を扱う。
+type EmailVerificationService struct {
+	userRepo         UserRepository
+	verificationRepo EmailVerificationRepository
+	mailer           Mailer
+	ttl              time.Duration
+	verifyURL        string
+	logger           *log.Logger
+}
+
+// NewEmailVerificationService は ttl が 0 以下なら domain.DefaultEmailVerificationTTL を使う。
+// verifyURL はメールに載せる確認ページの URL で、token クエリを付けて送る。
+func NewEmailVerificationService(userRepo UserRepository, verificationRepo EmailVerificationRepository, mailer Mailer, ttl time.Duration, verifyURL string, logger *log.Logger) *EmailVerificationService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if ttl <= 0 {
+		ttl = domain.DefaultEmailVerificationTTL
+	}
+	return &EmailVerificationService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		mailer:           mailer,
+		ttl:              ttl,
+		verifyURL:        verifyURL,
+		logger:           logger,
+	}
+}
+
+// SendVerification は user へ確認用リンクを送る。以前に送ったリンクは無効になる。
+// 確認済みなら domain.ErrEmailAlreadyVerified を返す。
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user domain.User) error {
+	if user.IsEmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	token, err := domain.NewOneTimeToken()
+	if err != nil {
+		s.logError("generate verification token", err)
+		return err
+	}
+
+	verification, err := domain.NewEmailVerification(user.ID(), token, time.Now(), s.ttl)
+	if err != nil {
+		s.logError("build email verification", err)
+		return err
+	}
+
+	if _, err := s.verificationRepo.DeleteByUserID(ctx, user.ID()); err != nil {
+		s.logError("delete previous verifications", err)
+		return err
+	}
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		s.logError("create email verification", err)
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, s.verificationMessage(user, token)); err != nil {
+		s.logError("send verification mail", err)
+		return err
+	}
+	return nil
+}
+
+// ResendVerification は email のユーザーへ確認用リンクを送り直す。
+// 登録の有無を推測されないよう、該当するユーザーがいない・無効化されている・確認済みの場合も何もせず nil を返す。
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, email domain.Email) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		s.logError("find user by email", err)
+		return err
+	}
+	if user.IsDisabled() || user.IsEmailVerified() {
+		return nil
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// Verify はトークンを一度だけ使ってユーザーのメールアドレスを確認済みにし、更新後のユーザーを返す。
+// トークンが不正・期限切れ・使用済みのいずれでも domain.ErrInvalidVerificationToken を返す。
+func (s *EmailVerificationService) Verify(ctx context.Context, rawToken string) (domain.User, error) {
+	token, err := domain.ParseOneTimeToken(rawToken)
+	if err != nil {
+		return domain.User{}, domain.ErrInvalidVerificationToken
+	}
+
+	verification, err := s.verificationRepo.FindByID(ctx, token.ID())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.User{}, domain.ErrInvalidVerificationToken
+	}
+	if err != nil {
+		s.logError("find email verification", err)
+		return domain.User{}, err
+	}
+
+	now := time.Now()
+	if err := verification.Verify(token, now); err != nil {
+		return domain.User{}, err
+	}
+
+	if err := s.verificationRepo.MarkUsed(ctx, verification.ID(), now); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, domain.ErrInvalidVerificationToken
+		}
+		s.logError("mark verification used", err)
+		return domain.User{}, err
+	}
+
+	// 確認済みなら確認した時刻は変えない。
+	user, err := s.userRepo.MarkEmailVerified(ctx, verification.UserID(), now)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, domain.ErrInvalidVerificationToken
+		}
+		s.logError("mark email verified", err)
+		return domain.User{}, err
+	}
+
+	if _, err := s.verificationRepo.DeleteByUserID(ctx, user.ID()); err != nil {
+		s.logError("delete email verifications", err)
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func (s *EmailVerificationService) verificationMessage(user domain.User, token domain.OneTimeToken) mail.Message {
+	link := linkWithToken(s.verifyURL, token)
+
+	body := fmt.Sprintf(`%s さん
+
+ご登録ありがとうございます。次のリンクから %d 時間以内にメールアドレスを確認してください。
+
+%s
+
+心当たりがない場合はこのメールを破棄してください。
+`, user.Username(), int(s.ttl/time.Hour), link)
+
+	return mail.Message{To: user.Email().String(), Subject: "メールアドレスの確認", Body: body}
+}
+
+func (s *EmailVerificationService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[EmailVerificationService] %s: %v", action, err)
+}
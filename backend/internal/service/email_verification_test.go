@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+)
+
+func TestEmailVerificationService_SignInAndVerify(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	mailer := &recordingMailer{}
+	verifier := NewEmailVerificationService(users, memory.NewEmailVerificationRepository(), mailer, 0, "https://example.com/verify-email", nil)
+	policy := domain.NewEmailVerificationPolicy(true, false)
+
+	signIn := NewSignInService(users, sessions, verifier, policy, domain.PasswordPolicy{}, testHasher, 0, nil)
+	credential, _ := domain.NewSignInCredential("alice", "alice@example.com", "secret")
+	if _, role, err := signIn.SignIn(ctx, credential); !errors.Is(err, domain.ErrEmailNotVerified) || role != domain.UserRoleUser {
+		t.Fatalf("expected ErrEmailNotVerified with user role, got %v (%s)", err, role)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To != "alice@example.com" {
+		t.Fatalf("expected one verification mail to alice, got %+v", mailer.sent)
+	}
+
+	login := NewLoginService(users, sessions, nil, policy, nil, 0, nil)
+	if _, _, err := login.Login(ctx, buildCredential(t, "alice", "secret"), testClientIP); !errors.Is(err, domain.ErrEmailNotVerified) {
+		t.Fatalf("expected login to be blocked, got %v", err)
+	}
+	if _, _, err := login.Login(ctx, buildCredential(t, "alice", "wrong"), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected wrong password to be reported first, got %v", err)
+	}
+
+	// 再送すると古いリンクは使えなくなる。
+	email, _ := domain.NewEmail("alice@example.com")
+	if err := verifier.ResendVerification(ctx, email); err != nil || len(mailer.sent) != 2 {
+		t.Fatalf("expected a second mail, got %v (%d mails)", err, len(mailer.sent))
+	}
+	stale := verificationToken(t, mailer.sent[0].Body)
+	token := verificationToken(t, mailer.sent[1].Body)
+	if _, err := verifier.Verify(ctx, stale); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Fatalf("expected the previous link to be invalid, got %v", err)
+	}
+
+	user, err := verifier.Verify(ctx, token)
+	if err != nil || !user.IsEmailVerified() {
+		t.Fatalf("expected verified user, got %v", err)
+	}
+	if _, err := verifier.Verify(ctx, token); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Fatalf("expected the token to be single-use, got %v", err)
+	}
+	if _, _, err := login.Login(ctx, buildCredential(t, "alice", "secret"), testClientIP); err != nil {
+		t.Fatalf("expected verified user to log in, got %v", err)
+	}
+
+	if err := verifier.ResendVerification(ctx, email); err != nil || len(mailer.sent) != 2 {
+		t.Fatalf("verified user should not get another mail, got %v (%d mails)", err, len(mailer.sent))
+	}
+	unknown, _ := domain.NewEmail("nobody@example.com")
+	if err := verifier.ResendVerification(ctx, unknown); err != nil || len(mailer.sent) != 2 {
+		t.Fatalf("unknown email should be ignored silently, got %v (%d mails)", err, len(mailer.sent))
+	}
+}
+
+func TestUserAdminService_ChangeRoleRequiresVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	admin := createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+	target := createUser(t, users, "bob", "secret", domain.UserRoleUser)
+
+	svc := NewUserAdminService(users, memory.NewLoginSessionRepository(), memory.NewAdminAuditRepository(), domain.NewEmailVerificationPolicy(false, true), nil)
+	if _, err := svc.ChangeRole(ctx, admin.ID(), target.ID(), domain.UserRoleAdmin); !errors.Is(err, domain.ErrEmailNotVerified) {
+		t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+	}
+
+	if _, err := users.MarkEmailVerified(ctx, target.ID(), target.CreatedAt()); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if _, err := svc.ChangeRole(ctx, admin.ID(), target.ID(), domain.UserRoleAdmin); err != nil {
+		t.Fatalf("expected verified user to be promoted, got %v", err)
+	}
+}
+
+func verificationToken(t *testing.T, body string) string {
+	t.Helper()
+	link := regexp.MustCompile(`https://example\.com/verify-email\?token=\S+`).FindString(body)
+	parsed, err := url.Parse(link)
+	if err != nil || link == "" {
+		t.Fatalf("expected verification link in body: %q", body)
+	}
+	return parsed.Query().Get("token")
+}
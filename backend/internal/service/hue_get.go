@@ -4,74 +4,111 @@ import (
 	"context"
 	"errors"
 	"log"
-	"time"
 
 	"backend/internal/domain"
-	"backend/internal/repository"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/google/uuid"
 )
 
+// HueGetService は Hue レコードを取得する。認可は handler の AuthMiddleware が担う。
 type HueGetService struct {
-	hueRepo     *repository.HueRepository
-	sessionRepo *repository.LoginSessionRepository
-	userRepo    *repository.UserRepository
+	hueRepo     HueRepository
+	paletteRepo PaletteRepository
 	logger      *log.Logger
 }
 
-func NewHueGetService(hueRepo *repository.HueRepository, sessionRepo *repository.LoginSessionRepository, userRepo *repository.UserRepository, logger *log.Logger) *HueGetService {
+func NewHueGetService(hueRepo HueRepository, paletteRepo PaletteRepository, logger *log.Logger) *HueGetService {
 	if logger == nil {
 		logger = log.Default()
 	}
 	return &HueGetService{
 		hueRepo:     hueRepo,
-		sessionRepo: sessionRepo,
-		userRepo:    userRepo,
+		paletteRepo: paletteRepo,
 		logger:      logger,
 	}
 }
 
-func (s *HueGetService) GetData(ctx context.Context, session domain.SessionData, recordRange domain.RecordRange) ([]domain.HueRecord, error) {
-	loginSession, err := s.sessionRepo.Find(ctx, session.UserID(), session.Token())
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			s.logError("session not found", err)
-			return nil, domain.ErrInvalidLoginSession
+// GetData は作成順で 1 ページ分のレコードを返す。絞り込みの色は有効な版の色 ID か表示名で指定でき、
+// その版に無い色なら domain.ErrInvalidFilter を返す。
+func (s *HueGetService) GetData(ctx context.Context, req domain.RecordPageRequest) (domain.RecordPage, error) {
+	if len(req.Filter().Choices()) > 0 {
+		resolved, err := s.resolveFilterColors(ctx, req)
+		if err != nil {
+			return domain.RecordPage{}, err
 		}
-		s.logError("find session", err)
-		return nil, err
+		req = resolved
 	}
 
-	if loginSession.IsExpired(time.Now()) {
-		s.logError("session expired", domain.ErrExpiredToken)
-		if delErr := s.sessionRepo.DeleteByID(ctx, loginSession.ID()); delErr != nil {
-			s.logError("cleanup expired session", delErr)
-		}
-		return nil, domain.ErrExpiredToken
+	page, err := s.hueRepo.FindPage(ctx, req)
+	if err != nil {
+		s.logError("fetch hue records", err)
+		return domain.RecordPage{}, err
 	}
 
-	user, err := s.userRepo.FindByID(ctx, session.UserID())
+	return page, nil
+}
+
+// GetUserResults は userID のユーザー自身の回答を作成順で 1 ページ分返す。匿名の回答は含まない。
+func (s *HueGetService) GetUserResults(ctx context.Context, userID uuid.UUID, after domain.RecordCursor, limit int) (domain.RecordPage, error) {
+	req, err := domain.NewRecordPageRequest(domain.RecordFilter{}.ForUser(userID), after, limit, false)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			s.logError("user not found", err)
-			return nil, domain.ErrInvalidLoginSession
+		return domain.RecordPage{}, err
+	}
+
+	page, err := s.hueRepo.FindPage(ctx, req)
+	if err != nil {
+		s.logError("fetch user hue records", err)
+		return domain.RecordPage{}, err
+	}
+
+	return page, nil
+}
+
+// GetStats は version の版で回答したレコードについて、単語ごとの色分布を集計した版と合わせて返す。
+// version が 0 なら有効な版を集計する。版が無ければ domain.ErrPaletteNotFound を返す。
+func (s *HueGetService) GetStats(ctx context.Context, version int) (domain.Palette, []domain.HueWordStats, error) {
+	palette, err := findPalette(ctx, s.paletteRepo, version)
+	if err != nil {
+		if !errors.Is(err, domain.ErrPaletteNotFound) {
+			s.logError("find palette", err)
 		}
-		s.logError("find user by id", err)
-		return nil, err
+		return domain.Palette{}, nil, err
+	}
+
+	stats, err := s.hueRepo.Stats(ctx, palette)
+	if err != nil {
+		s.logError("aggregate hue stats", err)
+		return domain.Palette{}, nil, err
 	}
 
-	if user.Role() != domain.UserRoleAdmin {
-		s.logError("non-admin access", domain.ErrInvalidLoginSession)
-		return nil, domain.ErrInvalidLoginSession
+	return palette, stats, nil
+}
+
+// Export は全レコードを write へ順に流す。write のエラー (クライアント切断など) もそのまま返す。
+func (s *HueGetService) Export(ctx context.Context, begin func(words []domain.HueWord) error, write func(domain.HueRecord) error) error {
+	if err := s.hueRepo.Export(ctx, begin, write); err != nil {
+		s.logError("export hue records", err)
+		return err
 	}
 
-	records, err := s.hueRepo.FindRange(ctx, recordRange)
+	return nil
+}
+
+func (s *HueGetService) resolveFilterColors(ctx context.Context, req domain.RecordPageRequest) (domain.RecordPageRequest, error) {
+	palette, err := findPalette(ctx, s.paletteRepo, 0)
 	if err != nil {
-		s.logError("fetch hue records", err)
-		return nil, err
+		if errors.Is(err, domain.ErrPaletteNotFound) {
+			return domain.RecordPageRequest{}, domain.ErrInvalidFilter
+		}
+		s.logError("find palette", err)
+		return domain.RecordPageRequest{}, err
 	}
 
-	return records, nil
+	filter, err := req.Filter().ResolveColors(palette)
+	if err != nil {
+		return domain.RecordPageRequest{}, err
+	}
+	return domain.NewRecordPageRequest(filter, req.After(), req.Limit(), req.IncludeTotal())
 }
 
 func (s *HueGetService) logError(action string, err error) {
@@ -2,28 +2,136 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
+	"time"
 
 	"backend/internal/domain"
-	"backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// HueSaveService は save-result で一括送信された回答を保存する。認証なしで呼べるため、送信には
+// サーバーが発行した使い捨てのチャレンジを求め、同じ送信の再送は Idempotency-Key で見分ける。
 type HueSaveService struct {
-	hueRepo *repository.HueRepository
-	logger  *log.Logger
+	hueRepo     HueRepository
+	wordSetRepo WordSetRepository
+	paletteRepo PaletteRepository
+	challenges  domain.HueChallengeSigner
+	logger      *log.Logger
 }
 
-func NewHueSaveService(hueRepo *repository.HueRepository, logger *log.Logger) *HueSaveService {
+func NewHueSaveService(hueRepo HueRepository, wordSetRepo WordSetRepository, paletteRepo PaletteRepository, challenges domain.HueChallengeSigner, logger *log.Logger) *HueSaveService {
 	if logger == nil {
 		logger = log.Default()
 	}
-	return &HueSaveService{hueRepo: hueRepo, logger: logger}
+	return &HueSaveService{hueRepo: hueRepo, wordSetRepo: wordSetRepo, paletteRepo: paletteRepo, challenges: challenges, logger: logger}
+}
+
+// IssueChallenge はテストを始めるときに渡すチャレンジとそのトークンを発行する。
+func (s *HueSaveService) IssueChallenge(_ context.Context) (domain.HueChallenge, string) {
+	return s.challenges.Issue(time.Now())
 }
 
-func (s *HueSaveService) SaveResult(ctx context.Context, record domain.HueRecord) error {
-	if err := s.hueRepo.Save(ctx, record); err != nil {
-		s.logger.Printf("[HueSaveService] save hue record: %v", err)
-		return err
+// SaveResult は回答が答えたと名乗る単語セットとパレットの版で単語と色を確かめてから保存し、保存した回答を返す。
+// 名乗っていなければ出題中のセット・有効な版への回答として扱い、色は版の色 ID にそろえて保存する。
+// record に Idempotency-Key が付いていて同じキーの回答が保存済みなら、保存し直さずにそれを返し、replayed を true にする。
+// challenge は IssueChallenge で発行したトークンで、発行から保存までの時間で回答の速さを判定する。
+//
+// セットや版が無ければ domain.ErrWordSetNotFound / domain.ErrPaletteNotFound、
+// セットに無い単語や版に無い色があれば domain.ErrInvalidChoice、チャレンジが不正か期限切れなら domain.ErrInvalidHueChallenge、
+// 使用済みなら domain.ErrHueChallengeUsed、同じキーで別の回答が保存済みなら domain.ErrIdempotencyKeyReused を返す。
+func (s *HueSaveService) SaveResult(ctx context.Context, record domain.HueRecord, challenge string) (saved domain.HueRecord, replayed bool, err error) {
+	set, err := s.answeredWordSet(ctx, record)
+	if err != nil {
+		return domain.HueRecord{}, false, err
+	}
+	if err := set.CheckChoices(record.Choices()); err != nil {
+		return domain.HueRecord{}, false, err
+	}
+
+	claimed, _ := record.PaletteVersion()
+	palette, err := findPalette(ctx, s.paletteRepo, claimed)
+	if err != nil {
+		if !errors.Is(err, domain.ErrPaletteNotFound) {
+			s.logger.Printf("[HueSaveService] find palette: %v", err)
+		}
+		return domain.HueRecord{}, false, err
+	}
+	painted, err := palette.Paint(record)
+	if err != nil {
+		return domain.HueRecord{}, false, err
+	}
+	painted = painted.AnsweredFrom(set.ID())
+
+	// 再送はチャレンジを使い切った後に届くため、チャレンジより先に保存済みの回答を探す。
+	if existing, found, err := s.findReplay(ctx, painted); found || err != nil {
+		return existing, found, err
+	}
+
+	now := time.Now()
+	proof, err := s.challenges.Verify(challenge, now)
+	if err != nil {
+		return domain.HueRecord{}, false, err
+	}
+	painted = painted.ProvenBy(proof).Assess(now.Sub(proof.IssuedAt()))
+
+	err = s.hueRepo.Save(ctx, painted)
+	if errors.Is(err, domain.ErrDuplicateIdempotencyKey) {
+		// 同じキーの送信が同時に届き、先に保存された。
+		existing, found, err := s.findReplay(ctx, painted)
+		if !found && err == nil {
+			err = domain.ErrDuplicateIdempotencyKey
+		}
+		return existing, found, err
+	}
+	if err != nil {
+		if !errors.Is(err, domain.ErrHueChallengeUsed) {
+			s.logger.Printf("[HueSaveService] save hue record: %v", err)
+		}
+		return domain.HueRecord{}, false, err
+	}
+	return painted, false, nil
+}
+
+// findReplay は record と同じ Idempotency-Key で保存済みの回答を探す。見つかった回答が record と違えば domain.ErrIdempotencyKeyReused を返す。
+func (s *HueSaveService) findReplay(ctx context.Context, record domain.HueRecord) (domain.HueRecord, bool, error) {
+	key, ok := record.IdempotencyKey()
+	if !ok {
+		return domain.HueRecord{}, false, nil
+	}
+
+	existing, err := s.hueRepo.FindByIdempotencyKey(ctx, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.HueRecord{}, false, nil
+	}
+	if err != nil {
+		s.logger.Printf("[HueSaveService] find by idempotency key: %v", err)
+		return domain.HueRecord{}, false, err
+	}
+	if !existing.SameAnswers(record) {
+		return domain.HueRecord{}, false, domain.ErrIdempotencyKeyReused
+	}
+	return existing, true, nil
+}
+
+func (s *HueSaveService) answeredWordSet(ctx context.Context, record domain.HueRecord) (domain.WordSet, error) {
+	var (
+		set domain.WordSet
+		err error
+	)
+	if id, ok := record.WordSetID(); ok {
+		set, err = s.wordSetRepo.FindByID(ctx, id)
+	} else {
+		set, err = s.wordSetRepo.FindActive(ctx)
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.WordSet{}, domain.ErrWordSetNotFound
+	}
+	if err != nil {
+		s.logger.Printf("[HueSaveService] find word set: %v", err)
+		return domain.WordSet{}, err
 	}
-	return nil
+	return set, nil
 }
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// HueSessionService は Hue テストを開始から終了まで 1 語ずつ受け付ける。
+// 終了したセッションの回答は HueSaveService と同じ hue_records に保存し、終わらなかったセッションも集計のために残す。
+// 出題順はセッションごとに orderMode で決める。終了は save-result と同じくチャレンジと Idempotency-Key で守る。
+type HueSessionService struct {
+	sessionRepo HueSessionRepository
+	hueRepo     HueRepository
+	wordSetRepo WordSetRepository
+	paletteRepo PaletteRepository
+	challenges  domain.HueChallengeSigner
+	orderMode   domain.WordOrderMode
+	logger      *log.Logger
+}
+
+func NewHueSessionService(sessionRepo HueSessionRepository, hueRepo HueRepository, wordSetRepo WordSetRepository, paletteRepo PaletteRepository, challenges domain.HueChallengeSigner, orderMode domain.WordOrderMode, logger *log.Logger) *HueSessionService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &HueSessionService{
+		sessionRepo: sessionRepo,
+		hueRepo:     hueRepo,
+		wordSetRepo: wordSetRepo,
+		paletteRepo: paletteRepo,
+		challenges:  challenges,
+		orderMode:   orderMode,
+		logger:      logger,
+	}
+}
+
+// maxShuffleSeed は shuffle の seed の上限。書き出した seed を JavaScript の Number でも正確に扱えるよう 2^53 未満にする。
+const maxShuffleSeed = 1 << 53
+
+// Start は出題中の単語セットと有効なパレットでセッションを始める。userID が uuid.Nil なら匿名のセッション。
+// 出題中のセットや有効な版が無ければ domain.ErrWordSetNotFound / domain.ErrPaletteNotFound を返す。
+func (s *HueSessionService) Start(ctx context.Context, userID uuid.UUID) (domain.HueSession, error) {
+	set, err := s.wordSetRepo.FindActive(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.HueSession{}, domain.ErrWordSetNotFound
+	}
+	if err != nil {
+		s.logError("find word set", err)
+		return domain.HueSession{}, err
+	}
+
+	palette, err := findPalette(ctx, s.paletteRepo, 0)
+	if err != nil {
+		if !errors.Is(err, domain.ErrPaletteNotFound) {
+			s.logError("find palette", err)
+		}
+		return domain.HueSession{}, err
+	}
+
+	order, err := s.wordOrder(ctx, set)
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+
+	session, err := domain.NewHueSession(set, palette, order, userID, time.Now())
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		s.logError("create session", err)
+		return domain.HueSession{}, err
+	}
+	return session, nil
+}
+
+// wordOrder は set の単語をこのセッションで出す順に並べる。ラテン方格では、同じセットで始めたセッションの数を行番号にして
+// 参加者に行を順番に割り当てる。同時に始めたセッションが同じ行になることはあるが、偏りは数件にとどまる。
+func (s *HueSessionService) wordOrder(ctx context.Context, set domain.WordSet) (domain.WordOrder, error) {
+	var seed int64
+	switch s.orderMode {
+	case domain.WordOrderShuffle:
+		seed = rand.Int64N(maxShuffleSeed)
+	case domain.WordOrderLatinSquare:
+		count, err := s.sessionRepo.CountByWordSet(ctx, set.ID())
+		if err != nil {
+			s.logError("count sessions", err)
+			return domain.WordOrder{}, err
+		}
+		seed = count
+	}
+	return domain.NewWordOrder(s.orderMode, seed, set.Words())
+}
+
+// Answer は word に color を選んだ回答を記録し、記録後のセッションを返す。color は色 ID か、セッションの版の表示名。
+// セッションが無ければ domain.ErrHueSessionNotFound、終了か離脱していれば domain.ErrHueSessionClosed、
+// 出題していない単語や版に無い色なら domain.ErrInvalidChoice、latency が範囲外なら domain.ErrInvalidLatency を返す。
+func (s *HueSessionService) Answer(ctx context.Context, sessionID uuid.UUID, word, color string, latency time.Duration) (domain.HueSession, error) {
+	session, err := s.find(ctx, sessionID)
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+	// 閉じたセッションには回答の中身に関わらず ErrHueSessionClosed を返す。
+	if session.Status(time.Now()) != domain.HueSessionInProgress {
+		return domain.HueSession{}, domain.ErrHueSessionClosed
+	}
+
+	palette, err := findPalette(ctx, s.paletteRepo, session.PaletteVersion())
+	if err != nil {
+		s.logError("find palette", err)
+		return domain.HueSession{}, err
+	}
+	colorID, ok := palette.Resolve(color)
+	if !ok {
+		return domain.HueSession{}, domain.ErrInvalidChoice
+	}
+
+	answer, err := domain.NewHueAnswer(domain.HueWord(word), colorID, latency, time.Now())
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+	updated, err := session.Answer(answer)
+	if err != nil {
+		return domain.HueSession{}, err
+	}
+
+	if err := s.sessionRepo.SaveAnswer(ctx, sessionID, answer); err != nil {
+		// 読み出した後に別のリクエストで終了された。
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.HueSession{}, domain.ErrHueSessionClosed
+		}
+		s.logError("save answer", err)
+		return domain.HueSession{}, err
+	}
+	return updated, nil
+}
+
+// Finish はセッションを終了し、ここまでの回答を name の回答として保存して返す。challenge は HueSaveService.IssueChallenge で
+// 発行したトークン。key が空でなく、同じキーでこのセッションを終了済みなら、保存済みの回答を返して replayed を true にする。
+// セッションが無ければ domain.ErrHueSessionNotFound、終了か離脱していれば domain.ErrHueSessionClosed、
+// 1 語も答えていなければ domain.ErrInvalidChoice、チャレンジが不正か期限切れなら domain.ErrInvalidHueChallenge、
+// 使用済みなら domain.ErrHueChallengeUsed、同じキーで別の回答が保存済みなら domain.ErrIdempotencyKeyReused を返す。
+func (s *HueSessionService) Finish(ctx context.Context, sessionID uuid.UUID, name domain.Name, key domain.IdempotencyKey, challenge string) (record domain.HueRecord, replayed bool, err error) {
+	session, err := s.find(ctx, sessionID)
+	if err != nil {
+		return domain.HueRecord{}, false, err
+	}
+
+	// 再送はセッションを終了した後に届くため、終了済みかを確かめる前に保存済みの回答を探す。
+	if existing, found, err := s.findReplay(ctx, session, key); found || err != nil {
+		return existing, found, err
+	}
+
+	now := time.Now()
+	finished, record, err := session.Finish(name, now)
+	if err != nil {
+		return domain.HueRecord{}, false, err
+	}
+	proof, err := s.challenges.Verify(challenge, now)
+	if err != nil {
+		return domain.HueRecord{}, false, err
+	}
+	record = record.ProvenBy(proof)
+	if key != "" {
+		record = record.KeyedBy(key)
+	}
+
+	err = s.sessionRepo.Finish(ctx, finished, record)
+	if key != "" && (errors.Is(err, domain.ErrDuplicateIdempotencyKey) || errors.Is(err, pgx.ErrNoRows)) {
+		// 同じキーの終了が同時に届き、先に終了された。
+		if current, findErr := s.find(ctx, sessionID); findErr == nil {
+			if existing, found, replayErr := s.findReplay(ctx, current, key); found || replayErr != nil {
+				return existing, found, replayErr
+			}
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.HueRecord{}, false, domain.ErrHueSessionClosed
+	}
+	if err != nil {
+		if !errors.Is(err, domain.ErrHueChallengeUsed) && !errors.Is(err, domain.ErrDuplicateIdempotencyKey) {
+			s.logError("finish session", err)
+		}
+		return domain.HueRecord{}, false, err
+	}
+	return record, false, nil
+}
+
+// findReplay は key で保存済みの回答を探す。見つかった回答が session を終了したときのものでなければ domain.ErrIdempotencyKeyReused を返す。
+func (s *HueSessionService) findReplay(ctx context.Context, session domain.HueSession, key domain.IdempotencyKey) (domain.HueRecord, bool, error) {
+	if key == "" {
+		return domain.HueRecord{}, false, nil
+	}
+
+	existing, err := s.hueRepo.FindByIdempotencyKey(ctx, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.HueRecord{}, false, nil
+	}
+	if err != nil {
+		s.logError("find by idempotency key", err)
+		return domain.HueRecord{}, false, err
+	}
+	if recordID, ok := session.RecordID(); !ok || recordID != existing.ID() {
+		return domain.HueRecord{}, false, domain.ErrIdempotencyKeyReused
+	}
+	return existing, true, nil
+}
+
+// Funnel は開始したセッションがどこまで進んだかと、単語ごとの回答時間を集計する。
+func (s *HueSessionService) Funnel(ctx context.Context) (domain.HueSessionFunnel, error) {
+	funnel, err := s.sessionRepo.Funnel(ctx, time.Now())
+	if err != nil {
+		s.logError("aggregate sessions", err)
+		return domain.HueSessionFunnel{}, err
+	}
+	return funnel, nil
+}
+
+func (s *HueSessionService) find(ctx context.Context, id uuid.UUID) (domain.HueSession, error) {
+	session, err := s.sessionRepo.FindByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.HueSession{}, domain.ErrHueSessionNotFound
+	}
+	if err != nil {
+		s.logError("find session", err)
+		return domain.HueSession{}, err
+	}
+	return session, nil
+}
+
+func (s *HueSessionService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[HueSessionService] %s: %v", action, err)
+}
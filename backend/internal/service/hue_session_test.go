@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func TestHueSessionService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	sessions := memory.NewHueSessionRepository(hues)
+	signer := testChallengeSigner(t)
+	svc := NewHueSessionService(sessions, hues, activeWordSets(t, hues, "夜", "海", "森"), activePalettes(t), signer, domain.WordOrderFixed, nil)
+
+	userID := uuid.New()
+	session, err := svc.Start(ctx, userID)
+	if err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	if words := session.Words(); len(words) != 3 || words[0] != "夜" || session.PaletteVersion() != 1 {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+
+	if _, err := svc.Answer(ctx, uuid.New(), "夜", "黒", time.Second); !errors.Is(err, domain.ErrHueSessionNotFound) {
+		t.Fatalf("expected ErrHueSessionNotFound, got %v", err)
+	}
+	if _, err := svc.Answer(ctx, session.ID(), "夜", "金", time.Second); !errors.Is(err, domain.ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice for a color outside the palette, got %v", err)
+	}
+	if _, err := svc.Answer(ctx, session.ID(), "夜", "黒", -time.Second); !errors.Is(err, domain.ErrInvalidLatency) {
+		t.Fatalf("expected ErrInvalidLatency, got %v", err)
+	}
+
+	answered, err := svc.Answer(ctx, session.ID(), "夜", "黒", 1200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("answer error: %v", err)
+	}
+	if answers := answered.Answers(); len(answers) != 1 || answers[0].Color() != "black" {
+		t.Fatalf("expected the color name to be stored as its ID, got %+v", answers)
+	}
+	if _, err := svc.Answer(ctx, session.ID(), "海", "blue", 800*time.Millisecond); err != nil {
+		t.Fatalf("answer error: %v", err)
+	}
+
+	name, _ := domain.NewName("Tester")
+	_, token := signer.Issue(time.Now())
+	record, _, err := svc.Finish(ctx, session.ID(), name, "", token)
+	if err != nil {
+		t.Fatalf("finish error: %v", err)
+	}
+	if got := record.ChoiceMap(); len(got) != 2 || got["海"] != "blue" {
+		t.Fatalf("unexpected choices: %+v", got)
+	}
+	if id, _ := record.UserID(); id != userID {
+		t.Fatalf("expected the record to belong to the user who started, got %v", id)
+	}
+	if order, ok := record.WordOrder(); !ok || order.Mode() != domain.WordOrderFixed || !slices.Equal(order.Words(), session.Words()) {
+		t.Fatalf("expected the record to keep the presented order, got %+v", order)
+	}
+
+	req, _ := domain.NewRecordPageRequest(domain.RecordFilter{}, domain.RecordCursor{}, 10, false)
+	page, err := hues.FindPage(ctx, req)
+	if err != nil || len(page.Records()) != 1 || page.Records()[0].ID() != record.ID() {
+		t.Fatalf("expected the record to be saved, got %+v (%v)", page.Records(), err)
+	}
+	if _, ok := page.Records()[0].WordOrder(); !ok {
+		t.Fatal("expected the saved record to keep the word order")
+	}
+
+	if _, err := svc.Answer(ctx, session.ID(), "森", "白", time.Second); !errors.Is(err, domain.ErrHueSessionClosed) {
+		t.Fatalf("expected ErrHueSessionClosed after finish, got %v", err)
+	}
+	_, token = signer.Issue(time.Now())
+	if _, _, err := svc.Finish(ctx, session.ID(), name, "", token); !errors.Is(err, domain.ErrHueSessionClosed) {
+		t.Fatalf("expected ErrHueSessionClosed on second finish, got %v", err)
+	}
+
+	// 1 語だけ答えて止めたセッション。
+	dropped, err := svc.Start(ctx, uuid.Nil)
+	if err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	if _, err := svc.Answer(ctx, dropped.ID(), "夜", "白", 3*time.Second); err != nil {
+		t.Fatalf("answer error: %v", err)
+	}
+
+	funnel, err := svc.Funnel(ctx)
+	if err != nil {
+		t.Fatalf("funnel error: %v", err)
+	}
+	if funnel.Started() != 2 || funnel.Finished() != 1 || funnel.InProgress() != 1 {
+		t.Fatalf("unexpected funnel: %+v", funnel)
+	}
+	if reached := funnel.Reached(); len(reached) != 2 || reached[0] != 2 || reached[1] != 1 {
+		t.Fatalf("expected reached [2 1], got %v", reached)
+	}
+	timings := funnel.Timings()
+	if len(timings) != 2 || timings[0].Word() != "夜" || timings[0].Answers() != 2 || timings[0].MedianLatency() != 1200*time.Millisecond {
+		t.Fatalf("unexpected timings: %+v", timings)
+	}
+}
+
+func TestHueSessionService_FinishChallengeAndIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	sessions := memory.NewHueSessionRepository(hues)
+	signer := testChallengeSigner(t)
+	svc := NewHueSessionService(sessions, hues, activeWordSets(t, hues, "夜", "海"), activePalettes(t), signer, domain.WordOrderFixed, nil)
+	name, _ := domain.NewName("Tester")
+	key, _ := domain.NewIdempotencyKey("key-1")
+
+	start := func() domain.HueSession {
+		t.Helper()
+		session, err := svc.Start(ctx, uuid.Nil)
+		if err != nil {
+			t.Fatalf("start error: %v", err)
+		}
+		// クライアントが長い latency を名乗っても、開始直後に届いた回答は too_fast にする。
+		if _, err := svc.Answer(ctx, session.ID(), "夜", "黒", 5*time.Second); err != nil {
+			t.Fatalf("answer error: %v", err)
+		}
+		return session
+	}
+
+	session := start()
+	other, _ := domain.NewHueChallengeSigner([]byte(strings.Repeat("x", domain.MinHueChallengeSecretBytes)))
+	_, forged := other.Issue(time.Now())
+	for _, token := range []string{"", forged} {
+		if _, _, err := svc.Finish(ctx, session.ID(), name, key, token); !errors.Is(err, domain.ErrInvalidHueChallenge) {
+			t.Fatalf("%q: expected ErrInvalidHueChallenge, got %v", token, err)
+		}
+	}
+
+	challenge, token := signer.Issue(time.Now())
+	record, replayed, err := svc.Finish(ctx, session.ID(), name, key, token)
+	if err != nil || replayed {
+		t.Fatalf("finish error: %v (replayed %v)", err, replayed)
+	}
+	if id, ok := record.ChallengeID(); !ok || id != challenge.ID() {
+		t.Fatalf("expected the record to be proven by the challenge, got %v", id)
+	}
+	if flags := record.QualityFlags(); !slices.Equal(flags, []domain.HueQualityFlag{domain.HueQualityTooFast}) {
+		t.Fatalf("expected too_fast from the server time, got %v", flags)
+	}
+
+	// 応答を受け取れずに再送すると、終了済みでも保存済みの回答が返る。
+	replay, replayed, err := svc.Finish(ctx, session.ID(), name, key, token)
+	if err != nil || !replayed || replay.ID() != record.ID() {
+		t.Fatalf("expected the saved record to be replayed, got %v (replayed %v, %v)", replay.ID(), replayed, err)
+	}
+
+	// 同じキーで別のセッションを終了しようとした。
+	if _, _, err := svc.Finish(ctx, start().ID(), name, key, token); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Fatalf("expected ErrIdempotencyKeyReused for another session, got %v", err)
+	}
+	if _, _, err := svc.Finish(ctx, start().ID(), name, "", token); !errors.Is(err, domain.ErrHueChallengeUsed) {
+		t.Fatalf("expected ErrHueChallengeUsed for a second session, got %v", err)
+	}
+}
+
+func TestHueSessionService_StartRequiresWordSetAndPalette(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	sessions := memory.NewHueSessionRepository(hues)
+
+	if _, err := NewHueSessionService(sessions, hues, memory.NewWordSetRepository(hues, sessions), activePalettes(t), testChallengeSigner(t), domain.WordOrderShuffle, nil).Start(ctx, uuid.Nil); !errors.Is(err, domain.ErrWordSetNotFound) {
+		t.Fatalf("expected ErrWordSetNotFound, got %v", err)
+	}
+	if _, err := NewHueSessionService(sessions, hues, activeWordSets(t, hues, "夜"), memory.NewPaletteRepository(), testChallengeSigner(t), domain.WordOrderShuffle, nil).Start(ctx, uuid.Nil); !errors.Is(err, domain.ErrPaletteNotFound) {
+		t.Fatalf("expected ErrPaletteNotFound, got %v", err)
+	}
+}
+
+func TestHueSessionService_WordOrder(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	wordSets := activeWordSets(t, hues, "夜", "海", "森")
+
+	// 3 語のラテン方格は 6 行で、7 人目は 1 人目と同じ行に戻る。
+	latin := NewHueSessionService(memory.NewHueSessionRepository(hues), hues, wordSets, activePalettes(t), testChallengeSigner(t), domain.WordOrderLatinSquare, nil)
+	var orders [][]domain.HueWord
+	for i := range 7 {
+		session, err := latin.Start(ctx, uuid.Nil)
+		if err != nil {
+			t.Fatalf("start error: %v", err)
+		}
+		if order := session.Order(); order.Mode() != domain.WordOrderLatinSquare || order.Seed() != int64(i) {
+			t.Fatalf("session %d: expected row %d, got %s/%d", i, i, order.Mode(), order.Seed())
+		}
+		orders = append(orders, session.Words())
+	}
+	for position := range 3 {
+		seen := make(map[domain.HueWord]int)
+		for _, words := range orders[:6] {
+			seen[words[position]]++
+		}
+		if len(seen) != 3 || seen["夜"] != 2 || seen["海"] != 2 || seen["森"] != 2 {
+			t.Fatalf("expected every word twice at position %d, got %v", position, seen)
+		}
+	}
+	if !slices.Equal(orders[6], orders[0]) {
+		t.Fatalf("expected the seventh session to reuse the first row, got %v", orders[6])
+	}
+
+	shuffle := NewHueSessionService(memory.NewHueSessionRepository(hues), hues, wordSets, activePalettes(t), testChallengeSigner(t), domain.WordOrderShuffle, nil)
+	session, err := shuffle.Start(ctx, uuid.Nil)
+	if err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	order := session.Order()
+	if order.Mode() != domain.WordOrderShuffle || order.Seed() < 0 || order.Seed() >= maxShuffleSeed {
+		t.Fatalf("unexpected order: %s/%d", order.Mode(), order.Seed())
+	}
+	replayed, _ := domain.NewWordOrder(domain.WordOrderShuffle, order.Seed(), []domain.HueWord{"夜", "海", "森"})
+	if !slices.Equal(replayed.Words(), session.Words()) {
+		t.Fatalf("expected the seed to reproduce the order, got %v and %v", replayed.Words(), session.Words())
+	}
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func TestHueSaveAndGet(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	palettes := activePalettes(t)
+	saveService := NewHueSaveService(hues, activeWordSets(t, hues, "夜", "海"), palettes, testChallengeSigner(t), nil)
+	getService := NewHueGetService(hues, palettes, nil)
+
+	record, err := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	if err != nil {
+		t.Fatalf("record error: %v", err)
+	}
+
+	if err := submit(ctx, saveService, record); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	req, _ := domain.NewRecordPageRequest(domain.RecordFilter{}, domain.RecordCursor{}, 10, false)
+	page, err := getService.GetData(ctx, req)
+	if err != nil {
+		t.Fatalf("get error: %v", err)
+	}
+
+	records := page.Records()
+	if len(records) != 1 || records[0].ID() != record.ID() {
+		t.Fatalf("expected saved record to be returned")
+	}
+	if version, _ := records[0].PaletteVersion(); version != 1 || records[0].ChoiceMap()["夜"] != "black" {
+		t.Fatalf("expected colors to be stored as ids of palette 1, got %v (version %d)", records[0].ChoiceMap(), version)
+	}
+
+	filter, _ := domain.NewRecordFilter(time.Time{}, time.Time{}, "", "", map[string]string{"夜": "黒"})
+	req, _ = domain.NewRecordPageRequest(filter, domain.RecordCursor{}, 10, false)
+	if page, err := getService.GetData(ctx, req); err != nil || len(page.Records()) != 1 {
+		t.Fatalf("expected a filter by color name to match, got %v", err)
+	}
+
+	unknown, _ := domain.NewRecordFilter(time.Time{}, time.Time{}, "", "", map[string]string{"夜": "金"})
+	req, _ = domain.NewRecordPageRequest(unknown, domain.RecordCursor{}, 10, false)
+	if _, err := getService.GetData(ctx, req); !errors.Is(err, domain.ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter for a color outside the palette, got %v", err)
+	}
+}
+
+func TestHueGetService_GetUserResults(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	palettes := activePalettes(t)
+	saveService := NewHueSaveService(hues, activeWordSets(t, hues, "夜", "海"), palettes, testChallengeSigner(t), nil)
+	owner, other := uuid.New(), uuid.New()
+
+	var mine domain.HueRecord
+	for _, userID := range []uuid.UUID{owner, other, uuid.Nil} {
+		record, err := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+		if err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+		record = record.SubmittedBy(userID)
+		if userID == owner {
+			mine = record
+		}
+		if err := submit(ctx, saveService, record); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+
+	page, err := NewHueGetService(hues, palettes, nil).GetUserResults(ctx, owner, domain.RecordCursor{}, 0)
+	if err != nil {
+		t.Fatalf("get error: %v", err)
+	}
+	if records := page.Records(); len(records) != 1 || records[0].ID() != mine.ID() {
+		t.Fatalf("expected only the owner's record, got %d records", len(records))
+	}
+}
+
+func TestHueGetService_GetStats(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	palettes := activePalettes(t)
+	saveService := NewHueSaveService(hues, activeWordSets(t, hues, "夜", "海"), palettes, testChallengeSigner(t), nil)
+
+	for _, raw := range []map[string]string{
+		{"夜": "黒", "海": "青"},
+		{"夜": "黒", "海": "緑"},
+		{"夜": "紫"},
+	} {
+		record, err := domain.NewHueRecordFromRaw("Tester", raw)
+		if err != nil {
+			t.Fatalf("record error: %v", err)
+		}
+		if err := submit(ctx, saveService, record); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+
+	palette, stats, err := NewHueGetService(hues, palettes, nil).GetStats(ctx, 0)
+	if err != nil {
+		t.Fatalf("stats error: %v", err)
+	}
+	if palette.Version() != 1 {
+		t.Fatalf("expected the active palette to be aggregated, got %d", palette.Version())
+	}
+
+	if len(stats) != 2 || stats[0].Word() != "夜" || stats[1].Word() != "海" {
+		t.Fatalf("expected stats for 夜 and 海 in word order, got %d entries", len(stats))
+	}
+	for _, s := range stats {
+		switch s.Word() {
+		case "夜":
+			if mode, _ := s.Mode(); s.Total() != 3 || mode != "black" || s.Count("purple") != 1 {
+				t.Fatalf("unexpected stats for 夜: total=%d mode=%s", s.Total(), mode)
+			}
+		case "海":
+			if s.Total() != 2 || s.Count("blue") != 1 || s.Count("green") != 1 {
+				t.Fatalf("unexpected stats for 海: total=%d", s.Total())
+			}
+		}
+	}
+}
+
+// activeWordSets は words を出題中の単語セットとして登録したリポジトリを返す。
+func activeWordSets(t *testing.T, hues *memory.HueRepository, words ...string) *memory.WordSetRepository {
+	t.Helper()
+	repo := memory.NewWordSetRepository(hues, nil)
+	set, err := domain.NewWordSet("テスト", words, time.Now())
+	if err != nil {
+		t.Fatalf("word set error: %v", err)
+	}
+	if err := repo.Create(context.Background(), set.Activate(time.Now())); err != nil {
+		t.Fatalf("create word set error: %v", err)
+	}
+	return repo
+}
+
+func TestHueSaveService_ChecksWordSet(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	wordSets := activeWordSets(t, hues, "夜", "海")
+	svc := NewHueSaveService(hues, wordSets, activePalettes(t), testChallengeSigner(t), nil)
+	active, _ := wordSets.FindActive(ctx)
+
+	unknown, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒", "森": "緑"})
+	if err := submit(ctx, svc, unknown); !errors.Is(err, domain.ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice for a word outside the set, got %v", err)
+	}
+
+	missing, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	if err := submit(ctx, svc, missing.AnsweredFrom(uuid.New())); !errors.Is(err, domain.ErrWordSetNotFound) {
+		t.Fatalf("expected ErrWordSetNotFound for an unknown set, got %v", err)
+	}
+
+	partial, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	if err := submit(ctx, svc, partial); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	if used, _ := hues.HasWordSet(ctx, active.ID()); !used {
+		t.Fatalf("expected the record to be tagged with the active set")
+	}
+
+	empty := memory.NewWordSetRepository(hues, nil)
+	if err := submit(ctx, NewHueSaveService(hues, empty, activePalettes(t), testChallengeSigner(t), nil), partial); !errors.Is(err, domain.ErrWordSetNotFound) {
+		t.Fatalf("expected ErrWordSetNotFound without an active set, got %v", err)
+	}
+}
+
+// activePalettes は黒・青・緑・紫・白の版 1 を有効にしたリポジトリを返す。
+func activePalettes(t *testing.T) *memory.PaletteRepository {
+	t.Helper()
+
+	var colors []domain.PaletteColor
+	for _, c := range []struct{ id, ja, hex string }{
+		{"black", "黒", "#444444"},
+		{"blue", "青", "#4f86e2"},
+		{"green", "緑", "#4cad68"},
+		{"purple", "紫", "#956ec4"},
+		{"white", "白", "#f5f5f5"},
+	} {
+		rgb, _ := domain.ParseRGB(c.hex)
+		color, err := domain.NewPaletteColor(c.id, map[string]string{"ja": c.ja}, rgb, domain.LabFromRGB(rgb))
+		if err != nil {
+			t.Fatalf("palette color error: %v", err)
+		}
+		colors = append(colors, color)
+	}
+	palette, err := domain.NewPalette(1, colors, time.Now())
+	if err != nil {
+		t.Fatalf("palette error: %v", err)
+	}
+
+	repo := memory.NewPaletteRepository()
+	if err := repo.Create(context.Background(), palette.Activate()); err != nil {
+		t.Fatalf("create palette error: %v", err)
+	}
+	return repo
+}
+
+func TestHueSaveService_ChecksPalette(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	palettes := activePalettes(t)
+	svc := NewHueSaveService(hues, activeWordSets(t, hues, "夜", "海"), palettes, testChallengeSigner(t), nil)
+
+	gold, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "金"})
+	if err := submit(ctx, svc, gold); !errors.Is(err, domain.ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice for a color outside the palette, got %v", err)
+	}
+
+	claimed, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	if err := submit(ctx, svc, claimed.ColoredWith(2)); !errors.Is(err, domain.ErrPaletteNotFound) {
+		t.Fatalf("expected ErrPaletteNotFound for an unknown version, got %v", err)
+	}
+	if err := submit(ctx, NewHueSaveService(hues, activeWordSets(t, hues, "夜"), memory.NewPaletteRepository(), testChallengeSigner(t), nil), claimed); !errors.Is(err, domain.ErrPaletteNotFound) {
+		t.Fatalf("expected ErrPaletteNotFound without an active palette, got %v", err)
+	}
+
+	if err := submit(ctx, svc, claimed.ColoredWith(1)); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+}
+
+// testChallengeSigner はテスト用の固定鍵で署名する。
+func testChallengeSigner(t *testing.T) domain.HueChallengeSigner {
+	t.Helper()
+	signer, err := domain.NewHueChallengeSigner([]byte(strings.Repeat("k", domain.MinHueChallengeSecretBytes)))
+	if err != nil {
+		t.Fatalf("signer error: %v", err)
+	}
+	return signer
+}
+
+// submit は新しいチャレンジを発行して record を保存する。
+func submit(ctx context.Context, svc *HueSaveService, record domain.HueRecord) error {
+	_, token := svc.IssueChallenge(ctx)
+	_, _, err := svc.SaveResult(ctx, record, token)
+	return err
+}
+
+func TestHueSaveService_Challenge(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	svc := NewHueSaveService(hues, activeWordSets(t, hues, "夜", "海"), activePalettes(t), testChallengeSigner(t), nil)
+	record, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+
+	other, _ := domain.NewHueChallengeSigner([]byte(strings.Repeat("x", domain.MinHueChallengeSecretBytes)))
+	_, forged := other.Issue(time.Now())
+	for _, token := range []string{"", "abc.def", forged} {
+		if _, _, err := svc.SaveResult(ctx, record, token); !errors.Is(err, domain.ErrInvalidHueChallenge) {
+			t.Fatalf("%q: expected ErrInvalidHueChallenge, got %v", token, err)
+		}
+	}
+
+	challenge, token := svc.IssueChallenge(ctx)
+	saved, replayed, err := svc.SaveResult(ctx, record, token)
+	if err != nil || replayed {
+		t.Fatalf("save error: %v (replayed %v)", err, replayed)
+	}
+	if id, ok := saved.ChallengeID(); !ok || id != challenge.ID() {
+		t.Fatalf("expected the record to be proven by the challenge, got %v", id)
+	}
+
+	again, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	if _, _, err := svc.SaveResult(ctx, again, token); !errors.Is(err, domain.ErrHueChallengeUsed) {
+		t.Fatalf("expected ErrHueChallengeUsed for a second submission, got %v", err)
+	}
+}
+
+func TestHueSaveService_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	svc := NewHueSaveService(hues, activeWordSets(t, hues, "夜", "海"), activePalettes(t), testChallengeSigner(t), nil)
+	key, _ := domain.NewIdempotencyKey("key-1")
+
+	record, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	_, token := svc.IssueChallenge(ctx)
+	saved, replayed, err := svc.SaveResult(ctx, record.KeyedBy(key), token)
+	if err != nil || replayed {
+		t.Fatalf("save error: %v (replayed %v)", err, replayed)
+	}
+
+	// 応答を受け取れずに再送すると、使用済みのチャレンジのままでも保存済みの回答が返る。
+	retry, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	replay, replayed, err := svc.SaveResult(ctx, retry.KeyedBy(key), token)
+	if err != nil || !replayed || replay.ID() != saved.ID() {
+		t.Fatalf("expected the saved record to be replayed, got %v (replayed %v, %v)", replay.ID(), replayed, err)
+	}
+
+	different, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "青"})
+	if _, _, err := svc.SaveResult(ctx, different.KeyedBy(key), token); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Fatalf("expected ErrIdempotencyKeyReused for different answers, got %v", err)
+	}
+
+	req, _ := domain.NewRecordPageRequest(domain.RecordFilter{}, domain.RecordCursor{}, 10, false)
+	if page, err := hues.FindPage(ctx, req); err != nil || len(page.Records()) != 1 {
+		t.Fatalf("expected a single saved record, got %d (%v)", len(page.Records()), err)
+	}
+}
+
+func TestHueSaveService_QualityFlags(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	svc := NewHueSaveService(hues, activeWordSets(t, hues, "夜", "海", "森"), activePalettes(t), testChallengeSigner(t), nil)
+
+	// 発行直後に 3 語とも同じ色で送った回答は、保存したうえで両方の印を付ける。
+	record, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒", "海": "黒", "森": "黒"})
+	_, token := svc.IssueChallenge(ctx)
+	saved, _, err := svc.SaveResult(ctx, record, token)
+	if err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	want := []domain.HueQualityFlag{domain.HueQualityTooFast, domain.HueQualitySingleColor}
+	if !slices.Equal(saved.QualityFlags(), want) {
+		t.Fatalf("expected %v, got %v", want, saved.QualityFlags())
+	}
+
+	req, _ := domain.NewRecordPageRequest(domain.RecordFilter{}, domain.RecordCursor{}, 10, false)
+	page, err := hues.FindPage(ctx, req)
+	if err != nil || len(page.Records()) != 1 || !slices.Equal(page.Records()[0].QualityFlags(), want) {
+		t.Fatalf("expected the flags to be stored, got %+v (%v)", page.Records(), err)
+	}
+}
@@ -7,30 +7,63 @@ import (
 	"time"
 
 	"backend/internal/domain"
-	"backend/internal/repository"
 
 	"github.com/jackc/pgx/v5"
 )
 
+// LoginAttemptGuard はアカウントと接続元の組ごとにパスワードの照合の試行を制限する。
+type LoginAttemptGuard interface {
+	// Check は client からの account の試行を許可できなければ domain.ErrRateLimited を包んだエラーを返す。
+	Check(ctx context.Context, account, client string) error
+	Failed(account, client string)
+	Succeeded(account, client string)
+}
+
 // LoginService はログイン処理の具象実装を提供する雛形。
 type LoginService struct {
-	userRepo    *repository.UserRepository
-	sessionRepo *repository.LoginSessionRepository
+	userRepo    UserRepository
+	sessionRepo LoginSessionRepository
+	guard       LoginAttemptGuard
+	policy      domain.EmailVerificationPolicy
+	hasher      domain.PasswordHasher
+	sessionTTL  time.Duration
 	logger      *log.Logger
 }
 
-func NewLoginService(userRepo *repository.UserRepository, sessionRepo *repository.LoginSessionRepository, logger *log.Logger) *LoginService {
+// NewLoginService は guard が nil の場合、試行回数を制限しない。sessionTTL が 0 以下なら既定値を使う。
+// policy がログインにメールアドレスの確認を求める場合、未確認のユーザーには domain.ErrEmailNotVerified を返す。
+// ログインに成功したユーザーのハッシュが hasher の設定より古ければ、その場で作り直す。hasher が nil なら作り直さない。
+func NewLoginService(userRepo UserRepository, sessionRepo LoginSessionRepository, guard LoginAttemptGuard, policy domain.EmailVerificationPolicy, hasher domain.PasswordHasher, sessionTTL time.Duration, logger *log.Logger) *LoginService {
 	if logger == nil {
 		logger = log.Default()
 	}
-	return &LoginService{userRepo: userRepo, sessionRepo: sessionRepo, logger: logger}
+	return &LoginService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		guard:       guard,
+		policy:      policy,
+		hasher:      hasher,
+		sessionTTL:  sessionTTLOrDefault(sessionTTL),
+		logger:      logger,
+	}
 }
 
-func (s *LoginService) Login(ctx context.Context, credential domain.AdminCredential) (domain.SessionData, domain.UserRole, error) {
+// Login は clientIP からのログインを受け付ける。連続した失敗は clientIP ごとに数えるため、
+// 他人が同じユーザー名で失敗を重ねても、そのユーザー自身の接続元からのログインは締め出されない。
+func (s *LoginService) Login(ctx context.Context, credential domain.AdminCredential, clientIP string) (domain.SessionData, domain.UserRole, error) {
+	username := credential.Name().String()
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, username, clientIP); err != nil {
+			s.logError("login attempt rejected", err)
+			return domain.SessionData{}, "", err
+		}
+	}
+
 	user, err := s.userRepo.FindByName(ctx, credential.Name())
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			s.logError("user not found", err)
+			s.recordFailure(username, clientIP)
 			return domain.SessionData{}, "", domain.ErrInvalidCredential
 		}
 		s.logError("find user by name", err)
@@ -44,41 +77,61 @@ func (s *LoginService) Login(ctx context.Context, credential domain.AdminCredent
 		}
 	*/
 
-	if err := user.HashedPassword().Verify(credential.Password()); err != nil {
+	matched, err := verifyPassword(user.HashedPassword(), credential.Password())
+	if err != nil {
 		s.logError("password verification failed", err)
+		s.recordFailure(username, clientIP)
 		return domain.SessionData{}, "", domain.ErrInvalidCredential
 	}
 
-	token, err := domain.NewLoginSessionToken()
-	if err != nil {
-		s.logError("issue login token", err)
-		return domain.SessionData{}, "", err
+	if s.guard != nil {
+		s.guard.Succeeded(username, clientIP)
 	}
 
-	sessionData, err := domain.NewSessionData(user.ID(), token)
-	if err != nil {
-		s.logError("build session data", err)
+	// パスワードが合っている場合に限り、無効化されていることを伝える。
+	if user.IsDisabled() {
+		s.logError("login by disabled user", domain.ErrUserDisabled)
+		return domain.SessionData{}, "", domain.ErrUserDisabled
+	}
+	if err := s.policy.CheckLogin(user); err != nil {
 		return domain.SessionData{}, "", err
 	}
+	s.rehashIfNeeded(ctx, user, matched)
 
-	hashedToken, err := token.Hash()
+	sessionData, err := issueLoginSession(ctx, s.sessionRepo, user.ID(), time.Now(), s.sessionTTL)
 	if err != nil {
-		s.logError("hash login token", err)
+		s.logError("issue login session", err)
 		return domain.SessionData{}, "", err
 	}
 
-	session, err := domain.NewLoginSession(user.ID(), hashedToken, time.Now())
-	if err != nil {
-		s.logError("build login session", err)
-		return domain.SessionData{}, "", err
+	return sessionData, user.Role(), nil
+}
+
+// rehashIfNeeded は古い方式・パラメータのハッシュと、前後の空白を除いてから作った以前のハッシュを password で作り直す。
+// 作り直したハッシュには以前の印が付かないため、それ以降は入力どおりにしか照合しない。失敗してもログインは続ける。
+// 照合してから保存するまでにパスワードが変わっていれば、新しい方を残す。
+func (s *LoginService) rehashIfNeeded(ctx context.Context, user domain.User, password string) {
+	if s.hasher == nil {
+		return
+	}
+	if current := user.HashedPassword(); !current.IsLegacyTrimmed() && !s.hasher.NeedsRehash(current) {
+		return
 	}
 
-	if err := s.sessionRepo.Create(ctx, session); err != nil {
-		s.logError("persist login session", err)
-		return domain.SessionData{}, "", err
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		s.logError("rehash password", err)
+		return
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID(), user.HashedPassword(), hashed); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		s.logError("update password hash", err)
 	}
+}
 
-	return sessionData, user.Role(), nil
+func (s *LoginService) recordFailure(username, clientIP string) {
+	if s.guard != nil {
+		s.guard.Failed(username, clientIP)
+	}
 }
 
 func (s *LoginService) logError(action string, err error) {
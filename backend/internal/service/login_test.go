@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/ratelimit"
+	"backend/internal/repository/memory"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginService_Login_Success(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	user := createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+
+	svc := NewLoginService(users, sessions, nil, domain.EmailVerificationPolicy{}, nil, 0, nil)
+	data, role, err := svc.Login(ctx, buildCredential(t, "admin", "secret"), testClientIP)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data.UserID() != user.ID() || role != domain.UserRoleAdmin {
+		t.Fatalf("unexpected session data or role")
+	}
+
+	if data.ExpiresAt().IsZero() {
+		t.Fatalf("expected expires_at to be set")
+	}
+
+	stored, err := sessions.FindByID(ctx, data.Token().ID())
+	if err != nil {
+		t.Fatalf("expected persisted session: %v", err)
+	}
+	if err := stored.Verify(data.Token()); err != nil {
+		t.Fatalf("persisted session should verify issued token: %v", err)
+	}
+}
+
+func TestLoginService_Login_InvalidCredential(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+	svc := NewLoginService(users, memory.NewLoginSessionRepository(), nil, domain.EmailVerificationPolicy{}, nil, 0, nil)
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "admin", "wrong"), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected ErrInvalidCredential for wrong password, got %v", err)
+	}
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "nobody", "secret"), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected ErrInvalidCredential for unknown user, got %v", err)
+	}
+}
+
+func TestLoginService_Login_Lockout(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+	guard := ratelimit.NewLoginGuard("login", nil, ratelimit.NewLockout(2, time.Minute))
+	svc := NewLoginService(users, memory.NewLoginSessionRepository(), guard, domain.EmailVerificationPolicy{}, nil, 0, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := svc.Login(ctx, buildCredential(t, "admin", "wrong"), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+			t.Fatalf("attempt %d: expected ErrInvalidCredential, got %v", i, err)
+		}
+	}
+
+	_, _, err := svc.Login(ctx, buildCredential(t, "admin", "secret"), testClientIP)
+	var rateErr *domain.RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.RetryAfter() <= 0 {
+		t.Fatalf("expected RateLimitError while locked, got %v", err)
+	}
+}
+
+func TestLoginService_Login_LockoutIsPerClient(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+	guard := ratelimit.NewLoginGuard("login", nil, ratelimit.NewLockout(2, time.Minute))
+	svc := NewLoginService(users, memory.NewLoginSessionRepository(), guard, domain.EmailVerificationPolicy{}, nil, 0, nil)
+
+	const attacker = "198.51.100.7"
+	for i := 0; i < 3; i++ {
+		if _, _, err := svc.Login(ctx, buildCredential(t, "admin", "wrong"), attacker); err == nil {
+			t.Fatalf("attempt %d: expected failure", i)
+		}
+	}
+	if _, _, err := svc.Login(ctx, buildCredential(t, "admin", "secret"), attacker); !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("expected attacker to be locked out, got %v", err)
+	}
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "admin", "secret"), testClientIP); err != nil {
+		t.Fatalf("owner should still log in from another client, got %v", err)
+	}
+}
+
+// testHasher はテストを速くするため最小コストの bcrypt を使う。
+var testHasher, _ = domain.NewBcryptHasher(bcrypt.MinCost)
+
+func createUser(t *testing.T, users UserRepository, name, password string, role domain.UserRole) domain.User {
+	t.Helper()
+	hashed, err := testHasher.Hash(password)
+	if err != nil {
+		t.Fatalf("hash error: %v", err)
+	}
+	n, err := domain.NewName(name)
+	if err != nil {
+		t.Fatalf("name error: %v", err)
+	}
+	email, err := domain.NewEmail(name + "@example.com")
+	if err != nil {
+		t.Fatalf("email error: %v", err)
+	}
+	user, err := domain.NewUser(n, email, hashed, role, time.Now())
+	if err != nil {
+		t.Fatalf("user error: %v", err)
+	}
+	if err := users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user error: %v", err)
+	}
+	return user
+}
+
+// testClientIP はテストでログインする接続元。
+const testClientIP = "203.0.113.1"
+
+func buildCredential(t *testing.T, name, password string) domain.AdminCredential {
+	t.Helper()
+	n, err := domain.NewName(name)
+	if err != nil {
+		t.Fatalf("name error: %v", err)
+	}
+	credential, err := domain.NewAdminCredential(n, password)
+	if err != nil {
+		t.Fatalf("credential error: %v", err)
+	}
+	return credential
+}
+
+// createLegacyUser は前後の空白を除いてからハッシュ化していた頃に password で登録したユーザーを作る。
+func createLegacyUser(t *testing.T, users *memory.UserRepository, name, password string) domain.User {
+	t.Helper()
+	user := createUser(t, users, name, password, domain.UserRoleUser)
+	legacy := user.HashedPassword().LegacyTrimmed()
+	if err := users.UpdatePassword(context.Background(), user.ID(), user.HashedPassword(), legacy); err != nil {
+		t.Fatalf("mark legacy error: %v", err)
+	}
+	user, _ = user.RehashPassword(legacy)
+	return user
+}
+
+func TestLoginService_AcceptsPasswordsStoredTrimmed(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	// 以前のサインインは前後の空白を除いてからハッシュ化していた。
+	createLegacyUser(t, users, "alice", "secret")
+	createUser(t, users, "bob", " spaced ", domain.UserRoleUser)
+	createUser(t, users, "dave", "secret", domain.UserRoleUser)
+	svc := NewLoginService(users, memory.NewLoginSessionRepository(), nil, domain.EmailVerificationPolicy{}, nil, 0, nil)
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "alice", "  secret "), testClientIP); err != nil {
+		t.Fatalf("expected legacy trimmed password to be accepted, got %v", err)
+	}
+	if _, _, err := svc.Login(ctx, buildCredential(t, "bob", " spaced "), testClientIP); err != nil {
+		t.Fatalf("expected password with spaces to be accepted, got %v", err)
+	}
+	if _, _, err := svc.Login(ctx, buildCredential(t, "bob", "spaced"), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected trimmed input not to match a password stored with spaces, got %v", err)
+	}
+	// 入力どおりに作った bcrypt のハッシュは、前後に空白を足した入力を受け付けない。
+	if _, _, err := svc.Login(ctx, buildCredential(t, "dave", "  secret "), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected padded input not to match a hash of the untrimmed password, got %v", err)
+	}
+
+	// argon2id のハッシュは入力どおりに作っているため、前後の空白を除いて照合し直さない。
+	argon2, _ := domain.NewArgon2idHasher(64, 1, 1)
+	hashed, err := argon2.Hash("secret")
+	if err != nil {
+		t.Fatalf("hash error: %v", err)
+	}
+	carol := createUser(t, users, "carol", "secret", domain.UserRoleUser)
+	if _, err := users.SetPassword(ctx, carol.ID(), hashed.LegacyTrimmed(), time.Now()); err != nil {
+		t.Fatalf("set password error: %v", err)
+	}
+	if _, _, err := svc.Login(ctx, buildCredential(t, "carol", "  secret "), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected untrimmed input not to match an argon2id hash, got %v", err)
+	}
+}
+
+func TestLoginService_RehashesOutdatedHash(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	user := createUser(t, users, "alice", "secret", domain.UserRoleUser)
+	argon2, err := domain.NewArgon2idHasher(64, 1, 1)
+	if err != nil {
+		t.Fatalf("hasher error: %v", err)
+	}
+	svc := NewLoginService(users, memory.NewLoginSessionRepository(), nil, domain.EmailVerificationPolicy{}, argon2, 0, nil)
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "alice", "wrong"), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected ErrInvalidCredential, got %v", err)
+	}
+	if stored, _ := users.FindByID(ctx, user.ID()); stored.HashedPassword() != user.HashedPassword() {
+		t.Fatalf("expected failed login to keep the hash")
+	}
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "alice", "secret"), testClientIP); err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+	stored, _ := users.FindByID(ctx, user.ID())
+	if argon2.NeedsRehash(stored.HashedPassword()) {
+		t.Fatalf("expected hash to be upgraded, got %q", stored.HashedPassword())
+	}
+	if !stored.UpdatedAt().Equal(user.UpdatedAt()) {
+		t.Fatalf("expected rehash to keep updated_at")
+	}
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "alice", "secret"), testClientIP); err != nil {
+		t.Fatalf("login with upgraded hash error: %v", err)
+	}
+	if again, _ := users.FindByID(ctx, user.ID()); again.HashedPassword() != stored.HashedPassword() {
+		t.Fatalf("expected up-to-date hash to be kept")
+	}
+}
+
+func TestLoginService_RehashKeepsTrimmedLegacyPassword(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	user := createLegacyUser(t, users, "alice", "secret")
+	// 方式もパラメータも現在の設定のままでも、以前のハッシュは作り直して印を外す。
+	svc := NewLoginService(users, memory.NewLoginSessionRepository(), nil, domain.EmailVerificationPolicy{}, testHasher, 0, nil)
+
+	if _, _, err := svc.Login(ctx, buildCredential(t, "alice", " secret "), testClientIP); err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+	stored, _ := users.FindByID(ctx, user.ID())
+	if stored.HashedPassword().IsLegacyTrimmed() || stored.HashedPassword() == user.HashedPassword() {
+		t.Fatalf("expected the legacy hash to be replaced, got %q", stored.HashedPassword())
+	}
+	if err := stored.HashedPassword().Verify("secret"); err != nil {
+		t.Fatalf("expected upgraded hash to keep the stored password: %v", err)
+	}
+	if _, _, err := svc.Login(ctx, buildCredential(t, "alice", " secret "), testClientIP); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected padded input to be rejected once the hash is replaced, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PaletteService は回答で選べる色のパレットを版ごとに管理する。版は作った後に変えず、管理操作は監査ログに残す。
+type PaletteService struct {
+	paletteRepo PaletteRepository
+	auditRepo   AdminAuditRepository
+	logger      *log.Logger
+}
+
+func NewPaletteService(paletteRepo PaletteRepository, auditRepo AdminAuditRepository, logger *log.Logger) *PaletteService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &PaletteService{paletteRepo: paletteRepo, auditRepo: auditRepo, logger: logger}
+}
+
+// Palette は version の版を返す。version が 0 なら有効な版を返す。無ければ domain.ErrPaletteNotFound を返す。
+func (s *PaletteService) Palette(ctx context.Context, version int) (domain.Palette, error) {
+	palette, err := findPalette(ctx, s.paletteRepo, version)
+	if err != nil && !errors.Is(err, domain.ErrPaletteNotFound) {
+		s.logError("find palette", err)
+	}
+	return palette, err
+}
+
+// ListPalettes はすべての版を版の昇順で返す。
+func (s *PaletteService) ListPalettes(ctx context.Context) ([]domain.Palette, error) {
+	palettes, err := s.paletteRepo.List(ctx)
+	if err != nil {
+		s.logError("list palettes", err)
+		return nil, err
+	}
+	return palettes, nil
+}
+
+// CreatePalette は最新の版の次の版として colors のパレットを作る。activate なら作った版を有効にする。
+func (s *PaletteService) CreatePalette(ctx context.Context, actorID uuid.UUID, colors []domain.PaletteColor, activate bool) (domain.Palette, error) {
+	palettes, err := s.ListPalettes(ctx)
+	if err != nil {
+		return domain.Palette{}, err
+	}
+	version := 1
+	if len(palettes) > 0 {
+		version = palettes[len(palettes)-1].Version() + 1
+	}
+
+	now := time.Now()
+	palette, err := domain.NewPalette(version, colors, now)
+	if err != nil {
+		return domain.Palette{}, err
+	}
+
+	if err := s.paletteRepo.Create(ctx, palette); err != nil {
+		s.logError("create palette", err)
+		return domain.Palette{}, err
+	}
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, palette.ID(), domain.AdminActionCreatePalette, fmt.Sprintf("version %d (%d colors)", version, len(colors)), now)
+
+	if !activate {
+		return palette, nil
+	}
+	return s.ActivatePalette(ctx, actorID, version)
+}
+
+// ActivatePalette は version の版を有効にし、それまでの版を回答で選べないようにする。
+func (s *PaletteService) ActivatePalette(ctx context.Context, actorID uuid.UUID, version int) (domain.Palette, error) {
+	if version <= 0 {
+		return domain.Palette{}, domain.ErrPaletteNotFound
+	}
+	if err := s.paletteRepo.Activate(ctx, version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Palette{}, domain.ErrPaletteNotFound
+		}
+		s.logError("activate palette", err)
+		return domain.Palette{}, err
+	}
+
+	palette, err := s.Palette(ctx, version)
+	if err != nil {
+		return domain.Palette{}, err
+	}
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, palette.ID(), domain.AdminActionActivatePalette, fmt.Sprintf("version %d", version), time.Now())
+	return palette, nil
+}
+
+func (s *PaletteService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[PaletteService] %s: %v", action, err)
+}
+
+// findPalette は version の版を、version が 0 なら有効な版を引く。見つからなければ domain.ErrPaletteNotFound を返す。
+func findPalette(ctx context.Context, repo PaletteRepository, version int) (domain.Palette, error) {
+	var (
+		palette domain.Palette
+		err     error
+	)
+	switch {
+	case version < 0:
+		return domain.Palette{}, domain.ErrPaletteNotFound
+	case version == 0:
+		palette, err = repo.FindActive(ctx)
+	default:
+		palette, err = repo.FindByVersion(ctx, version)
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Palette{}, domain.ErrPaletteNotFound
+	}
+	return palette, err
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func TestPaletteService_Versions(t *testing.T) {
+	ctx := context.Background()
+	audits := memory.NewAdminAuditRepository()
+	palettes := activePalettes(t)
+	svc := NewPaletteService(palettes, audits, nil)
+	actor := uuid.New()
+
+	current, err := svc.Palette(ctx, 0)
+	if err != nil || current.Version() != 1 {
+		t.Fatalf("expected version 1 to be active, got %d (%v)", current.Version(), err)
+	}
+
+	colors := current.Colors()
+	gold, err := domain.NewPaletteColor("gold", map[string]string{"ja": "金", "en": "Gold"}, domain.RGB{R: 0xd4, G: 0xaf, B: 0x37}, domain.Lab{L: 73.4, A: 1.2, B: 60.1})
+	if err != nil {
+		t.Fatalf("color error: %v", err)
+	}
+
+	created, err := svc.CreatePalette(ctx, actor, append(colors, gold), false)
+	if err != nil || created.Version() != 2 || created.IsActive() {
+		t.Fatalf("expected an inactive version 2, got %d active=%v (%v)", created.Version(), created.IsActive(), err)
+	}
+	if active, _ := svc.Palette(ctx, 0); active.Version() != 1 {
+		t.Fatalf("creating a version should not change the active one")
+	}
+
+	if _, err := svc.ActivatePalette(ctx, actor, 2); err != nil {
+		t.Fatalf("activate error: %v", err)
+	}
+	list, _ := svc.ListPalettes(ctx)
+	if len(list) != 2 || list[0].IsActive() || !list[1].IsActive() {
+		t.Fatalf("expected only version 2 to be active")
+	}
+
+	if _, err := svc.ActivatePalette(ctx, actor, 9); !errors.Is(err, domain.ErrPaletteNotFound) {
+		t.Fatalf("expected ErrPaletteNotFound, got %v", err)
+	}
+	if _, err := svc.CreatePalette(ctx, actor, colors[:1], false); !errors.Is(err, domain.ErrInvalidPalette) {
+		t.Fatalf("expected ErrInvalidPalette for a single color, got %v", err)
+	}
+
+	entries, _ := audits.ListRecent(ctx, 10)
+	if len(entries) != 2 || entries[0].Action() != domain.AdminActionActivatePalette || entries[0].TargetID() != created.ID() {
+		t.Fatalf("expected create and activate to be audited, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/mail"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Mailer はメールの配送境界。SMTP とファイル出力の実装を設定で切り替える。
+type Mailer interface {
+	Send(ctx context.Context, msg mail.Message) error
+}
+
+// PasswordService はパスワードの変更と、メールによる再設定を扱う。
+type PasswordService struct {
+	userRepo    UserRepository
+	sessionRepo LoginSessionRepository
+	resetRepo   PasswordResetRepository
+	mailer      Mailer
+	guard       LoginAttemptGuard
+	policy      domain.PasswordPolicy
+	hasher      domain.PasswordHasher
+	resetTTL    time.Duration
+	resetURL    string
+	logger      *log.Logger
+}
+
+// NewPasswordService は hasher が nil なら domain.DefaultBcryptHasher、resetTTL が 0 以下なら domain.DefaultPasswordResetTTL を使う。
+// resetURL はメールに載せる再設定ページの URL で、token クエリを付けて送る。新しいパスワードには policy を適用する。
+// guard はパスワード変更時の現在のパスワードの照合を、ユーザー ID ごとと、ユーザー ID とセッションの組ごとに制限する。
+// ログインの失敗とキーが重ならないよう、ログインとは別のガードを渡す。nil なら制限しない。
+func NewPasswordService(userRepo UserRepository, sessionRepo LoginSessionRepository, resetRepo PasswordResetRepository, mailer Mailer, guard LoginAttemptGuard, policy domain.PasswordPolicy, hasher domain.PasswordHasher, resetTTL time.Duration, resetURL string, logger *log.Logger) *PasswordService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if resetTTL <= 0 {
+		resetTTL = domain.DefaultPasswordResetTTL
+	}
+	return &PasswordService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		resetRepo:   resetRepo,
+		mailer:      mailer,
+		guard:       guard,
+		policy:      policy,
+		hasher:      passwordHasherOrDefault(hasher),
+		resetTTL:    resetTTL,
+		resetURL:    resetURL,
+		logger:      logger,
+	}
+}
+
+// ChangePassword は現在のパスワードを確かめてから新しいパスワードに置き換え、呼び出し元以外のセッションと
+// 未使用の再設定申請を破棄する。現在のパスワードが違えば domain.ErrInvalidCredential を返す。
+// 盗んだセッションで総当たりされないよう、照合の試行はユーザー ID ごとに制限し、連続した失敗はセッションごとに数えてロックする。
+// 超えれば domain.ErrRateLimited を包んだエラーを返す。
+func (s *PasswordService) ChangePassword(ctx context.Context, session domain.LoginSession, user domain.User, oldPassword, newPassword string) error {
+	key, client := user.ID().String(), session.ID().String()
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, key, client); err != nil {
+			s.logError("password change attempt rejected", err)
+			return err
+		}
+	}
+
+	if _, err := verifyPassword(user.HashedPassword(), oldPassword); err != nil {
+		if s.guard != nil {
+			s.guard.Failed(key, client)
+		}
+		return domain.ErrInvalidCredential
+	}
+	if s.guard != nil {
+		s.guard.Succeeded(key, client)
+	}
+
+	if err := s.replacePassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionRepo.DeleteByUserIDExcept(ctx, user.ID(), session.ID()); err != nil {
+		s.logError("revoke other sessions", err)
+		return err
+	}
+	// 変更前に申請されたリンクで、変えたばかりのパスワードを上書きされないようにする。
+	if _, err := s.resetRepo.DeleteByUserID(ctx, user.ID()); err != nil {
+		s.logError("delete password resets", err)
+		return err
+	}
+	return nil
+}
+
+// RequestPasswordReset は email のユーザーへ再設定用のリンクを送る。
+// 登録の有無を推測されないよう、該当するユーザーがいない・無効化されている場合も何もせず nil を返す。
+// 申請の保存やメールの送信に失敗しても、登録済みのときだけエラーになって見分けられないよう、記録するだけで nil を返す。
+func (s *PasswordService) RequestPasswordReset(ctx context.Context, email domain.Email) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		s.logError("find user by email", err)
+		return err
+	}
+	if user.IsDisabled() {
+		return nil
+	}
+
+	token, err := domain.NewOneTimeToken()
+	if err != nil {
+		s.logError("generate reset token", err)
+		return nil
+	}
+
+	reset, err := domain.NewPasswordReset(user.ID(), token, time.Now(), s.resetTTL)
+	if err != nil {
+		s.logError("build password reset", err)
+		return nil
+	}
+
+	if err := s.resetRepo.Create(ctx, reset); err != nil {
+		s.logError("create password reset", err)
+		return nil
+	}
+
+	if err := s.mailer.Send(ctx, s.resetMessage(user, token)); err != nil {
+		s.logError("send reset mail", err)
+	}
+	return nil
+}
+
+// ConfirmPasswordReset はトークンを一度だけ使ってパスワードを置き換え、対象ユーザーの全セッションと残りの申請を破棄する。
+// トークンが不正・期限切れ・使用済みのいずれでも domain.ErrInvalidResetToken を返す。
+func (s *PasswordService) ConfirmPasswordReset(ctx context.Context, rawToken, newPassword string) error {
+	token, err := domain.ParseOneTimeToken(rawToken)
+	if err != nil {
+		return domain.ErrInvalidResetToken
+	}
+
+	// トークンを消費する前にパスワードを検査し、入力ミスで申請が無駄にならないようにする。
+	hashed, err := s.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	reset, err := s.resetRepo.FindByID(ctx, token.ID())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrInvalidResetToken
+	}
+	if err != nil {
+		s.logError("find password reset", err)
+		return err
+	}
+
+	now := time.Now()
+	if err := reset.Verify(token, now); err != nil {
+		return err
+	}
+
+	// 申請を使用済みにしてからパスワードを置き換えるまでに失敗しても、片方だけが残らないようにまとめて行う。
+	user, err := s.resetRepo.Redeem(ctx, reset.ID(), hashed, now)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrInvalidResetToken
+		}
+		s.logError("redeem password reset", err)
+		return err
+	}
+
+	if _, err := s.sessionRepo.DeleteByUserID(ctx, user.ID()); err != nil {
+		s.logError("revoke sessions", err)
+		return err
+	}
+	if _, err := s.resetRepo.DeleteByUserID(ctx, user.ID()); err != nil {
+		s.logError("delete password resets", err)
+		return err
+	}
+	return nil
+}
+
+func (s *PasswordService) replacePassword(ctx context.Context, user domain.User, newPassword string) error {
+	hashed, err := s.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.SetPassword(ctx, user.ID(), hashed, time.Now()); err != nil {
+		s.logError("update password", err)
+		return err
+	}
+	return nil
+}
+
+func (s *PasswordService) resetMessage(user domain.User, token domain.OneTimeToken) mail.Message {
+	link := linkWithToken(s.resetURL, token)
+
+	body := fmt.Sprintf(`%s さん
+
+パスワードの再設定が申請されました。次のリンクから %d 分以内に新しいパスワードを設定してください。
+
+%s
+
+心当たりがない場合はこのメールを破棄してください。パスワードは変更されません。
+`, user.Username(), int(s.resetTTL/time.Minute), link)
+
+	return mail.Message{To: user.Email().String(), Subject: "パスワードの再設定", Body: body}
+}
+
+func (s *PasswordService) hashPassword(password string) (domain.HashedPassword, error) {
+	hashed, err := hashPassword(s.policy, s.hasher, password)
+	if err != nil && !errors.Is(err, domain.ErrInvalidPassword) {
+		s.logError("hash password", err)
+	}
+	return hashed, err
+}
+
+func (s *PasswordService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[PasswordService] %s: %v", action, err)
+}
+
+// hashPassword は policy を満たす平文を、入力されたとおりに hasher でハッシュ化する。
+// 満たさなければ domain.ErrInvalidPassword を包んだ *domain.PasswordPolicyError を返す。
+func hashPassword(policy domain.PasswordPolicy, hasher domain.PasswordHasher, password string) (domain.HashedPassword, error) {
+	if err := policy.Check(password); err != nil {
+		return domain.HashedPassword{}, err
+	}
+	return hasher.Hash(password)
+}
+
+// verifyPassword は password を入力されたとおりに照合し、一致した平文を返す。一致しなければ、以前は前後の空白を
+// 除いてからハッシュ化していたため、その頃の印が付いたハッシュに限って除いたものでも照合する。それ以外のハッシュは
+// 入力どおりの平文から作っているため、除いたもので照合し直すことはしない。
+func verifyPassword(hashed domain.HashedPassword, password string) (string, error) {
+	err := hashed.Verify(password)
+	if err == nil {
+		return password, nil
+	}
+	if trimmed := strings.TrimSpace(password); hashed.IsLegacyTrimmed() && trimmed != password && trimmed != "" {
+		if err := hashed.Verify(trimmed); err != nil {
+			return "", err
+		}
+		return trimmed, nil
+	}
+	return "", err
+}
+
+// linkWithToken はメールに載せるリンクとして、base の URL に token クエリを付ける。base を解釈できなければそのまま返す。
+func linkWithToken(base string, token domain.OneTimeToken) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	q := u.Query()
+	q.Set("token", token.String())
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func passwordHasherOrDefault(hasher domain.PasswordHasher) domain.PasswordHasher {
+	if hasher == nil {
+		return domain.DefaultBcryptHasher()
+	}
+	return hasher
+}
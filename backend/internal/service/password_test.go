@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/mail"
+	"backend/internal/ratelimit"
+	"backend/internal/repository/memory"
+)
+
+type recordingMailer struct {
+	sent []mail.Message
+	err  error
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+func TestPasswordService_RequestPasswordResetHidesMailFailure(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+	mailer := &recordingMailer{err: errors.New("smtp unavailable")}
+	svc := NewPasswordService(users, memory.NewLoginSessionRepository(), memory.NewPasswordResetRepository(users), mailer, nil, domain.PasswordPolicy{}, testHasher, time.Hour, "https://example.com/reset-password", nil)
+
+	// 登録済みのアドレスだけがエラーになると、登録の有無が分かってしまう。
+	email, _ := domain.NewEmail("alice@example.com")
+	if err := svc.RequestPasswordReset(ctx, email); err != nil || len(mailer.sent) != 1 {
+		t.Fatalf("expected the mail failure to be hidden, got %v (%d mails)", err, len(mailer.sent))
+	}
+}
+
+func TestPasswordService_ChangePasswordKeepsCurrentSession(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	user := createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+
+	login := NewLoginService(users, sessions, nil, domain.EmailVerificationPolicy{}, nil, 0, nil)
+	current, _, err := login.Login(ctx, buildCredential(t, "alice", "old-secret"), testClientIP)
+	if err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+	other, _, err := login.Login(ctx, buildCredential(t, "alice", "old-secret"), testClientIP)
+	if err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+	session, _ := sessions.FindByID(ctx, current.Token().ID())
+
+	svc := NewPasswordService(users, sessions, memory.NewPasswordResetRepository(users), &recordingMailer{}, nil, domain.PasswordPolicy{}, testHasher, 0, "", nil)
+	if err := svc.ChangePassword(ctx, session, user, "wrong", "new-secret"); !errors.Is(err, domain.ErrInvalidCredential) {
+		t.Fatalf("expected ErrInvalidCredential, got %v", err)
+	}
+	if err := svc.ChangePassword(ctx, session, user, "old-secret", "new-secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := sessions.FindByID(ctx, current.Token().ID()); err != nil {
+		t.Fatalf("expected the current session to survive: %v", err)
+	}
+	if _, err := sessions.FindByID(ctx, other.Token().ID()); err == nil {
+		t.Fatalf("expected other sessions to be revoked")
+	}
+	if _, _, err := login.Login(ctx, buildCredential(t, "alice", "new-secret"), testClientIP); err != nil {
+		t.Fatalf("expected new password to be accepted, got %v", err)
+	}
+}
+
+func TestPasswordService_ChangePasswordLockout(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	user := createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+	guard := ratelimit.NewLoginGuard("password-change", nil, ratelimit.NewLockout(2, time.Minute))
+	svc := NewPasswordService(users, memory.NewLoginSessionRepository(), memory.NewPasswordResetRepository(users), &recordingMailer{}, guard, domain.PasswordPolicy{}, testHasher, 0, "", nil)
+
+	for i := 0; i < 2; i++ {
+		if err := svc.ChangePassword(ctx, domain.LoginSession{}, user, "wrong", "new-secret"); !errors.Is(err, domain.ErrInvalidCredential) {
+			t.Fatalf("attempt %d: expected ErrInvalidCredential, got %v", i, err)
+		}
+	}
+
+	err := svc.ChangePassword(ctx, domain.LoginSession{}, user, "old-secret", "new-secret")
+	var rateErr *domain.RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.RetryAfter() <= 0 {
+		t.Fatalf("expected RateLimitError while locked, got %v", err)
+	}
+}
+
+func TestPasswordService_ChangePasswordDropsPendingResets(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	resets := memory.NewPasswordResetRepository(users)
+	mailer := &recordingMailer{}
+	user := createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+	svc := NewPasswordService(users, memory.NewLoginSessionRepository(), resets, mailer, nil, domain.PasswordPolicy{}, testHasher, time.Hour, "https://example.com/reset-password", nil)
+
+	if err := svc.RequestPasswordReset(ctx, user.Email()); err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	link := regexp.MustCompile(`https://example\.com/reset-password\?token=\S+`).FindString(mailer.sent[0].Body)
+	parsed, err := url.Parse(link)
+	if err != nil || link == "" {
+		t.Fatalf("expected reset link in body: %q", mailer.sent[0].Body)
+	}
+
+	if err := svc.ChangePassword(ctx, domain.LoginSession{}, user, "old-secret", "new-secret"); err != nil {
+		t.Fatalf("change error: %v", err)
+	}
+	// 変更前に申請されたリンクでは、変えたばかりのパスワードを上書きできない。
+	if err := svc.ConfirmPasswordReset(ctx, parsed.Query().Get("token"), "another"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("expected the pending reset to be revoked, got %v", err)
+	}
+}
+
+func TestPasswordService_ResetFlow(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	resets := memory.NewPasswordResetRepository(users)
+	mailer := &recordingMailer{}
+	createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+
+	login := NewLoginService(users, sessions, nil, domain.EmailVerificationPolicy{}, nil, 0, nil)
+	data, _, err := login.Login(ctx, buildCredential(t, "alice", "old-secret"), testClientIP)
+	if err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+
+	svc := NewPasswordService(users, sessions, resets, mailer, nil, domain.PasswordPolicy{}, testHasher, time.Hour, "https://example.com/reset-password", nil)
+
+	unknown, _ := domain.NewEmail("nobody@example.com")
+	if err := svc.RequestPasswordReset(ctx, unknown); err != nil || len(mailer.sent) != 0 {
+		t.Fatalf("unknown email should be ignored silently, got %v (%d mails)", err, len(mailer.sent))
+	}
+
+	email, _ := domain.NewEmail("alice@example.com")
+	if err := svc.RequestPasswordReset(ctx, email); err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To != "alice@example.com" {
+		t.Fatalf("expected one mail to alice, got %+v", mailer.sent)
+	}
+
+	link := regexp.MustCompile(`https://example\.com/reset-password\?token=\S+`).FindString(mailer.sent[0].Body)
+	parsed, err := url.Parse(link)
+	if err != nil || link == "" {
+		t.Fatalf("expected reset link in body: %q", mailer.sent[0].Body)
+	}
+	token := parsed.Query().Get("token")
+
+	if err := svc.ConfirmPasswordReset(ctx, token, "  "); !errors.Is(err, domain.ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+	if err := svc.ConfirmPasswordReset(ctx, token, "new-secret"); err != nil {
+		t.Fatalf("confirm error: %v", err)
+	}
+	if err := svc.ConfirmPasswordReset(ctx, token, "another"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("expected the token to be single-use, got %v", err)
+	}
+	if err := svc.ConfirmPasswordReset(ctx, "garbage", "another"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("expected ErrInvalidResetToken for malformed token, got %v", err)
+	}
+
+	if _, err := sessions.FindByID(ctx, data.Token().ID()); err == nil {
+		t.Fatalf("expected sessions to be revoked after reset")
+	}
+	if _, _, err := login.Login(ctx, buildCredential(t, "alice", "new-secret"), testClientIP); err != nil {
+		t.Fatalf("expected new password to be accepted, got %v", err)
+	}
+}
+
+func TestPasswordService_ConfirmRejectsExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	resets := memory.NewPasswordResetRepository(users)
+	user := createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+
+	token, err := domain.NewOneTimeToken()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+	reset, err := domain.NewPasswordReset(user.ID(), token, time.Now().Add(-2*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	if err := resets.Create(ctx, reset); err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+
+	svc := NewPasswordService(users, memory.NewLoginSessionRepository(), resets, &recordingMailer{}, nil, domain.PasswordPolicy{}, testHasher, 0, "", nil)
+	if err := svc.ConfirmPasswordReset(ctx, token.String(), "new-secret"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("expected ErrInvalidResetToken for expired token, got %v", err)
+	}
+}
+
+func TestPasswordService_ChangePasswordKeepsConcurrentDisable(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	// リクエストの認証時に読み込んだユーザー。その後で admin が無効化した。
+	user := createUser(t, users, "alice", "old-secret", domain.UserRoleUser)
+	if _, err := users.SetDisabled(ctx, user.ID(), true, time.Now()); err != nil {
+		t.Fatalf("disable error: %v", err)
+	}
+
+	svc := NewPasswordService(users, memory.NewLoginSessionRepository(), memory.NewPasswordResetRepository(users), &recordingMailer{}, nil, domain.PasswordPolicy{}, testHasher, 0, "", nil)
+	if err := svc.ChangePassword(ctx, domain.LoginSession{}, user, "old-secret", "new-secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ := users.FindByID(ctx, user.ID())
+	if !stored.IsDisabled() || stored.HashedPassword().Verify("new-secret") != nil {
+		t.Fatalf("expected the password to change without re-enabling the user, disabled=%v", stored.IsDisabled())
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// UserRepository は users の永続化境界。見つからない場合は pgx.ErrNoRows を返し、
+// ユニーク制約違反は domain.ErrDuplicateUsername / domain.ErrDuplicateEmail へ変換する。
+// List は作成順のページと一致した全件数を返す。UpdatePassword は保存済みのハッシュが current のときだけ replacement に
+// 置き換え、それ以外は pgx.ErrNoRows を返す。SetRole などの更新は対象の列だけを書き換えて書き換えた後のユーザーを返し、
+// 呼び出し側が先に読み込んだユーザーで他の列 (並行して変わった無効化やロール) を上書きしない。
+type UserRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+	FindByEmail(ctx context.Context, email domain.Email) (domain.User, error)
+	FindByName(ctx context.Context, name domain.Name) (domain.User, error)
+	List(ctx context.Context, q domain.UserQuery) ([]domain.User, int, error)
+	Create(ctx context.Context, user domain.User) error
+	SetRole(ctx context.Context, id uuid.UUID, role domain.UserRole, now time.Time) (domain.User, error)
+	SetDisabled(ctx context.Context, id uuid.UUID, disabled bool, now time.Time) (domain.User, error)
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, now time.Time) (domain.User, error)
+	SetPassword(ctx context.Context, id uuid.UUID, hashed domain.HashedPassword, now time.Time) (domain.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, current, replacement domain.HashedPassword) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AdminAuditRepository は admin_audit_logs の永続化境界。ListRecent は新しい順に返す。
+type AdminAuditRepository interface {
+	Append(ctx context.Context, entry domain.AdminAuditEntry) error
+	ListRecent(ctx context.Context, limit int) ([]domain.AdminAuditEntry, error)
+}
+
+// LoginSessionRepository は login_sessions の永続化境界。見つからない場合は pgx.ErrNoRows を返す。
+// Rotate は古いセッションの削除と新しいセッションの保存を不可分に行い、古いセッションが無ければ pgx.ErrNoRows を返す。
+type LoginSessionRepository interface {
+	Create(ctx context.Context, session domain.LoginSession) error
+	Rotate(ctx context.Context, oldID uuid.UUID, replacement domain.LoginSession) error
+	FindByID(ctx context.Context, id uuid.UUID) (domain.LoginSession, error)
+	DeleteByID(ctx context.Context, id uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteByUserIDExcept(ctx context.Context, userID, keepID uuid.UUID) (int64, error)
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// PasswordResetRepository は password_resets の永続化境界。Redeem は申請を使用済みにするのとパスワードの置き換えを
+// 1 つのトランザクションで行い、未使用かつ期限内の申請か対象のユーザーが無ければ何も変えずに pgx.ErrNoRows を返す。
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset domain.PasswordReset) error
+	FindByID(ctx context.Context, id uuid.UUID) (domain.PasswordReset, error)
+	Redeem(ctx context.Context, id uuid.UUID, hashed domain.HashedPassword, usedAt time.Time) (domain.User, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// EmailVerificationRepository は email_verifications の永続化境界。MarkUsed は未使用かつ期限内の申請が無ければ pgx.ErrNoRows を返す。
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, verification domain.EmailVerification) error
+	FindByID(ctx context.Context, id uuid.UUID) (domain.EmailVerification, error)
+	MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// HueRepository は hue_records の永続化境界。FindPage は (created_at, id) 昇順のキーセットでページを返し、
+// Stats は palette の版で回答したレコードについて単語の昇順で色別の回答数を返す。Export は全件を (created_at, id) 昇順で 1 件ずつ write へ流し、
+// begin が nil でなければ先に単語一覧を渡す。Save は再送判定キーかチャレンジが保存済みのものと重なれば
+// domain.ErrDuplicateIdempotencyKey / domain.ErrHueChallengeUsed を返し、FindByIdempotencyKey は見つからなければ pgx.ErrNoRows を返す。
+type HueRepository interface {
+	Save(ctx context.Context, record domain.HueRecord) error
+	FindByIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) (domain.HueRecord, error)
+	FindPage(ctx context.Context, req domain.RecordPageRequest) (domain.RecordPage, error)
+	Stats(ctx context.Context, palette domain.Palette) ([]domain.HueWordStats, error)
+	Export(ctx context.Context, begin func(words []domain.HueWord) error, write func(domain.HueRecord) error) error
+}
+
+// WordSetRepository は word_sets の永続化境界。見つからない場合は pgx.ErrNoRows を返す。
+// Update は有効かどうかを変えず、単語を変える場合は回答かテストセッションから参照されていれば domain.ErrWordSetInUse を返す。
+// Activate は id のセットを有効にして他をすべて無効にする。Delete は回答かテストセッションから参照されていれば domain.ErrWordSetInUse を返す。
+type WordSetRepository interface {
+	Create(ctx context.Context, set domain.WordSet) error
+	FindByID(ctx context.Context, id uuid.UUID) (domain.WordSet, error)
+	FindActive(ctx context.Context) (domain.WordSet, error)
+	List(ctx context.Context) ([]domain.WordSet, error)
+	Update(ctx context.Context, set domain.WordSet) error
+	Activate(ctx context.Context, id uuid.UUID, now time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// PaletteRepository は palettes の永続化境界。見つからない場合は pgx.ErrNoRows を返す。
+// List は版の昇順で返し、Activate は version の版を有効にして他をすべて無効にする。
+type PaletteRepository interface {
+	Create(ctx context.Context, palette domain.Palette) error
+	FindByVersion(ctx context.Context, version int) (domain.Palette, error)
+	FindActive(ctx context.Context) (domain.Palette, error)
+	List(ctx context.Context) ([]domain.Palette, error)
+	Activate(ctx context.Context, version int) error
+}
+
+// HueSessionRepository は hue_sessions と hue_session_answers の永続化境界。見つからない場合は pgx.ErrNoRows を返す。
+// SaveAnswer と Finish はセッションが既に終了していれば pgx.ErrNoRows を返し、Finish は回答の保存と終了を 1 つのトランザクションで行う。
+// Finish は回答の Idempotency-Key やチャレンジが使用済みなら HueRepository.Save と同じエラーを返す。
+// Funnel は now 時点の状態で数える。
+type HueSessionRepository interface {
+	Create(ctx context.Context, session domain.HueSession) error
+	FindByID(ctx context.Context, id uuid.UUID) (domain.HueSession, error)
+	CountByWordSet(ctx context.Context, wordSetID uuid.UUID) (int64, error)
+	SaveAnswer(ctx context.Context, sessionID uuid.UUID, answer domain.HueAnswer) error
+	Finish(ctx context.Context, session domain.HueSession, record domain.HueRecord) error
+	Funnel(ctx context.Context, now time.Time) (domain.HueSessionFunnel, error)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SessionService はログアウトとトークン更新を扱う。呼び出し元のセッションは AuthMiddleware で検証済みとする。
+type SessionService struct {
+	sessionRepo LoginSessionRepository
+	sessionTTL  time.Duration
+	logger      *log.Logger
+}
+
+// NewSessionService は sessionTTL が 0 以下の場合 domain.DefaultLoginSessionTTL を使う。
+func NewSessionService(sessionRepo LoginSessionRepository, sessionTTL time.Duration, logger *log.Logger) *SessionService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &SessionService{sessionRepo: sessionRepo, sessionTTL: sessionTTLOrDefault(sessionTTL), logger: logger}
+}
+
+// Logout は現在のセッションだけを削除する。
+func (s *SessionService) Logout(ctx context.Context, session domain.LoginSession) error {
+	if err := s.sessionRepo.DeleteByID(ctx, session.ID()); err != nil {
+		s.logError("delete session", err)
+		return err
+	}
+	return nil
+}
+
+// LogoutAll は指定ユーザーの全セッションを削除する。
+func (s *SessionService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		s.logError("delete user sessions", err)
+		return err
+	}
+	return nil
+}
+
+// Refresh は古いセッションを消費して新しいトークンのセッションを発行する。
+// 同じセッションで並行に呼ばれても成功するのは 1 回だけで、残りは domain.ErrInvalidSessionToken を返す。
+// 有効期限は発行時点から sessionTTL だけ延びる。
+func (s *SessionService) Refresh(ctx context.Context, session domain.LoginSession) (domain.SessionData, error) {
+	replacement, token, err := newLoginSession(session.UserID(), time.Now(), s.sessionTTL)
+	if err != nil {
+		s.logError("issue login session", err)
+		return domain.SessionData{}, err
+	}
+
+	if err := s.sessionRepo.Rotate(ctx, session.ID(), replacement); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.SessionData{}, domain.ErrInvalidSessionToken
+		}
+		s.logError("rotate session", err)
+		return domain.SessionData{}, err
+	}
+
+	return domain.NewIssuedSessionData(replacement, token)
+}
+
+func (s *SessionService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[SessionService] %s: %v", action, err)
+}
+
+// issueLoginSession は新しいトークンを発行し、そのハッシュを login_sessions へ保存する。
+func issueLoginSession(ctx context.Context, sessionRepo LoginSessionRepository, userID uuid.UUID, now time.Time, ttl time.Duration) (domain.SessionData, error) {
+	session, token, err := newLoginSession(userID, now, ttl)
+	if err != nil {
+		return domain.SessionData{}, err
+	}
+
+	if err := sessionRepo.Create(ctx, session); err != nil {
+		return domain.SessionData{}, err
+	}
+
+	return domain.NewIssuedSessionData(session, token)
+}
+
+func newLoginSession(userID uuid.UUID, now time.Time, ttl time.Duration) (domain.LoginSession, domain.LoginSessionToken, error) {
+	token, err := domain.NewLoginSessionToken()
+	if err != nil {
+		return domain.LoginSession{}, domain.LoginSessionToken{}, err
+	}
+
+	session, err := domain.NewLoginSession(userID, token, now, ttl)
+	if err != nil {
+		return domain.LoginSession{}, domain.LoginSessionToken{}, err
+	}
+	return session, token, nil
+}
+
+func sessionTTLOrDefault(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return domain.DefaultLoginSessionTTL
+	}
+	return ttl
+}
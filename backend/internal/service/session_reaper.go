@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultSessionReapInterval は期限切れセッションを掃除する間隔。
+	DefaultSessionReapInterval = 5 * time.Minute
+	// DefaultSessionReapBatchSize は 1 回の DELETE で削除する最大件数。
+	DefaultSessionReapBatchSize = 500
+)
+
+// ExpiredSessionDeleter は SessionReaper が必要とする削除口。before 以前に期限切れとなったセッションを最大 limit 件削除する。
+type ExpiredSessionDeleter interface {
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// SessionReaper は期限切れの login_sessions を定期的にバッチ削除するバックグラウンドワーカー。
+type SessionReaper struct {
+	sessionRepo ExpiredSessionDeleter
+	interval    time.Duration
+	batchSize   int
+	logger      *log.Logger
+}
+
+func NewSessionReaper(sessionRepo ExpiredSessionDeleter, interval time.Duration, batchSize int, logger *log.Logger) *SessionReaper {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if interval <= 0 {
+		interval = DefaultSessionReapInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultSessionReapBatchSize
+	}
+	return &SessionReaper{sessionRepo: sessionRepo, interval: interval, batchSize: batchSize, logger: logger}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに ReapOnce を呼ぶ。起動直後にも 1 回実行する。
+func (r *SessionReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.reap(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReapOnce は期限切れセッションが無くなるまでバッチ削除を繰り返し、削除した総数を返す。
+func (r *SessionReaper) ReapOnce(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	var total int64
+	for {
+		removed, err := r.sessionRepo.DeleteExpired(ctx, now, r.batchSize)
+		total += removed
+		if err != nil {
+			return total, err
+		}
+		if removed < int64(r.batchSize) {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+func (r *SessionReaper) reap(ctx context.Context) {
+	removed, err := r.ReapOnce(ctx)
+	if err != nil && ctx.Err() == nil {
+		r.logger.Printf("[SessionReaper] delete expired sessions: %v", err)
+	}
+	if removed > 0 {
+		r.logger.Printf("[SessionReaper] removed %d expired sessions", removed)
+	}
+}
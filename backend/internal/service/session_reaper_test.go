@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessionReaper_ReapOnce(t *testing.T) {
+	deleter := &fakeExpiredSessionDeleter{remaining: 5}
+	reaper := NewSessionReaper(deleter, time.Minute, 2, nil)
+
+	removed, err := reaper.ReapOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if removed != 5 {
+		t.Fatalf("expected 5 removed across batches, got %d", removed)
+	}
+
+	// 2 件 + 2 件 + 1 件で、満杯でないバッチが来た時点で止まる。
+	if len(deleter.limits) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(deleter.limits))
+	}
+	for _, limit := range deleter.limits {
+		if limit != 2 {
+			t.Fatalf("expected batch size 2, got %d", limit)
+		}
+	}
+}
+
+func TestSessionReaper_ReapOnceStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	deleter := &fakeExpiredSessionDeleter{remaining: 100, afterDelete: cancel}
+	reaper := NewSessionReaper(deleter, time.Minute, 2, nil)
+
+	removed, err := reaper.ReapOnce(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if removed != 2 || len(deleter.limits) != 1 {
+		t.Fatalf("expected to stop after the first batch, removed %d in %d batches", removed, len(deleter.limits))
+	}
+}
+
+func TestSessionReaper_RunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reaper := NewSessionReaper(&fakeExpiredSessionDeleter{}, time.Hour, 10, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reaper.Run(ctx)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("reaper did not stop after cancel")
+	}
+}
+
+type fakeExpiredSessionDeleter struct {
+	remaining   int64
+	limits      []int
+	afterDelete func()
+}
+
+func (f *fakeExpiredSessionDeleter) DeleteExpired(_ context.Context, _ time.Time, limit int) (int64, error) {
+	f.limits = append(f.limits, limit)
+
+	removed := min(f.remaining, int64(limit))
+	f.remaining -= removed
+	if f.afterDelete != nil {
+		f.afterDelete()
+	}
+	return removed, nil
+}
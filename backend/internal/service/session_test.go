@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func TestSessionService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	sessions := memory.NewLoginSessionRepository()
+	userID := uuid.New()
+
+	original, err := issueLoginSession(ctx, sessions, userID, time.Now().Add(-time.Minute), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("issue error: %v", err)
+	}
+	current, err := sessions.FindByID(ctx, original.Token().ID())
+	if err != nil {
+		t.Fatalf("find error: %v", err)
+	}
+
+	svc := NewSessionService(sessions, 2*time.Hour, nil)
+	before := time.Now()
+	refreshed, err := svc.Refresh(ctx, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if refreshed.Token().String() == original.Token().String() {
+		t.Fatalf("expected token rotation")
+	}
+
+	if !refreshed.ExpiresAt().After(original.ExpiresAt()) {
+		t.Fatalf("expected expiry to slide forward")
+	}
+
+	if refreshed.ExpiresAt().Before(before.Add(2 * time.Hour)) {
+		t.Fatalf("expected configured ttl to apply, got expiry %v", refreshed.ExpiresAt())
+	}
+
+	if _, err := sessions.FindByID(ctx, original.Token().ID()); err == nil {
+		t.Fatalf("old session should be revoked")
+	}
+
+	if _, err := sessions.FindByID(ctx, refreshed.Token().ID()); err != nil {
+		t.Fatalf("new session should be persisted: %v", err)
+	}
+}
+
+func TestSessionService_RefreshReplay(t *testing.T) {
+	ctx := context.Background()
+	sessions := memory.NewLoginSessionRepository()
+	userID := uuid.New()
+
+	original, err := issueLoginSession(ctx, sessions, userID, time.Now(), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("issue error: %v", err)
+	}
+	current, err := sessions.FindByID(ctx, original.Token().ID())
+	if err != nil {
+		t.Fatalf("find error: %v", err)
+	}
+
+	svc := NewSessionService(sessions, 0, nil)
+	refreshed, err := svc.Refresh(ctx, current)
+	if err != nil {
+		t.Fatalf("first refresh: %v", err)
+	}
+
+	if _, err := svc.Refresh(ctx, current); !errors.Is(err, domain.ErrInvalidSessionToken) {
+		t.Fatalf("expected ErrInvalidSessionToken on replay, got %v", err)
+	}
+
+	if _, err := sessions.FindByID(ctx, current.ID()); err == nil {
+		t.Fatalf("replayed session must stay revoked")
+	}
+	if _, err := sessions.FindByID(ctx, refreshed.Token().ID()); err != nil {
+		t.Fatalf("rotated session should remain: %v", err)
+	}
+	// 再送で新しいセッションが増えていないこと。
+	if count := sessions.CountByUserID(userID); count != 1 {
+		t.Fatalf("expected only the rotated session to remain, got %d sessions", count)
+	}
+}
+
+func TestSessionService_LogoutAndLogoutAll(t *testing.T) {
+	ctx := context.Background()
+	sessions := memory.NewLoginSessionRepository()
+	userID := uuid.New()
+	svc := NewSessionService(sessions, 0, nil)
+
+	var issued []uuid.UUID
+	for i := 0; i < 3; i++ {
+		data, err := issueLoginSession(ctx, sessions, userID, time.Now(), domain.DefaultLoginSessionTTL)
+		if err != nil {
+			t.Fatalf("issue error: %v", err)
+		}
+		issued = append(issued, data.Token().ID())
+	}
+	other, err := issueLoginSession(ctx, sessions, uuid.New(), time.Now(), domain.DefaultLoginSessionTTL)
+	if err != nil {
+		t.Fatalf("issue error: %v", err)
+	}
+
+	first, _ := sessions.FindByID(ctx, issued[0])
+	if err := svc.Logout(ctx, first); err != nil {
+		t.Fatalf("logout error: %v", err)
+	}
+	if _, err := sessions.FindByID(ctx, issued[0]); err == nil {
+		t.Fatalf("logged out session should be deleted")
+	}
+	if _, err := sessions.FindByID(ctx, issued[1]); err != nil {
+		t.Fatalf("other sessions should survive logout: %v", err)
+	}
+
+	if err := svc.LogoutAll(ctx, userID); err != nil {
+		t.Fatalf("logout-all error: %v", err)
+	}
+	for _, id := range issued[1:] {
+		if _, err := sessions.FindByID(ctx, id); err == nil {
+			t.Fatalf("session %s should be deleted by logout-all", id)
+		}
+	}
+	if _, err := sessions.FindByID(ctx, other.Token().ID()); err != nil {
+		t.Fatalf("other user's session must survive: %v", err)
+	}
+}
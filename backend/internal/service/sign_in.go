@@ -2,41 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
-
 	"backend/internal/domain"
-	"backend/internal/repository"
 )
 
+// EmailVerificationSender は登録直後のユーザーへ確認用リンクを送る。
+type EmailVerificationSender interface {
+	SendVerification(ctx context.Context, user domain.User) error
+}
+
 // SignInService はサインイン処理を司る具体実装の雛形。
 type SignInService struct {
-	userRepo    *repository.UserRepository
-	sessionRepo *repository.LoginSessionRepository
+	userRepo    UserRepository
+	sessionRepo LoginSessionRepository
+	verifier    EmailVerificationSender
+	policy      domain.EmailVerificationPolicy
+	passwords   domain.PasswordPolicy
+	hasher      domain.PasswordHasher
+	sessionTTL  time.Duration
 	logger      *log.Logger
 }
 
-func NewSignInService(userRepo *repository.UserRepository, sessionRepo *repository.LoginSessionRepository, logger *log.Logger) *SignInService {
+// NewSignInService は hasher が nil なら domain.DefaultBcryptHasher、sessionTTL が 0 以下なら既定の TTL を使う。
+// verifier が nil なら確認用リンクを送らない。パスワードが passwords を満たさなければ登録しない。
+func NewSignInService(userRepo UserRepository, sessionRepo LoginSessionRepository, verifier EmailVerificationSender, policy domain.EmailVerificationPolicy, passwords domain.PasswordPolicy, hasher domain.PasswordHasher, sessionTTL time.Duration, logger *log.Logger) *SignInService {
 	if logger == nil {
 		logger = log.Default()
 	}
-	return &SignInService{userRepo: userRepo, sessionRepo: sessionRepo, logger: logger}
+	return &SignInService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		verifier:    verifier,
+		policy:      policy,
+		passwords:   passwords,
+		hasher:      passwordHasherOrDefault(hasher),
+		sessionTTL:  sessionTTLOrDefault(sessionTTL),
+		logger:      logger,
+	}
 }
 
+// SignIn はユーザーを作成し、確認用リンクを送ってからセッションを発行する。
+// ポリシーがログインにメールアドレスの確認を求める場合は、ユーザーを作成したうえでセッションを発行せず domain.ErrEmailNotVerified を返す。
 func (s *SignInService) SignIn(ctx context.Context, credential domain.SignInCredential) (domain.SessionData, domain.UserRole, error) {
 	now := time.Now()
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(credential.Password()), bcrypt.DefaultCost)
-	if err != nil {
-		s.logError("hash password", err)
-		return domain.SessionData{}, "", err
-	}
-
-	password, err := domain.NewHashedPassword(string(hashedPassword))
+	password, err := hashPassword(s.passwords, s.hasher, credential.Password())
 	if err != nil {
-		s.logError("build hashed password domain", err)
+		if !errors.Is(err, domain.ErrInvalidPassword) {
+			s.logError("hash password", err)
+		}
 		return domain.SessionData{}, "", err
 	}
 
@@ -51,32 +68,19 @@ func (s *SignInService) SignIn(ctx context.Context, credential domain.SignInCred
 		return domain.SessionData{}, "", err
 	}
 
-	token, err := domain.NewLoginSessionToken()
-	if err != nil {
-		s.logError("issue session token", err)
-		return domain.SessionData{}, "", err
-	}
-
-	data, err := domain.NewSessionData(user.ID(), token)
-	if err != nil {
-		s.logError("build session data", err)
-		return domain.SessionData{}, "", err
+	// 送信に失敗しても登録自体は成立させる。リンクは後から再送できる。
+	if s.verifier != nil {
+		if err := s.verifier.SendVerification(ctx, user); err != nil {
+			s.logError("send verification", err)
+		}
 	}
-
-	hashedToken, err := data.Token().Hash()
-	if err != nil {
-		s.logError("hash login token", err)
-		return domain.SessionData{}, "", err
+	if err := s.policy.CheckLogin(user); err != nil {
+		return domain.SessionData{}, user.Role(), err
 	}
 
-	session, err := domain.NewLoginSession(data.UserID(), hashedToken, now)
+	data, err := issueLoginSession(ctx, s.sessionRepo, user.ID(), now, s.sessionTTL)
 	if err != nil {
-		s.logError("build login session", err)
-		return domain.SessionData{}, "", err
-	}
-
-	if err = s.sessionRepo.Create(ctx, session); err != nil {
-		s.logError("persist login session", err)
+		s.logError("issue login session", err)
 		return domain.SessionData{}, "", err
 	}
 	return data, user.Role(), nil
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+)
+
+func TestSignInService_SignIn(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	svc := NewSignInService(users, sessions, nil, domain.EmailVerificationPolicy{}, domain.PasswordPolicy{}, testHasher, 0, nil)
+
+	credential, err := domain.NewSignInCredential("alice", "alice@example.com", "secret")
+	if err != nil {
+		t.Fatalf("credential error: %v", err)
+	}
+
+	data, role, err := svc.SignIn(ctx, credential)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if role != domain.UserRoleUser {
+		t.Fatalf("expected user role, got %s", role)
+	}
+
+	user, err := users.FindByID(ctx, data.UserID())
+	if err != nil {
+		t.Fatalf("expected persisted user: %v", err)
+	}
+	if err := user.HashedPassword().Verify("secret"); err != nil {
+		t.Fatalf("expected stored password hash to verify: %v", err)
+	}
+
+	if _, err := sessions.FindByID(ctx, data.Token().ID()); err != nil {
+		t.Fatalf("expected persisted session: %v", err)
+	}
+}
+
+func TestSignInService_SignIn_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	svc := NewSignInService(memory.NewUserRepository(), memory.NewLoginSessionRepository(), nil, domain.EmailVerificationPolicy{}, domain.PasswordPolicy{}, testHasher, 0, nil)
+
+	first, _ := domain.NewSignInCredential("alice", "alice@example.com", "secret")
+	if _, _, err := svc.SignIn(ctx, first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sameName, _ := domain.NewSignInCredential("alice", "other@example.com", "secret")
+	if _, _, err := svc.SignIn(ctx, sameName); !errors.Is(err, domain.ErrDuplicateUsername) {
+		t.Fatalf("expected ErrDuplicateUsername, got %v", err)
+	}
+
+	sameEmail, _ := domain.NewSignInCredential("bob", "alice@example.com", "secret")
+	if _, _, err := svc.SignIn(ctx, sameEmail); !errors.Is(err, domain.ErrDuplicateEmail) {
+		t.Fatalf("expected ErrDuplicateEmail, got %v", err)
+	}
+}
+
+func TestSignInService_SignIn_PasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	svc := NewSignInService(users, memory.NewLoginSessionRepository(), nil, domain.EmailVerificationPolicy{}, domain.DefaultPasswordPolicy(), testHasher, 0, nil)
+
+	common, _ := domain.NewSignInCredential("alice", "alice@example.com", "qwerty123")
+	var policyErr *domain.PasswordPolicyError
+	if _, _, err := svc.SignIn(ctx, common); !errors.As(err, &policyErr) || policyErr.Rule() != domain.PasswordRuleCommon {
+		t.Fatalf("expected common password to be rejected, got %v", err)
+	}
+	if _, err := users.FindByName(ctx, common.Name()); err == nil {
+		t.Fatalf("expected no user to be created")
+	}
+
+	spaced, _ := domain.NewSignInCredential("alice", "alice@example.com", " long enough ")
+	data, _, err := svc.SignIn(ctx, spaced)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user, _ := users.FindByID(ctx, data.UserID())
+	if err := user.HashedPassword().Verify(" long enough "); err != nil {
+		t.Fatalf("expected the password to be stored as typed: %v", err)
+	}
+}
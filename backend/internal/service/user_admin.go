@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// UserAdminService は管理者によるユーザー管理を扱い、変更操作はすべて監査ログに残す。
+// 呼び出し元が管理者であることは AuthMiddleware で確認済みとする。
+type UserAdminService struct {
+	userRepo    UserRepository
+	sessionRepo LoginSessionRepository
+	auditRepo   AdminAuditRepository
+	policy      domain.EmailVerificationPolicy
+	logger      *log.Logger
+}
+
+// NewUserAdminService は policy が admin への昇格にメールアドレスの確認を求める場合、未確認のユーザーを昇格させない。
+func NewUserAdminService(userRepo UserRepository, sessionRepo LoginSessionRepository, auditRepo AdminAuditRepository, policy domain.EmailVerificationPolicy, logger *log.Logger) *UserAdminService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &UserAdminService{userRepo: userRepo, sessionRepo: sessionRepo, auditRepo: auditRepo, policy: policy, logger: logger}
+}
+
+// ListUsers は検索条件に一致するユーザーと全件数を返す。
+func (s *UserAdminService) ListUsers(ctx context.Context, q domain.UserQuery) ([]domain.User, int, error) {
+	users, total, err := s.userRepo.List(ctx, q)
+	if err != nil {
+		s.logError("list users", err)
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// ChangeRole は対象のロールを変更する。自分自身のロールは変えられない。
+// ポリシーが求める場合、メールアドレスが未確認のユーザーは admin にできず domain.ErrEmailNotVerified を返す。
+func (s *UserAdminService) ChangeRole(ctx context.Context, actorID, targetID uuid.UUID, role domain.UserRole) (domain.User, error) {
+	if actorID == targetID {
+		return domain.User{}, domain.ErrSelfAdministration
+	}
+
+	user, err := s.findUser(ctx, targetID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := s.policy.CheckRole(user, role); err != nil {
+		return domain.User{}, err
+	}
+
+	before := user.Role()
+	now := time.Now()
+	if _, err := user.ChangeRole(role, now); err != nil {
+		return domain.User{}, err
+	}
+
+	updated, err := s.userRepo.SetRole(ctx, targetID, role, now)
+	if err != nil {
+		return domain.User{}, s.translateNotFound("update user role", err)
+	}
+
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, targetID, domain.AdminActionChangeRole, fmt.Sprintf("%s -> %s", before, role), now)
+	return updated, nil
+}
+
+// Disable は対象を無効化し、既存のセッションもすべて破棄する。自分自身は無効化できない。
+func (s *UserAdminService) Disable(ctx context.Context, actorID, targetID uuid.UUID) (domain.User, error) {
+	if actorID == targetID {
+		return domain.User{}, domain.ErrSelfAdministration
+	}
+
+	now := time.Now()
+	updated, err := s.userRepo.SetDisabled(ctx, targetID, true, now)
+	if err != nil {
+		return domain.User{}, s.translateNotFound("disable user", err)
+	}
+
+	revoked, err := s.sessionRepo.DeleteByUserID(ctx, targetID)
+	if err != nil {
+		s.logError("revoke sessions of disabled user", err)
+		return domain.User{}, err
+	}
+
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, targetID, domain.AdminActionDisableUser, fmt.Sprintf("revoked %d sessions", revoked), now)
+	return updated, nil
+}
+
+// Enable は無効化を解除する。
+func (s *UserAdminService) Enable(ctx context.Context, actorID, targetID uuid.UUID) (domain.User, error) {
+	now := time.Now()
+	updated, err := s.userRepo.SetDisabled(ctx, targetID, false, now)
+	if err != nil {
+		return domain.User{}, s.translateNotFound("enable user", err)
+	}
+
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, targetID, domain.AdminActionEnableUser, "", now)
+	return updated, nil
+}
+
+// RevokeSessions は対象の全セッションを破棄して強制的にログアウトさせ、破棄した件数を返す。
+func (s *UserAdminService) RevokeSessions(ctx context.Context, actorID, targetID uuid.UUID) (int64, error) {
+	if _, err := s.findUser(ctx, targetID); err != nil {
+		return 0, err
+	}
+
+	revoked, err := s.sessionRepo.DeleteByUserID(ctx, targetID)
+	if err != nil {
+		s.logError("revoke user sessions", err)
+		return 0, err
+	}
+
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, targetID, domain.AdminActionRevokeSessions, fmt.Sprintf("revoked %d sessions", revoked), time.Now())
+	return revoked, nil
+}
+
+// Delete は対象を削除する。回答は匿名のものとして残る。自分自身は削除できない。
+func (s *UserAdminService) Delete(ctx context.Context, actorID, targetID uuid.UUID) error {
+	if actorID == targetID {
+		return domain.ErrSelfAdministration
+	}
+
+	user, err := s.findUser(ctx, targetID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.sessionRepo.DeleteByUserID(ctx, targetID); err != nil {
+		s.logError("revoke sessions of deleted user", err)
+		return err
+	}
+
+	if err := s.userRepo.Delete(ctx, targetID); err != nil {
+		return s.translateNotFound("delete user", err)
+	}
+
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, targetID, domain.AdminActionDeleteUser, user.Username().String(), time.Now())
+	return nil
+}
+
+// AuditLog は新しい順に最大 limit 件の監査ログを返す。
+func (s *UserAdminService) AuditLog(ctx context.Context, limit int) ([]domain.AdminAuditEntry, error) {
+	entries, err := s.auditRepo.ListRecent(ctx, limit)
+	if err != nil {
+		s.logError("list audit log", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *UserAdminService) findUser(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.User{}, s.translateNotFound("find user by id", err)
+	}
+	return user, nil
+}
+
+func (s *UserAdminService) translateNotFound(action string, err error) error {
+	s.logError(action, err)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrUserNotFound
+	}
+	return err
+}
+
+func (s *UserAdminService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[UserAdminService] %s: %v", action, err)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func TestUserAdminService_DisableRevokesSessionsAndAudits(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewLoginSessionRepository()
+	audits := memory.NewAdminAuditRepository()
+	admin := createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+	alice := createUser(t, users, "alice", "secret", domain.UserRoleUser)
+
+	data, _, err := NewLoginService(users, sessions, nil, domain.EmailVerificationPolicy{}, nil, 0, nil).Login(ctx, buildCredential(t, "alice", "secret"), testClientIP)
+	if err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+
+	svc := NewUserAdminService(users, sessions, audits, domain.EmailVerificationPolicy{}, nil)
+	disabled, err := svc.Disable(ctx, admin.ID(), alice.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !disabled.IsDisabled() {
+		t.Fatalf("expected returned user to be disabled")
+	}
+
+	if _, err := sessions.FindByID(ctx, data.Token().ID()); err == nil {
+		t.Fatalf("expected sessions of the disabled user to be revoked")
+	}
+
+	if _, _, err := NewAuthService(sessions, users, nil).Authenticate(ctx, data); err == nil {
+		t.Fatalf("disabled user should not authenticate")
+	}
+
+	entries, _ := audits.ListRecent(ctx, 10)
+	if len(entries) != 1 || entries[0].Action() != domain.AdminActionDisableUser || entries[0].ActorID() != admin.ID() || entries[0].TargetID() != alice.ID() {
+		t.Fatalf("unexpected audit entries: %+v", entries)
+	}
+}
+
+func TestUserAdminService_RejectsSelfAndUnknownTargets(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	audits := memory.NewAdminAuditRepository()
+	admin := createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+	svc := NewUserAdminService(users, memory.NewLoginSessionRepository(), audits, domain.EmailVerificationPolicy{}, nil)
+
+	if _, err := svc.ChangeRole(ctx, admin.ID(), admin.ID(), domain.UserRoleUser); !errors.Is(err, domain.ErrSelfAdministration) {
+		t.Fatalf("expected ErrSelfAdministration on self demotion, got %v", err)
+	}
+	if err := svc.Delete(ctx, admin.ID(), admin.ID()); !errors.Is(err, domain.ErrSelfAdministration) {
+		t.Fatalf("expected ErrSelfAdministration on self deletion, got %v", err)
+	}
+	if _, err := svc.Enable(ctx, admin.ID(), uuid.New()); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	if entries, _ := audits.ListRecent(ctx, 10); len(entries) != 0 {
+		t.Fatalf("rejected operations should not be audited, got %d entries", len(entries))
+	}
+}
+
+func TestUserAdminService_AuditFailureKeepsChange(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	admin := createUser(t, users, "admin", "secret", domain.UserRoleAdmin)
+	alice := createUser(t, users, "alice", "secret", domain.UserRoleUser)
+
+	svc := NewUserAdminService(users, memory.NewLoginSessionRepository(), failingAuditRepository{}, domain.EmailVerificationPolicy{}, nil)
+	if _, err := svc.ChangeRole(ctx, admin.ID(), alice.ID(), domain.UserRoleAdmin); err != nil {
+		t.Fatalf("audit failure should not fail the operation: %v", err)
+	}
+
+	stored, _ := users.FindByID(ctx, alice.ID())
+	if stored.Role() != domain.UserRoleAdmin {
+		t.Fatalf("expected role change to be persisted")
+	}
+}
+
+type failingAuditRepository struct{}
+
+func (failingAuditRepository) Append(context.Context, domain.AdminAuditEntry) error {
+	return errors.New("audit unavailable")
+}
+
+func (failingAuditRepository) ListRecent(context.Context, int) ([]domain.AdminAuditEntry, error) {
+	return nil, errors.New("audit unavailable")
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// WordSetService は Hue テストで出題する単語セットを管理する。管理操作は監査ログに残す。
+type WordSetService struct {
+	wordSetRepo WordSetRepository
+	auditRepo   AdminAuditRepository
+	logger      *log.Logger
+}
+
+func NewWordSetService(wordSetRepo WordSetRepository, auditRepo AdminAuditRepository, logger *log.Logger) *WordSetService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &WordSetService{wordSetRepo: wordSetRepo, auditRepo: auditRepo, logger: logger}
+}
+
+// ActiveWordSet は出題中の単語セットを返す。無ければ domain.ErrWordSetNotFound を返す。
+func (s *WordSetService) ActiveWordSet(ctx context.Context) (domain.WordSet, error) {
+	set, err := s.wordSetRepo.FindActive(ctx)
+	if err != nil {
+		return domain.WordSet{}, s.translateNotFound("find active word set", err)
+	}
+	return set, nil
+}
+
+// ListWordSets はすべての単語セットを作成順に返す。
+func (s *WordSetService) ListWordSets(ctx context.Context) ([]domain.WordSet, error) {
+	sets, err := s.wordSetRepo.List(ctx)
+	if err != nil {
+		s.logError("list word sets", err)
+		return nil, err
+	}
+	return sets, nil
+}
+
+// CreateWordSet は単語セットを作る。activate なら作ったセットを出題中にする。
+func (s *WordSetService) CreateWordSet(ctx context.Context, actorID uuid.UUID, name string, words []string, activate bool) (domain.WordSet, error) {
+	now := time.Now()
+	set, err := domain.NewWordSet(name, words, now)
+	if err != nil {
+		return domain.WordSet{}, err
+	}
+
+	if err := s.wordSetRepo.Create(ctx, set); err != nil {
+		s.logError("create word set", err)
+		return domain.WordSet{}, err
+	}
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, set.ID(), domain.AdminActionCreateWordSet, fmt.Sprintf("%s (%d words)", set.Name(), len(set.Words())), now)
+
+	if !activate {
+		return set, nil
+	}
+	return s.ActivateWordSet(ctx, actorID, set.ID())
+}
+
+// UpdateWordSet は名前と単語を置き換える。既に回答やテストセッションがあるセットの単語を変えると過去の回答の意味が変わるため、
+// その場合は domain.ErrWordSetInUse を返す。名前だけの変更はいつでもできる。
+func (s *WordSetService) UpdateWordSet(ctx context.Context, actorID, id uuid.UUID, name string, words []string) (domain.WordSet, error) {
+	set, err := s.findWordSet(ctx, id)
+	if err != nil {
+		return domain.WordSet{}, err
+	}
+
+	now := time.Now()
+	updated, err := set.Edit(name, words, now)
+	if err != nil {
+		return domain.WordSet{}, err
+	}
+
+	// 参照の確認と書き換えは、間に回答が保存されないようリポジトリが一度に行う。
+	if err := s.wordSetRepo.Update(ctx, updated); err != nil {
+		if errors.Is(err, domain.ErrWordSetInUse) {
+			return domain.WordSet{}, err
+		}
+		return domain.WordSet{}, s.translateNotFound("update word set", err)
+	}
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, id, domain.AdminActionUpdateWordSet, fmt.Sprintf("%s (%d words)", updated.Name(), len(updated.Words())), now)
+	return updated, nil
+}
+
+// ActivateWordSet は id のセットを出題中にし、それまでのセットを出題から外す。
+func (s *WordSetService) ActivateWordSet(ctx context.Context, actorID, id uuid.UUID) (domain.WordSet, error) {
+	now := time.Now()
+	if err := s.wordSetRepo.Activate(ctx, id, now); err != nil {
+		return domain.WordSet{}, s.translateNotFound("activate word set", err)
+	}
+
+	set, err := s.findWordSet(ctx, id)
+	if err != nil {
+		return domain.WordSet{}, err
+	}
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, id, domain.AdminActionActivateWordSet, set.Name(), now)
+	return set, nil
+}
+
+// DeleteWordSet は出題中でも回答済みでもないセットを削除する。
+// 出題中なら domain.ErrWordSetActive、回答から参照されていれば domain.ErrWordSetInUse を返す。
+func (s *WordSetService) DeleteWordSet(ctx context.Context, actorID, id uuid.UUID) error {
+	set, err := s.findWordSet(ctx, id)
+	if err != nil {
+		return err
+	}
+	if set.IsActive() {
+		return domain.ErrWordSetActive
+	}
+
+	if err := s.wordSetRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrWordSetInUse) {
+			return err
+		}
+		return s.translateNotFound("delete word set", err)
+	}
+	appendAudit(ctx, s.auditRepo, s.logError, actorID, id, domain.AdminActionDeleteWordSet, set.Name(), time.Now())
+	return nil
+}
+
+func (s *WordSetService) findWordSet(ctx context.Context, id uuid.UUID) (domain.WordSet, error) {
+	set, err := s.wordSetRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.WordSet{}, s.translateNotFound("find word set", err)
+	}
+	return set, nil
+}
+
+func (s *WordSetService) translateNotFound(action string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrWordSetNotFound
+	}
+	s.logError(action, err)
+	return err
+}
+
+func (s *WordSetService) logError(action string, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.Printf("[WordSetService] %s: %v", action, err)
+}
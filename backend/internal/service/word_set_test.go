@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func TestWordSetService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	audits := memory.NewAdminAuditRepository()
+	svc := NewWordSetService(memory.NewWordSetRepository(hues, nil), audits, nil)
+	actor := uuid.New()
+
+	if _, err := svc.ActiveWordSet(ctx); !errors.Is(err, domain.ErrWordSetNotFound) {
+		t.Fatalf("expected ErrWordSetNotFound, got %v", err)
+	}
+	if _, err := svc.CreateWordSet(ctx, actor, "重複", []string{"夜", " 夜 "}, false); !errors.Is(err, domain.ErrInvalidWordSet) {
+		t.Fatalf("expected ErrInvalidWordSet for duplicate words, got %v", err)
+	}
+
+	first, err := svc.CreateWordSet(ctx, actor, "標準", []string{"夜", "海"}, true)
+	if err != nil || !first.IsActive() {
+		t.Fatalf("expected an active set, got %+v (%v)", first, err)
+	}
+	second, err := svc.CreateWordSet(ctx, actor, "自然", []string{"森"}, false)
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+
+	if _, err := svc.ActivateWordSet(ctx, actor, second.ID()); err != nil {
+		t.Fatalf("activate error: %v", err)
+	}
+	if active, _ := svc.ActiveWordSet(ctx); active.ID() != second.ID() {
+		t.Fatalf("expected the second set to be active")
+	}
+	sets, _ := svc.ListWordSets(ctx)
+	if len(sets) != 2 || sets[0].IsActive() {
+		t.Fatalf("expected the first set to be deactivated, got %+v", sets)
+	}
+
+	record, _ := domain.NewHueRecordFromRaw("Tester", map[string]string{"夜": "黒"})
+	if err := hues.Save(ctx, record.AnsweredFrom(first.ID())); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	if _, err := svc.UpdateWordSet(ctx, actor, first.ID(), "標準", []string{"夜"}); !errors.Is(err, domain.ErrWordSetInUse) {
+		t.Fatalf("expected ErrWordSetInUse when changing answered words, got %v", err)
+	}
+	renamed, err := svc.UpdateWordSet(ctx, actor, first.ID(), "初期", []string{"夜", "海"})
+	if err != nil || renamed.Name() != "初期" {
+		t.Fatalf("expected rename to succeed, got %+v (%v)", renamed, err)
+	}
+
+	if err := svc.DeleteWordSet(ctx, actor, second.ID()); !errors.Is(err, domain.ErrWordSetActive) {
+		t.Fatalf("expected ErrWordSetActive, got %v", err)
+	}
+	if err := svc.DeleteWordSet(ctx, actor, first.ID()); !errors.Is(err, domain.ErrWordSetInUse) {
+		t.Fatalf("expected ErrWordSetInUse, got %v", err)
+	}
+	if err := svc.DeleteWordSet(ctx, actor, uuid.New()); !errors.Is(err, domain.ErrWordSetNotFound) {
+		t.Fatalf("expected ErrWordSetNotFound, got %v", err)
+	}
+
+	entries, _ := audits.ListRecent(ctx, 10)
+	if len(entries) != 5 || entries[0].Action() != domain.AdminActionUpdateWordSet {
+		t.Fatalf("expected audit entries for each change, got %d", len(entries))
+	}
+}
+
+func TestWordSetService_UpdateRejectsWordsStartedInSession(t *testing.T) {
+	ctx := context.Background()
+	hues := memory.NewHueRepository()
+	sessions := memory.NewHueSessionRepository(hues)
+	wordSets := memory.NewWordSetRepository(hues, sessions)
+	svc := NewWordSetService(wordSets, memory.NewAdminAuditRepository(), nil)
+	actor := uuid.New()
+
+	set, err := svc.CreateWordSet(ctx, actor, "標準", []string{"夜", "海"}, true)
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	// 回答はまだ無いが、出題中のセッションがこのセットの単語を見ている。
+	if _, err := NewHueSessionService(sessions, hues, wordSets, activePalettes(t), testChallengeSigner(t), domain.WordOrderFixed, nil).Start(ctx, uuid.Nil); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+
+	if _, err := svc.UpdateWordSet(ctx, actor, set.ID(), "標準", []string{"夜"}); !errors.Is(err, domain.ErrWordSetInUse) {
+		t.Fatalf("expected ErrWordSetInUse while a session uses the words, got %v", err)
+	}
+	if found, _ := wordSets.FindByID(ctx, set.ID()); !found.SameWords(set) {
+		t.Fatalf("expected the words to stay unchanged, got %v", found.Words())
+	}
+}
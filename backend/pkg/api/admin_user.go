@@ -0,0 +1,102 @@
+package api
+
+import (
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// AdminUserPayload は管理画面に表示するユーザー情報。パスワードハッシュは含めない。
+type AdminUserPayload struct {
+	ID              string    `json:"id"`
+	Username        string    `json:"username"`
+	Email           string    `json:"email"`
+	Role            string    `json:"role"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	DisabledAt      time.Time `json:"disabled_at,omitzero"`
+	EmailVerifiedAt time.Time `json:"email_verified_at,omitzero"`
+}
+
+func NewAdminUserPayload(user domain.User) AdminUserPayload {
+	payload := AdminUserPayload{
+		ID:        user.ID().String(),
+		Username:  user.Username().String(),
+		Email:     user.Email().String(),
+		Role:      user.Role().String(),
+		CreatedAt: user.CreatedAt(),
+		UpdatedAt: user.UpdatedAt(),
+	}
+	if disabledAt, ok := user.DisabledAt(); ok {
+		payload.DisabledAt = disabledAt
+	}
+	if verifiedAt, ok := user.EmailVerifiedAt(); ok {
+		payload.EmailVerifiedAt = verifiedAt
+	}
+	return payload
+}
+
+// ListUsersResponse は /api/admin/users の応答。total は検索条件に一致した全件数。
+type ListUsersResponse struct {
+	Users []AdminUserPayload `json:"users"`
+	Total int                `json:"total"`
+}
+
+func NewListUsersResponse(users []domain.User, total int) ListUsersResponse {
+	payloads := make([]AdminUserPayload, len(users))
+	for i, user := range users {
+		payloads[i] = NewAdminUserPayload(user)
+	}
+	return ListUsersResponse{Users: payloads, Total: total}
+}
+
+// AdminUserActionRequest は管理操作の対象を指定する。role はロール変更のときだけ使う。
+type AdminUserActionRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role,omitempty"`
+}
+
+func (r AdminUserActionRequest) TargetID() (uuid.UUID, error) {
+	id, err := uuid.Parse(r.UserID)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, domain.ErrInvalidUser
+	}
+	return id, nil
+}
+
+// RevokeSessionsResponse は破棄したセッション数を返す。
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// AuditEntryPayload は監査ログ 1 件。
+type AuditEntryPayload struct {
+	ID        string    `json:"id"`
+	ActorID   string    `json:"actor_id"`
+	TargetID  string    `json:"target_id"`
+	Action    string    `json:"action"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditLogResponse は新しい順の監査ログ。
+type AuditLogResponse struct {
+	Entries []AuditEntryPayload `json:"entries"`
+}
+
+func NewAuditLogResponse(entries []domain.AdminAuditEntry) AuditLogResponse {
+	payloads := make([]AuditEntryPayload, len(entries))
+	for i, entry := range entries {
+		payloads[i] = AuditEntryPayload{
+			ID:        entry.ID().String(),
+			ActorID:   entry.ActorID().String(),
+			TargetID:  entry.TargetID().String(),
+			Action:    string(entry.Action()),
+			Detail:    entry.Detail(),
+			CreatedAt: entry.CreatedAt(),
+		}
+	}
+	return AuditLogResponse{Entries: payloads}
+}
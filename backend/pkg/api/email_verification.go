@@ -0,0 +1,15 @@
+package api
+
+import "backend/internal/domain"
+
+type EmailVerifyRequest struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+func (r ResendVerificationRequest) ToDomain() (domain.Email, error) {
+	return domain.NewEmail(r.Email)
+}
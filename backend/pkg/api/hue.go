@@ -1,29 +1,77 @@
 package api
 
 import (
+	"time"
+
 	"backend/internal/domain"
+
 	"github.com/google/uuid"
 )
 
-// HueRecordPayload は hue-are-you の回答を JSON で表す。
+// HueRecordPayload は hue-are-you の回答を JSON で表す。word_set_id は回答した単語セット、palette_version は
+// 色を選んだパレットの版で、保存時に省略すると出題中のセット・有効な版への回答として扱う。
+// 保存時の choice の色は色 ID でも表示名でもよく、返すときは常に色 ID。
+// quality_flags はサーバーが付けた品質の印 (too_fast / single_color) で、返すときだけ使い、保存時は無視する。
 type HueRecordPayload struct {
-	Name   string            `json:"name"`
-	Choice map[string]string `json:"choice"`
+	Name           string            `json:"name"`
+	Choice         map[string]string `json:"choice"`
+	WordSetID      string            `json:"word_set_id,omitempty"`
+	PaletteVersion int               `json:"palette_version,omitempty"`
+	QualityFlags   []string          `json:"quality_flags,omitempty"`
 }
 
+// ToDomain は word_set_id が UUID でなければ domain.ErrInvalidWordSet を、palette_version が負なら
+// domain.ErrInvalidPalette を返す。
 func (p HueRecordPayload) ToDomain() (domain.HueRecord, error) {
-	return domain.NewHueRecordFromRaw(p.Name, p.Choice)
+	record, err := domain.NewHueRecordFromRaw(p.Name, p.Choice)
+	if err != nil {
+		return domain.HueRecord{}, err
+	}
+
+	if p.PaletteVersion < 0 {
+		return domain.HueRecord{}, domain.ErrInvalidPalette
+	}
+	if p.PaletteVersion > 0 {
+		record = record.ColoredWith(p.PaletteVersion)
+	}
+
+	if p.WordSetID == "" {
+		return record, nil
+	}
+
+	id, err := uuid.Parse(p.WordSetID)
+	if err != nil || id == uuid.Nil {
+		return domain.HueRecord{}, domain.ErrInvalidWordSet
+	}
+	return record.AnsweredFrom(id), nil
 }
 
 func NewHueRecordPayload(record domain.HueRecord) HueRecordPayload {
+	version, _ := record.PaletteVersion()
 	return HueRecordPayload{
-		Name:   record.Name().String(),
-		Choice: record.ChoiceMap(),
+		Name:           record.Name().String(),
+		Choice:         record.ChoiceMap(),
+		WordSetID:      optionalID(record.WordSetID()),
+		PaletteVersion: version,
+		QualityFlags:   qualityFlagStrings(record.QualityFlags()),
 	}
 }
 
+func qualityFlagStrings(flags []domain.HueQualityFlag) []string {
+	if len(flags) == 0 {
+		return nil
+	}
+	values := make([]string, len(flags))
+	for i, flag := range flags {
+		values[i] = flag.String()
+	}
+	return values
+}
+
+// SaveResultRequest は save-result のボディ。challenge は /api/hue-are-you/challenge で受け取ったトークン。
 type SaveResultRequest struct {
 	HueRecordPayload
+	Challenge string `json:"challenge"`
 }
 
 func (r SaveResultRequest) ToDomain() (domain.HueRecord, error) {
@@ -37,48 +85,119 @@ func (r SaveResultRequest) ToDomain() (domain.HueRecord, error) {
 // SaveResultResponse は仕様上ボディ不要のため空。
 type SaveResultResponse struct{}
 
+// HueChallengeResponse は /api/hue-are-you/challenge の応答。challenge を expires_at までに save-result へ添えて送る。
+type HueChallengeResponse struct {
+	Challenge string    `json:"challenge"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GetDataRequest は取得するページを指定する。cursor は前回の next_cursor をそのまま渡し、省略すると先頭から。
+// 絞り込みを変えた場合は cursor を付けずに先頭から取り直す。セッションは Authorization ヘッダーで渡す。
 type GetDataRequest struct {
-	Session   SessionPayload `json:"session"`
-	DataRange []int          `json:"data-range"`
+	Limit        int                `json:"limit,omitempty"`
+	Cursor       string             `json:"cursor,omitempty"`
+	IncludeTotal bool               `json:"include_total,omitempty"`
+	Filter       *RecordFilterQuery `json:"filter,omitempty"`
 }
 
-func (r GetDataRequest) ToDomain() (domain.SessionData, domain.RecordRange, error) {
-	id, err := uuid.Parse(r.Session.UserID)
-	if err != nil {
-		return domain.SessionData{}, domain.RecordRange{}, err
-	}
+// RecordFilterQuery は get-data の絞り込み条件。created_from 以上 created_to 未満で、choices はすべてを満たすものに一致する。
+// choices の色は有効な版の色 ID か表示名で指定する。
+type RecordFilterQuery struct {
+	CreatedFrom *time.Time        `json:"created_from,omitempty"`
+	CreatedTo   *time.Time        `json:"created_to,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	NameMatch   string            `json:"name_match,omitempty"`
+	Choices     map[string]string `json:"choices,omitempty"`
+}
 
-	token, err := domain.ParseLoginSessionToken(r.Session.Token)
-	if err != nil {
-		return domain.SessionData{}, domain.RecordRange{}, err
+func (q *RecordFilterQuery) ToDomain() (domain.RecordFilter, error) {
+	if q == nil {
+		return domain.RecordFilter{}, nil
 	}
 
-	if len(r.DataRange) != 2 {
-		return domain.SessionData{}, domain.RecordRange{}, domain.ErrInvalidRange
+	var from, to time.Time
+	if q.CreatedFrom != nil {
+		from = *q.CreatedFrom
 	}
+	if q.CreatedTo != nil {
+		to = *q.CreatedTo
+	}
+
+	return domain.NewRecordFilter(from, to, q.Name, domain.NameMatch(q.NameMatch), q.Choices)
+}
 
-	recordRange, err := domain.NewRecordRange(r.DataRange[0], r.DataRange[1])
+func (r GetDataRequest) ToDomain() (domain.RecordPageRequest, error) {
+	filter, err := r.Filter.ToDomain()
 	if err != nil {
-		return domain.SessionData{}, domain.RecordRange{}, err
+		return domain.RecordPageRequest{}, err
 	}
 
-	session, err := domain.NewSessionData(id, token)
+	cursor, err := domain.ParseRecordCursor(r.Cursor)
 	if err != nil {
-		return domain.SessionData{}, domain.RecordRange{}, err
+		return domain.RecordPageRequest{}, err
 	}
 
-	return session, recordRange, nil
+	return domain.NewRecordPageRequest(filter, cursor, r.Limit, r.IncludeTotal)
 }
 
+// GetDataResponse は 1 ページ分のレコード。next_cursor は続きがある場合だけ、total は include_total 指定時だけ返す。
 type GetDataResponse struct {
-	Records []HueRecordPayload `json:"records"`
+	Records    []HueRecordPayload `json:"records"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Total      *int               `json:"total,omitempty"`
 }
 
-func NewGetDataResponse(records []domain.HueRecord) GetDataResponse {
+func NewGetDataResponse(page domain.RecordPage) GetDataResponse {
+	records := page.Records()
 	payloads := make([]HueRecordPayload, len(records))
 	for i, record := range records {
 		payloads[i] = NewHueRecordPayload(record)
 	}
 
-	return GetDataResponse{Records: payloads}
+	resp := GetDataResponse{Records: payloads, NextCursor: page.Next().String()}
+	if total, ok := page.Total(); ok {
+		resp.Total = &total
+	}
+	return resp
+}
+
+// MyResultPayload は自分の回答 1 件。id と created_at で過去の回答を区別できるようにする。
+// choice の色は palette_version の版の色 ID。
+type MyResultPayload struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Choice         map[string]string `json:"choice"`
+	PaletteVersion int               `json:"palette_version,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// MyResultsResponse はログイン中のユーザー自身の回答 1 ページ分。next_cursor は続きがある場合だけ返す。
+type MyResultsResponse struct {
+	Results    []MyResultPayload `json:"results"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+func NewMyResultsResponse(page domain.RecordPage) MyResultsResponse {
+	records := page.Records()
+	results := make([]MyResultPayload, len(records))
+	for i, record := range records {
+		version, _ := record.PaletteVersion()
+		results[i] = MyResultPayload{
+			ID:             record.ID().String(),
+			Name:           record.Name().String(),
+			Choice:         record.ChoiceMap(),
+			PaletteVersion: version,
+			CreatedAt:      record.CreatedAt(),
+		}
+	}
+
+	return MyResultsResponse{Results: results, NextCursor: page.Next().String()}
+}
+
+// optionalID は (ID, 有無) の組を、無ければ省略される文字列に変換する。
+func optionalID(id uuid.UUID, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return id.String()
 }
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"time"
+
+	"backend/internal/domain"
+)
+
+// HueExportRecord はエクスポートの NDJSON 1 行分。
+type HueExportRecord struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	CreatedAt      time.Time         `json:"created_at"`
+	Choice         map[string]string `json:"choice"`
+	WordSetID      string            `json:"word_set_id,omitempty"`
+	PaletteVersion int               `json:"palette_version,omitempty"`
+	WordOrder      *WordOrderItem    `json:"word_order,omitempty"`
+	QualityFlags   []string          `json:"quality_flags,omitempty"`
+}
+
+func NewHueExportRecord(record domain.HueRecord) HueExportRecord {
+	version, _ := record.PaletteVersion()
+	var order *WordOrderItem
+	if o, ok := record.WordOrder(); ok {
+		item := NewWordOrderItem(o)
+		order = &item
+	}
+	return HueExportRecord{
+		ID:             record.ID().String(),
+		Name:           record.Name().String(),
+		CreatedAt:      record.CreatedAt(),
+		Choice:         record.ChoiceMap(),
+		WordSetID:      optionalID(record.WordSetID()),
+		PaletteVersion: version,
+		WordOrder:      order,
+		QualityFlags:   qualityFlagStrings(record.QualityFlags()),
+	}
+}
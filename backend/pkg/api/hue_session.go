@@ -0,0 +1,147 @@
+package api
+
+import (
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// HueSessionResponse は /api/hue-are-you/sessions/start と answer の応答。words は出題順で、answered はここまでに答えた語数。
+// word_order は出題順の決め方で、単語は words と同じため含めない。
+type HueSessionResponse struct {
+	SessionID      string        `json:"session_id"`
+	Words          []string      `json:"words"`
+	WordOrder      WordOrderItem `json:"word_order"`
+	WordSetID      string        `json:"word_set_id"`
+	PaletteVersion int           `json:"palette_version"`
+	Answered       int           `json:"answered"`
+	StartedAt      time.Time     `json:"started_at"`
+}
+
+// WordOrderItem は出題順の決め方 (fixed / shuffle / latin_square) と seed、実際に出した順の単語。
+// latin_square の seed はラテン方格の行番号。
+type WordOrderItem struct {
+	Mode  string   `json:"mode"`
+	Seed  int64    `json:"seed"`
+	Words []string `json:"words,omitempty"`
+}
+
+func NewWordOrderItem(order domain.WordOrder) WordOrderItem {
+	words := order.Words()
+	values := make([]string, len(words))
+	for i, word := range words {
+		values[i] = string(word)
+	}
+	return WordOrderItem{Mode: order.Mode().String(), Seed: order.Seed(), Words: values}
+}
+
+func NewHueSessionResponse(session domain.HueSession) HueSessionResponse {
+	order := NewWordOrderItem(session.Order())
+	words := order.Words
+	order.Words = nil
+
+	return HueSessionResponse{
+		SessionID:      session.ID().String(),
+		Words:          words,
+		WordOrder:      order,
+		WordSetID:      session.WordSetID().String(),
+		PaletteVersion: session.PaletteVersion(),
+		Answered:       len(session.Answers()),
+		StartedAt:      session.StartedAt(),
+	}
+}
+
+// HueAnswerRequest は 1 語への回答。color は色 ID か表示名、latency_ms は単語を表示してから選ぶまでのミリ秒。
+type HueAnswerRequest struct {
+	SessionID string `json:"session_id"`
+	Word      string `json:"word"`
+	Color     string `json:"color"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// TargetID は session_id が UUID でなければ domain.ErrInvalidHueSession を返す。
+func (r HueAnswerRequest) TargetID() (uuid.UUID, error) {
+	return parseSessionID(r.SessionID)
+}
+
+func (r HueAnswerRequest) Latency() time.Duration {
+	return time.Duration(r.LatencyMS) * time.Millisecond
+}
+
+// FinishHueSessionRequest はセッションを終了し、name の回答として保存する。Challenge は /api/hue-are-you/challenge で受け取ったトークン。
+type FinishHueSessionRequest struct {
+	SessionID string `json:"session_id"`
+	Name      string `json:"name"`
+	Challenge string `json:"challenge"`
+}
+
+// ToDomain は session_id が UUID でなければ domain.ErrInvalidHueSession を、name が不正なら domain.NewName のエラーを返す。
+func (r FinishHueSessionRequest) ToDomain() (uuid.UUID, domain.Name, error) {
+	id, err := parseSessionID(r.SessionID)
+	if err != nil {
+		return uuid.Nil, domain.Name{}, err
+	}
+	name, err := domain.NewName(r.Name)
+	if err != nil {
+		return uuid.Nil, domain.Name{}, err
+	}
+	return id, name, nil
+}
+
+// FinishHueSessionResponse は保存した回答の ID を返す。
+type FinishHueSessionResponse struct {
+	RecordID string `json:"record_id"`
+}
+
+// HueSessionFunnelResponse は /api/hue-are-you/sessions/funnel の応答。reached[i] は i+1 語以上に答えたセッション数。
+type HueSessionFunnelResponse struct {
+	Started    int                 `json:"started"`
+	Finished   int                 `json:"finished"`
+	Abandoned  int                 `json:"abandoned"`
+	InProgress int                 `json:"in_progress"`
+	Reached    []int               `json:"reached"`
+	Words      []HueWordTimingItem `json:"words"`
+}
+
+// HueWordTimingItem は単語ごとの回答数と回答時間の中央値 (ミリ秒)。
+type HueWordTimingItem struct {
+	Word            string `json:"word"`
+	Answers         int    `json:"answers"`
+	MedianLatencyMS int64  `json:"median_latency_ms"`
+}
+
+func NewHueSessionFunnelResponse(funnel domain.HueSessionFunnel) HueSessionFunnelResponse {
+	reached := funnel.Reached()
+	if reached == nil {
+		reached = []int{}
+	}
+
+	timings := funnel.Timings()
+	words := make([]HueWordTimingItem, len(timings))
+	for i, t := range timings {
+		words[i] = HueWordTimingItem{
+			Word:            string(t.Word()),
+			Answers:         t.Answers(),
+			MedianLatencyMS: t.MedianLatency().Milliseconds(),
+		}
+	}
+
+	return HueSessionFunnelResponse{
+		Started:    funnel.Started(),
+		Finished:   funnel.Finished(),
+		Abandoned:  funnel.Abandoned(),
+		InProgress: funnel.InProgress(),
+		Reached:    reached,
+		Words:      words,
+	}
+}
+
+func parseSessionID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, domain.ErrInvalidHueSession
+	}
+	return id, nil
+}
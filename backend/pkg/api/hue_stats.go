@@ -0,0 +1,49 @@
+package api
+
+import "backend/internal/domain"
+
+// HueStatsResponse は palette_version の版で回答したレコードの単語ごとの色分布。
+// colors は distribution のキー (色 ID) の表示順を示す。
+type HueStatsResponse struct {
+	PaletteVersion int                   `json:"palette_version"`
+	Colors         []string              `json:"colors"`
+	Words          []HueWordStatsPayload `json:"words"`
+}
+
+// HueWordStatsPayload は 1 単語分の集計。distribution には回答のない色も 0 で含める。
+type HueWordStatsPayload struct {
+	Word              string         `json:"word"`
+	Total             int            `json:"total"`
+	Distribution      map[string]int `json:"distribution"`
+	Mode              string         `json:"mode"`
+	NormalizedEntropy float64        `json:"normalized_entropy"`
+	Agreement         float64        `json:"agreement"`
+}
+
+func NewHueStatsResponse(palette domain.Palette, stats []domain.HueWordStats) HueStatsResponse {
+	colors := palette.ColorIDs()
+	names := make([]string, len(colors))
+	for i, color := range colors {
+		names[i] = string(color)
+	}
+
+	words := make([]HueWordStatsPayload, len(stats))
+	for i, s := range stats {
+		distribution := make(map[string]int, len(colors))
+		for _, color := range colors {
+			distribution[string(color)] = s.Count(color)
+		}
+		mode, _ := s.Mode()
+
+		words[i] = HueWordStatsPayload{
+			Word:              string(s.Word()),
+			Total:             s.Total(),
+			Distribution:      distribution,
+			Mode:              string(mode),
+			NormalizedEntropy: s.NormalizedEntropy(),
+			Agreement:         s.Agreement(),
+		}
+	}
+
+	return HueStatsResponse{PaletteVersion: palette.Version(), Colors: names, Words: words}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"time"
+
+	"backend/internal/domain"
+)
+
+// PaletteColorPayload はパレットの 1 色。id は回答の choice に入る値で、names はロケールごとの表示名。
+type PaletteColorPayload struct {
+	ID    string            `json:"id"`
+	Names map[string]string `json:"names"`
+	RGB   string            `json:"rgb"`
+	Lab   *LabPayload       `json:"lab,omitempty"`
+}
+
+// LabPayload は CIE L*a*b* (D65) の値。
+type LabPayload struct {
+	L float64 `json:"l"`
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+// ToDomain は lab を省略すると rgb から求める。値が不正なら domain.ErrInvalidPalette を返す。
+func (p PaletteColorPayload) ToDomain() (domain.PaletteColor, error) {
+	rgb, err := domain.ParseRGB(p.RGB)
+	if err != nil {
+		return domain.PaletteColor{}, err
+	}
+
+	lab := domain.LabFromRGB(rgb)
+	if p.Lab != nil {
+		lab = domain.Lab{L: p.Lab.L, A: p.Lab.A, B: p.Lab.B}
+	}
+	return domain.NewPaletteColor(p.ID, p.Names, rgb, lab)
+}
+
+// PalettePayload はパレットの 1 つの版。colors は回答画面と統計の表示順。
+type PalettePayload struct {
+	Version   int                   `json:"version"`
+	Colors    []PaletteColorPayload `json:"colors"`
+	Active    bool                  `json:"active"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+func NewPalettePayload(palette domain.Palette) PalettePayload {
+	colors := palette.Colors()
+	payloads := make([]PaletteColorPayload, len(colors))
+	for i, c := range colors {
+		lab := c.Lab()
+		payloads[i] = PaletteColorPayload{
+			ID:    string(c.ID()),
+			Names: c.Names(),
+			RGB:   c.RGB().Hex(),
+			Lab:   &LabPayload{L: lab.L, A: lab.A, B: lab.B},
+		}
+	}
+
+	return PalettePayload{
+		Version:   palette.Version(),
+		Colors:    payloads,
+		Active:    palette.IsActive(),
+		CreatedAt: palette.CreatedAt(),
+	}
+}
+
+// ListPalettesResponse は /api/admin/palettes の応答。版の昇順に並ぶ。
+type ListPalettesResponse struct {
+	Palettes []PalettePayload `json:"palettes"`
+}
+
+func NewListPalettesResponse(palettes []domain.Palette) ListPalettesResponse {
+	payloads := make([]PalettePayload, len(palettes))
+	for i, palette := range palettes {
+		payloads[i] = NewPalettePayload(palette)
+	}
+	return ListPalettesResponse{Palettes: payloads}
+}
+
+// AdminPaletteRequest はパレットの管理操作。colors と activate は作成で、version は有効化で使う。
+type AdminPaletteRequest struct {
+	Version  int                   `json:"version,omitempty"`
+	Colors   []PaletteColorPayload `json:"colors,omitempty"`
+	Activate bool                  `json:"activate,omitempty"`
+}
+
+// PaletteColors は colors を domain の色に変換する。1 色でも不正なら domain.ErrInvalidPalette を返す。
+func (r AdminPaletteRequest) PaletteColors() ([]domain.PaletteColor, error) {
+	colors := make([]domain.PaletteColor, len(r.Colors))
+	for i, c := range r.Colors {
+		color, err := c.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = color
+	}
+	return colors, nil
+}
@@ -0,0 +1,21 @@
+package api
+
+import "backend/internal/domain"
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+func (r PasswordResetRequest) ToDomain() (domain.Email, error) {
+	return domain.NewEmail(r.Email)
+}
+
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
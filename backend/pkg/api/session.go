@@ -1,18 +1,32 @@
 package api
 
 import (
+	"time"
+
 	"backend/internal/domain"
 )
 
 // SessionPayload は session-data-struct を JSON で表現する。
 type SessionPayload struct {
-	UserID string `json:"user_id"`
-	Token  string `json:"token"`
+	UserID    string    `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
 }
 
 func NewSessionPayload(session domain.SessionData) SessionPayload {
 	return SessionPayload{
-		UserID: session.UserID().String(),
-		Token:  session.Token().String(),
+		UserID:    session.UserID().String(),
+		Token:     session.Token().String(),
+		ExpiresAt: session.ExpiresAt(),
 	}
 }
+
+// RefreshResponse は /api/token/refresh の応答で、ログイン応答と同じ形を取る。
+type RefreshResponse struct {
+	SessionPayload
+	Role string `json:"role"`
+}
+
+func NewRefreshResponse(session domain.SessionData, role domain.UserRole) RefreshResponse {
+	return RefreshResponse{SessionPayload: NewSessionPayload(session), Role: role.String()}
+}
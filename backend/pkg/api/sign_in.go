@@ -20,3 +20,13 @@ type SignInResponse struct {
 func NewSignInResponse(session domain.SessionData, role domain.UserRole) SignInResponse {
 	return SignInResponse{SessionPayload: NewSessionPayload(session), Role: role.String()}
 }
+
+// SignInPendingResponse はメールアドレスの確認が済むまでセッションを発行しない設定のときに返す。
+type SignInPendingResponse struct {
+	VerificationRequired bool   `json:"verification_required"`
+	Role                 string `json:"role"`
+}
+
+func NewSignInPendingResponse(role domain.UserRole) SignInPendingResponse {
+	return SignInPendingResponse{VerificationRequired: true, Role: role.String()}
+}
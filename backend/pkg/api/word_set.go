@@ -0,0 +1,66 @@
+package api
+
+import (
+	"time"
+
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// WordSetPayload は単語セット 1 件。words は出題順。
+type WordSetPayload struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Words     []string  `json:"words"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewWordSetPayload(set domain.WordSet) WordSetPayload {
+	words := set.Words()
+	values := make([]string, len(words))
+	for i, word := range words {
+		values[i] = string(word)
+	}
+
+	return WordSetPayload{
+		ID:        set.ID().String(),
+		Name:      set.Name(),
+		Words:     values,
+		Active:    set.IsActive(),
+		CreatedAt: set.CreatedAt(),
+		UpdatedAt: set.UpdatedAt(),
+	}
+}
+
+// ListWordSetsResponse は /api/admin/word-sets の応答。作成順に並ぶ。
+type ListWordSetsResponse struct {
+	WordSets []WordSetPayload `json:"word_sets"`
+}
+
+func NewListWordSetsResponse(sets []domain.WordSet) ListWordSetsResponse {
+	payloads := make([]WordSetPayload, len(sets))
+	for i, set := range sets {
+		payloads[i] = NewWordSetPayload(set)
+	}
+	return ListWordSetsResponse{WordSets: payloads}
+}
+
+// AdminWordSetRequest は単語セットの管理操作。id は作成以外で、name と words は作成・更新で、
+// activate は作成のときだけ使う。
+type AdminWordSetRequest struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Words    []string `json:"words,omitempty"`
+	Activate bool     `json:"activate,omitempty"`
+}
+
+func (r AdminWordSetRequest) TargetID() (uuid.UUID, error) {
+	id, err := uuid.Parse(r.ID)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, domain.ErrInvalidWordSet
+	}
+	return id, nil
+}
@@ -9,6 +9,9 @@ import ToySpace from './pages/toy-space/ToySpace'
 import ToyDetail from './pages/toy-space/ToyDetail'
 import Contact from './pages/contact/Contact'
 import AdminDashboard from './pages/admin/AdminDashboard'
+import ChangePassword from './pages/account/ChangePassword'
+import ResetPassword from './pages/account/ResetPassword'
+import VerifyEmail from './pages/account/VerifyEmail'
 import { ToySpaceProvider } from './contexts/ToySpaceContext'
 import { clearSessionState, loadSessionState, saveSessionState } from './utils/sessionStorage'
 import './App.css'
@@ -132,6 +135,9 @@ function App() {
                           Welcome, {user}
                           {isAdmin ? ' (Admin)' : ''}
                         </span>
+                        <NavLink to="/account/password" className="account-link" onClick={handleNavClick}>
+                          パスワード変更
+                        </NavLink>
                         <button
                           className="logout-btn"
                           onClick={() => {
@@ -169,7 +175,7 @@ function App() {
       </header>
       <Routes>
         <Route path="/" element={<Home />} />
-        <Route path="/hue-are-you" element={<HueAreYouApp />} />
+        <Route path="/hue-are-you" element={<HueAreYouApp session={session ?? undefined} />} />
         <Route path="/portfolio" element={<Portfolio />} />
         <Route
           path="/toy-space"
@@ -188,6 +194,12 @@ function App() {
           }
         />
         <Route path="/contact" element={<Contact />} />
+        <Route
+          path="/account/password"
+          element={session ? <ChangePassword session={session} /> : <Navigate to="/" replace />}
+        />
+        <Route path="/reset-password" element={<ResetPassword />} />
+        <Route path="/verify-email" element={<VerifyEmail />} />
         <Route
           path="/admin"
           element={
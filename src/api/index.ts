@@ -1,10 +1,34 @@
 import type {
+  AdminAuditLogResponse,
+  AdminUser,
+  AdminUserAction,
+  ChangePasswordPayload,
+  ListAdminUsersParams,
+  ListAdminUsersResponse,
+  FinishHueSessionPayload,
+  FinishHueSessionResponse,
+  HueChallenge,
+  HueAnswerPayload,
+  HueSession,
+  HueSessionFunnel,
+  ListPalettesResponse,
+  ListWordSetsResponse,
   LoginPayload,
   SignInPayload,
+  SignInPendingResponse,
   FetchHueAreYouDataParams,
+  FetchMyHueAreYouResultsParams,
   HueAreYouDataResponse,
+  MyHueAreYouResultsResponse,
+  PasswordResetConfirmPayload,
   SaveHueAreYouResultPayload,
+  SessionData,
   SessionResponce,
+  UserRole,
+  Palette,
+  PalettePayload,
+  WordSet,
+  WordSetPayload,
 } from './types'
 
 const DEFAULT_DEV_API_BASE_URL = 'http://localhost:8080/api/'
@@ -74,13 +98,27 @@ export class ApiError extends Error {
   }
 }
 
+const passwordRuleMessages: Record<string, string> = {
+  password_required: 'パスワードを入力してください',
+  password_too_short: 'パスワードが短すぎます',
+  password_too_long: 'パスワードは 72 バイト以内にしてください',
+  password_character_classes: '英小文字・英大文字・数字・記号を組み合わせてください',
+  password_common: 'よく使われるパスワードは使えません',
+}
+
+// パスワード規則違反 (error が password_*) なら日本語の説明を返す。それ以外は null。
+export const passwordPolicyMessage = (error: ApiError): string | null =>
+  (error.code && passwordRuleMessages[error.code]) ?? null
+
 type RequestMethod = 'GET' | 'POST'
 
 interface RequestOptions {
   method?: RequestMethod
   body?: unknown
+  session?: SessionData
   searchParams?: Record<string, string | number | undefined>
   signal?: AbortSignal
+  headers?: Record<string, string>
 }
 
 const safeJsonParse = (raw: string) => {
@@ -92,10 +130,15 @@ const safeJsonParse = (raw: string) => {
 }
 
 async function request<T>(path: string, options: RequestOptions = {}): Promise<T> {
-  const { method = 'GET', body, searchParams, signal } = options
+  const { method = 'GET', body, session, searchParams, signal } = options
   const url = buildUrl(path, searchParams)
   const headers: Record<string, string> = {
     Accept: 'application/json',
+    ...options.headers,
+  }
+
+  if (session) {
+    headers.Authorization = `Bearer ${session.user_id}.${session.token}`
   }
 
   const init: RequestInit = {
@@ -156,24 +199,135 @@ export const login = async (
     signal: options?.signal,
   })
 
+// メールアドレスの確認が必要な設定では、セッションの代わりに verification_required を返す。
 export const signIn = async (
   payload: SignInPayload,
   options?: { signal?: AbortSignal }
-): Promise<SessionResponce> =>
-  request<SessionResponce>('sign-in', {
+): Promise<SessionResponce | SignInPendingResponse> =>
+  request<SessionResponce | SignInPendingResponse>('sign-in', {
+    method: 'POST',
+    body: payload,
+    signal: options?.signal,
+  })
+
+// 成功すると現在のセッション以外はログアウトされる。
+export const changePassword = async (
+  session: SessionData,
+  payload: ChangePasswordPayload
+): Promise<void> =>
+  request<void>('password/change', {
     method: 'POST',
+    session,
     body: payload,
+  })
+
+// 登録の有無にかかわらず 202 を返す。
+export const requestPasswordReset = async (email: string): Promise<void> =>
+  request<void>('password/reset-request', {
+    method: 'POST',
+    body: { email },
+  })
+
+export const confirmPasswordReset = async (payload: PasswordResetConfirmPayload): Promise<void> =>
+  request<void>('password/reset-confirm', {
+    method: 'POST',
+    body: payload,
+  })
+
+export const verifyEmail = async (token: string): Promise<void> =>
+  request<void>('email/verify', {
+    method: 'POST',
+    body: { token },
+  })
+
+// 登録の有無にかかわらず 202 を返す。
+export const resendVerificationEmail = async (email: string): Promise<void> =>
+  request<void>('email/resend-verification', {
+    method: 'POST',
+    body: { email },
+  })
+
+// テスト開始時に取得し、save-result か sessions/finish に添えて送る。save-result では発行から保存までの時間で回答の速さが判定される。
+export const fetchHueChallenge = async (options?: { signal?: AbortSignal }): Promise<HueChallenge> =>
+  request<HueChallenge>('hue-are-you/challenge', {
+    method: 'POST',
     signal: options?.signal,
   })
 
+// session を渡すとログイン中のユーザーの回答として保存される。
+// 同じ結果を送り直すときは同じ idempotencyKey を渡すと、二重に保存されない。
 export const saveHueAreYouResult = async (
   payload: SaveHueAreYouResultPayload,
-  options?: { signal?: AbortSignal }
+  options?: { signal?: AbortSignal; session?: SessionData; idempotencyKey?: string }
 ): Promise<void> =>
   request<void>('hue-are-you/save-result', {
+    method: 'POST',
+    session: options?.session,
+    body: payload,
+    signal: options?.signal,
+    headers: options?.idempotencyKey ? { 'Idempotency-Key': options.idempotencyKey } : undefined,
+  })
+
+// 出題中の単語セットを返す。認証は不要。
+export const fetchActiveWordSet = async (options?: { signal?: AbortSignal }): Promise<WordSet> =>
+  request<WordSet>('hue-are-you/words', {
+    method: 'GET',
+    signal: options?.signal,
+  })
+
+// 出題中のパレットを返す。version を渡すとその版を返す。認証は不要。
+export const fetchPalette = async (options?: { version?: number; signal?: AbortSignal }): Promise<Palette> =>
+  request<Palette>('hue-are-you/palette', {
+    method: 'GET',
+    searchParams: { version: options?.version },
+    signal: options?.signal,
+  })
+
+// session を渡すとログイン中のユーザーのセッションとして始め、終了時の回答もそのユーザーに紐づく。
+export const startHueSession = async (options?: { signal?: AbortSignal; session?: SessionData }): Promise<HueSession> =>
+  request<HueSession>('hue-are-you/sessions/start', {
+    method: 'POST',
+    session: options?.session,
+    signal: options?.signal,
+  })
+
+// 同じ単語に答え直すと上書きされる。終了済みや放置されたセッションは 409 になる。
+export const answerHueSession = async (payload: HueAnswerPayload): Promise<HueSession> =>
+  request<HueSession>('hue-are-you/sessions/answer', {
+    method: 'POST',
+    body: payload,
+  })
+
+// 同じ結果を送り直すときは同じ idempotencyKey を渡すと、終了済みでも保存済みの回答が返る。
+export const finishHueSession = async (
+  payload: FinishHueSessionPayload,
+  options?: { signal?: AbortSignal; idempotencyKey?: string }
+): Promise<FinishHueSessionResponse> =>
+  request<FinishHueSessionResponse>('hue-are-you/sessions/finish', {
     method: 'POST',
     body: payload,
     signal: options?.signal,
+    headers: options?.idempotencyKey ? { 'Idempotency-Key': options.idempotencyKey } : undefined,
+  })
+
+export const fetchHueSessionFunnel = async (session: SessionData): Promise<HueSessionFunnel> =>
+  request<HueSessionFunnel>('hue-are-you/sessions/funnel', {
+    method: 'GET',
+    session,
+  })
+
+export const fetchMyHueAreYouResults = async (
+  params: FetchMyHueAreYouResultsParams,
+  options?: { signal?: AbortSignal }
+): Promise<MyHueAreYouResultsResponse> =>
+  request<MyHueAreYouResultsResponse>('hue-are-you/my-results', {
+    method: 'GET',
+    session: params.session,
+    searchParams: {
+      limit: params.limit,
+      cursor: params.cursor,
+    },
+    signal: options?.signal,
   })
 
 export const fetchHueAreYouRecords = async (
@@ -182,11 +336,127 @@ export const fetchHueAreYouRecords = async (
 ): Promise<HueAreYouDataResponse> =>
   request<HueAreYouDataResponse>('hue-are-you/get-data', {
     method: 'POST',
+    session: params.session,
     body: {
-      session: params.session,
-      'data-range': params.dataRange,
+      limit: params.limit,
+      cursor: params.cursor,
+      include_total: params.includeTotal,
+      filter: params.filter,
     },
     signal: options?.signal,
   })
 
+export const listAdminUsers = async (
+  params: ListAdminUsersParams,
+  options?: { signal?: AbortSignal }
+): Promise<ListAdminUsersResponse> =>
+  request<ListAdminUsersResponse>('admin/users', {
+    method: 'GET',
+    session: params.session,
+    searchParams: {
+      q: params.q,
+      role: params.role,
+      limit: params.limit,
+      offset: params.offset,
+    },
+    signal: options?.signal,
+  })
+
+export const changeAdminUserRole = async (
+  session: SessionData,
+  userId: string,
+  role: UserRole
+): Promise<AdminUser> =>
+  request<AdminUser>('admin/users/role', {
+    method: 'POST',
+    session,
+    body: { user_id: userId, role },
+  })
+
+// delete は 204 で本文を返さない。
+export const runAdminUserAction = async (
+  session: SessionData,
+  userId: string,
+  action: AdminUserAction
+): Promise<unknown> =>
+  request<unknown>(`admin/users/${action}`, {
+    method: 'POST',
+    session,
+    body: { user_id: userId },
+  })
+
+export const fetchAdminAuditLog = async (
+  session: SessionData,
+  limit?: number
+): Promise<AdminAuditLogResponse> =>
+  request<AdminAuditLogResponse>('admin/audit-log', {
+    method: 'GET',
+    session,
+    searchParams: { limit },
+  })
+
+export const listWordSets = async (session: SessionData): Promise<ListWordSetsResponse> =>
+  request<ListWordSetsResponse>('admin/word-sets', {
+    method: 'GET',
+    session,
+  })
+
+export const createWordSet = async (
+  session: SessionData,
+  payload: WordSetPayload & { activate?: boolean }
+): Promise<WordSet> =>
+  request<WordSet>('admin/word-sets/create', {
+    method: 'POST',
+    session,
+    body: payload,
+  })
+
+// 回答済みのセットの単語を変えようとすると 409 になる。名前だけの変更はいつでもできる。
+export const updateWordSet = async (
+  session: SessionData,
+  id: string,
+  payload: WordSetPayload
+): Promise<WordSet> =>
+  request<WordSet>('admin/word-sets/update', {
+    method: 'POST',
+    session,
+    body: { id, ...payload },
+  })
+
+export const activateWordSet = async (session: SessionData, id: string): Promise<WordSet> =>
+  request<WordSet>('admin/word-sets/activate', {
+    method: 'POST',
+    session,
+    body: { id },
+  })
+
+// 204 で本文を返さない。出題中か回答済みのセットは 409 になる。
+export const deleteWordSet = async (session: SessionData, id: string): Promise<void> =>
+  request<void>('admin/word-sets/delete', {
+    method: 'POST',
+    session,
+    body: { id },
+  })
+
+export const listPalettes = async (session: SessionData): Promise<ListPalettesResponse> =>
+  request<ListPalettesResponse>('admin/palettes', {
+    method: 'GET',
+    session,
+  })
+
+// パレットは作った後に変更できない。色を変えるときは新しい版を作って有効にする。
+export const createPalette = async (session: SessionData, payload: PalettePayload): Promise<Palette> =>
+  request<Palette>('admin/palettes/create', {
+    method: 'POST',
+    session,
+    body: payload,
+  })
+
+export const activatePalette = async (session: SessionData, version: number): Promise<Palette> =>
+  request<Palette>('admin/palettes/activate', {
+    method: 'POST',
+    session,
+    body: { version },
+  })
+
 export * from './types'
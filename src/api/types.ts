@@ -1,6 +1,13 @@
+// サーバーが回答に付ける品質の印。too_fast は速すぎる回答、single_color は全単語が同じ色の回答。
+export type HueQualityFlag = 'too_fast' | 'single_color'
+
 export interface HueAreYouRecord {
   name: string
+  // 保存済みの回答では値は色 ID (例: "blue")。
   choice: Record<string, string>
+  palette_version?: number
+  // 保存済みの回答にだけ付く。
+  quality_flags?: HueQualityFlag[]
 }
 
 export type UserRole = 'admin' | 'user'
@@ -13,6 +20,7 @@ export interface SessionData {
 export interface SessionResponce {
   user_id: string
   token: string
+  expires_at?: string
   role: UserRole
 }
 
@@ -27,13 +35,195 @@ export interface SignInPayload {
   password: string
 }
 
-export type SaveHueAreYouResultPayload = HueAreYouRecord
+// REQUIRE_VERIFIED_EMAIL_FOR_LOGIN が有効なとき、サインアップはセッションの代わりにこれを返す。
+export interface SignInPendingResponse {
+  verification_required: true
+  role: UserRole
+}
+
+export interface ChangePasswordPayload {
+  old_password: string
+  new_password: string
+}
+
+export interface PasswordResetConfirmPayload {
+  token: string
+  new_password: string
+}
+
+export interface SaveHueAreYouResultPayload extends Omit<HueAreYouRecord, 'quality_flags'> {
+  // 回答した単語セット。省略するとサーバーで出題中のセットへの回答として扱う。
+  word_set_id?: string
+  // fetchHueChallenge で受け取ったトークン。1 回の保存にしか使えない。
+  challenge: string
+}
+
+export interface HueChallenge {
+  challenge: string
+  expires_at: string
+}
+
+export type WordOrderMode = 'fixed' | 'shuffle' | 'latin_square'
+
+export interface WordOrder {
+  mode: WordOrderMode
+  seed: number
+}
+
+export interface HueSession {
+  session_id: string
+  // 出題順の単語。
+  words: string[]
+  word_order: WordOrder
+  word_set_id: string
+  palette_version: number
+  answered: number
+  started_at: string
+}
+
+export interface HueAnswerPayload {
+  session_id: string
+  word: string
+  color: string
+  latency_ms: number
+}
+
+export interface FinishHueSessionPayload {
+  session_id: string
+  name: string
+  // fetchHueChallenge で受け取ったトークン。save-result と同じく 1 回の保存にしか使えない。
+  challenge: string
+}
+
+export interface FinishHueSessionResponse {
+  record_id: string
+}
+
+export interface HueSessionFunnel {
+  started: number
+  finished: number
+  abandoned: number
+  in_progress: number
+  // reached[i] は i+1 語以上に答えたセッション数。
+  reached: number[]
+  words: { word: string; answers: number; median_latency_ms: number }[]
+}
+
+export interface PaletteColor {
+  id: string
+  // ロケールごとの表示名。ja は必ずある。
+  names: Record<string, string>
+  rgb: string
+  lab?: { l: number; a: number; b: number }
+}
+
+export interface Palette {
+  version: number
+  colors: PaletteColor[]
+  active: boolean
+  created_at: string
+}
+
+export interface ListPalettesResponse {
+  palettes: Palette[]
+}
+
+export interface PalettePayload {
+  colors: PaletteColor[]
+  activate?: boolean
+}
+
+export interface WordSet {
+  id: string
+  name: string
+  words: string[]
+  active: boolean
+  created_at: string
+  updated_at: string
+}
+
+export interface ListWordSetsResponse {
+  word_sets: WordSet[]
+}
+
+export interface WordSetPayload {
+  name: string
+  words: string[]
+}
+
+export interface MyHueAreYouResult extends HueAreYouRecord {
+  id: string
+  created_at: string
+}
+
+export interface FetchMyHueAreYouResultsParams {
+  session: SessionData
+  limit?: number
+  cursor?: string
+}
+
+export interface MyHueAreYouResultsResponse {
+  results: MyHueAreYouResult[]
+  next_cursor?: string
+}
+
+export interface HueAreYouRecordFilter {
+  created_from?: string
+  created_to?: string
+  name?: string
+  name_match?: 'prefix' | 'contains'
+  choices?: Record<string, string>
+}
 
 export interface FetchHueAreYouDataParams {
   session: SessionData
-  dataRange: [number, number]
+  limit?: number
+  cursor?: string
+  includeTotal?: boolean
+  filter?: HueAreYouRecordFilter
 }
 
 export interface HueAreYouDataResponse {
   records: HueAreYouRecord[]
+  next_cursor?: string
+  total?: number
+}
+
+export interface AdminUser {
+  id: string
+  username: string
+  email: string
+  role: UserRole
+  created_at: string
+  updated_at: string
+  disabled_at?: string
+  email_verified_at?: string
+}
+
+export interface ListAdminUsersParams {
+  session: SessionData
+  q?: string
+  role?: UserRole
+  limit?: number
+  offset?: number
+}
+
+export interface ListAdminUsersResponse {
+  users: AdminUser[]
+  total: number
+}
+
+export type AdminUserAction = 'disable' | 'enable' | 'revoke-sessions' | 'delete'
+
+export interface AdminAuditEntry {
+  id: string
+  actor_id: string
+  target_id: string
+  action: string
+  detail?: string
+  created_at: string
+}
+
+export interface AdminAuditLogResponse {
+  entries: AdminAuditEntry[]
 }
@@ -1,8 +1,8 @@
 import { useEffect, useState, type FormEvent, type MouseEvent } from 'react'
-import { ApiError, login, signIn, type SessionResponce } from '../api'
+import { ApiError, login, passwordPolicyMessage, requestPasswordReset, resendVerificationEmail, signIn, type SessionResponce } from '../api'
 import './LoginModal.css'
 
-export type LoginModalState = 'login' | 'signup' | null
+export type LoginModalState = 'login' | 'signup' | 'reset' | 'resend' | null
 
 interface LoginModalProps {
   modalState: LoginModalState
@@ -20,14 +20,62 @@ const LoginModal = ({ modalState, onClose, onLogin }: LoginModalProps) => {
 
   useEffect(() => {
     setState(modalState)
-    if (modalState !== 'signup') {
+    if (modalState !== 'signup' && modalState !== 'reset' && modalState !== 'resend') {
       setEmail('')
       setConfirmPassword('')
     }
   }, [modalState])
 
+  const handleResetRequest = async () => {
+    if (!email.trim()) {
+      alert('メールアドレスを入力してください')
+      return
+    }
+
+    setIsLoading(true)
+    try {
+      await requestPasswordReset(email)
+      alert('登録されているメールアドレスであれば、パスワード再設定用のリンクを送信しました。')
+      setEmail('')
+      onClose()
+    } catch (error) {
+      const message = error instanceof Error ? error.message : '予期せぬエラーが発生しました'
+      alert(message)
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
+  const handleResendVerification = async () => {
+    if (!email.trim()) {
+      alert('メールアドレスを入力してください')
+      return
+    }
+
+    setIsLoading(true)
+    try {
+      await resendVerificationEmail(email)
+      alert('未確認のメールアドレスであれば、確認用のリンクを送信しました。')
+      setState('login')
+    } catch (error) {
+      const message = error instanceof Error ? error.message : '予期せぬエラーが発生しました'
+      alert(message)
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
   const handleSubmit = async (e: FormEvent<HTMLFormElement>) => {
     e.preventDefault()
+    if (state === 'reset') {
+      await handleResetRequest()
+      return
+    }
+    if (state === 'resend') {
+      await handleResendVerification()
+      return
+    }
+
     if (!username.trim() || !password.trim()) {
       alert('ユーザー名とパスワードを入力してください')
       return
@@ -51,6 +99,14 @@ const LoginModal = ({ modalState, onClose, onLogin }: LoginModalProps) => {
           ? await signIn({ name: username, email, password })
           : await login({ name: username, password })
 
+      if (session && 'verification_required' in session) {
+        alert(`${email} に確認用のリンクを送信しました。メールアドレスを確認してからログインしてください。`)
+        setPassword('')
+        setConfirmPassword('')
+        setState('login')
+        return
+      }
+
       if (!session?.token || !session.role) {
         throw new Error('サーバーから不正なレスポンスを受信しました。')
       }
@@ -62,10 +118,13 @@ const LoginModal = ({ modalState, onClose, onLogin }: LoginModalProps) => {
       setPassword('')
       setConfirmPassword('')
     } catch (error) {
-      if (state === 'signup' && error instanceof ApiError) {
+      if (state === 'login' && error instanceof ApiError && error.status === 403 && error.field === 'email') {
+        alert('メールアドレスの確認が済んでいません。届いたメールのリンクを開いてください。')
+        setState('resend')
+      } else if (state === 'signup' && error instanceof ApiError) {
         const fieldLabel = error.field ? `[${error.field}] ` : ''
         const duplicateHint = error.code === 'duplicate' ? '\n同じ情報のアカウントが既に存在します。' : ''
-        const detail = error.message || 'サインアップに失敗しました'
+        const detail = passwordPolicyMessage(error) ?? (error.message || 'サインアップに失敗しました')
         alert(`サインアップに失敗しました: ${fieldLabel}${detail}${duplicateHint}`)
       } else {
         const message = error instanceof Error ? error.message : '予期せぬエラーが発生しました'
@@ -92,24 +151,42 @@ const LoginModal = ({ modalState, onClose, onLogin }: LoginModalProps) => {
         </button>
         
         <div className="login-header">
-          <h2>{state === 'login' ? 'Login' : 'Sign Up'}</h2>
-          <p>{state === 'login' ? 'AhahaCraftにログイン' : 'AhahaCraftに新規登録'}</p>
+          <h2>
+            {state === 'login'
+              ? 'Login'
+              : state === 'signup'
+                ? 'Sign Up'
+                : state === 'reset'
+                  ? 'Reset Password'
+                  : 'Verify Email'}
+          </h2>
+          <p>
+            {state === 'login'
+              ? 'AhahaCraftにログイン'
+              : state === 'signup'
+                ? 'AhahaCraftに新規登録'
+                : state === 'reset'
+                  ? '登録したメールアドレスに再設定用のリンクを送ります'
+                  : '登録したメールアドレスに確認用のリンクを送り直します'}
+          </p>
         </div>
 
         <form onSubmit={handleSubmit} className="login-form">
-          <div className="form-group">
-            <label htmlFor="username">ユーザー名</label>
-            <input
-              id="username"
-              type="text"
-              value={username}
-              onChange={(e) => setUsername(e.target.value)}
-              placeholder="ユーザー名を入力"
-              disabled={isLoading}
-            />
-          </div>
+          {(state === 'login' || state === 'signup') && (
+            <div className="form-group">
+              <label htmlFor="username">ユーザー名</label>
+              <input
+                id="username"
+                type="text"
+                value={username}
+                onChange={(e) => setUsername(e.target.value)}
+                placeholder="ユーザー名を入力"
+                disabled={isLoading}
+              />
+            </div>
+          )}
 
-          {state === 'signup' && (
+          {(state === 'signup' || state === 'reset' || state === 'resend') && (
             <div className="form-group">
               <label htmlFor="email">メールアドレス</label>
               <input
@@ -123,17 +200,19 @@ const LoginModal = ({ modalState, onClose, onLogin }: LoginModalProps) => {
             </div>
           )}
 
-          <div className="form-group">
-            <label htmlFor="password">パスワード</label>
-            <input
-              id="password"
-              type="password"
-              value={password}
-              onChange={(e) => setPassword(e.target.value)}
-              placeholder="パスワードを入力"
-              disabled={isLoading}
-            />
-          </div>
+          {(state === 'login' || state === 'signup') && (
+            <div className="form-group">
+              <label htmlFor="password">パスワード</label>
+              <input
+                id="password"
+                type="password"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                placeholder="パスワードを入力"
+                disabled={isLoading}
+              />
+            </div>
+          )}
 
           {state === 'signup' && (
             <div className="form-group">
@@ -158,15 +237,34 @@ const LoginModal = ({ modalState, onClose, onLogin }: LoginModalProps) => {
               ? '処理中...'
               : state === 'login'
                 ? 'ログイン'
-                : 'サインアップ'}
+                : state === 'signup'
+                  ? 'サインアップ'
+                  : state === 'reset'
+                    ? '再設定メールを送信'
+                    : '確認メールを再送'}
           </button>
         </form>
 
         <div className="switch-mode">
+          {state === 'login' && (
+            <p>
+              パスワードをお忘れの方は
+              <button
+                type="button"
+                className="switch-btn"
+                onClick={() => setState('reset')}
+                disabled={isLoading}
+              >
+                再設定
+              </button>
+            </p>
+          )}
           <p>
             {state === 'login'
               ? 'アカウントをお持ちでない方は'
-              : 'すでにアカウントをお持ちの方は'}
+              : state === 'signup'
+                ? 'すでにアカウントをお持ちの方は'
+                : 'ログイン画面に戻る:'}
             <button
               type="button"
               className="switch-btn"
@@ -1,4 +1,5 @@
 import { Color } from '../types'
+import type { Palette } from '../api/types'
 
 export const colors: Color[] = [
   '黒', 
@@ -27,3 +28,40 @@ export const colorToHex: Record<Color, string> = {
   '紫': '#956ec4',       // ライラック寄りの濃いめパープル
   '茶': '#a1693c'        // キャラメルとチョコの中間くらい
 }
+
+// 初期パレット (版 1) の色 ID。サーバーに保存された回答は色 ID で返ってくる。
+export const colorIds: Record<string, Color> = {
+  black: '黒',
+  gray: '灰色',
+  white: '白',
+  pink: 'ピンク',
+  red: '赤',
+  orange: 'オレンジ',
+  yellow: '黄色',
+  green: '緑',
+  blue: '青',
+  purple: '紫',
+  brown: '茶'
+}
+
+export interface ColorOption {
+  id: string
+  name: string
+  hex: string
+}
+
+// パレットを取得できなければ同梱の色で出題する。
+export const fallbackColorOptions: ColorOption[] = Object.entries(colorIds).map(([id, name]) => ({
+  id,
+  name,
+  hex: colorToHex[name]
+}))
+
+export const paletteColorOptions = (palette: Palette | null, locale = 'ja'): ColorOption[] =>
+  palette
+    ? palette.colors.map((c) => ({ id: c.id, name: c.names[locale] ?? c.names.ja, hex: c.rgb }))
+    : fallbackColorOptions
+
+// 色 ID か表示名から表示用の色を引く。見つからなければ undefined。
+export const findColorOption = (value: string, options: ColorOption[] = fallbackColorOptions) =>
+  options.find((o) => o.id === value || o.name === value)
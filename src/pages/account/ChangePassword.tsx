@@ -0,0 +1,98 @@
+import { useState, type FormEvent } from 'react'
+import { ApiError, changePassword, passwordPolicyMessage, type SessionData } from '../../api'
+import './Account.css'
+
+interface ChangePasswordProps {
+  session: SessionData
+}
+
+const ChangePassword = ({ session }: ChangePasswordProps) => {
+  const [oldPassword, setOldPassword] = useState('')
+  const [newPassword, setNewPassword] = useState('')
+  const [confirmPassword, setConfirmPassword] = useState('')
+  const [isLoading, setIsLoading] = useState(false)
+  const [message, setMessage] = useState<string | null>(null)
+  const [error, setError] = useState<string | null>(null)
+
+  const handleSubmit = async (e: FormEvent<HTMLFormElement>) => {
+    e.preventDefault()
+    setMessage(null)
+    setError(null)
+
+    if (!oldPassword.trim() || !newPassword.trim()) {
+      setError('現在のパスワードと新しいパスワードを入力してください')
+      return
+    }
+    if (newPassword !== confirmPassword) {
+      setError('新しいパスワードが一致しません')
+      return
+    }
+
+    setIsLoading(true)
+    try {
+      await changePassword(session, { old_password: oldPassword, new_password: newPassword })
+      setOldPassword('')
+      setNewPassword('')
+      setConfirmPassword('')
+      setMessage('パスワードを変更しました。他の端末のセッションはログアウトされました。')
+    } catch (err) {
+      if (err instanceof ApiError && err.field === 'old_password') {
+        setError('現在のパスワードが正しくありません')
+      } else if (err instanceof ApiError && passwordPolicyMessage(err)) {
+        setError(passwordPolicyMessage(err))
+      } else {
+        setError(err instanceof Error ? err.message : 'パスワードの変更に失敗しました')
+      }
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
+  return (
+    <main className="account-page">
+      <h2>パスワード変更</h2>
+      <form onSubmit={handleSubmit} className="login-form">
+        <div className="form-group">
+          <label htmlFor="oldPassword">現在のパスワード</label>
+          <input
+            id="oldPassword"
+            type="password"
+            autoComplete="current-password"
+            value={oldPassword}
+            onChange={(e) => setOldPassword(e.target.value)}
+            disabled={isLoading}
+          />
+        </div>
+        <div className="form-group">
+          <label htmlFor="newPassword">新しいパスワード</label>
+          <input
+            id="newPassword"
+            type="password"
+            autoComplete="new-password"
+            value={newPassword}
+            onChange={(e) => setNewPassword(e.target.value)}
+            disabled={isLoading}
+          />
+        </div>
+        <div className="form-group">
+          <label htmlFor="confirmNewPassword">新しいパスワード確認</label>
+          <input
+            id="confirmNewPassword"
+            type="password"
+            autoComplete="new-password"
+            value={confirmPassword}
+            onChange={(e) => setConfirmPassword(e.target.value)}
+            disabled={isLoading}
+          />
+        </div>
+        {error && <p className="account-error">{error}</p>}
+        {message && <p className="account-message">{message}</p>}
+        <button type="submit" className="submit-btn" disabled={isLoading}>
+          {isLoading ? '処理中...' : '変更する'}
+        </button>
+      </form>
+    </main>
+  )
+}
+
+export default ChangePassword
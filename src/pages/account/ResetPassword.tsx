@@ -0,0 +1,100 @@
+import { useState, type FormEvent } from 'react'
+import { NavLink, useSearchParams } from 'react-router-dom'
+import { ApiError, confirmPasswordReset, passwordPolicyMessage } from '../../api'
+import './Account.css'
+
+// メールのリンク (/reset-password?token=...) から開く再設定ページ。
+const ResetPassword = () => {
+  const [searchParams] = useSearchParams()
+  const token = searchParams.get('token') ?? ''
+  const [newPassword, setNewPassword] = useState('')
+  const [confirmPassword, setConfirmPassword] = useState('')
+  const [isLoading, setIsLoading] = useState(false)
+  const [isDone, setIsDone] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const handleSubmit = async (e: FormEvent<HTMLFormElement>) => {
+    e.preventDefault()
+    setError(null)
+
+    if (!newPassword.trim()) {
+      setError('新しいパスワードを入力してください')
+      return
+    }
+    if (newPassword !== confirmPassword) {
+      setError('パスワードが一致しません')
+      return
+    }
+
+    setIsLoading(true)
+    try {
+      await confirmPasswordReset({ token, new_password: newPassword })
+      setIsDone(true)
+    } catch (err) {
+      if (err instanceof ApiError && err.field === 'token') {
+        setError('リンクが無効か期限切れです。もう一度再設定を申請してください。')
+      } else if (err instanceof ApiError && passwordPolicyMessage(err)) {
+        setError(passwordPolicyMessage(err))
+      } else {
+        setError(err instanceof Error ? err.message : 'パスワードの再設定に失敗しました')
+      }
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
+  if (!token) {
+    return (
+      <main className="account-page">
+        <h2>パスワード再設定</h2>
+        <p className="account-error">リンクが正しくありません。メールのリンクをもう一度開いてください。</p>
+      </main>
+    )
+  }
+
+  if (isDone) {
+    return (
+      <main className="account-page">
+        <h2>パスワード再設定</h2>
+        <p className="account-message">パスワードを再設定しました。新しいパスワードでログインしてください。</p>
+        <NavLink to="/">ホームへ戻る</NavLink>
+      </main>
+    )
+  }
+
+  return (
+    <main className="account-page">
+      <h2>パスワード再設定</h2>
+      <form onSubmit={handleSubmit} className="login-form">
+        <div className="form-group">
+          <label htmlFor="resetPassword">新しいパスワード</label>
+          <input
+            id="resetPassword"
+            type="password"
+            autoComplete="new-password"
+            value={newPassword}
+            onChange={(e) => setNewPassword(e.target.value)}
+            disabled={isLoading}
+          />
+        </div>
+        <div className="form-group">
+          <label htmlFor="resetPasswordConfirm">パスワード確認</label>
+          <input
+            id="resetPasswordConfirm"
+            type="password"
+            autoComplete="new-password"
+            value={confirmPassword}
+            onChange={(e) => setConfirmPassword(e.target.value)}
+            disabled={isLoading}
+          />
+        </div>
+        {error && <p className="account-error">{error}</p>}
+        <button type="submit" className="submit-btn" disabled={isLoading}>
+          {isLoading ? '処理中...' : '再設定する'}
+        </button>
+      </form>
+    </main>
+  )
+}
+
+export default ResetPassword
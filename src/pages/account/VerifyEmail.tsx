@@ -0,0 +1,58 @@
+import { useEffect, useState } from 'react'
+import { NavLink, useSearchParams } from 'react-router-dom'
+import { ApiError, verifyEmail } from '../../api'
+import './Account.css'
+
+type VerifyStatus = 'pending' | 'done' | 'error'
+
+// メールのリンク (/verify-email?token=...) から開く確認ページ。開いた時点で確認を送る。
+const VerifyEmail = () => {
+  const [searchParams] = useSearchParams()
+  const token = searchParams.get('token') ?? ''
+  const [status, setStatus] = useState<VerifyStatus>('pending')
+  const [error, setError] = useState<string | null>(null)
+
+  useEffect(() => {
+    if (!token) return
+
+    let cancelled = false
+    verifyEmail(token)
+      .then(() => {
+        if (!cancelled) setStatus('done')
+      })
+      .catch((err: unknown) => {
+        if (cancelled) return
+        setStatus('error')
+        if (err instanceof ApiError && err.field === 'token') {
+          setError('リンクが無効か期限切れです。ログイン画面から確認メールを再送してください。')
+        } else {
+          setError(err instanceof Error ? err.message : 'メールアドレスの確認に失敗しました')
+        }
+      })
+
+    return () => {
+      cancelled = true
+    }
+  }, [token])
+
+  if (!token) {
+    return (
+      <main className="account-page">
+        <h2>メールアドレスの確認</h2>
+        <p className="account-error">リンクが正しくありません。メールのリンクをもう一度開いてください。</p>
+      </main>
+    )
+  }
+
+  return (
+    <main className="account-page">
+      <h2>メールアドレスの確認</h2>
+      {status === 'pending' && <p>確認しています...</p>}
+      {status === 'done' && <p className="account-message">メールアドレスを確認しました。ログインできます。</p>}
+      {status === 'error' && <p className="account-error">{error}</p>}
+      {status !== 'pending' && <NavLink to="/">ホームへ戻る</NavLink>}
+    </main>
+  )
+}
+
+export default VerifyEmail
@@ -3,15 +3,23 @@ import {
   ApiError,
   fetchHueAreYouRecords,
   type HueAreYouRecord,
+  type HueAreYouRecordFilter,
+  type HueQualityFlag,
   type SessionData,
   type SessionResponce,
 } from '../../api'
-import { colorToHex } from '../../data/colors'
+import { findColorOption, fallbackColorOptions } from '../../data/colors'
 import ErrorNotice, { type ErrorDescriptor } from '../../components/ErrorNotice'
+import UserManagementPanel from './UserManagementPanel'
 import './AdminDashboard.css'
 
 type AdminNavItem = 'user-management' | 'hue-results'
 
+const QUALITY_FLAG_LABELS: Record<HueQualityFlag, string> = {
+  too_fast: '速すぎる',
+  single_color: '全て同じ色',
+}
+
 interface AdminDashboardProps {
   username: string
   session: SessionResponce
@@ -25,7 +33,7 @@ const sections = [
       {
         id: 'user-management' as AdminNavItem,
         label: 'ユーザー管理',
-        description: 'ユーザーデータの閲覧・編集機能（近日追加）',
+        description: 'ユーザーの検索・ロール変更・無効化',
       },
     ],
   },
@@ -83,7 +91,7 @@ const AdminDashboard = ({ username, session }: AdminDashboardProps) => {
       </aside>
       <main className="admin-content">
         {activeItem === 'user-management' ? (
-          <UserManagementPlaceholder />
+          <UserManagementPanel session={sessionPayload} />
         ) : (
           <HueResultsPanel session={sessionPayload} />
         )}
@@ -92,37 +100,69 @@ const AdminDashboard = ({ username, session }: AdminDashboardProps) => {
   )
 }
 
-const UserManagementPlaceholder = () => (
-  <section className="admin-card">
-    <header>
-      <h2>ユーザー管理</h2>
-      <p>ユーザー管理ツールは現在設計中です。今後のリリースをお待ちください。</p>
-    </header>
-    <ul className="todo-list">
-      <li>ユーザー検索・フィルタ</li>
-      <li>ロール変更 / 無効化操作</li>
-      <li>活動ログの確認</li>
-    </ul>
-  </section>
-)
-
 interface HueResultsPanelProps {
   session: SessionData
 }
 
+const MAX_PAGE_SIZE = 100
+
+interface FilterForm {
+  dateFrom: string
+  dateTo: string
+  name: string
+  nameMatch: 'prefix' | 'contains'
+  word: string
+  color: string
+}
+
+const emptyFilterForm: FilterForm = {
+  dateFrom: '',
+  dateTo: '',
+  name: '',
+  nameMatch: 'contains',
+  word: '',
+  color: '',
+}
+
+// 日付入力はローカル時刻の 0 時として扱い、終了日はその日の終わりまで含める。
+const toFilter = (form: FilterForm): HueAreYouRecordFilter | undefined => {
+  const filter: HueAreYouRecordFilter = {}
+  if (form.dateFrom) {
+    filter.created_from = new Date(`${form.dateFrom}T00:00:00`).toISOString()
+  }
+  if (form.dateTo) {
+    const end = new Date(`${form.dateTo}T00:00:00`)
+    end.setDate(end.getDate() + 1)
+    filter.created_to = end.toISOString()
+  }
+  if (form.name.trim()) {
+    filter.name = form.name.trim()
+    filter.name_match = form.nameMatch
+  }
+  if (form.word.trim() && form.color) {
+    filter.choices = { [form.word.trim()]: form.color }
+  }
+  return Object.keys(filter).length > 0 ? filter : undefined
+}
+
 const HueResultsPanel = ({ session }: HueResultsPanelProps) => {
-  const [rangeStart, setRangeStart] = useState(0)
-  const [rangeEnd, setRangeEnd] = useState(24)
+  const [pageSize, setPageSize] = useState(25)
+  const [filterForm, setFilterForm] = useState<FilterForm>(emptyFilterForm)
+  const [activeFilter, setActiveFilter] = useState<HueAreYouRecordFilter | undefined>(undefined)
   const [isLoading, setIsLoading] = useState(false)
   const [error, setError] = useState<ErrorDescriptor | null>(null)
   const [records, setRecords] = useState<HueAreYouRecord[]>([])
+  const [nextCursor, setNextCursor] = useState<string | undefined>(undefined)
+  const [total, setTotal] = useState<number | undefined>(undefined)
   const [lastFetchedAt, setLastFetchedAt] = useState<Date | null>(null)
 
-  const handleSubmit = async (event: FormEvent<HTMLFormElement>) => {
-    event.preventDefault()
+  const updateFilter = <K extends keyof FilterForm>(key: K, value: FilterForm[K]) =>
+    setFilterForm((current) => ({ ...current, [key]: value }))
 
-    if (rangeStart < 0 || rangeEnd < 0 || rangeStart > rangeEnd) {
-      setError({ message: 'データ範囲が不正です', field: 'data-range' })
+  // 続きの取得では、最初のページを取ったときの条件をそのまま使う。
+  const loadPage = async (filter: HueAreYouRecordFilter | undefined, cursor?: string) => {
+    if (pageSize < 1 || pageSize > MAX_PAGE_SIZE) {
+      setError({ message: `取得件数は1〜${MAX_PAGE_SIZE}件で指定してください`, field: 'limit' })
       return
     }
 
@@ -132,10 +172,18 @@ const HueResultsPanel = ({ session }: HueResultsPanelProps) => {
     try {
       const response = await fetchHueAreYouRecords({
         session,
-        dataRange: [rangeStart, rangeEnd],
+        limit: pageSize,
+        cursor,
+        includeTotal: cursor === undefined,
+        filter,
       })
 
-      setRecords(response.records ?? [])
+      const fetched = response.records ?? []
+      setRecords((current) => (cursor === undefined ? fetched : [...current, ...fetched]))
+      setNextCursor(response.next_cursor)
+      if (response.total !== undefined) {
+        setTotal(response.total)
+      }
       setLastFetchedAt(new Date())
     } catch (err) {
       if (err instanceof ApiError) {
@@ -148,6 +196,13 @@ const HueResultsPanel = ({ session }: HueResultsPanelProps) => {
     }
   }
 
+  const handleSubmit = (event: FormEvent<HTMLFormElement>) => {
+    event.preventDefault()
+    const filter = toFilter(filterForm)
+    setActiveFilter(filter)
+    void loadPage(filter)
+  }
+
   return (
     <section className="admin-card">
       <header className="results-header">
@@ -162,23 +217,68 @@ const HueResultsPanel = ({ session }: HueResultsPanelProps) => {
 
       <form className="results-form" onSubmit={handleSubmit}>
         <label>
-          開始インデックス
+          取得件数
           <input
             type="number"
-            value={rangeStart}
-            min={0}
-            onChange={(e) => setRangeStart(Number(e.target.value))}
+            value={pageSize}
+            min={1}
+            max={MAX_PAGE_SIZE}
+            onChange={(e) => setPageSize(Number(e.target.value))}
           />
         </label>
         <label>
-          終了インデックス
+          回答日 (から)
           <input
-            type="number"
-            value={rangeEnd}
-            min={0}
-            onChange={(e) => setRangeEnd(Number(e.target.value))}
+            type="date"
+            value={filterForm.dateFrom}
+            onChange={(e) => updateFilter('dateFrom', e.target.value)}
+          />
+        </label>
+        <label>
+          回答日 (まで)
+          <input
+            type="date"
+            value={filterForm.dateTo}
+            onChange={(e) => updateFilter('dateTo', e.target.value)}
           />
         </label>
+        <label>
+          名前
+          <input
+            type="text"
+            value={filterForm.name}
+            onChange={(e) => updateFilter('name', e.target.value)}
+          />
+        </label>
+        <label>
+          一致方法
+          <select
+            value={filterForm.nameMatch}
+            onChange={(e) => updateFilter('nameMatch', e.target.value as FilterForm['nameMatch'])}
+          >
+            <option value="contains">部分一致</option>
+            <option value="prefix">前方一致</option>
+          </select>
+        </label>
+        <label>
+          単語
+          <input
+            type="text"
+            value={filterForm.word}
+            onChange={(e) => updateFilter('word', e.target.value)}
+          />
+        </label>
+        <label>
+          色
+          <select value={filterForm.color} onChange={(e) => updateFilter('color', e.target.value)}>
+            <option value="">指定なし</option>
+            {fallbackColorOptions.map((color) => (
+              <option key={color.id} value={color.id}>
+                {color.name}
+              </option>
+            ))}
+          </select>
+        </label>
         <button type="submit" disabled={isLoading}>
           {isLoading ? '取得中...' : 'データ取得'}
         </button>
@@ -196,23 +296,43 @@ const HueResultsPanel = ({ session }: HueResultsPanelProps) => {
               <span>
                 #{index + 1} {record.name}
               </span>
-              <span className="word-count">{Object.keys(record.choice).length}語</span>
+              <span className="word-count">
+                {record.quality_flags?.map((flag) => (
+                  <span key={flag} className="quality-flag">
+                    {QUALITY_FLAG_LABELS[flag]}
+                  </span>
+                ))}
+                {Object.keys(record.choice).length}語
+              </span>
             </summary>
             <div className="record-body">
               {Object.entries(record.choice).map(([word, color]) => (
                 <span
                   key={`${word}-${color}`}
                   className="word-chip"
-                  style={{ backgroundColor: colorToHex[color as keyof typeof colorToHex] ?? '#eee' }}
+                  style={{ backgroundColor: findColorOption(color)?.hex ?? '#eee' }}
                 >
                   {word}
-                  <strong>{color}</strong>
+                  <strong>{findColorOption(color)?.name ?? color}</strong>
                 </span>
               ))}
             </div>
           </details>
         ))}
       </div>
+
+      {(records.length > 0 || total !== undefined) && (
+        <footer className="results-footer">
+          <span>
+            {records.length}件表示{total !== undefined && ` / 全${total}件`}
+          </span>
+          {nextCursor && (
+            <button type="button" disabled={isLoading} onClick={() => void loadPage(activeFilter, nextCursor)}>
+              {isLoading ? '取得中...' : 'さらに読み込む'}
+            </button>
+          )}
+        </footer>
+      )}
     </section>
   )
 }
@@ -0,0 +1,212 @@
+import { useState, type FormEvent } from 'react'
+import {
+  ApiError,
+  changeAdminUserRole,
+  fetchAdminAuditLog,
+  listAdminUsers,
+  runAdminUserAction,
+  type AdminAuditEntry,
+  type AdminUser,
+  type AdminUserAction,
+  type SessionData,
+  type UserRole,
+} from '../../api'
+import ErrorNotice, { type ErrorDescriptor } from '../../components/ErrorNotice'
+
+const PAGE_SIZE = 50
+
+interface UserManagementPanelProps {
+  session: SessionData
+}
+
+const UserManagementPanel = ({ session }: UserManagementPanelProps) => {
+  const [search, setSearch] = useState('')
+  const [roleFilter, setRoleFilter] = useState<UserRole | ''>('')
+  const [users, setUsers] = useState<AdminUser[]>([])
+  const [total, setTotal] = useState<number | undefined>(undefined)
+  const [offset, setOffset] = useState(0)
+  const [auditLog, setAuditLog] = useState<AdminAuditEntry[]>([])
+  const [isLoading, setIsLoading] = useState(false)
+  const [error, setError] = useState<ErrorDescriptor | null>(null)
+
+  const handleError = (err: unknown, fallback: string) => {
+    if (err instanceof ApiError) {
+      setError({ message: err.message, field: err.field, code: err.code })
+    } else {
+      setError({ message: fallback })
+    }
+  }
+
+  const loadUsers = async (nextOffset: number) => {
+    setIsLoading(true)
+    setError(null)
+    try {
+      const response = await listAdminUsers({
+        session,
+        q: search.trim() || undefined,
+        role: roleFilter || undefined,
+        limit: PAGE_SIZE,
+        offset: nextOffset,
+      })
+      setUsers(response.users ?? [])
+      setTotal(response.total)
+      setOffset(nextOffset)
+    } catch (err) {
+      handleError(err, 'ユーザー一覧の取得に失敗しました')
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
+  const loadAuditLog = async () => {
+    try {
+      const response = await fetchAdminAuditLog(session, 20)
+      setAuditLog(response.entries ?? [])
+    } catch (err) {
+      handleError(err, '監査ログの取得に失敗しました')
+    }
+  }
+
+  const handleSubmit = (event: FormEvent<HTMLFormElement>) => {
+    event.preventDefault()
+    void loadUsers(0)
+  }
+
+  // 操作後は一覧と監査ログを取り直して、サーバー側の状態を表示する。
+  const runAction = async (user: AdminUser, action: AdminUserAction | UserRole) => {
+    if (action === 'delete' && !window.confirm(`${user.username} を削除しますか？`)) {
+      return
+    }
+
+    setIsLoading(true)
+    setError(null)
+    try {
+      if (action === 'admin' || action === 'user') {
+        await changeAdminUserRole(session, user.id, action)
+      } else {
+        await runAdminUserAction(session, user.id, action)
+      }
+      await Promise.all([loadUsers(offset), loadAuditLog()])
+    } catch (err) {
+      handleError(err, '操作に失敗しました')
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
+  return (
+    <section className="admin-card">
+      <header className="results-header">
+        <div>
+          <h2>ユーザー管理</h2>
+          <p>ユーザーの検索、ロール変更、無効化、強制ログアウト、削除ができます。</p>
+        </div>
+      </header>
+
+      <form className="results-form" onSubmit={handleSubmit}>
+        <label>
+          ユーザー名 / メール
+          <input type="text" value={search} onChange={(e) => setSearch(e.target.value)} />
+        </label>
+        <label>
+          ロール
+          <select value={roleFilter} onChange={(e) => setRoleFilter(e.target.value as UserRole | '')}>
+            <option value="">すべて</option>
+            <option value="user">user</option>
+            <option value="admin">admin</option>
+          </select>
+        </label>
+        <button type="submit" disabled={isLoading}>
+          {isLoading ? '取得中...' : '検索'}
+        </button>
+      </form>
+
+      {error && <ErrorNotice {...error} onDismiss={() => setError(null)} />}
+
+      {total !== undefined && (
+        <table className="user-table">
+          <thead>
+            <tr>
+              <th>ユーザー名</th>
+              <th>メール</th>
+              <th>確認</th>
+              <th>ロール</th>
+              <th>状態</th>
+              <th>操作</th>
+            </tr>
+          </thead>
+          <tbody>
+            {users.map((user) => (
+              <tr key={user.id} className={user.disabled_at ? 'disabled' : undefined}>
+                <td>{user.username}</td>
+                <td>{user.email}</td>
+                <td>{user.email_verified_at ? '確認済み' : '未確認'}</td>
+                <td>{user.role}</td>
+                <td>{user.disabled_at ? `無効 (${new Date(user.disabled_at).toLocaleString()})` : '有効'}</td>
+                <td className="user-actions">
+                  <button
+                    type="button"
+                    disabled={isLoading || user.id === session.user_id}
+                    onClick={() => void runAction(user, user.role === 'admin' ? 'user' : 'admin')}
+                  >
+                    {user.role === 'admin' ? 'userにする' : 'adminにする'}
+                  </button>
+                  <button
+                    type="button"
+                    disabled={isLoading || user.id === session.user_id}
+                    onClick={() => void runAction(user, user.disabled_at ? 'enable' : 'disable')}
+                  >
+                    {user.disabled_at ? '有効化' : '無効化'}
+                  </button>
+                  <button type="button" disabled={isLoading} onClick={() => void runAction(user, 'revoke-sessions')}>
+                    強制ログアウト
+                  </button>
+                  <button
+                    type="button"
+                    disabled={isLoading || user.id === session.user_id}
+                    onClick={() => void runAction(user, 'delete')}
+                  >
+                    削除
+                  </button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      )}
+
+      {total !== undefined && (
+        <footer className="results-footer">
+          <span>
+            {total === 0 ? 0 : offset + 1}〜{offset + users.length}件 / 全{total}件
+          </span>
+          <button type="button" disabled={isLoading || offset === 0} onClick={() => void loadUsers(Math.max(0, offset - PAGE_SIZE))}>
+            前へ
+          </button>
+          <button
+            type="button"
+            disabled={isLoading || offset + users.length >= total}
+            onClick={() => void loadUsers(offset + PAGE_SIZE)}
+          >
+            次へ
+          </button>
+        </footer>
+      )}
+
+      <details className="audit-log" onToggle={(e) => e.currentTarget.open && void loadAuditLog()}>
+        <summary>監査ログ (最新20件)</summary>
+        <ul>
+          {auditLog.map((entry) => (
+            <li key={entry.id}>
+              <span className="timestamp">{new Date(entry.created_at).toLocaleString()}</span> {entry.action}{' '}
+              {entry.target_id}
+              {entry.detail && ` (${entry.detail})`}
+            </li>
+          ))}
+        </ul>
+      </details>
+    </section>
+  )
+}
+
+export default UserManagementPanel
@@ -1,5 +1,19 @@
-import React, { useState } from 'react'
-import { saveHueAreYouResult } from '../../api'
+import React, { useEffect, useState } from 'react'
+import {
+  answerHueSession,
+  fetchActiveWordSet,
+  fetchHueChallenge,
+  fetchPalette,
+  finishHueSession,
+  saveHueAreYouResult,
+  startHueSession,
+  type HueSession,
+  type Palette,
+  type SessionData,
+  type WordSet,
+} from '../../api'
+import { paletteColorOptions } from '../../data/colors'
+import { getWords } from '../../data/words'
 import StartScreen from './user/StartScreen'
 import SelectionScreen from './user/SelectionScreen'
 import ResultScreen from './user/ResultScreen'
@@ -7,17 +21,67 @@ import './HueAreYouApp.css'
 
 type Screen = 'start' | 'selection' | 'result'
 
-const HueAreYouApp: React.FC = () => {
+// SelectionScreen のスキップボタンが入れる値。
+const SKIPPED_COLOR = '未選択'
+
+interface HueAreYouAppProps {
+  // ログイン中なら回答をそのユーザーに紐づけて保存する。
+  session?: SessionData
+}
+
+const HueAreYouApp: React.FC<HueAreYouAppProps> = ({ session }) => {
   const [currentScreen, setCurrentScreen] = useState<Screen>('start')
   const [assignments, setAssignments] = useState<Record<string, string>>({})
   const [userName, setUserName] = useState('')
+  // 取得できなければ同梱の単語で出題し、word_set_id は送らない。
+  const [wordSet, setWordSet] = useState<WordSet | null>(null)
+  // 取得できなければ同梱の色で出題し、palette_version は送らない。
+  const [palette, setPalette] = useState<Palette | null>(null)
+  // 開始できなければ従来どおり結果画面でまとめて保存する。
+  const [hueSession, setHueSession] = useState<HueSession | null>(null)
+  // 保存するときに添えるチャレンジ。セッションの有無にかかわらずテスト開始時に取得する。
+  const [challenge, setChallenge] = useState<string | null>(null)
+  // 保存をやり直しても二重に保存されないよう、結果ごとに 1 つ決める。
+  const [idempotencyKey, setIdempotencyKey] = useState<string | null>(null)
 
-  const handleStart = () => {
+  useEffect(() => {
+    const controller = new AbortController()
+    fetchActiveWordSet({ signal: controller.signal })
+      .then(setWordSet)
+      .catch(() => setWordSet(null))
+    fetchPalette({ signal: controller.signal })
+      .then(setPalette)
+      .catch(() => setPalette(null))
+    return () => controller.abort()
+  }, [])
+
+  const handleStart = async () => {
+    const started = await startHueSession({ session }).catch(() => null)
+    // 読み込み後にパレットが切り替わっていたら、セッションの版で出題し直す。
+    if (started && started.palette_version !== palette?.version) {
+      fetchPalette({ version: started.palette_version })
+        .then(setPalette)
+        .catch(() => undefined)
+    }
+    setHueSession(started)
+    setChallenge(null)
+    fetchHueChallenge()
+      .then((issued) => setChallenge(issued.challenge))
+      .catch(() => undefined)
     setCurrentScreen('selection')
   }
 
+  const handleAnswer = (word: string, color: string, latencyMs: number) => {
+    // スキップ (未選択) はパレットに無いため送らない。送れなかった回答は終了時の記録に含まれないだけで、テストは続ける。
+    if (!hueSession || color === SKIPPED_COLOR) {
+      return
+    }
+    answerHueSession({ session_id: hueSession.session_id, word, color, latency_ms: latencyMs }).catch(() => undefined)
+  }
+
   const handleComplete = (results: Record<string, string>) => {
     setAssignments(results)
+    setIdempotencyKey(crypto.randomUUID())
     setCurrentScreen('result')
   }
 
@@ -31,14 +95,35 @@ const HueAreYouApp: React.FC = () => {
 
     setUserName(normalizedName)
 
-    await saveHueAreYouResult({
-      name: normalizedName,
-      choice: assignments,
-    })
+    // 開始時に取得できなかったときだけここで取得する。
+    const token = challenge ?? (await fetchHueChallenge()).challenge
+    setChallenge(token)
+
+    if (hueSession) {
+      await finishHueSession(
+        { session_id: hueSession.session_id, name: normalizedName, challenge: token },
+        { idempotencyKey: idempotencyKey ?? undefined }
+      )
+      return
+    }
+
+    await saveHueAreYouResult(
+      {
+        name: normalizedName,
+        choice: assignments,
+        word_set_id: wordSet?.id,
+        palette_version: palette?.version,
+        challenge: token,
+      },
+      { session, idempotencyKey: idempotencyKey ?? undefined }
+    )
   }
 
   const handleRestart = () => {
     setAssignments({})
+    setHueSession(null)
+    setChallenge(null)
+    setIdempotencyKey(null)
     setCurrentScreen('start')
   }
 
@@ -56,6 +141,9 @@ const HueAreYouApp: React.FC = () => {
         )}
         {currentScreen === 'selection' && (
           <SelectionScreen 
+            words={hueSession?.words ?? wordSet?.words ?? getWords()}
+            colors={paletteColorOptions(palette)}
+            onAnswer={handleAnswer}
             onComplete={handleComplete}
             onBack={handleBack}
           />
@@ -63,6 +151,7 @@ const HueAreYouApp: React.FC = () => {
         {currentScreen === 'result' && (
           <ResultScreen
             assignments={assignments}
+            colors={paletteColorOptions(palette)}
             userName={userName}
             onRestart={handleRestart}
             onSave={handleSave}
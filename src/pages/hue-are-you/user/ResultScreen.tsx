@@ -1,11 +1,12 @@
 import React, { useState } from 'react'
 import { ApiError } from '../../../api'
 import ErrorNotice, { type ErrorDescriptor } from '../../../components/ErrorNotice'
-import { colorToHex } from '../../../data/colors'
+import { findColorOption, type ColorOption } from '../../../data/colors'
 import './ResultScreen.css'
 
 interface ResultScreenProps {
   assignments: Record<string, string>
+  colors: ColorOption[]
   userName: string
   onRestart: () => void
   onSave: (name: string) => Promise<void>
@@ -13,6 +14,7 @@ interface ResultScreenProps {
 
 const ResultScreen: React.FC<ResultScreenProps> = ({ 
   assignments, 
+  colors,
   userName, 
   onRestart, 
   onSave 
@@ -68,8 +70,8 @@ const ResultScreen: React.FC<ResultScreenProps> = ({
               <div 
                 className="color-indicator"
                 style={{ 
-                  backgroundColor: colorToHex[color as keyof typeof colorToHex],
-                  border: color === '白' ? '2px solid #ccc' : 'none'
+                  backgroundColor: findColorOption(color, colors)?.hex,
+                  border: findColorOption(color, colors)?.id === 'white' ? '2px solid #ccc' : 'none'
                 }}
               />
               <span className="color-name">{color}</span>
@@ -1,29 +1,39 @@
-import React, { useState, useCallback } from 'react'
-import { getWords } from '../../../data/words'
-import { colors, colorToHex } from '../../../data/colors'
-import { WordColorAssignment, Color } from '../../../types'
+import React, { useState, useCallback, useEffect, useRef } from 'react'
+import { findColorOption, type ColorOption } from '../../../data/colors'
+import { WordColorAssignment } from '../../../types'
 import './SelectionScreen.css'
 
 interface SelectionScreenProps {
+  words: string[]
+  colors: ColorOption[]
+  // 色を選ぶたびに、単語を表示してから選ぶまでのミリ秒と一緒に呼ぶ。
+  onAnswer?: (word: string, color: string, latencyMs: number) => void
   onComplete: (assignments: Record<string, string>) => void
   onBack: () => void
 }
 
-const SelectionScreen: React.FC<SelectionScreenProps> = ({ onComplete, onBack }) => {
-  const wordsToUse = getWords()
+const SelectionScreen: React.FC<SelectionScreenProps> = ({ words, colors, onAnswer, onComplete, onBack }) => {
+  const wordsToUse = words
   const [assignments, setAssignments] = useState<WordColorAssignment[]>(
     wordsToUse.map(word => ({ word, color: null }))
   )
   const [currentWordIndex, setCurrentWordIndex] = useState(0)
   const [draggedWord, setDraggedWord] = useState<string | null>(null)
 
+  const shownAtRef = useRef(performance.now())
+
+  useEffect(() => {
+    shownAtRef.current = performance.now()
+  }, [currentWordIndex])
+
   const currentWord = assignments[currentWordIndex]
   const progress = ((currentWordIndex + 1) / wordsToUse.length) * 100
 
-  const handleColorSelect = useCallback((color: Color) => {
+  const handleColorSelect = useCallback((color: string) => {
     const newAssignments = [...assignments]
     newAssignments[currentWordIndex] = { ...currentWord, color }
     setAssignments(newAssignments)
+    onAnswer?.(currentWord.word, color, Math.round(performance.now() - shownAtRef.current))
 
     if (currentWordIndex < wordsToUse.length - 1) {
       setCurrentWordIndex(currentWordIndex + 1)
@@ -34,7 +44,7 @@ const SelectionScreen: React.FC<SelectionScreenProps> = ({ onComplete, onBack })
       })
       onComplete(result)
     }
-  }, [assignments, currentWordIndex, currentWord, onComplete])
+  }, [assignments, currentWordIndex, currentWord, onAnswer, onComplete, wordsToUse.length])
 
   const handlePrevious = useCallback(() => {
     if (currentWordIndex > 0) {
@@ -52,7 +62,7 @@ const SelectionScreen: React.FC<SelectionScreenProps> = ({ onComplete, onBack })
     e.dataTransfer.dropEffect = 'move'
   }
 
-  const handleDrop = (e: React.DragEvent, color: Color) => {
+  const handleDrop = (e: React.DragEvent, color: string) => {
     e.preventDefault()
     if (draggedWord === currentWord.word) {
       handleColorSelect(color)
@@ -92,7 +102,7 @@ const SelectionScreen: React.FC<SelectionScreenProps> = ({ onComplete, onBack })
             </div>
             {currentWord.color && (
               <div className="selected-color-info">
-                <span style={{ color: colorToHex[currentWord.color] }}>
+                <span style={{ color: findColorOption(currentWord.color, colors)?.hex }}>
                   {currentWord.color}
                 </span>
               </div>
@@ -100,7 +110,7 @@ const SelectionScreen: React.FC<SelectionScreenProps> = ({ onComplete, onBack })
           </div>
           
           <div className="color-circle">
-            {colors.map((color, index) => {
+            {colors.map(({ name: color, hex }, index) => {
               const angle = (index * 360) / colors.length
               const radius = 180
               const x = Math.cos((angle - 90) * Math.PI / 180) * radius
@@ -111,7 +121,7 @@ const SelectionScreen: React.FC<SelectionScreenProps> = ({ onComplete, onBack })
                   key={color}
                   className={`color-option color-position-${index} ${draggedWord ? 'drag-target' : ''}`}
                   style={{ 
-                    backgroundColor: colorToHex[color],
+                    backgroundColor: hex,
                     '--x-offset': `${x}px`,
                     '--y-offset': `${y}px`
                   } as React.CSSProperties}
@@ -136,7 +146,7 @@ const SelectionScreen: React.FC<SelectionScreenProps> = ({ onComplete, onBack })
           </button>
           <button 
             className="nav-button skip-button" 
-            onClick={() => handleColorSelect(currentWord.color || '未選択' as Color)}
+            onClick={() => handleColorSelect(currentWord.color || '未選択')}
           >
             スキップ
           </button>
@@ -46,5 +46,6 @@ export type Color =
 
 export interface WordColorAssignment {
   word: string
-  color: Color | null
+  // パレットの表示名。
+  color: string | null
 }
\ No newline at end of file